@@ -1447,6 +1447,107 @@ func (*RuntimeUpdateResponse) Descriptor() ([]byte, []int) {
 	return file_v1alpha1_runtime_proto_rawDescGZIP(), []int{25}
 }
 
+type RuntimeUpdateStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ControllerToken string    `protobuf:"bytes,1,opt,name=controller_token,json=controllerToken,proto3" json:"controller_token,omitempty"`
+	CurrentVersion  string    `protobuf:"bytes,2,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"`
+	NewResource     *Resource `protobuf:"bytes,3,opt,name=new_resource,json=newResource,proto3" json:"new_resource,omitempty"`
+}
+
+func (x *RuntimeUpdateStatusRequest) Reset() {
+	*x = RuntimeUpdateStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1alpha1_runtime_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RuntimeUpdateStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuntimeUpdateStatusRequest) ProtoMessage() {}
+
+func (x *RuntimeUpdateStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1alpha1_runtime_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuntimeUpdateStatusRequest.ProtoReflect.Descriptor instead.
+func (*RuntimeUpdateStatusRequest) Descriptor() ([]byte, []int) {
+	return file_v1alpha1_runtime_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RuntimeUpdateStatusRequest) GetControllerToken() string {
+	if x != nil {
+		return x.ControllerToken
+	}
+	return ""
+}
+
+func (x *RuntimeUpdateStatusRequest) GetCurrentVersion() string {
+	if x != nil {
+		return x.CurrentVersion
+	}
+	return ""
+}
+
+func (x *RuntimeUpdateStatusRequest) GetNewResource() *Resource {
+	if x != nil {
+		return x.NewResource
+	}
+	return nil
+}
+
+type RuntimeUpdateStatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *RuntimeUpdateStatusResponse) Reset() {
+	*x = RuntimeUpdateStatusResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1alpha1_runtime_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RuntimeUpdateStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuntimeUpdateStatusResponse) ProtoMessage() {}
+
+func (x *RuntimeUpdateStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1alpha1_runtime_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuntimeUpdateStatusResponse.ProtoReflect.Descriptor instead.
+func (*RuntimeUpdateStatusResponse) Descriptor() ([]byte, []int) {
+	return file_v1alpha1_runtime_proto_rawDescGZIP(), []int{27}
+}
+
 type RuntimeTeardownRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1461,7 +1562,7 @@ type RuntimeTeardownRequest struct {
 func (x *RuntimeTeardownRequest) Reset() {
 	*x = RuntimeTeardownRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1alpha1_runtime_proto_msgTypes[26]
+		mi := &file_v1alpha1_runtime_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1474,7 +1575,7 @@ func (x *RuntimeTeardownRequest) String() string {
 func (*RuntimeTeardownRequest) ProtoMessage() {}
 
 func (x *RuntimeTeardownRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_v1alpha1_runtime_proto_msgTypes[26]
+	mi := &file_v1alpha1_runtime_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1487,7 +1588,7 @@ func (x *RuntimeTeardownRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RuntimeTeardownRequest.ProtoReflect.Descriptor instead.
 func (*RuntimeTeardownRequest) Descriptor() ([]byte, []int) {
-	return file_v1alpha1_runtime_proto_rawDescGZIP(), []int{26}
+	return file_v1alpha1_runtime_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *RuntimeTeardownRequest) GetControllerToken() string {
@@ -1529,7 +1630,7 @@ type RuntimeTeardownResponse struct {
 func (x *RuntimeTeardownResponse) Reset() {
 	*x = RuntimeTeardownResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1alpha1_runtime_proto_msgTypes[27]
+		mi := &file_v1alpha1_runtime_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1542,7 +1643,7 @@ func (x *RuntimeTeardownResponse) String() string {
 func (*RuntimeTeardownResponse) ProtoMessage() {}
 
 func (x *RuntimeTeardownResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_v1alpha1_runtime_proto_msgTypes[27]
+	mi := &file_v1alpha1_runtime_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1555,7 +1656,7 @@ func (x *RuntimeTeardownResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RuntimeTeardownResponse.ProtoReflect.Descriptor instead.
 func (*RuntimeTeardownResponse) Descriptor() ([]byte, []int) {
-	return file_v1alpha1_runtime_proto_rawDescGZIP(), []int{27}
+	return file_v1alpha1_runtime_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *RuntimeTeardownResponse) GetReady() bool {
@@ -1579,7 +1680,7 @@ type RuntimeDestroyRequest struct {
 func (x *RuntimeDestroyRequest) Reset() {
 	*x = RuntimeDestroyRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1alpha1_runtime_proto_msgTypes[28]
+		mi := &file_v1alpha1_runtime_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1592,7 +1693,7 @@ func (x *RuntimeDestroyRequest) String() string {
 func (*RuntimeDestroyRequest) ProtoMessage() {}
 
 func (x *RuntimeDestroyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_v1alpha1_runtime_proto_msgTypes[28]
+	mi := &file_v1alpha1_runtime_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1605,7 +1706,7 @@ func (x *RuntimeDestroyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RuntimeDestroyRequest.ProtoReflect.Descriptor instead.
 func (*RuntimeDestroyRequest) Descriptor() ([]byte, []int) {
-	return file_v1alpha1_runtime_proto_rawDescGZIP(), []int{28}
+	return file_v1alpha1_runtime_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *RuntimeDestroyRequest) GetControllerToken() string {
@@ -1645,7 +1746,7 @@ type RuntimeDestroyResponse struct {
 func (x *RuntimeDestroyResponse) Reset() {
 	*x = RuntimeDestroyResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1alpha1_runtime_proto_msgTypes[29]
+		mi := &file_v1alpha1_runtime_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1658,7 +1759,7 @@ func (x *RuntimeDestroyResponse) String() string {
 func (*RuntimeDestroyResponse) ProtoMessage() {}
 
 func (x *RuntimeDestroyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_v1alpha1_runtime_proto_msgTypes[29]
+	mi := &file_v1alpha1_runtime_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1671,7 +1772,7 @@ func (x *RuntimeDestroyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RuntimeDestroyResponse.ProtoReflect.Descriptor instead.
 func (*RuntimeDestroyResponse) Descriptor() ([]byte, []int) {
-	return file_v1alpha1_runtime_proto_rawDescGZIP(), []int{29}
+	return file_v1alpha1_runtime_proto_rawDescGZIP(), []int{31}
 }
 
 type RuntimeAddFinalizerRequest struct {
@@ -1689,7 +1790,7 @@ type RuntimeAddFinalizerRequest struct {
 func (x *RuntimeAddFinalizerRequest) Reset() {
 	*x = RuntimeAddFinalizerRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1alpha1_runtime_proto_msgTypes[30]
+		mi := &file_v1alpha1_runtime_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1702,7 +1803,7 @@ func (x *RuntimeAddFinalizerRequest) String() string {
 func (*RuntimeAddFinalizerRequest) ProtoMessage() {}
 
 func (x *RuntimeAddFinalizerRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_v1alpha1_runtime_proto_msgTypes[30]
+	mi := &file_v1alpha1_runtime_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1715,7 +1816,7 @@ func (x *RuntimeAddFinalizerRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RuntimeAddFinalizerRequest.ProtoReflect.Descriptor instead.
 func (*RuntimeAddFinalizerRequest) Descriptor() ([]byte, []int) {
-	return file_v1alpha1_runtime_proto_rawDescGZIP(), []int{30}
+	return file_v1alpha1_runtime_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *RuntimeAddFinalizerRequest) GetControllerToken() string {
@@ -1762,7 +1863,7 @@ type RuntimeAddFinalizerResponse struct {
 func (x *RuntimeAddFinalizerResponse) Reset() {
 	*x = RuntimeAddFinalizerResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1alpha1_runtime_proto_msgTypes[31]
+		mi := &file_v1alpha1_runtime_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1775,7 +1876,7 @@ func (x *RuntimeAddFinalizerResponse) String() string {
 func (*RuntimeAddFinalizerResponse) ProtoMessage() {}
 
 func (x *RuntimeAddFinalizerResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_v1alpha1_runtime_proto_msgTypes[31]
+	mi := &file_v1alpha1_runtime_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1788,7 +1889,7 @@ func (x *RuntimeAddFinalizerResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RuntimeAddFinalizerResponse.ProtoReflect.Descriptor instead.
 func (*RuntimeAddFinalizerResponse) Descriptor() ([]byte, []int) {
-	return file_v1alpha1_runtime_proto_rawDescGZIP(), []int{31}
+	return file_v1alpha1_runtime_proto_rawDescGZIP(), []int{33}
 }
 
 type RuntimeRemoveFinalizerRequest struct {
@@ -1806,7 +1907,7 @@ type RuntimeRemoveFinalizerRequest struct {
 func (x *RuntimeRemoveFinalizerRequest) Reset() {
 	*x = RuntimeRemoveFinalizerRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1alpha1_runtime_proto_msgTypes[32]
+		mi := &file_v1alpha1_runtime_proto_msgTypes[34]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1819,7 +1920,7 @@ func (x *RuntimeRemoveFinalizerRequest) String() string {
 func (*RuntimeRemoveFinalizerRequest) ProtoMessage() {}
 
 func (x *RuntimeRemoveFinalizerRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_v1alpha1_runtime_proto_msgTypes[32]
+	mi := &file_v1alpha1_runtime_proto_msgTypes[34]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1832,7 +1933,7 @@ func (x *RuntimeRemoveFinalizerRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RuntimeRemoveFinalizerRequest.ProtoReflect.Descriptor instead.
 func (*RuntimeRemoveFinalizerRequest) Descriptor() ([]byte, []int) {
-	return file_v1alpha1_runtime_proto_rawDescGZIP(), []int{32}
+	return file_v1alpha1_runtime_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *RuntimeRemoveFinalizerRequest) GetControllerToken() string {
@@ -1879,7 +1980,7 @@ type RuntimeRemoveFinalizerResponse struct {
 func (x *RuntimeRemoveFinalizerResponse) Reset() {
 	*x = RuntimeRemoveFinalizerResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1alpha1_runtime_proto_msgTypes[33]
+		mi := &file_v1alpha1_runtime_proto_msgTypes[35]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1892,7 +1993,7 @@ func (x *RuntimeRemoveFinalizerResponse) String() string {
 func (*RuntimeRemoveFinalizerResponse) ProtoMessage() {}
 
 func (x *RuntimeRemoveFinalizerResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_v1alpha1_runtime_proto_msgTypes[33]
+	mi := &file_v1alpha1_runtime_proto_msgTypes[35]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1905,7 +2006,7 @@ func (x *RuntimeRemoveFinalizerResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RuntimeRemoveFinalizerResponse.ProtoReflect.Descriptor instead.
 func (*RuntimeRemoveFinalizerResponse) Descriptor() ([]byte, []int) {
-	return file_v1alpha1_runtime_proto_rawDescGZIP(), []int{33}
+	return file_v1alpha1_runtime_proto_rawDescGZIP(), []int{35}
 }
 
 var File_v1alpha1_runtime_proto protoreflect.FileDescriptor
@@ -2046,30 +2147,21 @@ var file_v1alpha1_runtime_proto_rawDesc = []byte{
 	0x17, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x2e,
 	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x0b, 0x6e, 0x65, 0x77, 0x52, 0x65, 0x73,
 	0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0x17, 0x0a, 0x15, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
-	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x85,
-	0x01, 0x0a, 0x16, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x54, 0x65, 0x61, 0x72, 0x64, 0x6f,
-	0x77, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x6f, 0x6e,
-	0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x54,
-	0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63,
-	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61,
-	0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x2f, 0x0a, 0x17, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d,
-	0x65, 0x54, 0x65, 0x61, 0x72, 0x64, 0x6f, 0x77, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x65, 0x61, 0x64, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x05, 0x72, 0x65, 0x61, 0x64, 0x79, 0x22, 0x84, 0x01, 0x0a, 0x15, 0x52, 0x75, 0x6e, 0x74,
-	0x69, 0x6d, 0x65, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x5f,
-	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x6f, 0x6e,
-	0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1c, 0x0a, 0x09,
-	0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79,
-	0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x0e,
-	0x0a, 0x02, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x18,
-	0x0a, 0x16, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xa9, 0x01, 0x0a, 0x1a, 0x52, 0x75, 0x6e,
-	0x74, 0x69, 0x6d, 0x65, 0x41, 0x64, 0x64, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xac,
+	0x01, 0x0a, 0x1a, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a,
+	0x10, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x5f, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x6c, 0x65, 0x72, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x74, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x3a, 0x0a, 0x0c, 0x6e, 0x65, 0x77, 0x5f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72,
+	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x52, 0x0b, 0x6e, 0x65, 0x77, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0x1d, 0x0a,
+	0x1b, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x85, 0x01, 0x0a,
+	0x16, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x54, 0x65, 0x61, 0x72, 0x64, 0x6f, 0x77, 0x6e,
 	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x6f, 0x6e, 0x74, 0x72,
 	0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x09, 0x52, 0x0f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x54, 0x6f, 0x6b,
@@ -2077,12 +2169,21 @@ var file_v1alpha1_runtime_proto_rawDesc = []byte{
 	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65,
 	0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
 	0x74, 0x79, 0x70, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x02, 0x69, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65,
-	0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69,
-	0x7a, 0x65, 0x72, 0x73, 0x22, 0x1d, 0x0a, 0x1b, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x41,
-	0x64, 0x64, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x22, 0xac, 0x01, 0x0a, 0x1d, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52,
-	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x52, 0x65,
+	0x52, 0x02, 0x69, 0x64, 0x22, 0x2f, 0x0a, 0x17, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x54,
+	0x65, 0x61, 0x72, 0x64, 0x6f, 0x77, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x72, 0x65, 0x61, 0x64, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05,
+	0x72, 0x65, 0x61, 0x64, 0x79, 0x22, 0x84, 0x01, 0x0a, 0x15, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d,
+	0x65, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x29, 0x0a, 0x10, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x5f, 0x74, 0x6f,
+	0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x6f, 0x6e, 0x74, 0x72,
+	0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61,
+	0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e,
+	0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x18, 0x0a, 0x16,
+	0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xa9, 0x01, 0x0a, 0x1a, 0x52, 0x75, 0x6e, 0x74, 0x69,
+	0x6d, 0x65, 0x41, 0x64, 0x64, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x52, 0x65,
 	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
 	0x6c, 0x65, 0x72, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
 	0x0f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x54, 0x6f, 0x6b, 0x65, 0x6e,
@@ -2092,104 +2193,123 @@ var file_v1alpha1_runtime_proto_rawDesc = []byte{
 	0x70, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
 	0x69, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x73,
 	0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65,
-	0x72, 0x73, 0x22, 0x20, 0x0a, 0x1e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x6d,
-	0x6f, 0x76, 0x65, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x2a, 0x3e, 0x0a, 0x13, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c,
-	0x65, 0x72, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x08, 0x0a, 0x04, 0x57,
-	0x45, 0x41, 0x4b, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x54, 0x52, 0x4f, 0x4e, 0x47, 0x10,
-	0x01, 0x12, 0x11, 0x0a, 0x0d, 0x44, 0x45, 0x53, 0x54, 0x52, 0x4f, 0x59, 0x5f, 0x52, 0x45, 0x41,
-	0x44, 0x59, 0x10, 0x02, 0x2a, 0x31, 0x0a, 0x14, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c,
-	0x65, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x0d, 0x0a, 0x09,
-	0x45, 0x58, 0x43, 0x4c, 0x55, 0x53, 0x49, 0x56, 0x45, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53,
-	0x48, 0x41, 0x52, 0x45, 0x44, 0x10, 0x01, 0x32, 0xfd, 0x01, 0x0a, 0x11, 0x43, 0x6f, 0x6e, 0x74,
-	0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x67, 0x0a,
-	0x12, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
-	0x6c, 0x65, 0x72, 0x12, 0x27, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69,
-	0x6d, 0x65, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x72,
-	0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x63,
-	0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x65, 0x67, 0x69,
-	0x73, 0x74, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12,
-	0x1a, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x53,
-	0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x63, 0x6f,
-	0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x04, 0x53, 0x74, 0x6f, 0x70,
-	0x12, 0x19, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e,
-	0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x63, 0x6f,
-	0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xc3, 0x08, 0x0a, 0x11, 0x43, 0x6f, 0x6e, 0x74,
-	0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x41, 0x64, 0x61, 0x70, 0x74, 0x65, 0x72, 0x12, 0x60, 0x0a,
-	0x0f, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73,
-	0x12, 0x24, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e,
-	0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75,
-	0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x45,
-	0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12,
-	0x5b, 0x0a, 0x0e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c,
-	0x65, 0x12, 0x23, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
-	0x2e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75,
-	0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x6e,
-	0x63, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x55, 0x0a, 0x0c,
-	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x12, 0x21, 0x2e, 0x63,
-	0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x55, 0x70, 0x64, 0x61,
-	0x74, 0x65, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x22, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x55,
-	0x70, 0x64, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a, 0x03, 0x47, 0x65, 0x74, 0x12, 0x1f, 0x2e, 0x63, 0x6f, 0x73,
-	0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d,
-	0x65, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x63, 0x6f,
-	0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69,
-	0x6d, 0x65, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a,
-	0x04, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x20, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e,
+	0x72, 0x73, 0x22, 0x1d, 0x0a, 0x1b, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x41, 0x64, 0x64,
+	0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0xac, 0x01, 0x0a, 0x1d, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65,
+	0x72, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x63,
+	0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1c,
+	0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x1e, 0x0a, 0x0a, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x73, 0x18, 0x05,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x73,
+	0x22, 0x20, 0x0a, 0x1e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x2a, 0x3e, 0x0a, 0x13, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72,
+	0x49, 0x6e, 0x70, 0x75, 0x74, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x08, 0x0a, 0x04, 0x57, 0x45, 0x41,
+	0x4b, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x54, 0x52, 0x4f, 0x4e, 0x47, 0x10, 0x01, 0x12,
+	0x11, 0x0a, 0x0d, 0x44, 0x45, 0x53, 0x54, 0x52, 0x4f, 0x59, 0x5f, 0x52, 0x45, 0x41, 0x44, 0x59,
+	0x10, 0x02, 0x2a, 0x31, 0x0a, 0x14, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72,
+	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x0d, 0x0a, 0x09, 0x45, 0x58,
+	0x43, 0x4c, 0x55, 0x53, 0x49, 0x56, 0x45, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x48, 0x41,
+	0x52, 0x45, 0x44, 0x10, 0x01, 0x32, 0xfd, 0x01, 0x0a, 0x11, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f,
+	0x6c, 0x6c, 0x65, 0x72, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x67, 0x0a, 0x12, 0x52,
+	0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65,
+	0x72, 0x12, 0x27, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
+	0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x6c, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x63, 0x6f, 0x73,
+	0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x65, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x1a, 0x2e,
+	0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x63, 0x6f, 0x73, 0x69,
+	0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x04, 0x53, 0x74, 0x6f, 0x70, 0x12, 0x19,
+	0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x53, 0x74,
+	0x6f, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x63, 0x6f, 0x73, 0x69,
+	0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xa8, 0x09, 0x0a, 0x11, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f,
+	0x6c, 0x6c, 0x65, 0x72, 0x41, 0x64, 0x61, 0x70, 0x74, 0x65, 0x72, 0x12, 0x60, 0x0a, 0x0f, 0x52,
+	0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x24,
+	0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x65,
+	0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74,
+	0x69, 0x6d, 0x65, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x5b, 0x0a,
+	0x0e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x12,
+	0x23, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x51,
+	0x75, 0x65, 0x75, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74,
+	0x69, 0x6d, 0x65, 0x2e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69,
+	0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x55, 0x0a, 0x0c, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x12, 0x21, 0x2e, 0x63, 0x6f, 0x73,
+	0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x49, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e,
+	0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x48, 0x0a, 0x03, 0x47, 0x65, 0x74, 0x12, 0x1f, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e,
+	0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x47,
+	0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x63, 0x6f, 0x73, 0x69,
+	0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
+	0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x04, 0x4c,
+	0x69, 0x73, 0x74, 0x12, 0x20, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69,
+	0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e,
 	0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x4c, 0x69, 0x73, 0x74,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72,
-	0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x4c, 0x69,
-	0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x57, 0x0a, 0x08,
-	0x57, 0x61, 0x74, 0x63, 0x68, 0x46, 0x6f, 0x72, 0x12, 0x24, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e,
-	0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x57,
-	0x61, 0x74, 0x63, 0x68, 0x46, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25,
-	0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75,
-	0x6e, 0x74, 0x69, 0x6d, 0x65, 0x57, 0x61, 0x74, 0x63, 0x68, 0x46, 0x6f, 0x72, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x06, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12,
-	0x22, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52,
-	0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69,
-	0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x06, 0x55, 0x70, 0x64, 0x61,
-	0x74, 0x65, 0x12, 0x22, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d,
-	0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75,
-	0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x55, 0x70, 0x64,
-	0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x57, 0x0a, 0x08, 0x54,
-	0x65, 0x61, 0x72, 0x64, 0x6f, 0x77, 0x6e, 0x12, 0x24, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72,
-	0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x54, 0x65,
-	0x61, 0x72, 0x64, 0x6f, 0x77, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x57, 0x0a, 0x08, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x46, 0x6f, 0x72, 0x12, 0x24, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75,
+	0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x57, 0x61, 0x74,
+	0x63, 0x68, 0x46, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x63,
+	0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74,
+	0x69, 0x6d, 0x65, 0x57, 0x61, 0x74, 0x63, 0x68, 0x46, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x06, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x22, 0x2e,
 	0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e,
-	0x74, 0x69, 0x6d, 0x65, 0x54, 0x65, 0x61, 0x72, 0x64, 0x6f, 0x77, 0x6e, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x54, 0x0a, 0x07, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x12,
-	0x23, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52,
-	0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74,
-	0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x44, 0x65, 0x73, 0x74, 0x72,
-	0x6f, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x63, 0x0a, 0x0c, 0x41, 0x64,
-	0x64, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x12, 0x28, 0x2e, 0x63, 0x6f, 0x73,
+	0x74, 0x69, 0x6d, 0x65, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x23, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
+	0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x06, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x12, 0x22, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e,
+	0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74,
+	0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x63, 0x0a, 0x0c, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x28, 0x2e, 0x63, 0x6f, 0x73, 0x69,
+	0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69,
+	0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x57,
+	0x0a, 0x08, 0x54, 0x65, 0x61, 0x72, 0x64, 0x6f, 0x77, 0x6e, 0x12, 0x24, 0x2e, 0x63, 0x6f, 0x73,
 	0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d,
-	0x65, 0x41, 0x64, 0x64, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74,
-	0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x41, 0x64, 0x64, 0x46, 0x69,
-	0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x6c, 0x0a, 0x0f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a,
-	0x65, 0x72, 0x12, 0x2b, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d,
+	0x65, 0x54, 0x65, 0x61, 0x72, 0x64, 0x6f, 0x77, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x25, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e,
+	0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x54, 0x65, 0x61, 0x72, 0x64, 0x6f, 0x77, 0x6e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x54, 0x0a, 0x07, 0x44, 0x65, 0x73, 0x74, 0x72,
+	0x6f, 0x79, 0x12, 0x23, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d,
+	0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72,
+	0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x44, 0x65,
+	0x73, 0x74, 0x72, 0x6f, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x63, 0x0a,
+	0x0c, 0x41, 0x64, 0x64, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x12, 0x28, 0x2e,
+	0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e,
+	0x74, 0x69, 0x6d, 0x65, 0x41, 0x64, 0x64, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72,
+	0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x41, 0x64,
+	0x64, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x6c, 0x0a, 0x0f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x46, 0x69, 0x6e, 0x61,
+	0x6c, 0x69, 0x7a, 0x65, 0x72, 0x12, 0x2b, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e,
+	0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d,
 	0x65, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x46,
-	0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x2c, 0x2e, 0x63, 0x6f, 0x73, 0x69, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x52,
-	0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x46, 0x69, 0x6e, 0x61,
-	0x6c, 0x69, 0x7a, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x2e, 0x5a,
-	0x2c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6f, 0x73, 0x69,
-	0x2d, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2f, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
-	0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x42, 0x2e, 0x5a, 0x2c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63,
+	0x6f, 0x73, 0x69, 0x2d, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2f, 0x72, 0x75, 0x6e, 0x74,
+	0x69, 0x6d, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -2205,7 +2325,7 @@ func file_v1alpha1_runtime_proto_rawDescGZIP() []byte {
 }
 
 var file_v1alpha1_runtime_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_v1alpha1_runtime_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
+var file_v1alpha1_runtime_proto_msgTypes = make([]protoimpl.MessageInfo, 36)
 var file_v1alpha1_runtime_proto_goTypes = []interface{}{
 	(ControllerInputKind)(0),               // 0: cosi.runtime.ControllerInputKind
 	(ControllerOutputKind)(0),              // 1: cosi.runtime.ControllerOutputKind
@@ -2235,16 +2355,18 @@ var file_v1alpha1_runtime_proto_goTypes = []interface{}{
 	(*RuntimeCreateResponse)(nil),          // 25: cosi.runtime.RuntimeCreateResponse
 	(*RuntimeUpdateRequest)(nil),           // 26: cosi.runtime.RuntimeUpdateRequest
 	(*RuntimeUpdateResponse)(nil),          // 27: cosi.runtime.RuntimeUpdateResponse
-	(*RuntimeTeardownRequest)(nil),         // 28: cosi.runtime.RuntimeTeardownRequest
-	(*RuntimeTeardownResponse)(nil),        // 29: cosi.runtime.RuntimeTeardownResponse
-	(*RuntimeDestroyRequest)(nil),          // 30: cosi.runtime.RuntimeDestroyRequest
-	(*RuntimeDestroyResponse)(nil),         // 31: cosi.runtime.RuntimeDestroyResponse
-	(*RuntimeAddFinalizerRequest)(nil),     // 32: cosi.runtime.RuntimeAddFinalizerRequest
-	(*RuntimeAddFinalizerResponse)(nil),    // 33: cosi.runtime.RuntimeAddFinalizerResponse
-	(*RuntimeRemoveFinalizerRequest)(nil),  // 34: cosi.runtime.RuntimeRemoveFinalizerRequest
-	(*RuntimeRemoveFinalizerResponse)(nil), // 35: cosi.runtime.RuntimeRemoveFinalizerResponse
-	(*Resource)(nil),                       // 36: cosi.resource.Resource
-	(*LabelQuery)(nil),                     // 37: cosi.resource.LabelQuery
+	(*RuntimeUpdateStatusRequest)(nil),     // 28: cosi.runtime.RuntimeUpdateStatusRequest
+	(*RuntimeUpdateStatusResponse)(nil),    // 29: cosi.runtime.RuntimeUpdateStatusResponse
+	(*RuntimeTeardownRequest)(nil),         // 30: cosi.runtime.RuntimeTeardownRequest
+	(*RuntimeTeardownResponse)(nil),        // 31: cosi.runtime.RuntimeTeardownResponse
+	(*RuntimeDestroyRequest)(nil),          // 32: cosi.runtime.RuntimeDestroyRequest
+	(*RuntimeDestroyResponse)(nil),         // 33: cosi.runtime.RuntimeDestroyResponse
+	(*RuntimeAddFinalizerRequest)(nil),     // 34: cosi.runtime.RuntimeAddFinalizerRequest
+	(*RuntimeAddFinalizerResponse)(nil),    // 35: cosi.runtime.RuntimeAddFinalizerResponse
+	(*RuntimeRemoveFinalizerRequest)(nil),  // 36: cosi.runtime.RuntimeRemoveFinalizerRequest
+	(*RuntimeRemoveFinalizerResponse)(nil), // 37: cosi.runtime.RuntimeRemoveFinalizerResponse
+	(*Resource)(nil),                       // 38: cosi.resource.Resource
+	(*LabelQuery)(nil),                     // 39: cosi.resource.LabelQuery
 }
 var file_v1alpha1_runtime_proto_depIdxs = []int32{
 	0,  // 0: cosi.runtime.ControllerInput.kind:type_name -> cosi.runtime.ControllerInputKind
@@ -2252,49 +2374,52 @@ var file_v1alpha1_runtime_proto_depIdxs = []int32{
 	2,  // 2: cosi.runtime.RegisterControllerRequest.inputs:type_name -> cosi.runtime.ControllerInput
 	3,  // 3: cosi.runtime.RegisterControllerRequest.outputs:type_name -> cosi.runtime.ControllerOutput
 	2,  // 4: cosi.runtime.UpdateInputsRequest.inputs:type_name -> cosi.runtime.ControllerInput
-	36, // 5: cosi.runtime.RuntimeGetResponse.resource:type_name -> cosi.resource.Resource
-	37, // 6: cosi.runtime.RuntimeListOptions.label_query:type_name -> cosi.resource.LabelQuery
+	38, // 5: cosi.runtime.RuntimeGetResponse.resource:type_name -> cosi.resource.Resource
+	39, // 6: cosi.runtime.RuntimeListOptions.label_query:type_name -> cosi.resource.LabelQuery
 	18, // 7: cosi.runtime.RuntimeListRequest.options:type_name -> cosi.runtime.RuntimeListOptions
-	36, // 8: cosi.runtime.RuntimeListResponse.resource:type_name -> cosi.resource.Resource
+	38, // 8: cosi.runtime.RuntimeListResponse.resource:type_name -> cosi.resource.Resource
 	22, // 9: cosi.runtime.RuntimeWatchForRequest.finalizers_empty:type_name -> cosi.runtime.ConditionFinalizersEmpty
-	36, // 10: cosi.runtime.RuntimeWatchForResponse.resource:type_name -> cosi.resource.Resource
-	36, // 11: cosi.runtime.RuntimeCreateRequest.resource:type_name -> cosi.resource.Resource
-	36, // 12: cosi.runtime.RuntimeUpdateRequest.new_resource:type_name -> cosi.resource.Resource
-	4,  // 13: cosi.runtime.ControllerRuntime.RegisterController:input_type -> cosi.runtime.RegisterControllerRequest
-	6,  // 14: cosi.runtime.ControllerRuntime.Start:input_type -> cosi.runtime.StartRequest
-	8,  // 15: cosi.runtime.ControllerRuntime.Stop:input_type -> cosi.runtime.StopRequest
-	10, // 16: cosi.runtime.ControllerAdapter.ReconcileEvents:input_type -> cosi.runtime.ReconcileEventsRequest
-	12, // 17: cosi.runtime.ControllerAdapter.QueueReconcile:input_type -> cosi.runtime.QueueReconcileRequest
-	14, // 18: cosi.runtime.ControllerAdapter.UpdateInputs:input_type -> cosi.runtime.UpdateInputsRequest
-	16, // 19: cosi.runtime.ControllerAdapter.Get:input_type -> cosi.runtime.RuntimeGetRequest
-	19, // 20: cosi.runtime.ControllerAdapter.List:input_type -> cosi.runtime.RuntimeListRequest
-	21, // 21: cosi.runtime.ControllerAdapter.WatchFor:input_type -> cosi.runtime.RuntimeWatchForRequest
-	24, // 22: cosi.runtime.ControllerAdapter.Create:input_type -> cosi.runtime.RuntimeCreateRequest
-	26, // 23: cosi.runtime.ControllerAdapter.Update:input_type -> cosi.runtime.RuntimeUpdateRequest
-	28, // 24: cosi.runtime.ControllerAdapter.Teardown:input_type -> cosi.runtime.RuntimeTeardownRequest
-	30, // 25: cosi.runtime.ControllerAdapter.Destroy:input_type -> cosi.runtime.RuntimeDestroyRequest
-	32, // 26: cosi.runtime.ControllerAdapter.AddFinalizer:input_type -> cosi.runtime.RuntimeAddFinalizerRequest
-	34, // 27: cosi.runtime.ControllerAdapter.RemoveFinalizer:input_type -> cosi.runtime.RuntimeRemoveFinalizerRequest
-	5,  // 28: cosi.runtime.ControllerRuntime.RegisterController:output_type -> cosi.runtime.RegisterControllerResponse
-	7,  // 29: cosi.runtime.ControllerRuntime.Start:output_type -> cosi.runtime.StartResponse
-	9,  // 30: cosi.runtime.ControllerRuntime.Stop:output_type -> cosi.runtime.StopResponse
-	11, // 31: cosi.runtime.ControllerAdapter.ReconcileEvents:output_type -> cosi.runtime.ReconcileEventsResponse
-	13, // 32: cosi.runtime.ControllerAdapter.QueueReconcile:output_type -> cosi.runtime.QueueReconcileResponse
-	15, // 33: cosi.runtime.ControllerAdapter.UpdateInputs:output_type -> cosi.runtime.UpdateInputsResponse
-	17, // 34: cosi.runtime.ControllerAdapter.Get:output_type -> cosi.runtime.RuntimeGetResponse
-	20, // 35: cosi.runtime.ControllerAdapter.List:output_type -> cosi.runtime.RuntimeListResponse
-	23, // 36: cosi.runtime.ControllerAdapter.WatchFor:output_type -> cosi.runtime.RuntimeWatchForResponse
-	25, // 37: cosi.runtime.ControllerAdapter.Create:output_type -> cosi.runtime.RuntimeCreateResponse
-	27, // 38: cosi.runtime.ControllerAdapter.Update:output_type -> cosi.runtime.RuntimeUpdateResponse
-	29, // 39: cosi.runtime.ControllerAdapter.Teardown:output_type -> cosi.runtime.RuntimeTeardownResponse
-	31, // 40: cosi.runtime.ControllerAdapter.Destroy:output_type -> cosi.runtime.RuntimeDestroyResponse
-	33, // 41: cosi.runtime.ControllerAdapter.AddFinalizer:output_type -> cosi.runtime.RuntimeAddFinalizerResponse
-	35, // 42: cosi.runtime.ControllerAdapter.RemoveFinalizer:output_type -> cosi.runtime.RuntimeRemoveFinalizerResponse
-	28, // [28:43] is the sub-list for method output_type
-	13, // [13:28] is the sub-list for method input_type
-	13, // [13:13] is the sub-list for extension type_name
-	13, // [13:13] is the sub-list for extension extendee
-	0,  // [0:13] is the sub-list for field type_name
+	38, // 10: cosi.runtime.RuntimeWatchForResponse.resource:type_name -> cosi.resource.Resource
+	38, // 11: cosi.runtime.RuntimeCreateRequest.resource:type_name -> cosi.resource.Resource
+	38, // 12: cosi.runtime.RuntimeUpdateRequest.new_resource:type_name -> cosi.resource.Resource
+	38, // 13: cosi.runtime.RuntimeUpdateStatusRequest.new_resource:type_name -> cosi.resource.Resource
+	4,  // 14: cosi.runtime.ControllerRuntime.RegisterController:input_type -> cosi.runtime.RegisterControllerRequest
+	6,  // 15: cosi.runtime.ControllerRuntime.Start:input_type -> cosi.runtime.StartRequest
+	8,  // 16: cosi.runtime.ControllerRuntime.Stop:input_type -> cosi.runtime.StopRequest
+	10, // 17: cosi.runtime.ControllerAdapter.ReconcileEvents:input_type -> cosi.runtime.ReconcileEventsRequest
+	12, // 18: cosi.runtime.ControllerAdapter.QueueReconcile:input_type -> cosi.runtime.QueueReconcileRequest
+	14, // 19: cosi.runtime.ControllerAdapter.UpdateInputs:input_type -> cosi.runtime.UpdateInputsRequest
+	16, // 20: cosi.runtime.ControllerAdapter.Get:input_type -> cosi.runtime.RuntimeGetRequest
+	19, // 21: cosi.runtime.ControllerAdapter.List:input_type -> cosi.runtime.RuntimeListRequest
+	21, // 22: cosi.runtime.ControllerAdapter.WatchFor:input_type -> cosi.runtime.RuntimeWatchForRequest
+	24, // 23: cosi.runtime.ControllerAdapter.Create:input_type -> cosi.runtime.RuntimeCreateRequest
+	26, // 24: cosi.runtime.ControllerAdapter.Update:input_type -> cosi.runtime.RuntimeUpdateRequest
+	28, // 25: cosi.runtime.ControllerAdapter.UpdateStatus:input_type -> cosi.runtime.RuntimeUpdateStatusRequest
+	30, // 26: cosi.runtime.ControllerAdapter.Teardown:input_type -> cosi.runtime.RuntimeTeardownRequest
+	32, // 27: cosi.runtime.ControllerAdapter.Destroy:input_type -> cosi.runtime.RuntimeDestroyRequest
+	34, // 28: cosi.runtime.ControllerAdapter.AddFinalizer:input_type -> cosi.runtime.RuntimeAddFinalizerRequest
+	36, // 29: cosi.runtime.ControllerAdapter.RemoveFinalizer:input_type -> cosi.runtime.RuntimeRemoveFinalizerRequest
+	5,  // 30: cosi.runtime.ControllerRuntime.RegisterController:output_type -> cosi.runtime.RegisterControllerResponse
+	7,  // 31: cosi.runtime.ControllerRuntime.Start:output_type -> cosi.runtime.StartResponse
+	9,  // 32: cosi.runtime.ControllerRuntime.Stop:output_type -> cosi.runtime.StopResponse
+	11, // 33: cosi.runtime.ControllerAdapter.ReconcileEvents:output_type -> cosi.runtime.ReconcileEventsResponse
+	13, // 34: cosi.runtime.ControllerAdapter.QueueReconcile:output_type -> cosi.runtime.QueueReconcileResponse
+	15, // 35: cosi.runtime.ControllerAdapter.UpdateInputs:output_type -> cosi.runtime.UpdateInputsResponse
+	17, // 36: cosi.runtime.ControllerAdapter.Get:output_type -> cosi.runtime.RuntimeGetResponse
+	20, // 37: cosi.runtime.ControllerAdapter.List:output_type -> cosi.runtime.RuntimeListResponse
+	23, // 38: cosi.runtime.ControllerAdapter.WatchFor:output_type -> cosi.runtime.RuntimeWatchForResponse
+	25, // 39: cosi.runtime.ControllerAdapter.Create:output_type -> cosi.runtime.RuntimeCreateResponse
+	27, // 40: cosi.runtime.ControllerAdapter.Update:output_type -> cosi.runtime.RuntimeUpdateResponse
+	29, // 41: cosi.runtime.ControllerAdapter.UpdateStatus:output_type -> cosi.runtime.RuntimeUpdateStatusResponse
+	31, // 42: cosi.runtime.ControllerAdapter.Teardown:output_type -> cosi.runtime.RuntimeTeardownResponse
+	33, // 43: cosi.runtime.ControllerAdapter.Destroy:output_type -> cosi.runtime.RuntimeDestroyResponse
+	35, // 44: cosi.runtime.ControllerAdapter.AddFinalizer:output_type -> cosi.runtime.RuntimeAddFinalizerResponse
+	37, // 45: cosi.runtime.ControllerAdapter.RemoveFinalizer:output_type -> cosi.runtime.RuntimeRemoveFinalizerResponse
+	30, // [30:46] is the sub-list for method output_type
+	14, // [14:30] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_v1alpha1_runtime_proto_init() }
@@ -2617,7 +2742,7 @@ func file_v1alpha1_runtime_proto_init() {
 			}
 		}
 		file_v1alpha1_runtime_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RuntimeTeardownRequest); i {
+			switch v := v.(*RuntimeUpdateStatusRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2629,7 +2754,7 @@ func file_v1alpha1_runtime_proto_init() {
 			}
 		}
 		file_v1alpha1_runtime_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RuntimeTeardownResponse); i {
+			switch v := v.(*RuntimeUpdateStatusResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2641,7 +2766,7 @@ func file_v1alpha1_runtime_proto_init() {
 			}
 		}
 		file_v1alpha1_runtime_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RuntimeDestroyRequest); i {
+			switch v := v.(*RuntimeTeardownRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2653,7 +2778,7 @@ func file_v1alpha1_runtime_proto_init() {
 			}
 		}
 		file_v1alpha1_runtime_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RuntimeDestroyResponse); i {
+			switch v := v.(*RuntimeTeardownResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2665,7 +2790,7 @@ func file_v1alpha1_runtime_proto_init() {
 			}
 		}
 		file_v1alpha1_runtime_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RuntimeAddFinalizerRequest); i {
+			switch v := v.(*RuntimeDestroyRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2677,7 +2802,7 @@ func file_v1alpha1_runtime_proto_init() {
 			}
 		}
 		file_v1alpha1_runtime_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RuntimeAddFinalizerResponse); i {
+			switch v := v.(*RuntimeDestroyResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2689,7 +2814,7 @@ func file_v1alpha1_runtime_proto_init() {
 			}
 		}
 		file_v1alpha1_runtime_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RuntimeRemoveFinalizerRequest); i {
+			switch v := v.(*RuntimeAddFinalizerRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2701,6 +2826,30 @@ func file_v1alpha1_runtime_proto_init() {
 			}
 		}
 		file_v1alpha1_runtime_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RuntimeAddFinalizerResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1alpha1_runtime_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RuntimeRemoveFinalizerRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1alpha1_runtime_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RuntimeRemoveFinalizerResponse); i {
 			case 0:
 				return &v.state
@@ -2720,7 +2869,7 @@ func file_v1alpha1_runtime_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_v1alpha1_runtime_proto_rawDesc,
 			NumEnums:      2,
-			NumMessages:   34,
+			NumMessages:   36,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
@@ -242,6 +242,13 @@ type ControllerAdapterClient interface {
 	// Resource should be an output of the controller, for shared outputs
 	// resource should be owned by the controller.
 	Update(ctx context.Context, in *RuntimeUpdateRequest, opts ...grpc.CallOption) (*RuntimeUpdateResponse, error)
+	// UpdateStatus updates the status of a resource.
+	//
+	// Up-to-date current version should be specified for the update to succeed.
+	//
+	// Resource should be an input or an output of the controller,
+	// the controller claims the ownership of the resource status.
+	UpdateStatus(ctx context.Context, in *RuntimeUpdateStatusRequest, opts ...grpc.CallOption) (*RuntimeUpdateStatusResponse, error)
 	// Teardown marks a resource as going through the teardown phase.
 	//
 	// Teardown phase notifies other controllers using the resource as a strong input
@@ -394,6 +401,15 @@ func (c *controllerAdapterClient) Update(ctx context.Context, in *RuntimeUpdateR
 	return out, nil
 }
 
+func (c *controllerAdapterClient) UpdateStatus(ctx context.Context, in *RuntimeUpdateStatusRequest, opts ...grpc.CallOption) (*RuntimeUpdateStatusResponse, error) {
+	out := new(RuntimeUpdateStatusResponse)
+	err := c.cc.Invoke(ctx, "/cosi.runtime.ControllerAdapter/UpdateStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *controllerAdapterClient) Teardown(ctx context.Context, in *RuntimeTeardownRequest, opts ...grpc.CallOption) (*RuntimeTeardownResponse, error) {
 	out := new(RuntimeTeardownResponse)
 	err := c.cc.Invoke(ctx, "/cosi.runtime.ControllerAdapter/Teardown", in, out, opts...)
@@ -469,6 +485,13 @@ type ControllerAdapterServer interface {
 	// Resource should be an output of the controller, for shared outputs
 	// resource should be owned by the controller.
 	Update(context.Context, *RuntimeUpdateRequest) (*RuntimeUpdateResponse, error)
+	// UpdateStatus updates the status of a resource.
+	//
+	// Up-to-date current version should be specified for the update to succeed.
+	//
+	// Resource should be an input or an output of the controller,
+	// the controller claims the ownership of the resource status.
+	UpdateStatus(context.Context, *RuntimeUpdateStatusRequest) (*RuntimeUpdateStatusResponse, error)
 	// Teardown marks a resource as going through the teardown phase.
 	//
 	// Teardown phase notifies other controllers using the resource as a strong input
@@ -524,6 +547,9 @@ func (UnimplementedControllerAdapterServer) Create(context.Context, *RuntimeCrea
 func (UnimplementedControllerAdapterServer) Update(context.Context, *RuntimeUpdateRequest) (*RuntimeUpdateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
 }
+func (UnimplementedControllerAdapterServer) UpdateStatus(context.Context, *RuntimeUpdateStatusRequest) (*RuntimeUpdateStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateStatus not implemented")
+}
 func (UnimplementedControllerAdapterServer) Teardown(context.Context, *RuntimeTeardownRequest) (*RuntimeTeardownResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Teardown not implemented")
 }
@@ -699,6 +725,24 @@ func _ControllerAdapter_Update_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ControllerAdapter_UpdateStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RuntimeUpdateStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerAdapterServer).UpdateStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cosi.runtime.ControllerAdapter/UpdateStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerAdapterServer).UpdateStatus(ctx, req.(*RuntimeUpdateStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ControllerAdapter_Teardown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RuntimeTeardownRequest)
 	if err := dec(in); err != nil {
@@ -802,6 +846,10 @@ var ControllerAdapter_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Update",
 			Handler:    _ControllerAdapter_Update_Handler,
 		},
+		{
+			MethodName: "UpdateStatus",
+			Handler:    _ControllerAdapter_UpdateStatus_Handler,
+		},
 		{
 			MethodName: "Teardown",
 			Handler:    _ControllerAdapter_Teardown_Handler,
@@ -39,6 +39,11 @@ type StateClient interface {
 	// On update current version of resource `new` in the state should match
 	// curVersion, otherwise conflict error is returned.
 	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
+	// Update the status of a resource.
+	//
+	// Only the status of the resource is replaced, the spec and the generation are not changed.
+	// Status has its own owner, which is claimed by the first status update.
+	UpdateStatus(ctx context.Context, in *UpdateStatusRequest, opts ...grpc.CallOption) (*UpdateStatusResponse, error)
 	// Destroy a resource.
 	//
 	// If a resource doesn't exist, error is returned.
@@ -51,6 +56,25 @@ type StateClient interface {
 	// Watch sends initial resource state as the very first event on the channel,
 	// and then sends any updates to the resource as events.
 	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (State_WatchClient, error)
+	// List the resource definitions registered in the state.
+	//
+	// Resource definitions describe the available resource types, so that generic clients can discover them.
+	ListResourceDefinitions(ctx context.Context, in *ListResourceDefinitionsRequest, opts ...grpc.CallOption) (*ListResourceDefinitionsResponse, error)
+	// Create multiple resources in a single call.
+	//
+	// The batch is applied atomically if the state supports it, otherwise the requests
+	// are applied in order stopping on the first error.
+	BatchCreate(ctx context.Context, in *BatchCreateRequest, opts ...grpc.CallOption) (*BatchCreateResponse, error)
+	// Update multiple resources in a single call.
+	//
+	// The batch is applied atomically if the state supports it, otherwise the requests
+	// are applied in order stopping on the first error.
+	BatchUpdate(ctx context.Context, in *BatchUpdateRequest, opts ...grpc.CallOption) (*BatchUpdateResponse, error)
+	// Destroy multiple resources in a single call.
+	//
+	// The batch is applied atomically if the state supports it, otherwise the requests
+	// are applied in order stopping on the first error.
+	BatchDestroy(ctx context.Context, in *BatchDestroyRequest, opts ...grpc.CallOption) (*BatchDestroyResponse, error)
 }
 
 type stateClient struct {
@@ -120,6 +144,15 @@ func (c *stateClient) Update(ctx context.Context, in *UpdateRequest, opts ...grp
 	return out, nil
 }
 
+func (c *stateClient) UpdateStatus(ctx context.Context, in *UpdateStatusRequest, opts ...grpc.CallOption) (*UpdateStatusResponse, error) {
+	out := new(UpdateStatusResponse)
+	err := c.cc.Invoke(ctx, "/cosi.resource.State/UpdateStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *stateClient) Destroy(ctx context.Context, in *DestroyRequest, opts ...grpc.CallOption) (*DestroyResponse, error) {
 	out := new(DestroyResponse)
 	err := c.cc.Invoke(ctx, "/cosi.resource.State/Destroy", in, out, opts...)
@@ -161,6 +194,42 @@ func (x *stateWatchClient) Recv() (*WatchResponse, error) {
 	return m, nil
 }
 
+func (c *stateClient) ListResourceDefinitions(ctx context.Context, in *ListResourceDefinitionsRequest, opts ...grpc.CallOption) (*ListResourceDefinitionsResponse, error) {
+	out := new(ListResourceDefinitionsResponse)
+	err := c.cc.Invoke(ctx, "/cosi.resource.State/ListResourceDefinitions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stateClient) BatchCreate(ctx context.Context, in *BatchCreateRequest, opts ...grpc.CallOption) (*BatchCreateResponse, error) {
+	out := new(BatchCreateResponse)
+	err := c.cc.Invoke(ctx, "/cosi.resource.State/BatchCreate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stateClient) BatchUpdate(ctx context.Context, in *BatchUpdateRequest, opts ...grpc.CallOption) (*BatchUpdateResponse, error) {
+	out := new(BatchUpdateResponse)
+	err := c.cc.Invoke(ctx, "/cosi.resource.State/BatchUpdate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stateClient) BatchDestroy(ctx context.Context, in *BatchDestroyRequest, opts ...grpc.CallOption) (*BatchDestroyResponse, error) {
+	out := new(BatchDestroyResponse)
+	err := c.cc.Invoke(ctx, "/cosi.resource.State/BatchDestroy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StateServer is the server API for State service.
 // All implementations must embed UnimplementedStateServer
 // for forward compatibility
@@ -181,6 +250,11 @@ type StateServer interface {
 	// On update current version of resource `new` in the state should match
 	// curVersion, otherwise conflict error is returned.
 	Update(context.Context, *UpdateRequest) (*UpdateResponse, error)
+	// Update the status of a resource.
+	//
+	// Only the status of the resource is replaced, the spec and the generation are not changed.
+	// Status has its own owner, which is claimed by the first status update.
+	UpdateStatus(context.Context, *UpdateStatusRequest) (*UpdateStatusResponse, error)
 	// Destroy a resource.
 	//
 	// If a resource doesn't exist, error is returned.
@@ -193,6 +267,25 @@ type StateServer interface {
 	// Watch sends initial resource state as the very first event on the channel,
 	// and then sends any updates to the resource as events.
 	Watch(*WatchRequest, State_WatchServer) error
+	// List the resource definitions registered in the state.
+	//
+	// Resource definitions describe the available resource types, so that generic clients can discover them.
+	ListResourceDefinitions(context.Context, *ListResourceDefinitionsRequest) (*ListResourceDefinitionsResponse, error)
+	// Create multiple resources in a single call.
+	//
+	// The batch is applied atomically if the state supports it, otherwise the requests
+	// are applied in order stopping on the first error.
+	BatchCreate(context.Context, *BatchCreateRequest) (*BatchCreateResponse, error)
+	// Update multiple resources in a single call.
+	//
+	// The batch is applied atomically if the state supports it, otherwise the requests
+	// are applied in order stopping on the first error.
+	BatchUpdate(context.Context, *BatchUpdateRequest) (*BatchUpdateResponse, error)
+	// Destroy multiple resources in a single call.
+	//
+	// The batch is applied atomically if the state supports it, otherwise the requests
+	// are applied in order stopping on the first error.
+	BatchDestroy(context.Context, *BatchDestroyRequest) (*BatchDestroyResponse, error)
 	mustEmbedUnimplementedStateServer()
 }
 
@@ -212,12 +305,27 @@ func (UnimplementedStateServer) Create(context.Context, *CreateRequest) (*Create
 func (UnimplementedStateServer) Update(context.Context, *UpdateRequest) (*UpdateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
 }
+func (UnimplementedStateServer) UpdateStatus(context.Context, *UpdateStatusRequest) (*UpdateStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateStatus not implemented")
+}
 func (UnimplementedStateServer) Destroy(context.Context, *DestroyRequest) (*DestroyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Destroy not implemented")
 }
 func (UnimplementedStateServer) Watch(*WatchRequest, State_WatchServer) error {
 	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
 }
+func (UnimplementedStateServer) ListResourceDefinitions(context.Context, *ListResourceDefinitionsRequest) (*ListResourceDefinitionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListResourceDefinitions not implemented")
+}
+func (UnimplementedStateServer) BatchCreate(context.Context, *BatchCreateRequest) (*BatchCreateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCreate not implemented")
+}
+func (UnimplementedStateServer) BatchUpdate(context.Context, *BatchUpdateRequest) (*BatchUpdateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchUpdate not implemented")
+}
+func (UnimplementedStateServer) BatchDestroy(context.Context, *BatchDestroyRequest) (*BatchDestroyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDestroy not implemented")
+}
 func (UnimplementedStateServer) mustEmbedUnimplementedStateServer() {}
 
 // UnsafeStateServer may be embedded to opt out of forward compatibility for this service.
@@ -306,6 +414,24 @@ func _State_Update_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _State_UpdateStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateServer).UpdateStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cosi.resource.State/UpdateStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateServer).UpdateStatus(ctx, req.(*UpdateStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _State_Destroy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DestroyRequest)
 	if err := dec(in); err != nil {
@@ -345,6 +471,78 @@ func (x *stateWatchServer) Send(m *WatchResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _State_ListResourceDefinitions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListResourceDefinitionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateServer).ListResourceDefinitions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cosi.resource.State/ListResourceDefinitions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateServer).ListResourceDefinitions(ctx, req.(*ListResourceDefinitionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _State_BatchCreate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateServer).BatchCreate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cosi.resource.State/BatchCreate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateServer).BatchCreate(ctx, req.(*BatchCreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _State_BatchUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateServer).BatchUpdate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cosi.resource.State/BatchUpdate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateServer).BatchUpdate(ctx, req.(*BatchUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _State_BatchDestroy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDestroyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateServer).BatchDestroy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cosi.resource.State/BatchDestroy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateServer).BatchDestroy(ctx, req.(*BatchDestroyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // State_ServiceDesc is the grpc.ServiceDesc for State service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -364,10 +562,30 @@ var State_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Update",
 			Handler:    _State_Update_Handler,
 		},
+		{
+			MethodName: "UpdateStatus",
+			Handler:    _State_UpdateStatus_Handler,
+		},
 		{
 			MethodName: "Destroy",
 			Handler:    _State_Destroy_Handler,
 		},
+		{
+			MethodName: "ListResourceDefinitions",
+			Handler:    _State_ListResourceDefinitions_Handler,
+		},
+		{
+			MethodName: "BatchCreate",
+			Handler:    _State_BatchCreate_Handler,
+		},
+		{
+			MethodName: "BatchUpdate",
+			Handler:    _State_BatchUpdate_Handler,
+		},
+		{
+			MethodName: "BatchDestroy",
+			Handler:    _State_BatchDestroy_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -0,0 +1,74 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package clock provides the time source which can be replaced with a fake one in tests.
+package clock
+
+import "time"
+
+// Clock is the source of the current time and the timers.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	Until(t time.Time) time.Duration
+
+	After(d time.Duration) <-chan time.Time
+	AfterFunc(d time.Duration, f func()) Timer
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is the timer created by the Clock, see time.Timer.
+type Timer interface {
+	// C returns the channel the time is delivered on, it's nil for the timers created by AfterFunc.
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker is the ticker created by the Clock, see time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Real returns the clock backed by the package time.
+func Real() Clock { //nolint:ireturn
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) Until(t time.Time) time.Duration        { return time.Until(t) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer { //nolint:ireturn
+	return realTimer{time.AfterFunc(d, f)}
+}
+
+func (realClock) NewTimer(d time.Duration) Timer { //nolint:ireturn
+	return realTimer{time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker { //nolint:ireturn
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (timer realTimer) C() <-chan time.Time {
+	return timer.Timer.C
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (ticker realTicker) C() <-chan time.Time {
+	return ticker.Ticker.C
+}
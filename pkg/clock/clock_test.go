@@ -0,0 +1,62 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package clock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/clock"
+)
+
+func TestFake(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+
+	timer := fake.NewTimer(time.Minute)
+	ticker := fake.NewTicker(20 * time.Second)
+	stopped := fake.NewTimer(time.Second)
+
+	fired := make(chan struct{})
+	fake.AfterFunc(30*time.Second, func() { close(fired) })
+
+	assert.Equal(t, 4, fake.Waiters())
+	assert.True(t, stopped.Stop())
+	assert.False(t, stopped.Stop())
+
+	fake.Advance(30 * time.Second)
+
+	assert.Equal(t, start.Add(30*time.Second), fake.Now())
+	assert.Equal(t, start.Add(20*time.Second), <-ticker.C())
+	<-fired
+
+	select {
+	case <-timer.C():
+		t.Fatal("timer fired too early")
+	default:
+	}
+
+	fake.Advance(30 * time.Second)
+
+	assert.Equal(t, start.Add(time.Minute), <-timer.C())
+	assert.Equal(t, start.Add(40*time.Second), <-ticker.C(), "the tick which wasn't received is dropped")
+	assert.Equal(t, time.Minute, fake.Since(start))
+
+	ticker.Stop()
+	assert.Equal(t, 0, fake.Waiters())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	go fake.After(time.Hour)
+
+	require.NoError(t, fake.BlockUntil(ctx, 1))
+}
@@ -0,0 +1,231 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package clock
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is the clock which moves only when advanced by the test.
+//
+// Timers and tickers fire synchronously from Advance, the functions of AfterFunc are run in new goroutines.
+type Fake struct {
+	now     time.Time
+	waiters []*fakeTimer
+	changed chan struct{}
+	mu      sync.Mutex
+}
+
+// NewFake creates the fake clock set to now.
+func NewFake(now time.Time) *Fake {
+	return &Fake{
+		now:     now,
+		changed: make(chan struct{}),
+	}
+}
+
+// Now implements Clock.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// Since implements Clock.
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// Until implements Clock.
+func (f *Fake) Until(t time.Time) time.Duration {
+	return t.Sub(f.Now())
+}
+
+// After implements Clock.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+// AfterFunc implements Clock.
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer { //nolint:ireturn
+	return f.add(&fakeTimer{clock: f, fn: fn}, d)
+}
+
+// NewTimer implements Clock.
+func (f *Fake) NewTimer(d time.Duration) Timer { //nolint:ireturn
+	return f.add(&fakeTimer{clock: f, ch: make(chan time.Time, 1)}, d)
+}
+
+// NewTicker implements Clock.
+func (f *Fake) NewTicker(d time.Duration) Ticker { //nolint:ireturn
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+
+	return fakeTicker{f.add(&fakeTimer{clock: f, ch: make(chan time.Time, 1), period: d}, d)}
+}
+
+// Advance moves the clock forward firing the timers which are due.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	target := f.now.Add(d)
+	f.mu.Unlock()
+
+	for {
+		f.mu.Lock()
+
+		if len(f.waiters) == 0 || f.waiters[0].deadline.After(target) {
+			f.now = target
+			f.mu.Unlock()
+
+			return
+		}
+
+		timer := f.waiters[0]
+		f.waiters = f.waiters[1:]
+
+		if timer.deadline.After(f.now) {
+			f.now = timer.deadline
+		}
+
+		now := f.now
+
+		if timer.period > 0 {
+			timer.deadline = timer.deadline.Add(timer.period)
+			f.insert(timer)
+		}
+
+		f.mu.Unlock()
+
+		timer.fire(now)
+	}
+}
+
+// Waiters returns the number of the active timers and tickers.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.waiters)
+}
+
+// BlockUntil waits until the number of the active timers and tickers reaches n.
+//
+// BlockUntil should be used before Advance to make sure the code under test has armed its timers.
+func (f *Fake) BlockUntil(ctx context.Context, n int) error {
+	for {
+		f.mu.Lock()
+		count, changed := len(f.waiters), f.changed
+		f.mu.Unlock()
+
+		if count >= n {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+func (f *Fake) add(timer *fakeTimer, d time.Duration) *fakeTimer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	timer.deadline = f.now.Add(d)
+	f.insert(timer)
+
+	return timer
+}
+
+// insert should be called with mu held.
+func (f *Fake) insert(timer *fakeTimer) {
+	i := sort.Search(len(f.waiters), func(i int) bool {
+		return f.waiters[i].deadline.After(timer.deadline)
+	})
+
+	f.waiters = append(f.waiters, nil)
+	copy(f.waiters[i+1:], f.waiters[i:])
+	f.waiters[i] = timer
+
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+// remove should be called with mu held.
+func (f *Fake) remove(timer *fakeTimer) bool {
+	for i, t := range f.waiters {
+		if t == timer {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+type fakeTimer struct {
+	deadline time.Time
+	clock    *Fake
+	ch       chan time.Time
+	fn       func()
+	period   time.Duration
+}
+
+func (timer *fakeTimer) fire(now time.Time) {
+	if timer.fn != nil {
+		go timer.fn()
+
+		return
+	}
+
+	// same as the real timers, the tick is dropped if the previous one wasn't received
+	select {
+	case timer.ch <- now:
+	default:
+	}
+}
+
+func (timer *fakeTimer) C() <-chan time.Time {
+	return timer.ch
+}
+
+func (timer *fakeTimer) Stop() bool {
+	timer.clock.mu.Lock()
+	defer timer.clock.mu.Unlock()
+
+	return timer.clock.remove(timer)
+}
+
+func (timer *fakeTimer) Reset(d time.Duration) bool {
+	timer.clock.mu.Lock()
+	defer timer.clock.mu.Unlock()
+
+	active := timer.clock.remove(timer)
+
+	timer.deadline = timer.clock.now.Add(d)
+	timer.clock.insert(timer)
+
+	return active
+}
+
+type fakeTicker struct {
+	timer *fakeTimer
+}
+
+func (ticker fakeTicker) C() <-chan time.Time {
+	return ticker.timer.ch
+}
+
+func (ticker fakeTicker) Stop() {
+	ticker.timer.Stop()
+}
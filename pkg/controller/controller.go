@@ -7,6 +7,7 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -20,6 +21,74 @@ type Controller interface {
 	Run(context.Context, Runtime, *zap.Logger) error
 }
 
+// PeriodicController can be implemented by controllers which should be reconciled periodically
+// in addition to the reconciles triggered by the input changes.
+type PeriodicController interface {
+	Controller
+
+	// ReconcileSchedule returns the schedule of periodic reconciles, nil disables periodic reconciles.
+	ReconcileSchedule() Schedule
+}
+
+// Schedule returns the time of the next periodic reconcile after the given time.
+//
+// Zero time stops periodic reconciles. Every provides a fixed interval schedule,
+// other schedules can be implemented by the controller.
+type Schedule interface {
+	Next(time.Time) time.Time
+}
+
+// Every returns a Schedule which reconciles every interval.
+func Every(interval time.Duration) Schedule { //nolint:ireturn
+	return every(interval)
+}
+
+type every time.Duration
+
+func (interval every) Next(t time.Time) time.Time {
+	if interval <= 0 {
+		return time.Time{}
+	}
+
+	return t.Add(time.Duration(interval))
+}
+
+// ExternalTrigger is a source of reconcile events outside of the state, e.g. a webhook receiver or a message queue consumer.
+type ExternalTrigger interface {
+	// Run the trigger until the context is canceled, calling queueReconcile to wake up the controller.
+	//
+	// If Run returns an error, it is restarted with a backoff.
+	Run(ctx context.Context, queueReconcile func()) error
+}
+
+// ExternalTriggerFunc is an adapter to allow the use of ordinary functions as external triggers.
+type ExternalTriggerFunc func(ctx context.Context, queueReconcile func()) error
+
+// Run implements ExternalTrigger interface.
+func (f ExternalTriggerFunc) Run(ctx context.Context, queueReconcile func()) error {
+	return f(ctx, queueReconcile)
+}
+
+// ExternalTriggerController can be implemented by controllers which are woken up by external triggers.
+//
+// Reconciles requested by external triggers are coalesced with the reconciles triggered by the inputs.
+type ExternalTriggerController interface {
+	Controller
+
+	ExternalTriggers() []ExternalTrigger
+}
+
+// DependentController can be implemented by controllers which should be started only after other controllers are bootstrapped.
+//
+// The controller is bootstrapped once it has processed its initial reconcile and waits for the next reconcile event.
+// Start order should not form cycles.
+type DependentController interface {
+	Controller
+
+	// StartAfter returns the names of the controllers which should be bootstrapped before this controller starts.
+	StartAfter() []string
+}
+
 // Engine is the entrypoint into Controller Runtime.
 type Engine interface {
 	// RegisterController registers new controller.
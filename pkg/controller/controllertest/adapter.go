@@ -0,0 +1,158 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package controllertest
+
+import (
+	"context"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// adapter implements controller.Runtime for the controller in the harness.
+type adapter struct {
+	h *Harness
+}
+
+// EventCh implements controller.Runtime interface.
+func (adapter *adapter) EventCh() <-chan controller.ReconcileEvent {
+	ch := make(chan controller.ReconcileEvent, 1)
+
+	// the controller is waiting for the next event, hand the channel over to Reconcile
+	select {
+	case adapter.h.waiting <- ch:
+	case <-adapter.h.ctx.Done():
+	}
+
+	return ch
+}
+
+// QueueReconcile implements controller.Runtime interface.
+func (adapter *adapter) QueueReconcile() {
+	adapter.h.mu.Lock()
+	defer adapter.h.mu.Unlock()
+
+	adapter.h.queued++
+}
+
+// UpdateInputs implements controller.Runtime interface.
+func (adapter *adapter) UpdateInputs(inputs []controller.Input) error {
+	adapter.h.mu.Lock()
+	defer adapter.h.mu.Unlock()
+
+	adapter.h.inputs = append([]controller.Input(nil), inputs...)
+
+	return nil
+}
+
+// Get implements controller.Runtime interface.
+func (adapter *adapter) Get(ctx context.Context, ptr resource.Pointer) (resource.Resource, error) { //nolint:ireturn
+	return adapter.h.State.Get(ctx, ptr)
+}
+
+// List implements controller.Runtime interface.
+func (adapter *adapter) List(ctx context.Context, kind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	return adapter.h.State.List(ctx, kind, opts...)
+}
+
+// WatchFor implements controller.Runtime interface.
+func (adapter *adapter) WatchFor(ctx context.Context, ptr resource.Pointer, conditions ...state.WatchForConditionFunc) (resource.Resource, error) { //nolint:ireturn
+	return adapter.h.State.WatchFor(ctx, ptr, conditions...)
+}
+
+// Create implements controller.Runtime interface.
+func (adapter *adapter) Create(ctx context.Context, r resource.Resource) error {
+	owner, err := adapter.h.owner(r.Metadata().Type())
+	if err != nil {
+		return err
+	}
+
+	return adapter.h.State.Create(ctx, r, state.WithCreateOwner(owner))
+}
+
+// Update implements controller.Runtime interface.
+func (adapter *adapter) Update(ctx context.Context, curVersion resource.Version, newResource resource.Resource) error {
+	owner, err := adapter.h.owner(newResource.Metadata().Type())
+	if err != nil {
+		return err
+	}
+
+	return adapter.h.State.Update(ctx, curVersion, newResource, state.WithUpdateOwner(owner))
+}
+
+// UpdateStatus implements controller.StatusWriter interface.
+func (adapter *adapter) UpdateStatus(ctx context.Context, curVersion resource.Version, newResource resource.StatusResource) error {
+	return state.UpdateStatus(ctx, adapter.h.State, curVersion, newResource, state.WithUpdateOwner(adapter.h.ctrl.Name()))
+}
+
+// Modify implements controller.Runtime interface.
+func (adapter *adapter) Modify(ctx context.Context, emptyResource resource.Resource, updateFunc func(resource.Resource) error) error {
+	_, err := adapter.ModifyWithResult(ctx, emptyResource, updateFunc)
+
+	return err
+}
+
+// ModifyWithResult implements controller.ResultWriter interface.
+func (adapter *adapter) ModifyWithResult(ctx context.Context, emptyResource resource.Resource, updateFunc func(resource.Resource) error) (resource.Resource, error) { //nolint:ireturn
+	owner, err := adapter.h.owner(emptyResource.Metadata().Type())
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = adapter.h.State.Get(ctx, emptyResource.Metadata())
+	if state.IsNotFoundError(err) {
+		if err = updateFunc(emptyResource); err != nil {
+			return nil, err
+		}
+
+		if err = adapter.h.State.Create(ctx, emptyResource, state.WithCreateOwner(owner)); err != nil {
+			return nil, err
+		}
+
+		return adapter.h.State.Get(ctx, emptyResource.Metadata())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return adapter.h.State.UpdateWithConflicts(ctx, emptyResource.Metadata(), updateFunc, state.WithUpdateOwner(owner))
+}
+
+// Teardown implements controller.Runtime interface.
+func (adapter *adapter) Teardown(ctx context.Context, ptr resource.Pointer) (bool, error) {
+	owner, err := adapter.h.owner(ptr.Type())
+	if err != nil {
+		return false, err
+	}
+
+	return adapter.h.State.Teardown(ctx, ptr, state.WithTeardownOwner(owner))
+}
+
+// Destroy implements controller.Runtime interface.
+func (adapter *adapter) Destroy(ctx context.Context, ptr resource.Pointer) error {
+	owner, err := adapter.h.owner(ptr.Type())
+	if err != nil {
+		return err
+	}
+
+	return adapter.h.State.Destroy(ctx, ptr, state.WithDestroyOwner(owner))
+}
+
+// AddFinalizer implements controller.Runtime interface.
+func (adapter *adapter) AddFinalizer(ctx context.Context, ptr resource.Pointer, fins ...resource.Finalizer) error {
+	return adapter.h.State.AddFinalizer(ctx, ptr, fins...)
+}
+
+// RemoveFinalizer implements controller.Runtime interface.
+func (adapter *adapter) RemoveFinalizer(ctx context.Context, ptr resource.Pointer, fins ...resource.Finalizer) error {
+	err := adapter.h.State.RemoveFinalizer(ctx, ptr, fins...)
+	if state.IsNotFoundError(err) {
+		err = nil
+	}
+
+	return err
+}
@@ -0,0 +1,187 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package controllertest provides a harness to unit test a single controller without the controller runtime.
+package controllertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+// Harness runs a single controller against the in-memory state.
+//
+// Unlike the controller runtime, the harness doesn't watch the inputs: reconciles are triggered
+// with Reconcile, which returns once the reconcile is complete. The controller is considered to be done
+// with the reconcile when it calls EventCh again, which is what the controller loops normally do.
+//
+// Writes are restricted to the controller outputs and are done on behalf of the controller,
+// merge strategies of the merged outputs are not checked.
+type Harness struct {
+	// State is the state the controller runs against, it can be used to seed and to inspect the resources.
+	State state.State
+
+	t      testing.TB
+	ctrl   controller.Controller
+	logger *zap.Logger
+
+	ctx     context.Context //nolint:containedctx
+	waiting chan chan controller.ReconcileEvent
+	pending chan controller.ReconcileEvent
+	done    chan struct{}
+	err     error
+
+	mu      sync.Mutex
+	inputs  []controller.Input
+	outputs map[resource.Type]controller.Output
+	queued  int
+}
+
+// New starts the controller in the harness, the controller is stopped when the test finishes.
+func New(t testing.TB, ctrl controller.Controller) *Harness {
+	t.Helper()
+
+	h := &Harness{
+		State:   state.WrapCore(namespaced.NewState(inmem.Build)),
+		t:       t,
+		ctrl:    ctrl,
+		logger:  zaptest.NewLogger(t),
+		waiting: make(chan chan controller.ReconcileEvent),
+		done:    make(chan struct{}),
+		inputs:  append([]controller.Input(nil), ctrl.Inputs()...),
+		outputs: map[resource.Type]controller.Output{},
+	}
+
+	for _, output := range ctrl.Outputs() {
+		h.outputs[output.Type] = output
+	}
+
+	var cancel context.CancelFunc
+
+	h.ctx, cancel = context.WithCancel(context.Background())
+
+	go func() {
+		defer close(h.done)
+
+		h.err = ctrl.Run(h.ctx, &adapter{h}, h.logger)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+
+		<-h.done
+
+		if h.err != nil && !errors.Is(h.err, context.Canceled) {
+			t.Errorf("controller %q failed: %s", ctrl.Name(), h.err)
+		}
+	})
+
+	return h
+}
+
+// Seed creates the resources in the state, e.g. the inputs of the controller.
+func (h *Harness) Seed(resources ...resource.Resource) {
+	h.t.Helper()
+
+	for _, r := range resources {
+		if err := h.State.Create(context.Background(), r); err != nil {
+			h.t.Fatalf("error seeding resource %s: %s", r.Metadata(), err)
+		}
+	}
+}
+
+// Reconcile triggers a reconcile, and waits for it to complete.
+//
+// If the controller returns instead of completing the reconcile, the test fails.
+func (h *Harness) Reconcile() {
+	h.t.Helper()
+
+	ch := h.pending
+
+	if ch == nil {
+		select {
+		case ch = <-h.waiting:
+		case <-h.done:
+			h.t.Fatalf("controller %q stopped: %v", h.ctrl.Name(), h.err)
+		}
+	}
+
+	ch <- controller.ReconcileEvent{}
+
+	// the reconcile is complete once the controller waits for the next event
+	select {
+	case h.pending = <-h.waiting:
+	case <-h.done:
+		h.t.Fatalf("controller %q stopped during reconcile: %v", h.ctrl.Name(), h.err)
+	}
+}
+
+// Get returns the resource from the state, the test fails if the resource doesn't exist.
+func (h *Harness) Get(ptr resource.Pointer) resource.Resource { //nolint:ireturn
+	h.t.Helper()
+
+	r, err := h.State.Get(context.Background(), ptr)
+	if err != nil {
+		h.t.Fatalf("error getting resource %s: %s", ptr, err)
+	}
+
+	return r
+}
+
+// AssertMissing fails the test if the resource exists.
+func (h *Harness) AssertMissing(ptr resource.Pointer) {
+	h.t.Helper()
+
+	_, err := h.State.Get(context.Background(), ptr)
+
+	switch {
+	case err == nil:
+		h.t.Errorf("resource %s exists", ptr)
+	case !state.IsNotFoundError(err):
+		h.t.Errorf("error getting resource %s: %s", ptr, err)
+	}
+}
+
+// Inputs returns the current inputs of the controller, including the ones set with UpdateInputs.
+func (h *Harness) Inputs() []controller.Input {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]controller.Input(nil), h.inputs...)
+}
+
+// QueuedReconciles returns how many times the controller called QueueReconcile.
+//
+// Queued reconciles are not run automatically, use Reconcile.
+func (h *Harness) QueuedReconciles() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.queued
+}
+
+func (h *Harness) owner(resourceType resource.Type) (string, error) {
+	output, ok := h.outputs[resourceType]
+	if !ok {
+		return "", fmt.Errorf("resource type %q is not an output for controller %q", resourceType, h.ctrl.Name())
+	}
+
+	if output.Kind == controller.OutputMerged {
+		return controller.MergedOwner(resourceType), nil
+	}
+
+	return h.ctrl.Name(), nil
+}
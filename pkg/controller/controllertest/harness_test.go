@@ -0,0 +1,48 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package controllertest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/controller/controllertest"
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+func TestHarness(t *testing.T) {
+	t.Parallel()
+
+	h := controllertest.New(t, &conformance.IntToStrController{
+		SourceNamespace: "default",
+		TargetNamespace: "default",
+	})
+
+	one := conformance.NewIntResource("default", "one", 1)
+
+	h.Seed(one)
+	h.Reconcile()
+
+	str := conformance.NewStrResource("default", "one", "")
+
+	assert.Equal(t, "1", h.Get(str.Metadata()).(*conformance.StrResource).Value()) //nolint:forcetypeassert,errcheck
+	assert.Equal(t, "IntToStrController", h.Get(str.Metadata()).Metadata().Owner())
+
+	// the controller put the finalizer on the input
+	assert.Equal(t, resource.Finalizers{resource.String(str)}, *h.Get(one.Metadata()).Metadata().Finalizers())
+
+	ok, err := h.State.Teardown(context.Background(), one.Metadata())
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	h.Reconcile()
+
+	h.AssertMissing(str.Metadata())
+	assert.True(t, h.Get(one.Metadata()).Metadata().Finalizers().Empty())
+}
@@ -0,0 +1,285 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package controllertest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+)
+
+// Call is a recorded call of the MockRuntime.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// MockRuntime is a mock implementation of controller.Runtime, it can be used as controller.Reader or controller.Writer as well.
+//
+// MockRuntime is intended for the unit tests of the functions which accept the controller runtime,
+// while Harness is better suited for running the whole controller.
+//
+// Each method calls the corresponding function, if set. Otherwise the methods behave as if the state was empty:
+// Get and WatchFor return a not found error, List returns an empty list, the writes succeed,
+// Modify applies the modification to a copy of the passed resource, and Teardown reports the resource as ready to be destroyed.
+// All calls are recorded.
+type MockRuntime struct { //nolint:govet
+	UpdateInputsFunc func(inputs []controller.Input) error
+
+	GetFunc      func(ctx context.Context, ptr resource.Pointer) (resource.Resource, error)
+	ListFunc     func(ctx context.Context, kind resource.Kind, opts ...state.ListOption) (resource.List, error)
+	WatchForFunc func(ctx context.Context, ptr resource.Pointer, conditions ...state.WatchForConditionFunc) (resource.Resource, error)
+
+	CreateFunc       func(ctx context.Context, r resource.Resource) error
+	UpdateFunc       func(ctx context.Context, curVersion resource.Version, r resource.Resource) error
+	UpdateStatusFunc func(ctx context.Context, curVersion resource.Version, r resource.StatusResource) error
+	// ModifyWithResultFunc is called both for Modify and ModifyWithResult.
+	ModifyWithResultFunc func(ctx context.Context, emptyResource resource.Resource, updateFunc func(resource.Resource) error) (resource.Resource, error)
+	TeardownFunc         func(ctx context.Context, ptr resource.Pointer) (bool, error)
+	DestroyFunc          func(ctx context.Context, ptr resource.Pointer) error
+
+	AddFinalizerFunc    func(ctx context.Context, ptr resource.Pointer, fins ...resource.Finalizer) error
+	RemoveFinalizerFunc func(ctx context.Context, ptr resource.Pointer, fins ...resource.Finalizer) error
+
+	mu     sync.Mutex
+	calls  []Call
+	events chan controller.ReconcileEvent
+}
+
+var (
+	_ controller.Runtime      = (*MockRuntime)(nil)
+	_ controller.StatusWriter = (*MockRuntime)(nil)
+	_ controller.ResultWriter = (*MockRuntime)(nil)
+)
+
+// Calls returns the recorded calls, optionally filtered by the method names.
+func (mock *MockRuntime) Calls(methods ...string) []Call {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	if len(methods) == 0 {
+		return append([]Call(nil), mock.calls...)
+	}
+
+	var calls []Call
+
+	for _, call := range mock.calls {
+		for _, method := range methods {
+			if call.Method == method {
+				calls = append(calls, call)
+
+				break
+			}
+		}
+	}
+
+	return calls
+}
+
+// Reset forgets the recorded calls.
+func (mock *MockRuntime) Reset() {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	mock.calls = nil
+}
+
+func (mock *MockRuntime) record(method string, args ...interface{}) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	mock.calls = append(mock.calls, Call{Method: method, Args: args})
+}
+
+func (mock *MockRuntime) eventCh() chan controller.ReconcileEvent {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	if mock.events == nil {
+		mock.events = make(chan controller.ReconcileEvent, 1)
+	}
+
+	return mock.events
+}
+
+// EventCh implements controller.Runtime interface.
+//
+// The reconcile events are delivered by QueueReconcile.
+func (mock *MockRuntime) EventCh() <-chan controller.ReconcileEvent {
+	return mock.eventCh()
+}
+
+// QueueReconcile implements controller.Runtime interface.
+func (mock *MockRuntime) QueueReconcile() {
+	mock.record("QueueReconcile")
+
+	select {
+	case mock.eventCh() <- controller.ReconcileEvent{}:
+	default:
+	}
+}
+
+// UpdateInputs implements controller.Runtime interface.
+func (mock *MockRuntime) UpdateInputs(inputs []controller.Input) error {
+	mock.record("UpdateInputs", inputs)
+
+	if mock.UpdateInputsFunc != nil {
+		return mock.UpdateInputsFunc(inputs)
+	}
+
+	return nil
+}
+
+// Get implements controller.Reader interface.
+func (mock *MockRuntime) Get(ctx context.Context, ptr resource.Pointer) (resource.Resource, error) { //nolint:ireturn
+	mock.record("Get", ptr)
+
+	if mock.GetFunc != nil {
+		return mock.GetFunc(ctx, ptr)
+	}
+
+	return nil, inmem.ErrNotFound(ptr)
+}
+
+// List implements controller.Reader interface.
+func (mock *MockRuntime) List(ctx context.Context, kind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	mock.record("List", kind)
+
+	if mock.ListFunc != nil {
+		return mock.ListFunc(ctx, kind, opts...)
+	}
+
+	return resource.List{}, nil
+}
+
+// WatchFor implements controller.Reader interface.
+func (mock *MockRuntime) WatchFor(ctx context.Context, ptr resource.Pointer, conditions ...state.WatchForConditionFunc) (resource.Resource, error) { //nolint:ireturn
+	mock.record("WatchFor", ptr)
+
+	if mock.WatchForFunc != nil {
+		return mock.WatchForFunc(ctx, ptr, conditions...)
+	}
+
+	return nil, inmem.ErrNotFound(ptr)
+}
+
+// Create implements controller.Writer interface.
+func (mock *MockRuntime) Create(ctx context.Context, r resource.Resource) error {
+	mock.record("Create", r)
+
+	if mock.CreateFunc != nil {
+		return mock.CreateFunc(ctx, r)
+	}
+
+	return nil
+}
+
+// Update implements controller.Writer interface.
+func (mock *MockRuntime) Update(ctx context.Context, curVersion resource.Version, r resource.Resource) error {
+	mock.record("Update", curVersion, r)
+
+	if mock.UpdateFunc != nil {
+		return mock.UpdateFunc(ctx, curVersion, r)
+	}
+
+	return nil
+}
+
+// UpdateStatus implements controller.StatusWriter interface.
+func (mock *MockRuntime) UpdateStatus(ctx context.Context, curVersion resource.Version, r resource.StatusResource) error {
+	mock.record("UpdateStatus", curVersion, r)
+
+	if mock.UpdateStatusFunc != nil {
+		return mock.UpdateStatusFunc(ctx, curVersion, r)
+	}
+
+	return nil
+}
+
+// Modify implements controller.Writer interface.
+//
+// The call is recorded with the resource after the modification as the last argument.
+func (mock *MockRuntime) Modify(ctx context.Context, emptyResource resource.Resource, updateFunc func(resource.Resource) error) error {
+	_, err := mock.modify(ctx, "Modify", emptyResource, updateFunc)
+
+	return err
+}
+
+// ModifyWithResult implements controller.ResultWriter interface.
+//
+// The call is recorded with the resource after the modification as the last argument.
+func (mock *MockRuntime) ModifyWithResult(ctx context.Context, emptyResource resource.Resource, updateFunc func(resource.Resource) error) (resource.Resource, error) { //nolint:ireturn
+	return mock.modify(ctx, "ModifyWithResult", emptyResource, updateFunc)
+}
+
+func (mock *MockRuntime) modify(ctx context.Context, method string, emptyResource resource.Resource, updateFunc func(resource.Resource) error) (resource.Resource, error) { //nolint:ireturn
+	var (
+		r   resource.Resource
+		err error
+	)
+
+	if mock.ModifyWithResultFunc != nil {
+		r, err = mock.ModifyWithResultFunc(ctx, emptyResource, updateFunc)
+	} else {
+		r = emptyResource.DeepCopy()
+		err = updateFunc(r)
+	}
+
+	if err != nil {
+		r = nil
+	}
+
+	mock.record(method, emptyResource, r)
+
+	return r, err
+}
+
+// Teardown implements controller.Writer interface.
+func (mock *MockRuntime) Teardown(ctx context.Context, ptr resource.Pointer) (bool, error) {
+	mock.record("Teardown", ptr)
+
+	if mock.TeardownFunc != nil {
+		return mock.TeardownFunc(ctx, ptr)
+	}
+
+	return true, nil
+}
+
+// Destroy implements controller.Writer interface.
+func (mock *MockRuntime) Destroy(ctx context.Context, ptr resource.Pointer) error {
+	mock.record("Destroy", ptr)
+
+	if mock.DestroyFunc != nil {
+		return mock.DestroyFunc(ctx, ptr)
+	}
+
+	return nil
+}
+
+// AddFinalizer implements controller.Writer interface.
+func (mock *MockRuntime) AddFinalizer(ctx context.Context, ptr resource.Pointer, fins ...resource.Finalizer) error {
+	mock.record("AddFinalizer", ptr, fins)
+
+	if mock.AddFinalizerFunc != nil {
+		return mock.AddFinalizerFunc(ctx, ptr, fins...)
+	}
+
+	return nil
+}
+
+// RemoveFinalizer implements controller.Writer interface.
+func (mock *MockRuntime) RemoveFinalizer(ctx context.Context, ptr resource.Pointer, fins ...resource.Finalizer) error {
+	mock.record("RemoveFinalizer", ptr, fins)
+
+	if mock.RemoveFinalizerFunc != nil {
+		return mock.RemoveFinalizerFunc(ctx, ptr, fins...)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package controllertest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/controller/controllertest"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+func TestMockRuntime(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	one := conformance.NewIntResource("default", "one", 1)
+
+	mock := &controllertest.MockRuntime{
+		ListFunc: func(context.Context, resource.Kind, ...state.ListOption) (resource.List, error) {
+			return resource.List{Items: []resource.Resource{one}}, nil
+		},
+	}
+
+	ctrl := &conformance.IntToStrController{
+		SourceNamespace: "default",
+		TargetNamespace: "default",
+	}
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- ctrl.Run(ctx, mock, zaptest.NewLogger(t))
+	}()
+
+	mock.QueueReconcile()
+
+	require.Eventually(t, func() bool {
+		return len(mock.Calls("Modify")) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	str := conformance.NewStrResource("default", "one", "")
+
+	finalizers := mock.Calls("AddFinalizer")
+	require.Len(t, finalizers, 1)
+	assert.Equal(t, []resource.Finalizer{resource.String(str)}, finalizers[0].Args[1])
+
+	modified := mock.Calls("Modify")[0].Args[1].(*conformance.StrResource) //nolint:forcetypeassert,errcheck
+	assert.Equal(t, "1", modified.Value())
+
+	cancel()
+	require.NoError(t, <-errCh)
+
+	// defaults behave as the empty state
+	_, err := mock.Get(context.Background(), str.Metadata())
+	assert.True(t, state.IsNotFoundError(err))
+
+	ready, err := mock.Teardown(context.Background(), str.Metadata())
+	require.NoError(t, err)
+	assert.True(t, ready)
+
+	// the injected errors are returned to the controller
+	mock.Reset()
+	mock.AddFinalizerFunc = func(context.Context, resource.Pointer, ...resource.Finalizer) error {
+		return errors.New("boom")
+	}
+
+	mock.QueueReconcile()
+
+	assert.ErrorContains(t, ctrl.Run(context.Background(), mock, zaptest.NewLogger(t)), "boom")
+	assert.Empty(t, mock.Calls("Modify"))
+}
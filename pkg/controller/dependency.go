@@ -21,6 +21,7 @@ const (
 	EdgeInputStrong
 	EdgeInputWeak
 	EdgeInputDestroyReady
+	EdgeOutputMerged
 )
 
 // DependencyEdge represents relationship between controller and resource(s).
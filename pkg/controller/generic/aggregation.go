@@ -0,0 +1,167 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generic
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// AggregationController folds all resources of type T matching the label query into a single summary resource S.
+//
+// Summary is recomputed only if the set of the matching resources or their versions changed since the last reconcile.
+type AggregationController[T, S resource.Resource] struct {
+	newSummary     func() S
+	fold           func(summary S, items []T) error
+	name           string
+	inputNamespace resource.Namespace
+	inputType      resource.Type
+	labelQuery     []resource.LabelQueryOption
+
+	// last is the fingerprint of the inputs the summary was computed from
+	last map[resource.ID]resource.Version
+}
+
+// AggregationOption configures AggregationController.
+type AggregationOption func(*aggregationOptions)
+
+type aggregationOptions struct {
+	labelQuery []resource.LabelQueryOption
+}
+
+// WithAggregationLabelQuery limits aggregated resources to the ones matching the label query.
+func WithAggregationLabelQuery(opts ...resource.LabelQueryOption) AggregationOption {
+	return func(options *aggregationOptions) {
+		options.labelQuery = append(options.labelQuery, opts...)
+	}
+}
+
+// NewAggregationController creates a controller which folds resources of the input type in the namespace into a summary.
+//
+// newSummary returns the summary resource with the target namespace and ID set, it's used as the template for the output.
+// fold is called with the current summary resource and all matching input resources, it should overwrite the
+// summary spec completely.
+func NewAggregationController[T, S resource.Resource](
+	name string, inputNamespace resource.Namespace, inputType resource.Type,
+	newSummary func() S, fold func(summary S, items []T) error,
+	opts ...AggregationOption,
+) *AggregationController[T, S] {
+	var options aggregationOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &AggregationController[T, S]{
+		name:           name,
+		inputNamespace: inputNamespace,
+		inputType:      inputType,
+		newSummary:     newSummary,
+		fold:           fold,
+		labelQuery:     options.labelQuery,
+	}
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *AggregationController[T, S]) Name() string {
+	return ctrl.name
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *AggregationController[T, S]) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: ctrl.inputNamespace,
+			Type:      ctrl.inputType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *AggregationController[T, S]) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: ctrl.newSummary().Metadata().Type(),
+			Kind: controller.OutputShared,
+		},
+	}
+}
+
+// Run implements controller.Controller interface.
+func (ctrl *AggregationController[T, S]) Run(ctx context.Context, r controller.Runtime, _ *zap.Logger) error {
+	// controller might be restarted, so the summary should be recomputed
+	ctrl.last = nil
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		if err := ctrl.reconcile(ctx, r); err != nil {
+			return err
+		}
+	}
+}
+
+func (ctrl *AggregationController[T, S]) reconcile(ctx context.Context, r controller.Runtime) error {
+	list, err := safe.ReaderList[T](ctx, r,
+		resource.NewMetadata(ctrl.inputNamespace, ctrl.inputType, "", resource.VersionUndefined),
+		state.WithLabelQuery(ctrl.labelQuery...),
+	)
+	if err != nil {
+		return fmt.Errorf("error listing aggregated resources: %w", err)
+	}
+
+	items := make([]T, 0, list.Len())
+	fingerprint := make(map[resource.ID]resource.Version, list.Len())
+
+	for iter := safe.IteratorFromList(list); iter.Next(); {
+		if iter.Value().Metadata().Phase() != resource.PhaseRunning {
+			continue
+		}
+
+		items = append(items, iter.Value())
+		fingerprint[iter.Value().Metadata().ID()] = iter.Value().Metadata().Version()
+	}
+
+	if ctrl.last != nil && sameFingerprint(ctrl.last, fingerprint) {
+		return nil
+	}
+
+	if err = safe.WriterModify(ctx, r, ctrl.newSummary(), func(summary S) error {
+		return ctrl.fold(summary, items)
+	}); err != nil {
+		return fmt.Errorf("error updating summary: %w", err)
+	}
+
+	ctrl.last = fingerprint
+
+	return nil
+}
+
+func sameFingerprint(a, b map[resource.ID]resource.Version) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for id, version := range a {
+		other, ok := b[id]
+		if !ok || !other.Equal(version) {
+			return false
+		}
+	}
+
+	return true
+}
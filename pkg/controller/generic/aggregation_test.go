@@ -0,0 +1,64 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generic_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/controller/generic"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestAggregation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	runControllers(ctx, t, st, generic.NewAggregationController("IntSum", "source", conformance.IntResourceType,
+		func() *conformance.IntResource {
+			return conformance.NewIntResource("target", "sum", 0)
+		},
+		func(summary *conformance.IntResource, items []*conformance.IntResource) error {
+			var sum int
+
+			for _, item := range items {
+				sum += item.Value()
+			}
+
+			summary.SetValue(sum)
+
+			return nil
+		},
+		generic.WithAggregationLabelQuery(resource.LabelExists("summed")),
+	))
+
+	sum := conformance.NewIntResource("target", "sum", 0).Metadata()
+
+	assertValue(ctx, t, st, sum, 0)
+
+	for i, value := range []int{1, 2, 3} {
+		r := conformance.NewIntResource("source", string(rune('a'+i)), value)
+		r.Metadata().Labels().Set("summed", "")
+
+		require.NoError(t, st.Create(ctx, r))
+	}
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("source", "ignored", 100)))
+
+	assertValue(ctx, t, st, sum, 6)
+
+	require.NoError(t, st.Destroy(ctx, conformance.NewIntResource("source", "b", 0).Metadata()))
+
+	assertValue(ctx, t, st, sum, 4)
+}
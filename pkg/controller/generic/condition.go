@@ -0,0 +1,228 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generic
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// ConditionDependency describes the kind of the dependent resources which contribute to the parent condition.
+type ConditionDependency struct {
+	// Status returns the readiness status of the dependent resource.
+	Status    func(resource.Resource) meta.ConditionStatus
+	Namespace resource.Namespace
+	Type      resource.Type
+}
+
+// ConditionRule evaluates the statuses of the dependent resources.
+//
+// The rule returns false if it doesn't apply, so that the next rule is evaluated.
+type ConditionRule func(statuses []meta.ConditionStatus) (meta.ConditionStatus, bool)
+
+// RuleAnyFailed reports the condition as failed if any dependent resource failed.
+func RuleAnyFailed() ConditionRule {
+	return func(statuses []meta.ConditionStatus) (meta.ConditionStatus, bool) {
+		for _, status := range statuses {
+			if status == meta.ConditionFailed {
+				return meta.ConditionFailed, true
+			}
+		}
+
+		return "", false
+	}
+}
+
+// RuleAllReady reports the condition as ready if all dependent resources are ready.
+func RuleAllReady() ConditionRule {
+	return func(statuses []meta.ConditionStatus) (meta.ConditionStatus, bool) {
+		for _, status := range statuses {
+			if status != meta.ConditionReady {
+				return "", false
+			}
+		}
+
+		return meta.ConditionReady, true
+	}
+}
+
+// ConditionController rolls up the status of the dependent resources into a meta.Condition for each parent.
+//
+// Dependent resources are linked to the parent with the parent label which holds the parent ID.
+// Rules are evaluated in order, and the first matching rule sets the condition status;
+// if no rule matches, the condition is pending.
+// Condition is destroyed once the parent has no dependent resources left.
+type ConditionController struct {
+	name            string
+	parentLabel     string
+	outputNamespace resource.Namespace
+	dependencies    []ConditionDependency
+	rules           []ConditionRule
+}
+
+// NewConditionController creates a controller which writes conditions to the output namespace.
+//
+// If no rules are given, RuleAnyFailed and RuleAllReady are used.
+func NewConditionController(
+	name string, parentLabel string, outputNamespace resource.Namespace,
+	dependencies []ConditionDependency, rules ...ConditionRule,
+) *ConditionController {
+	if len(rules) == 0 {
+		rules = []ConditionRule{RuleAnyFailed(), RuleAllReady()}
+	}
+
+	return &ConditionController{
+		name:            name,
+		parentLabel:     parentLabel,
+		outputNamespace: outputNamespace,
+		dependencies:    dependencies,
+		rules:           rules,
+	}
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *ConditionController) Name() string {
+	return ctrl.name
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *ConditionController) Inputs() []controller.Input {
+	inputs := make([]controller.Input, 0, len(ctrl.dependencies))
+
+	for _, dep := range ctrl.dependencies {
+		inputs = append(inputs, controller.Input{
+			Namespace: dep.Namespace,
+			Type:      dep.Type,
+			Kind:      controller.InputWeak,
+		})
+	}
+
+	return inputs
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *ConditionController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: meta.ConditionType,
+			Kind: controller.OutputShared,
+		},
+	}
+}
+
+// Run implements controller.Controller interface.
+func (ctrl *ConditionController) Run(ctx context.Context, r controller.Runtime, _ *zap.Logger) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		if err := ctrl.reconcile(ctx, r); err != nil {
+			return err
+		}
+	}
+}
+
+type conditionParent struct {
+	statuses []meta.ConditionStatus
+	failed   []string
+}
+
+func (ctrl *ConditionController) reconcile(ctx context.Context, r controller.Runtime) error {
+	parents := map[resource.ID]*conditionParent{}
+
+	for _, dep := range ctrl.dependencies {
+		list, err := r.List(ctx, resource.NewMetadata(dep.Namespace, dep.Type, "", resource.VersionUndefined),
+			state.WithLabelQuery(resource.LabelExists(ctrl.parentLabel)))
+		if err != nil {
+			return fmt.Errorf("error listing dependent resources: %w", err)
+		}
+
+		for _, item := range list.Items {
+			if item.Metadata().Phase() != resource.PhaseRunning {
+				continue
+			}
+
+			parentID, _ := item.Metadata().Labels().Get(ctrl.parentLabel)
+
+			parent := parents[parentID]
+			if parent == nil {
+				parent = &conditionParent{}
+				parents[parentID] = parent
+			}
+
+			status := dep.Status(item)
+
+			parent.statuses = append(parent.statuses, status)
+
+			if status == meta.ConditionFailed {
+				parent.failed = append(parent.failed, resource.String(item))
+			}
+		}
+	}
+
+	for parentID, parent := range parents {
+		sort.Strings(parent.failed)
+
+		if err := safe.WriterModify(ctx, r, meta.NewCondition(ctrl.outputNamespace, parentID), func(condition *meta.Condition) error {
+			spec := condition.TypedSpec()
+
+			spec.Status = ctrl.evaluate(parent.statuses)
+			spec.Failed = parent.failed
+			spec.Total = len(parent.statuses)
+			spec.Ready = 0
+
+			for _, status := range parent.statuses {
+				if status == meta.ConditionReady {
+					spec.Ready++
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return fmt.Errorf("error updating condition: %w", err)
+		}
+	}
+
+	conditions, err := safe.ReaderList[*meta.Condition](ctx, r, resource.NewMetadata(ctrl.outputNamespace, meta.ConditionType, "", resource.VersionUndefined))
+	if err != nil {
+		return fmt.Errorf("error listing conditions: %w", err)
+	}
+
+	for iter := safe.IteratorFromList(conditions); iter.Next(); {
+		condition := iter.Value()
+
+		if _, ok := parents[condition.Metadata().ID()]; ok || condition.Metadata().Owner() != ctrl.name {
+			continue
+		}
+
+		if _, err = destroyOutput(ctx, r, condition.Metadata()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ctrl *ConditionController) evaluate(statuses []meta.ConditionStatus) meta.ConditionStatus {
+	for _, rule := range ctrl.rules {
+		if status, ok := rule(statuses); ok {
+			return status
+		}
+	}
+
+	return meta.ConditionPending
+}
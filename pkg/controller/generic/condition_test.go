@@ -0,0 +1,95 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generic_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/controller/generic"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestCondition(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	// int resources are ready when positive, failed when negative
+	runControllers(ctx, t, st, generic.NewConditionController("AppCondition", "app", "conditions", []generic.ConditionDependency{
+		{
+			Namespace: "default",
+			Type:      conformance.IntResourceType,
+			Status: func(r resource.Resource) meta.ConditionStatus {
+				switch value := r.(conformance.IntegerResource).Value(); { //nolint:forcetypeassert
+				case value > 0:
+					return meta.ConditionReady
+				case value < 0:
+					return meta.ConditionFailed
+				default:
+					return meta.ConditionPending
+				}
+			},
+		},
+	}))
+
+	condition := meta.NewCondition("conditions", "web").Metadata()
+
+	waitStatus := func(status meta.ConditionStatus, ready, total int) {
+		_, err := st.WatchFor(ctx, condition, state.WithEventTypes(state.Created, state.Updated), state.WithCondition(func(r resource.Resource) (bool, error) {
+			spec := r.(*meta.Condition).TypedSpec() //nolint:forcetypeassert
+
+			return spec.Status == status && spec.Ready == ready && spec.Total == total, nil
+		}))
+		require.NoError(t, err)
+	}
+
+	setValue := func(id resource.ID, value int) {
+		r := conformance.NewIntResource("default", id, value)
+		r.Metadata().Labels().Set("app", "web")
+
+		_, err := st.Get(ctx, r.Metadata())
+		if state.IsNotFoundError(err) {
+			require.NoError(t, st.Create(ctx, r))
+
+			return
+		}
+
+		_, err = st.UpdateWithConflicts(ctx, r.Metadata(), func(r resource.Resource) error {
+			r.(*conformance.IntResource).SetValue(value) //nolint:forcetypeassert
+
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	setValue("one", 1)
+	setValue("two", 0)
+
+	waitStatus(meta.ConditionPending, 1, 2)
+
+	setValue("two", 2)
+
+	waitStatus(meta.ConditionReady, 2, 2)
+
+	setValue("one", -1)
+
+	waitStatus(meta.ConditionFailed, 1, 2)
+
+	require.NoError(t, st.Destroy(ctx, conformance.NewIntResource("default", "one", 0).Metadata()))
+	require.NoError(t, st.Destroy(ctx, conformance.NewIntResource("default", "two", 0).Metadata()))
+
+	_, err := st.WatchFor(ctx, condition, state.WithEventTypes(state.Destroyed))
+	require.NoError(t, err)
+}
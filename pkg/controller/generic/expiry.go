@@ -0,0 +1,216 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generic
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/clock"
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// ExpiryFunc returns the expiration time of the resource, if it has one.
+type ExpiryFunc[T resource.Resource] func(T) (time.Time, bool)
+
+// ExpiryFromLabel reads the expiration time in RFC3339 format from the label.
+//
+// Resources with missing or malformed label never expire.
+func ExpiryFromLabel[T resource.Resource](key string) ExpiryFunc[T] {
+	return func(r T) (time.Time, bool) {
+		value, ok := r.Metadata().Labels().Get(key)
+		if !ok {
+			return time.Time{}, false
+		}
+
+		expires, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, false
+		}
+
+		return expires, true
+	}
+}
+
+// ExpiryController tears down and destroys resources of a type once their expiration time passes.
+//
+// Instead of periodic listing, the controller arms a single timer for the closest expiration time.
+// The type is a shared output of the controller, and only the resources owned by the controller are expired:
+// resources are handed over to the controller by creating them with the controller name as the owner
+// (see state.WithCreateOwner). Resources owned by other controllers are left to their owners.
+type ExpiryController[T resource.Resource] struct {
+	clock        clock.Clock
+	expires      ExpiryFunc[T]
+	name         string
+	namespace    resource.Namespace
+	resourceType resource.Type
+}
+
+// ExpiryOption configures ExpiryController.
+type ExpiryOption func(*expiryOptions)
+
+type expiryOptions struct {
+	clock clock.Clock
+}
+
+// WithExpiryClock sets the clock the expiration times are compared against.
+func WithExpiryClock(c clock.Clock) ExpiryOption {
+	return func(options *expiryOptions) {
+		options.clock = c
+	}
+}
+
+// NewExpiryController creates a controller which expires resources of the type in the namespace.
+func NewExpiryController[T resource.Resource](
+	name string, namespace resource.Namespace, resourceType resource.Type, expires ExpiryFunc[T],
+	opts ...ExpiryOption,
+) *ExpiryController[T] {
+	options := expiryOptions{
+		clock: clock.Real(),
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &ExpiryController[T]{
+		name:         name,
+		namespace:    namespace,
+		resourceType: resourceType,
+		clock:        options.clock,
+		expires:      expires,
+	}
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *ExpiryController[T]) Name() string {
+	return ctrl.name
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *ExpiryController[T]) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: ctrl.namespace,
+			Type:      ctrl.resourceType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *ExpiryController[T]) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: ctrl.resourceType,
+			Kind: controller.OutputShared,
+		},
+	}
+}
+
+// Run implements controller.Controller interface.
+func (ctrl *ExpiryController[T]) Run(ctx context.Context, r controller.Runtime, _ *zap.Logger) error {
+	var timer clock.Timer
+
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		next, err := ctrl.reconcile(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		if timer != nil {
+			timer.Stop()
+			timer = nil
+		}
+
+		if !next.IsZero() {
+			timer = ctrl.clock.AfterFunc(ctrl.clock.Until(next), r.QueueReconcile)
+		}
+	}
+}
+
+// reconcile expires the resources, and returns the closest expiration time of the remaining resources.
+func (ctrl *ExpiryController[T]) reconcile(ctx context.Context, r controller.Runtime) (time.Time, error) {
+	list, err := safe.ReaderList[T](ctx, r, resource.NewMetadata(ctrl.namespace, ctrl.resourceType, "", resource.VersionUndefined))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error listing resources: %w", err)
+	}
+
+	var next time.Time
+
+	now := ctrl.clock.Now()
+
+	for iter := safe.IteratorFromList(list); iter.Next(); {
+		res := iter.Value()
+
+		if res.Metadata().Owner() != ctrl.name {
+			continue
+		}
+
+		expires, ok := ctrl.expires(res)
+		if !ok {
+			continue
+		}
+
+		if expires.After(now) {
+			if next.IsZero() || expires.Before(next) {
+				next = expires
+			}
+
+			continue
+		}
+
+		if err = ctrl.expire(ctx, r, res); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	return next, nil
+}
+
+// expire tears down the resource, and destroys it once the finalizers are removed.
+//
+// Input watch triggers reconcile once the finalizers change.
+func (ctrl *ExpiryController[T]) expire(ctx context.Context, r controller.Runtime, res T) error {
+	md := res.Metadata()
+
+	if md.Phase() == resource.PhaseRunning {
+		if _, err := r.Teardown(ctx, md); err != nil {
+			if state.IsNotFoundError(err) {
+				return nil
+			}
+
+			return fmt.Errorf("error tearing down expired %s: %w", resource.String(res), err)
+		}
+	}
+
+	if !md.Finalizers().Empty() {
+		return nil
+	}
+
+	if err := r.Destroy(ctx, md); err != nil && !state.IsNotFoundError(err) && !state.IsConflictError(err) {
+		return fmt.Errorf("error destroying expired %s: %w", resource.String(res), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generic_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/clock"
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/controller/generic"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestExpiry(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	runControllers(ctx, t, st, generic.NewExpiryController("IntExpiry", "default", conformance.IntResourceType,
+		generic.ExpiryFromLabel[*conformance.IntResource]("expires"),
+	))
+
+	expiring := conformance.NewIntResource("default", "expiring", 1)
+	expiring.Metadata().Labels().Set("expires", time.Now().Add(time.Second).Format(time.RFC3339))
+	expiring.Metadata().Finalizers().Add("test")
+
+	expired := conformance.NewIntResource("default", "expired", 2)
+	expired.Metadata().Labels().Set("expires", time.Now().Add(-time.Second).Format(time.RFC3339))
+
+	permanent := conformance.NewIntResource("default", "permanent", 3)
+
+	for _, r := range []resource.Resource{expiring, expired, permanent} {
+		require.NoError(t, st.Create(ctx, r, state.WithCreateOwner("IntExpiry")))
+	}
+
+	// resources not owned by the controller are left to their owners
+	foreign := conformance.NewIntResource("default", "foreign", 4)
+	foreign.Metadata().Labels().Set("expires", time.Now().Add(-time.Second).Format(time.RFC3339))
+
+	require.NoError(t, st.Create(ctx, foreign))
+
+	_, err := st.WatchFor(ctx, expired.Metadata(), state.WithEventTypes(state.Destroyed))
+	require.NoError(t, err)
+
+	// resource with finalizers is torn down once expired, and destroyed after finalizers are removed
+	_, err = st.WatchFor(ctx, expiring.Metadata(), state.WithPhases(resource.PhaseTearingDown))
+	require.NoError(t, err)
+
+	require.NoError(t, st.RemoveFinalizer(ctx, expiring.Metadata(), "test"))
+
+	_, err = st.WatchFor(ctx, expiring.Metadata(), state.WithEventTypes(state.Destroyed))
+	require.NoError(t, err)
+
+	_, err = st.Get(ctx, permanent.Metadata())
+	assert.NoError(t, err)
+
+	r, err := st.Get(ctx, foreign.Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, resource.PhaseRunning, r.Metadata().Phase())
+}
+
+func TestExpiryFakeClock(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	fake := clock.NewFake(time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	runControllers(ctx, t, st, generic.NewExpiryController("IntExpiry", "default", conformance.IntResourceType,
+		generic.ExpiryFromLabel[*conformance.IntResource]("expires"),
+		generic.WithExpiryClock(fake),
+	))
+
+	expiring := conformance.NewIntResource("default", "expiring", 1)
+	expiring.Metadata().Labels().Set("expires", fake.Now().Add(time.Hour).Format(time.RFC3339))
+
+	require.NoError(t, st.Create(ctx, expiring, state.WithCreateOwner("IntExpiry")))
+
+	// timer is armed for the expiration time
+	require.NoError(t, fake.BlockUntil(ctx, 1))
+
+	fake.Advance(time.Hour)
+
+	_, err := st.WatchFor(ctx, expiring.Metadata(), state.WithEventTypes(state.Destroyed))
+	require.NoError(t, err)
+}
@@ -0,0 +1,171 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generic
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+)
+
+// FanOutFunc expands a single input resource into the set of the output resources.
+//
+// Output resources should have the output namespace and type of the controller, and they are keyed by ID.
+type FanOutFunc[T, S resource.Resource] func(input T) ([]S, error)
+
+// FanOutController expands each resource of type T into a dynamic set of resources of type S.
+//
+// Outputs which are no longer returned by the expand function (or whose input is gone) are destroyed.
+type FanOutController[T, S resource.Resource] struct {
+	expand          FanOutFunc[T, S]
+	name            string
+	inputNamespace  resource.Namespace
+	inputType       resource.Type
+	outputNamespace resource.Namespace
+	outputType      resource.Type
+}
+
+// NewFanOutController creates a controller which expands input resources into output resources.
+func NewFanOutController[T, S resource.Resource](
+	name string, inputNamespace resource.Namespace, inputType resource.Type,
+	outputNamespace resource.Namespace, outputType resource.Type,
+	expand FanOutFunc[T, S],
+) *FanOutController[T, S] {
+	return &FanOutController[T, S]{
+		name:            name,
+		inputNamespace:  inputNamespace,
+		inputType:       inputType,
+		outputNamespace: outputNamespace,
+		outputType:      outputType,
+		expand:          expand,
+	}
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *FanOutController[T, S]) Name() string {
+	return ctrl.name
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *FanOutController[T, S]) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: ctrl.inputNamespace,
+			Type:      ctrl.inputType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *FanOutController[T, S]) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: ctrl.outputType,
+			Kind: controller.OutputShared,
+		},
+	}
+}
+
+// Run implements controller.Controller interface.
+func (ctrl *FanOutController[T, S]) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		if err := ctrl.reconcile(ctx, r, logger); err != nil {
+			return err
+		}
+	}
+}
+
+func (ctrl *FanOutController[T, S]) reconcile(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	inputs, err := safe.ReaderList[T](ctx, r, resource.NewMetadata(ctrl.inputNamespace, ctrl.inputType, "", resource.VersionUndefined))
+	if err != nil {
+		return fmt.Errorf("error listing inputs: %w", err)
+	}
+
+	outputs, err := safe.ReaderList[S](ctx, r, resource.NewMetadata(ctrl.outputNamespace, ctrl.outputType, "", resource.VersionUndefined))
+	if err != nil {
+		return fmt.Errorf("error listing outputs: %w", err)
+	}
+
+	existing := make(map[resource.ID]S, outputs.Len())
+	foreign := map[resource.ID]string{}
+
+	for iter := safe.IteratorFromList(outputs); iter.Next(); {
+		if owner := iter.Value().Metadata().Owner(); owner == ctrl.name {
+			existing[iter.Value().Metadata().ID()] = iter.Value()
+		} else {
+			foreign[iter.Value().Metadata().ID()] = owner
+		}
+	}
+
+	// desired maps output ID to the ID of the input which produced it
+	desired := map[resource.ID]resource.ID{}
+
+	for iter := safe.IteratorFromList(inputs); iter.Next(); {
+		input := iter.Value()
+
+		if input.Metadata().Phase() != resource.PhaseRunning {
+			continue
+		}
+
+		var expanded []S
+
+		expanded, err = ctrl.expand(input)
+		if err != nil {
+			return fmt.Errorf("error expanding %s: %w", resource.String(input), err)
+		}
+
+		for _, output := range expanded {
+			md := output.Metadata()
+
+			if md.Namespace() != ctrl.outputNamespace || md.Type() != ctrl.outputType {
+				return fmt.Errorf("output %s of %s doesn't match controller output %s/%s", resource.String(output), resource.String(input), ctrl.outputNamespace, ctrl.outputType)
+			}
+
+			if other, ok := desired[md.ID()]; ok {
+				return fmt.Errorf("output %s is produced by both %q and %q", resource.String(output), other, input.Metadata().ID())
+			}
+
+			desired[md.ID()] = input.Metadata().ID()
+
+			if owner, conflict := foreign[md.ID()]; conflict {
+				// failing the controller would only restart it over and over, the output is retried on the next reconcile
+				logger.Warn("output is owned by another controller, skipping",
+					zap.String("id", md.ID()), zap.String("owner", owner))
+
+				continue
+			}
+
+			current, ok := existing[md.ID()]
+
+			if err = writeOutput(ctx, r, output, current, ok); err != nil {
+				return err
+			}
+		}
+	}
+
+	for id, output := range existing {
+		if _, ok := desired[id]; ok {
+			continue
+		}
+
+		if _, err = destroyOutput(ctx, r, output.Metadata()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
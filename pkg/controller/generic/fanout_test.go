@@ -0,0 +1,116 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generic_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/controller/generic"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestFanOut(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	runControllers(ctx, t, st, generic.NewFanOutController("IntFanOut", "source", conformance.IntResourceType, "target", conformance.StrResourceType,
+		func(input *conformance.IntResource) ([]*conformance.StrResource, error) {
+			outputs := make([]*conformance.StrResource, 0, input.Value())
+
+			for i := 0; i < input.Value(); i++ {
+				outputs = append(outputs, conformance.NewStrResource("target", fmt.Sprintf("%s-%d", input.Metadata().ID(), i), input.Metadata().ID()))
+			}
+
+			return outputs, nil
+		},
+	))
+
+	outputs := func(n int) []string {
+		var ids []string
+
+		require.Eventually(t, func() bool {
+			list, err := st.List(ctx, resource.NewMetadata("target", conformance.StrResourceType, "", resource.VersionUndefined))
+			require.NoError(t, err)
+
+			ids = ids[:0]
+
+			for _, r := range list.Items {
+				ids = append(ids, r.Metadata().ID())
+			}
+
+			return len(ids) == n
+		}, 10*time.Second, 10*time.Millisecond)
+
+		return ids
+	}
+
+	one := conformance.NewIntResource("source", "one", 3)
+
+	require.NoError(t, st.Create(ctx, one))
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("source", "two", 1)))
+
+	assert.Equal(t, []string{"one-0", "one-1", "one-2", "two-0"}, outputs(4))
+
+	_, err := st.UpdateWithConflicts(ctx, one.Metadata(), func(r resource.Resource) error {
+		r.(*conformance.IntResource).SetValue(1) //nolint:forcetypeassert
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"one-0", "two-0"}, outputs(2))
+
+	require.NoError(t, st.Destroy(ctx, one.Metadata()))
+
+	assert.Equal(t, []string{"two-0"}, outputs(1))
+}
+
+func TestFanOutOwnerConflict(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	require.NoError(t, st.Create(ctx, conformance.NewStrResource("target", "one-0", "taken"), state.WithCreateOwner("Other")))
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("source", "one", 2)))
+
+	runControllers(ctx, t, st, generic.NewFanOutController("IntFanOut", "source", conformance.IntResourceType, "target", conformance.StrResourceType,
+		func(input *conformance.IntResource) ([]*conformance.StrResource, error) {
+			outputs := make([]*conformance.StrResource, 0, input.Value())
+
+			for i := 0; i < input.Value(); i++ {
+				outputs = append(outputs, conformance.NewStrResource("target", fmt.Sprintf("%s-%d", input.Metadata().ID(), i), input.Metadata().ID()))
+			}
+
+			return outputs, nil
+		},
+	))
+
+	// conflicting output is skipped, the rest is written
+	require.Eventually(t, func() bool {
+		_, err := st.Get(ctx, conformance.NewStrResource("target", "one-1", "").Metadata())
+
+		return err == nil
+	}, 10*time.Second, 10*time.Millisecond)
+
+	r, err := st.Get(ctx, conformance.NewStrResource("target", "one-0", "").Metadata())
+	require.NoError(t, err)
+
+	assert.Equal(t, "Other", r.Metadata().Owner())
+	assert.Equal(t, "taken", r.(*conformance.StrResource).Value()) //nolint:forcetypeassert
+}
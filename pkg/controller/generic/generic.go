@@ -0,0 +1,93 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package generic provides reusable controllers for the common patterns.
+package generic
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// watchTrigger returns an external trigger which queues reconcile on any change of the resources of the kind in the state.
+func watchTrigger(st state.State, kind resource.Kind) controller.ExternalTrigger { //nolint:ireturn
+	return controller.ExternalTriggerFunc(func(ctx context.Context, queueReconcile func()) error {
+		ch := make(chan state.Event)
+
+		// bootstrap contents to catch up with the changes which happened before the watch was established
+		if err := st.WatchKind(ctx, kind, ch, state.WithBootstrapContents(true)); err != nil {
+			return fmt.Errorf("error watching %s/%s: %w", kind.Namespace(), kind.Type(), err)
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ch:
+				queueReconcile()
+			}
+		}
+	})
+}
+
+// destroyOutput tears down the output resource and destroys it once it has no finalizers.
+//
+// It returns false if the resource is still being torn down.
+func destroyOutput(ctx context.Context, r controller.Runtime, ptr resource.Pointer) (bool, error) {
+	ready, err := r.Teardown(ctx, ptr)
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("error tearing down %s/%s/%s: %w", ptr.Namespace(), ptr.Type(), ptr.ID(), err)
+	}
+
+	if !ready {
+		return false, nil
+	}
+
+	if err = r.Destroy(ctx, ptr); err != nil && !state.IsNotFoundError(err) {
+		return false, fmt.Errorf("error destroying %s/%s/%s: %w", ptr.Namespace(), ptr.Type(), ptr.ID(), err)
+	}
+
+	return true, nil
+}
+
+// writeOutput creates the desired output resource, or updates the current one if labels or spec differ.
+func writeOutput[S resource.Resource](ctx context.Context, r controller.Runtime, desired, current S, exists bool) error {
+	if !exists {
+		if err := r.Create(ctx, desired); err != nil {
+			return fmt.Errorf("error creating %s: %w", resource.String(desired), err)
+		}
+
+		return nil
+	}
+
+	if current.Metadata().Phase() != resource.PhaseRunning {
+		// output is being torn down by someone else, wait for it to be destroyed
+		return nil
+	}
+
+	// keep the metadata managed by the state, and compare only labels and spec
+	labels := *desired.Metadata().Labels()
+	*desired.Metadata() = current.Metadata().Copy()
+	*desired.Metadata().Labels() = labels
+
+	if resource.Equal(current, desired) {
+		return nil
+	}
+
+	desired.Metadata().BumpVersion()
+
+	if err := r.Update(ctx, current.Metadata().Version(), desired); err != nil {
+		return fmt.Errorf("error updating %s: %w", resource.String(desired), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// ReferenceSource describes the kind of the resources which hold references to other resources.
+type ReferenceSource struct {
+	// References extracts the references from the resource, zero references are ignored.
+	References func(resource.Resource) []resource.Ref
+	Namespace  resource.Namespace
+	Type       resource.Type
+	// Targets lists the kinds of the referenced resources, so that their changes trigger the check.
+	//
+	// References to the kinds not listed in Targets can't be resolved.
+	Targets []resource.Kind
+}
+
+// IntegrityController checks that the references held by the source resources resolve.
+//
+// For each source resource a meta.Condition is written to the output namespace with the ID `<type>/<id>` of the source:
+// the condition is ready if all references resolve, and failed listing the dangling references otherwise.
+// A reference is dangling if the referenced resource doesn't exist, or it has a different version than the reference.
+type IntegrityController struct {
+	name            string
+	outputNamespace resource.Namespace
+	sources         []ReferenceSource
+}
+
+// NewIntegrityController creates a controller which writes integrity conditions to the output namespace.
+func NewIntegrityController(name string, outputNamespace resource.Namespace, sources []ReferenceSource) *IntegrityController {
+	return &IntegrityController{
+		name:            name,
+		outputNamespace: outputNamespace,
+		sources:         sources,
+	}
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *IntegrityController) Name() string {
+	return ctrl.name
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *IntegrityController) Inputs() []controller.Input {
+	var inputs []controller.Input
+
+	seen := map[string]struct{}{}
+
+	addInput := func(ns resource.Namespace, typ resource.Type) {
+		key := ns + "/" + typ
+
+		if _, ok := seen[key]; ok {
+			return
+		}
+
+		seen[key] = struct{}{}
+
+		inputs = append(inputs, controller.Input{
+			Namespace: ns,
+			Type:      typ,
+			Kind:      controller.InputWeak,
+		})
+	}
+
+	for _, source := range ctrl.sources {
+		addInput(source.Namespace, source.Type)
+
+		for _, target := range source.Targets {
+			addInput(target.Namespace(), target.Type())
+		}
+	}
+
+	return inputs
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *IntegrityController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: meta.ConditionType,
+			Kind: controller.OutputShared,
+		},
+	}
+}
+
+// Run implements controller.Controller interface.
+func (ctrl *IntegrityController) Run(ctx context.Context, r controller.Runtime, _ *zap.Logger) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		if err := ctrl.reconcile(ctx, r); err != nil {
+			return err
+		}
+	}
+}
+
+func (ctrl *IntegrityController) reconcile(ctx context.Context, r controller.Runtime) error {
+	touched := map[resource.ID]struct{}{}
+
+	for _, source := range ctrl.sources {
+		list, err := r.List(ctx, resource.NewMetadata(source.Namespace, source.Type, "", resource.VersionUndefined))
+		if err != nil {
+			return fmt.Errorf("error listing source resources: %w", err)
+		}
+
+		for _, item := range list.Items {
+			if item.Metadata().Phase() != resource.PhaseRunning {
+				continue
+			}
+
+			var (
+				dangling []string
+				total    int
+			)
+
+			for _, ref := range source.References(item) {
+				if ref.IsZero() {
+					continue
+				}
+
+				total++
+
+				_, err = safe.ReaderResolve[resource.Resource](ctx, r, ref)
+
+				var staleErr *safe.StaleReferenceError
+
+				switch {
+				case err == nil:
+				case state.IsNotFoundError(err), errors.As(err, &staleErr):
+					dangling = append(dangling, ref.String())
+				default:
+					return fmt.Errorf("error resolving reference %s: %w", ref, err)
+				}
+			}
+
+			sort.Strings(dangling)
+
+			id := item.Metadata().Type() + "/" + item.Metadata().ID()
+			touched[id] = struct{}{}
+
+			if err = safe.WriterModify(ctx, r, meta.NewCondition(ctrl.outputNamespace, id), func(condition *meta.Condition) error {
+				spec := condition.TypedSpec()
+
+				spec.Status = meta.ConditionReady
+				spec.Failed = dangling
+				spec.Total = total
+				spec.Ready = total - len(dangling)
+
+				if len(dangling) > 0 {
+					spec.Status = meta.ConditionFailed
+				}
+
+				return nil
+			}); err != nil {
+				return fmt.Errorf("error updating condition: %w", err)
+			}
+		}
+	}
+
+	conditions, err := safe.ReaderList[*meta.Condition](ctx, r, resource.NewMetadata(ctrl.outputNamespace, meta.ConditionType, "", resource.VersionUndefined))
+	if err != nil {
+		return fmt.Errorf("error listing conditions: %w", err)
+	}
+
+	for iter := safe.IteratorFromList(conditions); iter.Next(); {
+		condition := iter.Value()
+
+		if _, ok := touched[condition.Metadata().ID()]; ok || condition.Metadata().Owner() != ctrl.name {
+			continue
+		}
+
+		if _, err = destroyOutput(ctx, r, condition.Metadata()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
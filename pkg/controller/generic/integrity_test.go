@@ -0,0 +1,74 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generic_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/controller/generic"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestIntegrity(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	// str resources reference the int resource with the ID equal to their value
+	runControllers(ctx, t, st, generic.NewIntegrityController("Integrity", "integrity", []generic.ReferenceSource{
+		{
+			Namespace: "default",
+			Type:      conformance.StrResourceType,
+			Targets:   []resource.Kind{resource.NewMetadata("default", conformance.IntResourceType, "", resource.VersionUndefined)},
+			References: func(r resource.Resource) []resource.Ref {
+				return []resource.Ref{
+					{
+						Namespace: "default",
+						Type:      conformance.IntResourceType,
+						ID:        r.(conformance.StringResource).Value(), //nolint:forcetypeassert
+					},
+				}
+			},
+		},
+	}))
+
+	condition := meta.NewCondition("integrity", conformance.StrResourceType+"/a").Metadata()
+
+	waitStatus := func(status meta.ConditionStatus, failed ...string) {
+		_, err := st.WatchFor(ctx, condition, state.WithEventTypes(state.Created, state.Updated), state.WithCondition(func(r resource.Resource) (bool, error) {
+			spec := r.(*meta.Condition).TypedSpec() //nolint:forcetypeassert
+
+			return spec.Status == status && len(spec.Failed) == len(failed) && (len(failed) == 0 || spec.Failed[0] == failed[0]), nil
+		}))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, st.Create(ctx, conformance.NewStrResource("default", "a", "one")))
+
+	waitStatus(meta.ConditionFailed, "test/int(default/one)")
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("default", "one", 1)))
+
+	waitStatus(meta.ConditionReady)
+
+	require.NoError(t, st.Destroy(ctx, conformance.NewIntResource("default", "one", 0).Metadata()))
+
+	waitStatus(meta.ConditionFailed, "test/int(default/one)")
+
+	require.NoError(t, st.Destroy(ctx, conformance.NewStrResource("default", "a", "").Metadata()))
+
+	_, err := st.WatchFor(ctx, condition, state.WithEventTypes(state.Destroyed))
+	require.NoError(t, err)
+}
@@ -0,0 +1,215 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generic
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// MirrorController mirrors all resources of a type from the source namespace to the target namespace.
+//
+// Mirrored resources keep the ID, labels and spec of the source resources (optionally transformed),
+// and they are destroyed once the source resource is torn down or destroyed.
+// Mirrored resources are shared outputs of the controller, so other controllers might produce
+// resources of the same type. If the target resource already exists with another owner,
+// the resource is not mirrored, and the conflict is logged.
+type MirrorController[T resource.Resource] struct {
+	sourceState     state.State
+	transform       func(T) error
+	name            string
+	resourceType    resource.Type
+	sourceNamespace resource.Namespace
+	targetNamespace resource.Namespace
+}
+
+// MirrorOption configures MirrorController.
+type MirrorOption[T resource.Resource] func(ctrl *MirrorController[T])
+
+// WithMirrorTransform applies the transform to the mirrored resource before it is written.
+//
+// Transform is called on a copy of the source resource which already has the target namespace set.
+func WithMirrorTransform[T resource.Resource](transform func(T) error) MirrorOption[T] {
+	return func(ctrl *MirrorController[T]) {
+		ctrl.transform = transform
+	}
+}
+
+// WithMirrorSourceState mirrors resources from a different state (e.g. a remote one) instead of the runtime state.
+//
+// Source resources are watched directly in the source state, and they are not declared as controller inputs.
+func WithMirrorSourceState[T resource.Resource](st state.State) MirrorOption[T] {
+	return func(ctrl *MirrorController[T]) {
+		ctrl.sourceState = st
+	}
+}
+
+// NewMirrorController creates a controller which mirrors resources of the type from the source namespace to the target namespace.
+func NewMirrorController[T resource.Resource](
+	name string, resourceType resource.Type, sourceNamespace, targetNamespace resource.Namespace, opts ...MirrorOption[T],
+) *MirrorController[T] {
+	ctrl := &MirrorController[T]{
+		name:            name,
+		resourceType:    resourceType,
+		sourceNamespace: sourceNamespace,
+		targetNamespace: targetNamespace,
+	}
+
+	for _, opt := range opts {
+		opt(ctrl)
+	}
+
+	return ctrl
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *MirrorController[T]) Name() string {
+	return ctrl.name
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *MirrorController[T]) Inputs() []controller.Input {
+	if ctrl.sourceState != nil {
+		return nil
+	}
+
+	return []controller.Input{
+		{
+			Namespace: ctrl.sourceNamespace,
+			Type:      ctrl.resourceType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *MirrorController[T]) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: ctrl.resourceType,
+			Kind: controller.OutputShared,
+		},
+	}
+}
+
+// ExternalTriggers implements controller.ExternalTriggerController interface.
+func (ctrl *MirrorController[T]) ExternalTriggers() []controller.ExternalTrigger {
+	if ctrl.sourceState == nil {
+		return nil
+	}
+
+	return []controller.ExternalTrigger{
+		watchTrigger(ctrl.sourceState, resource.NewMetadata(ctrl.sourceNamespace, ctrl.resourceType, "", resource.VersionUndefined)),
+	}
+}
+
+// Run implements controller.Controller interface.
+func (ctrl *MirrorController[T]) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		if err := ctrl.reconcile(ctx, r, logger); err != nil {
+			return err
+		}
+	}
+}
+
+func (ctrl *MirrorController[T]) listSources(ctx context.Context, r controller.Runtime) (safe.List[T], error) {
+	kind := resource.NewMetadata(ctrl.sourceNamespace, ctrl.resourceType, "", resource.VersionUndefined)
+
+	if ctrl.sourceState != nil {
+		return safe.StateList[T](ctx, ctrl.sourceState, kind)
+	}
+
+	return safe.ReaderList[T](ctx, r, kind)
+}
+
+func (ctrl *MirrorController[T]) reconcile(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	sources, err := ctrl.listSources(ctx, r)
+	if err != nil {
+		return fmt.Errorf("error listing source resources: %w", err)
+	}
+
+	targets, err := safe.ReaderList[T](ctx, r, resource.NewMetadata(ctrl.targetNamespace, ctrl.resourceType, "", resource.VersionUndefined))
+	if err != nil {
+		return fmt.Errorf("error listing mirrored resources: %w", err)
+	}
+
+	existing := make(map[resource.ID]T, targets.Len())
+	foreign := map[resource.ID]string{}
+
+	for iter := safe.IteratorFromList(targets); iter.Next(); {
+		if owner := iter.Value().Metadata().Owner(); owner == ctrl.name {
+			existing[iter.Value().Metadata().ID()] = iter.Value()
+		} else {
+			foreign[iter.Value().Metadata().ID()] = owner
+		}
+	}
+
+	touched := make(map[resource.ID]struct{}, sources.Len())
+
+	for iter := safe.IteratorFromList(sources); iter.Next(); {
+		source := iter.Value()
+
+		if source.Metadata().Phase() != resource.PhaseRunning {
+			continue
+		}
+
+		touched[source.Metadata().ID()] = struct{}{}
+
+		if owner, conflict := foreign[source.Metadata().ID()]; conflict {
+			// failing the controller would only restart it over and over, mirroring is retried on the next reconcile
+			logger.Warn("mirrored resource is owned by another controller, skipping",
+				zap.String("id", source.Metadata().ID()), zap.String("owner", owner))
+
+			continue
+		}
+
+		if err = ctrl.mirror(ctx, r, source, existing); err != nil {
+			return err
+		}
+	}
+
+	for id, target := range existing {
+		if _, ok := touched[id]; ok {
+			continue
+		}
+
+		if _, err = destroyOutput(ctx, r, target.Metadata()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ctrl *MirrorController[T]) mirror(ctx context.Context, r controller.Runtime, source T, existing map[resource.ID]T) error {
+	mirrored := source.DeepCopy().(T) //nolint:forcetypeassert
+
+	md := resource.NewMetadata(ctrl.targetNamespace, ctrl.resourceType, source.Metadata().ID(), resource.VersionUndefined)
+	*md.Labels() = source.Metadata().Labels().DeepCopy()
+	*mirrored.Metadata() = md
+
+	if ctrl.transform != nil {
+		if err := ctrl.transform(mirrored); err != nil {
+			return fmt.Errorf("error transforming %s: %w", resource.String(source), err)
+		}
+	}
+
+	current, ok := existing[source.Metadata().ID()]
+
+	return writeOutput(ctx, r, mirrored, current, ok)
+}
@@ -0,0 +1,149 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generic_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/controller/generic"
+	"github.com/cosi-project/runtime/pkg/controller/runtime"
+	"github.com/cosi-project/runtime/pkg/logging"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+// runControllers runs the controllers on top of the state until the test is done.
+func runControllers(ctx context.Context, t *testing.T, st state.State, controllers ...controller.Controller) {
+	t.Helper()
+
+	rt, err := runtime.NewRuntime(st, logging.DefaultLogger())
+	require.NoError(t, err)
+
+	for _, ctrl := range controllers {
+		require.NoError(t, rt.RegisterController(ctrl))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	var eg errgroup.Group
+
+	eg.Go(func() error {
+		return rt.Run(ctx)
+	})
+
+	t.Cleanup(func() {
+		cancel()
+
+		assert.NoError(t, eg.Wait())
+	})
+}
+
+func assertValue(ctx context.Context, t *testing.T, st state.State, ptr resource.Pointer, value int) {
+	t.Helper()
+
+	_, err := st.WatchFor(ctx, ptr, state.WithEventTypes(state.Created, state.Updated), state.WithCondition(func(r resource.Resource) (bool, error) {
+		return r.(conformance.IntegerResource).Value() == value, nil //nolint:forcetypeassert
+	}))
+	require.NoError(t, err)
+}
+
+func TestMirror(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	runControllers(ctx, t, st, generic.NewMirrorController("IntMirror", conformance.IntResourceType, "source", "target",
+		generic.WithMirrorTransform(func(r *conformance.IntResource) error {
+			r.SetValue(r.Value() * 2)
+
+			return nil
+		}),
+	))
+
+	one := conformance.NewIntResource("source", "one", 1)
+	one.Metadata().Labels().Set("app", "test")
+
+	require.NoError(t, st.Create(ctx, one))
+
+	mirrored := conformance.NewIntResource("target", "one", 0)
+
+	assertValue(ctx, t, st, mirrored.Metadata(), 2)
+
+	r, err := st.Get(ctx, mirrored.Metadata())
+	require.NoError(t, err)
+
+	assert.Equal(t, "IntMirror", r.Metadata().Owner())
+
+	app, _ := r.Metadata().Labels().Get("app")
+	assert.Equal(t, "test", app)
+
+	_, err = st.UpdateWithConflicts(ctx, one.Metadata(), func(r resource.Resource) error {
+		r.(*conformance.IntResource).SetValue(5) //nolint:forcetypeassert
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assertValue(ctx, t, st, mirrored.Metadata(), 10)
+
+	require.NoError(t, st.Destroy(ctx, one.Metadata()))
+
+	_, err = st.WatchFor(ctx, mirrored.Metadata(), state.WithEventTypes(state.Destroyed))
+	require.NoError(t, err)
+}
+
+func TestMirrorOwnerConflict(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("target", "one", 100), state.WithCreateOwner("Other")))
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("source", "one", 1)))
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("source", "two", 2)))
+
+	runControllers(ctx, t, st, generic.NewMirrorController[*conformance.IntResource]("IntMirror", conformance.IntResourceType, "source", "target"))
+
+	// conflicting resource is skipped, the rest is mirrored
+	assertValue(ctx, t, st, conformance.NewIntResource("target", "two", 0).Metadata(), 2)
+
+	r, err := st.Get(ctx, conformance.NewIntResource("target", "one", 0).Metadata())
+	require.NoError(t, err)
+
+	assert.Equal(t, "Other", r.Metadata().Owner())
+	assert.Equal(t, 100, r.(*conformance.IntResource).Value()) //nolint:forcetypeassert
+}
+
+func TestMirrorSourceState(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	source := state.WrapCore(namespaced.NewState(inmem.Build))
+	target := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	require.NoError(t, source.Create(ctx, conformance.NewIntResource("default", "one", 1)))
+
+	runControllers(ctx, t, target, generic.NewMirrorController("IntMirror", conformance.IntResourceType, "default", "default",
+		generic.WithMirrorSourceState[*conformance.IntResource](source),
+	))
+
+	assertValue(ctx, t, target, conformance.NewIntResource("default", "one", 0).Metadata(), 1)
+
+	require.NoError(t, source.Create(ctx, conformance.NewIntResource("default", "two", 2)))
+
+	assertValue(ctx, t, target, conformance.NewIntResource("default", "two", 0).Metadata(), 2)
+}
@@ -0,0 +1,173 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generic
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// SettingsMergeFunc merges the settings from src into dst.
+//
+// Merge func defines the merge strategy, e.g. non-zero fields of src override dst fields.
+type SettingsMergeFunc[T resource.Resource] func(dst, src T) error
+
+// SettingsController merges layers of settings resources (e.g. defaults and overrides) into the final settings resource.
+//
+// Layers are merged in order, so the later layers take precedence; missing layers are skipped.
+// The environment overlay, if set, is applied last.
+// The final settings resource is destroyed if none of the layers exist.
+type SettingsController[T resource.Resource] struct {
+	newOutput   func() T
+	merge       SettingsMergeFunc[T]
+	environment func(T) error
+	name        string
+	layers      []resource.Pointer
+}
+
+// SettingsOption configures SettingsController.
+type SettingsOption[T resource.Resource] func(ctrl *SettingsController[T])
+
+// WithSettingsEnvironment applies the environment-derived values on top of the merged layers.
+func WithSettingsEnvironment[T resource.Resource](environment func(T) error) SettingsOption[T] {
+	return func(ctrl *SettingsController[T]) {
+		ctrl.environment = environment
+	}
+}
+
+// NewSettingsController creates a controller which merges the layers into the output.
+//
+// Layers should have the same type as the output, newOutput returns the empty output resource with namespace and ID set.
+// Typical layers are the defaults resource followed by the override resource.
+func NewSettingsController[T resource.Resource](
+	name string, layers []resource.Pointer, newOutput func() T, merge SettingsMergeFunc[T], opts ...SettingsOption[T],
+) *SettingsController[T] {
+	ctrl := &SettingsController[T]{
+		name:      name,
+		layers:    layers,
+		newOutput: newOutput,
+		merge:     merge,
+	}
+
+	for _, opt := range opts {
+		opt(ctrl)
+	}
+
+	return ctrl
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *SettingsController[T]) Name() string {
+	return ctrl.name
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *SettingsController[T]) Inputs() []controller.Input {
+	inputs := make([]controller.Input, 0, len(ctrl.layers))
+
+	for _, layer := range ctrl.layers {
+		id := layer.ID()
+
+		inputs = append(inputs, controller.Input{
+			Namespace: layer.Namespace(),
+			Type:      layer.Type(),
+			ID:        &id,
+			Kind:      controller.InputWeak,
+		})
+	}
+
+	return inputs
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *SettingsController[T]) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: ctrl.newOutput().Metadata().Type(),
+			Kind: controller.OutputShared,
+		},
+	}
+}
+
+// Run implements controller.Controller interface.
+func (ctrl *SettingsController[T]) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		if err := ctrl.reconcile(ctx, r, logger); err != nil {
+			return err
+		}
+	}
+}
+
+func (ctrl *SettingsController[T]) reconcile(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	output := ctrl.newOutput()
+	found := false
+
+	for _, layer := range ctrl.layers {
+		res, err := safe.ReaderGet[T](ctx, r, layer)
+		if err != nil {
+			if state.IsNotFoundError(err) {
+				continue
+			}
+
+			return fmt.Errorf("error getting settings layer: %w", err)
+		}
+
+		if res.Metadata().Phase() != resource.PhaseRunning {
+			continue
+		}
+
+		found = true
+
+		if err = ctrl.merge(output, res); err != nil {
+			return fmt.Errorf("error merging %s: %w", resource.String(res), err)
+		}
+	}
+
+	current, err := safe.ReaderGet[T](ctx, r, output.Metadata())
+	if err != nil && !state.IsNotFoundError(err) {
+		return fmt.Errorf("error getting settings: %w", err)
+	}
+
+	exists := err == nil
+
+	if exists && current.Metadata().Owner() != ctrl.name {
+		// failing the controller would only restart it over and over, the output is retried on the next reconcile
+		logger.Warn("output is owned by another controller, skipping",
+			zap.String("id", current.Metadata().ID()), zap.String("owner", current.Metadata().Owner()))
+
+		return nil
+	}
+
+	if !found {
+		if !exists {
+			return nil
+		}
+
+		_, err = destroyOutput(ctx, r, current.Metadata())
+
+		return err
+	}
+
+	if ctrl.environment != nil {
+		if err = ctrl.environment(output); err != nil {
+			return fmt.Errorf("error applying environment: %w", err)
+		}
+	}
+
+	return writeOutput(ctx, r, output, current, exists)
+}
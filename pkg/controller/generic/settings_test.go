@@ -0,0 +1,76 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generic_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/controller/generic"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestSettings(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	defaults := conformance.NewIntResource("config", "defaults", 1)
+	override := conformance.NewIntResource("config", "override", 5)
+
+	runControllers(ctx, t, st, generic.NewSettingsController("IntSettings",
+		[]resource.Pointer{defaults.Metadata(), override.Metadata()},
+		func() *conformance.IntResource {
+			return conformance.NewIntResource("config", "final", 0)
+		},
+		func(dst, src *conformance.IntResource) error {
+			// non-zero values override
+			if src.Value() != 0 {
+				dst.SetValue(src.Value())
+			}
+
+			return nil
+		},
+		generic.WithSettingsEnvironment(func(r *conformance.IntResource) error {
+			r.Metadata().Labels().Set("env", "test")
+
+			return nil
+		}),
+	))
+
+	final := conformance.NewIntResource("config", "final", 0).Metadata()
+
+	require.NoError(t, st.Create(ctx, defaults))
+
+	assertValue(ctx, t, st, final, 1)
+
+	require.NoError(t, st.Create(ctx, override))
+
+	assertValue(ctx, t, st, final, 5)
+
+	r, err := st.Get(ctx, final)
+	require.NoError(t, err)
+
+	env, _ := r.Metadata().Labels().Get("env")
+	assert.Equal(t, "test", env)
+
+	require.NoError(t, st.Destroy(ctx, override.Metadata()))
+
+	assertValue(ctx, t, st, final, 1)
+
+	require.NoError(t, st.Destroy(ctx, defaults.Metadata()))
+
+	_, err = st.WatchFor(ctx, final, state.WithEventTypes(state.Destroyed))
+	require.NoError(t, err)
+}
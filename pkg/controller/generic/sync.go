@@ -0,0 +1,302 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generic
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// SyncConflictPolicy defines which copy wins when a resource with the same ID originates on both sides.
+type SyncConflictPolicy int
+
+// Sync conflict policies.
+const (
+	// SyncPreferLocal keeps the local resource, and overwrites the remote one.
+	SyncPreferLocal SyncConflictPolicy = iota
+	// SyncPreferRemote keeps the remote resource, and overwrites the local one.
+	SyncPreferRemote
+	// SyncPreferNewer keeps the resource which was updated last.
+	SyncPreferNewer
+)
+
+// SyncController synchronizes resources of a type between the local state and a remote state in both directions.
+//
+// The remote state is usually a protobuf state client (see pkg/state/protobuf/client) wrapped with state.WrapCore.
+//
+// Resources created on one side are copied to the other side, and the copies are owned by the controller.
+// Copies are updated and destroyed following the original resource.
+// If a resource with the same ID originates on both sides, the conflict policy decides which one wins,
+// and the losing original gets the labels and spec of the winning one if it is not owned by any controller
+// (e.g. it was created through the API). Originals owned by other controllers are never overwritten,
+// the conflict is reported instead, and both sides keep their resources.
+// Local copies are written through the controller runtime, while the unowned local originals which lose the conflict
+// are overwritten directly in the local state.
+type SyncController[T resource.Resource] struct {
+	local           state.State
+	remote          state.State
+	name            string
+	resourceType    resource.Type
+	localNamespace  resource.Namespace
+	remoteNamespace resource.Namespace
+	policy          SyncConflictPolicy
+}
+
+// NewSyncController creates a controller which synchronizes resources of the type between local and remote namespaces.
+func NewSyncController[T resource.Resource](
+	name string, resourceType resource.Type,
+	local state.State, localNamespace resource.Namespace, remote state.State, remoteNamespace resource.Namespace,
+	policy SyncConflictPolicy,
+) *SyncController[T] {
+	return &SyncController[T]{
+		name:            name,
+		resourceType:    resourceType,
+		local:           local,
+		localNamespace:  localNamespace,
+		remote:          remote,
+		remoteNamespace: remoteNamespace,
+		policy:          policy,
+	}
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *SyncController[T]) Name() string {
+	return ctrl.name
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *SyncController[T]) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: ctrl.localNamespace,
+			Type:      ctrl.resourceType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *SyncController[T]) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: ctrl.resourceType,
+			Kind: controller.OutputShared,
+		},
+	}
+}
+
+// ExternalTriggers implements controller.ExternalTriggerController interface.
+func (ctrl *SyncController[T]) ExternalTriggers() []controller.ExternalTrigger {
+	return []controller.ExternalTrigger{
+		watchTrigger(ctrl.remote, resource.NewMetadata(ctrl.remoteNamespace, ctrl.resourceType, "", resource.VersionUndefined)),
+	}
+}
+
+// Run implements controller.Controller interface.
+func (ctrl *SyncController[T]) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		if err := ctrl.reconcile(ctx, r, logger); err != nil {
+			return err
+		}
+	}
+}
+
+func (ctrl *SyncController[T]) reconcile(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	localList, err := safe.ReaderList[T](ctx, r, resource.NewMetadata(ctrl.localNamespace, ctrl.resourceType, "", resource.VersionUndefined))
+	if err != nil {
+		return fmt.Errorf("error listing local resources: %w", err)
+	}
+
+	remoteList, err := safe.StateList[T](ctx, ctrl.remote, resource.NewMetadata(ctrl.remoteNamespace, ctrl.resourceType, "", resource.VersionUndefined))
+	if err != nil {
+		return fmt.Errorf("error listing remote resources: %w", err)
+	}
+
+	local := make(map[resource.ID]T, localList.Len())
+
+	for iter := safe.IteratorFromList(localList); iter.Next(); {
+		local[iter.Value().Metadata().ID()] = iter.Value()
+	}
+
+	remote := make(map[resource.ID]T, remoteList.Len())
+
+	for iter := safe.IteratorFromList(remoteList); iter.Next(); {
+		remote[iter.Value().Metadata().ID()] = iter.Value()
+	}
+
+	for id, l := range local {
+		rm, exists := remote[id]
+
+		switch {
+		case l.Metadata().Owner() == ctrl.name:
+			// local copy of the remote resource
+			if !exists || rm.Metadata().Owner() == ctrl.name || rm.Metadata().Phase() != resource.PhaseRunning {
+				if _, err = destroyOutput(ctx, r, l.Metadata()); err != nil {
+					return err
+				}
+			}
+		case l.Metadata().Phase() != resource.PhaseRunning:
+			// local original is going away, its remote copy is cleaned up below
+		case !exists || rm.Metadata().Owner() == ctrl.name:
+			if err = ctrl.push(ctx, l, rm, exists); err != nil {
+				return err
+			}
+		case ctrl.localWins(l, rm):
+			if rm.Metadata().Owner() != "" {
+				ctrl.reportConflict(logger, l, rm)
+
+				continue
+			}
+
+			if err = ctrl.push(ctx, l, rm, exists); err != nil {
+				return err
+			}
+		case l.Metadata().Owner() != "":
+			ctrl.reportConflict(logger, l, rm)
+		default:
+			logger.Debug("resource originates on both sides, overwriting local resource", zap.String("id", id))
+
+			if err = ctrl.overwrite(ctx, ctrl.local, rm, l); err != nil {
+				return fmt.Errorf("error updating local %s: %w", resource.String(l), err)
+			}
+		}
+	}
+
+	for id, rm := range remote {
+		l, exists := local[id]
+
+		switch {
+		case rm.Metadata().Owner() == ctrl.name:
+			// remote copy of the local resource
+			if !exists || l.Metadata().Owner() == ctrl.name || l.Metadata().Phase() != resource.PhaseRunning {
+				if err = ctrl.destroyRemote(ctx, rm); err != nil {
+					return err
+				}
+			}
+		case rm.Metadata().Phase() != resource.PhaseRunning:
+			// remote original is going away, its local copy is cleaned up above
+		case !exists || l.Metadata().Owner() == ctrl.name:
+			if err = ctrl.pull(ctx, r, rm, l, exists); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// localWins resolves the conflict between the local and remote resources which both originate on their sides.
+func (ctrl *SyncController[T]) localWins(l, rm T) bool {
+	switch ctrl.policy {
+	case SyncPreferLocal:
+		return true
+	case SyncPreferRemote:
+		return false
+	case SyncPreferNewer:
+		return !l.Metadata().Updated().Before(rm.Metadata().Updated())
+	}
+
+	return true
+}
+
+// reportConflict logs the conflict which can't be resolved, as the losing original is owned by another controller.
+func (ctrl *SyncController[T]) reportConflict(logger *zap.Logger, l, rm T) {
+	logger.Warn("resource originates on both sides, and the losing side is owned by a controller",
+		zap.String("id", l.Metadata().ID()),
+		zap.String("local_owner", l.Metadata().Owner()),
+		zap.String("remote_owner", rm.Metadata().Owner()),
+	)
+}
+
+// copyTo returns a copy of the resource in the namespace, keeping only labels and spec.
+func (ctrl *SyncController[T]) copyTo(r T, ns resource.Namespace) T { //nolint:ireturn
+	copied := r.DeepCopy().(T) //nolint:forcetypeassert
+
+	md := resource.NewMetadata(ns, ctrl.resourceType, r.Metadata().ID(), resource.VersionUndefined)
+	*md.Labels() = r.Metadata().Labels().DeepCopy()
+	*copied.Metadata() = md
+
+	return copied
+}
+
+func (ctrl *SyncController[T]) pull(ctx context.Context, r controller.Runtime, rm, l T, exists bool) error {
+	return writeOutput(ctx, r, ctrl.copyTo(rm, ctrl.localNamespace), l, exists)
+}
+
+func (ctrl *SyncController[T]) push(ctx context.Context, l, rm T, exists bool) error {
+	if !exists {
+		desired := ctrl.copyTo(l, ctrl.remoteNamespace)
+
+		if err := ctrl.remote.Create(ctx, desired, state.WithCreateOwner(ctrl.name)); err != nil {
+			return fmt.Errorf("error creating remote %s: %w", resource.String(desired), err)
+		}
+
+		return nil
+	}
+
+	// remote resource is either a copy, or an unowned original which lost the conflict
+	if err := ctrl.overwrite(ctx, ctrl.remote, l, rm); err != nil {
+		return fmt.Errorf("error updating remote %s: %w", resource.String(rm), err)
+	}
+
+	return nil
+}
+
+// overwrite updates the current resource in the state with the labels and spec of the winner.
+//
+// Current resource is either a copy owned by the controller, or an original which is not owned by any controller.
+func (ctrl *SyncController[T]) overwrite(ctx context.Context, st state.State, winner, current T) error {
+	if current.Metadata().Phase() != resource.PhaseRunning {
+		return nil
+	}
+
+	desired := ctrl.copyTo(winner, current.Metadata().Namespace())
+
+	labels := *desired.Metadata().Labels()
+	*desired.Metadata() = current.Metadata().Copy()
+	*desired.Metadata().Labels() = labels
+
+	if resource.Equal(current, desired) {
+		return nil
+	}
+
+	desired.Metadata().BumpVersion()
+
+	return st.Update(ctx, current.Metadata().Version(), desired, state.WithUpdateOwner(current.Metadata().Owner()))
+}
+
+func (ctrl *SyncController[T]) destroyRemote(ctx context.Context, rm T) error {
+	ready, err := ctrl.remote.Teardown(ctx, rm.Metadata(), state.WithTeardownOwner(ctrl.name))
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return nil
+		}
+
+		return fmt.Errorf("error tearing down remote %s: %w", resource.String(rm), err)
+	}
+
+	if !ready {
+		return nil
+	}
+
+	if err = ctrl.remote.Destroy(ctx, rm.Metadata(), state.WithDestroyOwner(ctrl.name)); err != nil && !state.IsNotFoundError(err) {
+		return fmt.Errorf("error destroying remote %s: %w", resource.String(rm), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generic_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/controller/generic"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestSync(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	local := state.WrapCore(namespaced.NewState(inmem.Build))
+	remote := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	require.NoError(t, local.Create(ctx, conformance.NewIntResource("local", "conflict", 1)))
+	require.NoError(t, remote.Create(ctx, conformance.NewIntResource("remote", "conflict", 2)))
+
+	runControllers(ctx, t, local, generic.NewSyncController[*conformance.IntResource]("IntSync", conformance.IntResourceType,
+		local, "local", remote, "remote", generic.SyncPreferLocal))
+
+	// conflicting resource is overwritten on the remote side
+	assertValue(ctx, t, remote, conformance.NewIntResource("remote", "conflict", 0).Metadata(), 1)
+
+	// local to remote
+	require.NoError(t, local.Create(ctx, conformance.NewIntResource("local", "pushed", 3)))
+
+	pushed := conformance.NewIntResource("remote", "pushed", 0).Metadata()
+
+	assertValue(ctx, t, remote, pushed, 3)
+
+	r, err := remote.Get(ctx, pushed)
+	require.NoError(t, err)
+	assert.Equal(t, "IntSync", r.Metadata().Owner())
+
+	// remote to local
+	pulledRemote := conformance.NewIntResource("remote", "pulled", 4)
+
+	require.NoError(t, remote.Create(ctx, pulledRemote))
+
+	pulled := conformance.NewIntResource("local", "pulled", 0).Metadata()
+
+	assertValue(ctx, t, local, pulled, 4)
+
+	_, err = remote.UpdateWithConflicts(ctx, pulledRemote.Metadata(), func(r resource.Resource) error {
+		r.(*conformance.IntResource).SetValue(5) //nolint:forcetypeassert
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assertValue(ctx, t, local, pulled, 5)
+
+	// deletions are propagated both ways
+	require.NoError(t, remote.Destroy(ctx, pulledRemote.Metadata()))
+
+	_, err = local.WatchFor(ctx, pulled, state.WithEventTypes(state.Destroyed))
+	require.NoError(t, err)
+
+	require.NoError(t, local.Destroy(ctx, conformance.NewIntResource("local", "pushed", 0).Metadata()))
+
+	_, err = remote.WatchFor(ctx, pushed, state.WithEventTypes(state.Destroyed))
+	require.NoError(t, err)
+}
+
+func TestSyncConflictPolicy(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		policy      generic.SyncConflictPolicy
+		localNewer  bool
+		expectLocal bool
+	}{
+		{
+			name:        "prefer remote",
+			policy:      generic.SyncPreferRemote,
+			localNewer:  true,
+			expectLocal: false,
+		},
+		{
+			name:        "prefer newer remote",
+			policy:      generic.SyncPreferNewer,
+			localNewer:  false,
+			expectLocal: false,
+		},
+		{
+			name:        "prefer newer local",
+			policy:      generic.SyncPreferNewer,
+			localNewer:  true,
+			expectLocal: true,
+		},
+	} {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+
+			local := state.WrapCore(namespaced.NewState(inmem.Build))
+			remote := state.WrapCore(namespaced.NewState(inmem.Build))
+
+			newLocal := func() *conformance.IntResource {
+				r := conformance.NewIntResource("local", "conflict", 1)
+				r.Metadata().Labels().Set("side", "local")
+
+				return r
+			}
+
+			newRemote := func() *conformance.IntResource {
+				r := conformance.NewIntResource("remote", "conflict", 2)
+				r.Metadata().Labels().Set("side", "remote")
+
+				return r
+			}
+
+			var localRes, remoteRes *conformance.IntResource
+
+			// updated timestamp is set when the resource is initialized
+			if test.localNewer {
+				remoteRes = newRemote()
+				time.Sleep(10 * time.Millisecond)
+				localRes = newLocal()
+			} else {
+				localRes = newLocal()
+				time.Sleep(10 * time.Millisecond)
+				remoteRes = newRemote()
+			}
+
+			require.NoError(t, local.Create(ctx, localRes))
+			require.NoError(t, remote.Create(ctx, remoteRes))
+
+			runControllers(ctx, t, local, generic.NewSyncController[*conformance.IntResource]("IntSync", conformance.IntResourceType,
+				local, "local", remote, "remote", test.policy))
+
+			expected, expectedSide := 2, "remote"
+			if test.expectLocal {
+				expected, expectedSide = 1, "local"
+			}
+
+			assertValue(ctx, t, local, localRes.Metadata(), expected)
+			assertValue(ctx, t, remote, remoteRes.Metadata(), expected)
+
+			// the stored originals keep their owners, and get the labels of the winner
+			for st, ptr := range map[state.State]resource.Pointer{local: localRes.Metadata(), remote: remoteRes.Metadata()} {
+				r, err := st.Get(ctx, ptr)
+				require.NoError(t, err)
+
+				assert.Equal(t, expected, r.(*conformance.IntResource).Value()) //nolint:forcetypeassert
+				assert.Empty(t, r.Metadata().Owner())
+
+				side, _ := r.Metadata().Labels().Get("side")
+				assert.Equal(t, expectedSide, side)
+			}
+		})
+	}
+}
+
+func TestSyncConflictOwned(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	local := state.WrapCore(namespaced.NewState(inmem.Build))
+	remote := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	// losing local original is owned by another controller
+	require.NoError(t, local.Create(ctx, conformance.NewIntResource("local", "conflict", 1), state.WithCreateOwner("Other")))
+	require.NoError(t, remote.Create(ctx, conformance.NewIntResource("remote", "conflict", 2)))
+
+	runControllers(ctx, t, local, generic.NewSyncController[*conformance.IntResource]("IntSync", conformance.IntResourceType,
+		local, "local", remote, "remote", generic.SyncPreferRemote))
+
+	// wait for the controller to reconcile
+	require.NoError(t, local.Create(ctx, conformance.NewIntResource("local", "pushed", 3)))
+
+	assertValue(ctx, t, remote, conformance.NewIntResource("remote", "pushed", 0).Metadata(), 3)
+
+	// conflict is reported, and both sides keep their resources
+	assertValue(ctx, t, local, conformance.NewIntResource("local", "conflict", 0).Metadata(), 1)
+	assertValue(ctx, t, remote, conformance.NewIntResource("remote", "conflict", 0).Metadata(), 2)
+}
@@ -0,0 +1,92 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+// MergedOwnerPrefix is the prefix of the common owner of OutputMerged resources.
+const MergedOwnerPrefix = "merged:"
+
+// MergedOwner returns the owner of the OutputMerged resources of the type.
+//
+// Merged resources are modified by all controllers which declare them as merged outputs,
+// so they are owned by the resource type instead of a single controller.
+func MergedOwner(resourceType resource.Type) string {
+	return MergedOwnerPrefix + resourceType
+}
+
+// IsMergedOwner checks whether the owner is the common owner of OutputMerged resources, and returns the resource type.
+func IsMergedOwner(owner string) (resource.Type, bool) {
+	if !strings.HasPrefix(owner, MergedOwnerPrefix) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(owner, MergedOwnerPrefix), true
+}
+
+// MergeStrategy defines how multiple controllers contribute to the same resources of OutputMerged outputs.
+type MergeStrategy interface {
+	// CheckMerge returns an error if the change of the resource by the controller violates the merge contract.
+	//
+	// Old is nil when the resource is created, new is nil when the resource is torn down or destroyed.
+	CheckMerge(controllerName string, old, new resource.Resource) error
+}
+
+// MergeStrategyFunc is an adapter to allow the use of ordinary functions as merge strategies.
+type MergeStrategyFunc func(controllerName string, old, new resource.Resource) error
+
+// CheckMerge implements MergeStrategy interface.
+func (f MergeStrategyFunc) CheckMerge(controllerName string, old, new resource.Resource) error {
+	return f(controllerName, old, new)
+}
+
+// MergeLabelSubset returns a MergeStrategy which lets each controller manage only the labels
+// with the key prefix "<controller name>/" on the merged resources.
+//
+// Any controller might create the resource, but the rest of the resource (including the spec) can't be changed
+// afterwards, and merged resources can't be destroyed by the controllers.
+func MergeLabelSubset() MergeStrategy { //nolint:ireturn
+	return MergeStrategyFunc(checkLabelSubset)
+}
+
+func checkLabelSubset(controllerName string, old, new resource.Resource) error {
+	prefix := controllerName + "/"
+
+	switch {
+	case new == nil:
+		return fmt.Errorf("controller %q can't destroy merged resource %s", controllerName, resource.String(old))
+	case old == nil:
+		for key := range new.Metadata().Labels().Raw() {
+			if !strings.HasPrefix(key, prefix) {
+				return fmt.Errorf("controller %q can't set label %q on merged resource %s", controllerName, key, resource.String(new))
+			}
+		}
+
+		return nil
+	}
+
+	oldCopy, newCopy := old.DeepCopy(), new.DeepCopy()
+
+	for _, r := range []resource.Resource{oldCopy, newCopy} {
+		for key := range r.Metadata().Labels().Raw() {
+			if strings.HasPrefix(key, prefix) {
+				r.Metadata().Labels().Delete(key)
+			}
+		}
+	}
+
+	newCopy.Metadata().SetVersion(oldCopy.Metadata().Version())
+
+	if !resource.Equal(oldCopy, newCopy) {
+		return fmt.Errorf("controller %q can only change labels with prefix %q on merged resource %s", controllerName, prefix, resource.String(new))
+	}
+
+	return nil
+}
@@ -82,7 +82,7 @@ func convertInputs(inputs []controller.Input) []*v1alpha1.ControllerInput {
 	return protoInputs
 }
 
-func convertOutputs(outputs []controller.Output) []*v1alpha1.ControllerOutput {
+func convertOutputs(outputs []controller.Output) ([]*v1alpha1.ControllerOutput, error) {
 	protoOutputs := make([]*v1alpha1.ControllerOutput, len(outputs))
 
 	for i := range protoOutputs {
@@ -95,18 +95,25 @@ func convertOutputs(outputs []controller.Output) []*v1alpha1.ControllerOutput {
 			protoOutputs[i].Kind = v1alpha1.ControllerOutputKind_EXCLUSIVE
 		case controller.OutputShared:
 			protoOutputs[i].Kind = v1alpha1.ControllerOutputKind_SHARED
+		case controller.OutputMerged:
+			return nil, fmt.Errorf("merged output %q is not supported over gRPC", outputs[i].Type)
 		}
 	}
 
-	return protoOutputs
+	return protoOutputs, nil
 }
 
 // RegisterController registers new controller.
 func (adapter *Adapter) RegisterController(ctrl controller.Controller) error {
+	outputs, err := convertOutputs(ctrl.Outputs())
+	if err != nil {
+		return err
+	}
+
 	resp, err := adapter.client.RegisterController(context.Background(), &v1alpha1.RegisterControllerRequest{
 		ControllerName: ctrl.Name(),
 		Inputs:         convertInputs(ctrl.Inputs()),
-		Outputs:        convertOutputs(ctrl.Outputs()),
+		Outputs:        outputs,
 	})
 	if err != nil {
 		return err
@@ -564,19 +571,62 @@ func (ctrlAdapter *controllerAdapter) Update(ctx context.Context, curVersion res
 	return nil
 }
 
+func (ctrlAdapter *controllerAdapter) UpdateStatus(ctx context.Context, curVersion resource.Version, newResource resource.StatusResource) error {
+	protoR, err := protobuf.FromResource(newResource)
+	if err != nil {
+		return err
+	}
+
+	marshaled, err := protoR.Marshal()
+	if err != nil {
+		return err
+	}
+
+	_, err = ctrlAdapter.adapter.client.UpdateStatus(ctx, &v1alpha1.RuntimeUpdateStatusRequest{
+		ControllerToken: ctrlAdapter.token,
+
+		CurrentVersion: curVersion.String(),
+		NewResource:    marshaled,
+	})
+
+	if err != nil {
+		switch status.Code(err) { //nolint:exhaustive
+		case codes.NotFound:
+			return eNotFound{err}
+		case codes.FailedPrecondition:
+			return eConflict{err}
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (ctrlAdapter *controllerAdapter) Modify(ctx context.Context, emptyResource resource.Resource, updateFunc func(resource.Resource) error) error {
+	_, err := ctrlAdapter.ModifyWithResult(ctx, emptyResource, updateFunc)
+
+	return err
+}
+
+func (ctrlAdapter *controllerAdapter) ModifyWithResult(ctx context.Context, emptyResource resource.Resource, updateFunc func(resource.Resource) error) (resource.Resource, error) { //nolint:ireturn
 	_, err := ctrlAdapter.Get(ctx, emptyResource.Metadata())
 	if err != nil {
 		if state.IsNotFoundError(err) {
 			err = updateFunc(emptyResource)
 			if err != nil {
-				return err
+				return nil, err
+			}
+
+			if err = ctrlAdapter.Create(ctx, emptyResource); err != nil {
+				return nil, err
 			}
 
-			return ctrlAdapter.Create(ctx, emptyResource)
+			// owner is assigned by the server, so fetch the created resource
+			return ctrlAdapter.Get(ctx, emptyResource.Metadata())
 		}
 
-		return fmt.Errorf("error querying current object state: %w", err)
+		return nil, fmt.Errorf("error querying current object state: %w", err)
 	}
 
 	resourcePointer := emptyResource.Metadata()
@@ -584,7 +634,7 @@ func (ctrlAdapter *controllerAdapter) Modify(ctx context.Context, emptyResource
 	for {
 		current, err := ctrlAdapter.Get(ctx, resourcePointer)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		curVersion := current.Metadata().Version()
@@ -592,25 +642,25 @@ func (ctrlAdapter *controllerAdapter) Modify(ctx context.Context, emptyResource
 		newResource := current.DeepCopy()
 
 		if err = updateFunc(newResource); err != nil {
-			return err
+			return nil, err
 		}
 
-		if resource.Equal(current, newResource) {
-			return nil
+		if resource.ContentEqual(current, newResource) {
+			return current, nil
 		}
 
 		newResource.Metadata().BumpVersion()
 
 		err = ctrlAdapter.Update(ctx, curVersion, newResource)
 		if err == nil {
-			return nil
+			return newResource, nil
 		}
 
 		if state.IsConflictError(err) {
 			continue
 		}
 
-		return err
+		return nil, err
 	}
 }
 
@@ -435,6 +435,52 @@ func (runtime *Runtime) Update(ctx context.Context, req *v1alpha1.RuntimeUpdateR
 	return &v1alpha1.RuntimeUpdateResponse{}, nil
 }
 
+// UpdateStatus updates the status of a resource.
+func (runtime *Runtime) UpdateStatus(ctx context.Context, req *v1alpha1.RuntimeUpdateStatusRequest) (*v1alpha1.RuntimeUpdateStatusResponse, error) {
+	bridge, err := runtime.getBridge(ctx, req.ControllerToken)
+	if err != nil {
+		return nil, err
+	}
+
+	protoR, err := protobuf.Unmarshal(req.NewResource)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := protobuf.UnmarshalResource(protoR)
+	if err != nil {
+		return nil, err
+	}
+
+	statusResource, ok := r.(resource.StatusResource)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "resource %s doesn't have a status", r.Metadata())
+	}
+
+	currentVersion, err := resource.ParseVersion(req.CurrentVersion)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	statusWriter, ok := bridge.adapter.(controller.StatusWriter)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "controller runtime doesn't support status updates")
+	}
+
+	err = statusWriter.UpdateStatus(ctx, currentVersion, statusResource)
+
+	switch {
+	case state.IsNotFoundError(err):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case state.IsConflictError(err):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	return &v1alpha1.RuntimeUpdateStatusResponse{}, nil
+}
+
 // Teardown a resource.
 func (runtime *Runtime) Teardown(ctx context.Context, req *v1alpha1.RuntimeTeardownRequest) (*v1alpha1.RuntimeTeardownResponse, error) {
 	bridge, err := runtime.getBridge(ctx, req.ControllerToken)
@@ -6,6 +6,7 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	"github.com/cosi-project/runtime/pkg/resource"
 	"github.com/cosi-project/runtime/pkg/state"
@@ -48,11 +49,16 @@ const (
 // some of its outputs to be ready to be destroyed. Controller will be notified
 // when the resource enters "teardown" phase and has no finalizers attached.
 // Resources are filtered to be owned by the controller.
+//
+// If SampleInterval is set, the input is "sampled": the controller is woken up
+// at most once per SampleInterval on changes to the input, no matter how many events arrived.
+// This is useful for inputs which change often, e.g. statistics.
 type Input struct {
-	ID        *resource.ID
-	Namespace resource.Namespace
-	Type      resource.Type
-	Kind      InputKind
+	ID             *resource.ID
+	Namespace      resource.Namespace
+	Type           resource.Type
+	Kind           InputKind
+	SampleInterval time.Duration
 }
 
 // OutputKind for outputs.
@@ -62,14 +68,19 @@ type OutputKind = int
 const (
 	OutputExclusive OutputKind = iota
 	OutputShared
+	OutputMerged
 )
 
 // Output of the controller.
 //
 // Controller can only modify resources which are declared as outputs.
+//
+// Merged outputs are modified by multiple controllers at the same time according to the Merge strategy,
+// all controllers should declare the output as merged.
 type Output struct {
-	Type resource.Type
-	Kind OutputKind
+	Merge MergeStrategy
+	Type  resource.Type
+	Kind  OutputKind
 }
 
 // Reader provides read-only access to the state.
@@ -92,3 +103,18 @@ type Writer interface {
 	AddFinalizer(context.Context, resource.Pointer, ...resource.Finalizer) error
 	RemoveFinalizer(context.Context, resource.Pointer, ...resource.Finalizer) error
 }
+
+// StatusWriter is an optional interface of the Writer which updates the status of the resources.
+//
+// The status can be updated both on the outputs and on the inputs of the controller (see resource.StatusResource).
+// Writers provided by the runtime implement it.
+type StatusWriter interface {
+	UpdateStatus(context.Context, resource.Version, resource.StatusResource) error
+}
+
+// ResultWriter is an optional interface of the Writer which returns the resource as it was written by Modify.
+//
+// Writers provided by the runtime implement it, see safe.WriterModifyWithResult.
+type ResultWriter interface {
+	ModifyWithResult(context.Context, resource.Resource, func(resource.Resource) error) (resource.Resource, error)
+}
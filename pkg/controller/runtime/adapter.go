@@ -10,13 +10,16 @@ import (
 	"fmt"
 	"runtime/debug"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/siderolabs/go-pointer"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
+	"github.com/cosi-project/runtime/pkg/clock"
 	"github.com/cosi-project/runtime/pkg/controller"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/dependency"
 	"github.com/cosi-project/runtime/pkg/logging"
@@ -31,33 +34,87 @@ type adapter struct {
 	ctrl controller.Controller
 	ch   chan controller.ReconcileEvent
 
-	backoff *backoff.ExponentialBackOff
+	backoffMu sync.Mutex
+	backoff   *backoff.ExponentialBackOff
+
+	logLevel zap.AtomicLevel
 
 	watchFilters map[watchKey]watchFilter
+	samplers     map[watchKey]*sampler
 
 	name string
 
 	inputs  []controller.Input
 	outputs []controller.Output
 
-	// watchFilterMu protects watchFilters
+	scope  resource.Namespace
+	scoped bool
+
+	faults *faultInjector
+
+	wakeups wakeupCounters
+
+	watchdog watchdog
+
+	// step is set in the stepping mode
+	step *stepState
+
+	bootstrapOnce sync.Once
+	bootstrapped  chan struct{}
+
+	// watchFilterMu protects watchFilters and samplers
 	watchFilterMu sync.Mutex
+
+	// rateLimitMu protects rateLimit
+	rateLimitMu sync.Mutex
+	rateLimit   sampler
+
+	// pauseMu protects paused and pending
+	pauseMu sync.Mutex
+	paused  bool
+	pending bool
 }
 
 type watchFilter func(*resource.Metadata) bool
 
+// sampler limits the rate of reconciles triggered by a sampled input, or by any source.
+type sampler struct {
+	last     time.Time
+	timer    clock.Timer
+	interval time.Duration
+}
+
 // EventCh implements controller.Runtime interface.
 func (adapter *adapter) EventCh() <-chan controller.ReconcileEvent {
+	adapter.markBootstrapped()
+
+	if adapter.step != nil {
+		// in the stepping mode, reconcile events are delivered only by Step
+		adapter.watchdog.wait(false)
+
+		return adapter.step.eventCh()
+	}
+
+	adapter.watchdog.wait(len(adapter.ch) > 0)
+
 	return adapter.ch
 }
 
 // QueueReconcile implements controller.Runtime interface.
 func (adapter *adapter) QueueReconcile() {
+	adapter.wakeups.inc(wakeupKey{Source: WakeupQueue})
+
 	adapter.triggerReconcile()
 }
 
 // UpdateDependencies implements controller.Runtime interface.
 func (adapter *adapter) UpdateInputs(deps []controller.Input) error {
+	for _, dep := range deps {
+		if err := adapter.checkScope(dep.Namespace); err != nil {
+			return err
+		}
+	}
+
 	sort.Slice(deps, func(i, j int) bool {
 		return dependency.Less(&deps[i], &deps[j])
 	})
@@ -128,6 +185,8 @@ func (adapter *adapter) UpdateInputs(deps []controller.Input) error {
 
 	adapter.inputs = append([]controller.Input(nil), deps...)
 
+	adapter.updateSamplers(deps)
+
 	return nil
 }
 
@@ -141,7 +200,73 @@ func (adapter *adapter) isOutput(resourceType resource.Type) bool {
 	return false
 }
 
+// mergeStrategy returns the merge strategy if the resource type is a merged output of the controller.
+func (adapter *adapter) mergeStrategy(resourceType resource.Type) controller.MergeStrategy { //nolint:ireturn
+	for _, output := range adapter.outputs {
+		if output.Type == resourceType && output.Kind == controller.OutputMerged {
+			return output.Merge
+		}
+	}
+
+	return nil
+}
+
+// outputOwner returns the owner of the output resources.
+//
+// Merged outputs are owned by all controllers which declare them, so they have a common owner.
+func (adapter *adapter) outputOwner(resourceType resource.Type) string {
+	if adapter.mergeStrategy(resourceType) != nil {
+		return controller.MergedOwner(resourceType)
+	}
+
+	return adapter.name
+}
+
+// checkMerge verifies that the change of the merged output follows the merge strategy.
+func (adapter *adapter) checkMerge(ctx context.Context, resourcePointer resource.Pointer, newResource resource.Resource, exists bool) error {
+	strategy := adapter.mergeStrategy(resourcePointer.Type())
+	if strategy == nil {
+		return nil
+	}
+
+	var old resource.Resource
+
+	if exists {
+		var err error
+
+		old, err = adapter.runtime.state.Get(ctx, resourcePointer)
+		if err != nil {
+			return err
+		}
+	}
+
+	return strategy.CheckMerge(adapter.name, old, newResource)
+}
+
+func (adapter *adapter) checkScope(resourceNamespace resource.Namespace) error {
+	if !adapter.scoped || inScope(resourceNamespace, adapter.scope) {
+		return nil
+	}
+
+	return fmt.Errorf("namespace %q is out of scope %q for controller %q", resourceNamespace, adapter.scope, adapter.name)
+}
+
+// inScope checks whether the namespace is the scope itself or nested under it.
+//
+// The prefix should be followed by a separator, so that scope "tenant-a" doesn't match "tenant-ab".
+func inScope(resourceNamespace, scope resource.Namespace) bool {
+	if resourceNamespace == scope {
+		return true
+	}
+
+	return strings.HasPrefix(resourceNamespace, scope+NamespaceScopeSeparator)
+}
+
 func (adapter *adapter) checkReadAccess(resourceNamespace resource.Namespace, resourceType resource.Type, resourceID *resource.ID) error {
+	if err := adapter.checkScope(resourceNamespace); err != nil {
+		return err
+	}
+
 	if adapter.isOutput(resourceType) {
 		return nil
 	}
@@ -169,6 +294,10 @@ func (adapter *adapter) checkReadAccess(resourceNamespace resource.Namespace, re
 }
 
 func (adapter *adapter) checkFinalizerAccess(resourceNamespace resource.Namespace, resourceType resource.Type, resourceID resource.ID) error {
+	if err := adapter.checkScope(resourceNamespace); err != nil {
+		return err
+	}
+
 	// go over cached dependencies here
 	for _, dep := range adapter.inputs {
 		if dep.Namespace == resourceNamespace && dep.Type == resourceType && dep.Kind == controller.InputStrong {
@@ -186,12 +315,42 @@ func (adapter *adapter) checkFinalizerAccess(resourceNamespace resource.Namespac
 	return fmt.Errorf("attempt to change finalizers for resource %q/%q, not an input with Strong dependency for controller %q", resourceNamespace, resourceType, adapter.name)
 }
 
+func (adapter *adapter) checkStatusAccess(resourceNamespace resource.Namespace, resourceType resource.Type, resourceID resource.ID) error {
+	if err := adapter.checkScope(resourceNamespace); err != nil {
+		return err
+	}
+
+	if adapter.isOutput(resourceType) {
+		return nil
+	}
+
+	// go over cached dependencies here
+	for _, dep := range adapter.inputs {
+		if dep.Namespace == resourceNamespace && dep.Type == resourceType {
+			// any ID is allowed
+			if dep.ID == nil {
+				return nil
+			}
+
+			if *dep.ID == resourceID {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("attempt to update status of resource %q/%q, not input or output for controller %q", resourceNamespace, resourceType, adapter.name)
+}
+
 // Get implements controller.Runtime interface.
 func (adapter *adapter) Get(ctx context.Context, resourcePointer resource.Pointer) (resource.Resource, error) { //nolint:ireturn
 	if err := adapter.checkReadAccess(resourcePointer.Namespace(), resourcePointer.Type(), pointer.To(resourcePointer.ID())); err != nil {
 		return nil, err
 	}
 
+	if err := adapter.faults.inject(false); err != nil {
+		return nil, err
+	}
+
 	return adapter.runtime.state.Get(ctx, resourcePointer)
 }
 
@@ -201,6 +360,10 @@ func (adapter *adapter) List(ctx context.Context, resourceKind resource.Kind, op
 		return resource.List{}, err
 	}
 
+	if err := adapter.faults.inject(false); err != nil {
+		return resource.List{}, err
+	}
+
 	return adapter.runtime.state.List(ctx, resourceKind, opts...)
 }
 
@@ -210,53 +373,165 @@ func (adapter *adapter) WatchFor(ctx context.Context, resourcePointer resource.P
 		return nil, err
 	}
 
+	if err := adapter.faults.inject(false); err != nil {
+		return nil, err
+	}
+
 	return adapter.runtime.state.WatchFor(ctx, resourcePointer, opts...)
 }
 
 // Create implements controller.Runtime interface.
 func (adapter *adapter) Create(ctx context.Context, r resource.Resource) error {
+	if err := adapter.checkScope(r.Metadata().Namespace()); err != nil {
+		return err
+	}
+
 	if !adapter.isOutput(r.Metadata().Type()) {
 		return fmt.Errorf("resource %q/%q is not an output for controller %q, create attempted on %q",
 			r.Metadata().Namespace(), r.Metadata().Type(), adapter.name, r.Metadata().ID())
 	}
 
-	return adapter.runtime.state.Create(ctx, r, state.WithCreateOwner(adapter.name))
+	if err := adapter.faults.inject(true); err != nil {
+		return err
+	}
+
+	if err := adapter.checkMerge(ctx, r.Metadata(), r, false); err != nil {
+		return err
+	}
+
+	if err := adapter.runtime.state.Create(ctx, r, state.WithCreateOwner(adapter.outputOwner(r.Metadata().Type()))); err != nil {
+		return err
+	}
+
+	adapter.recordOutput(RecordCreate, r.Metadata(), r)
+
+	return nil
 }
 
 // Update implements controller.Runtime interface.
 func (adapter *adapter) Update(ctx context.Context, curVersion resource.Version, newResource resource.Resource) error {
+	if err := adapter.checkScope(newResource.Metadata().Namespace()); err != nil {
+		return err
+	}
+
 	if !adapter.isOutput(newResource.Metadata().Type()) {
 		return fmt.Errorf("resource %q/%q is not an output for controller %q, create attempted on %q",
 			newResource.Metadata().Namespace(), newResource.Metadata().Type(), adapter.name, newResource.Metadata().ID())
 	}
 
-	return adapter.runtime.state.Update(ctx, curVersion, newResource, state.WithUpdateOwner(adapter.name))
+	if err := adapter.faults.inject(true); err != nil {
+		return err
+	}
+
+	if err := adapter.checkMerge(ctx, newResource.Metadata(), newResource, true); err != nil {
+		return err
+	}
+
+	if err := adapter.runtime.state.Update(ctx, curVersion, newResource, state.WithUpdateOwner(adapter.outputOwner(newResource.Metadata().Type()))); err != nil {
+		return err
+	}
+
+	adapter.recordOutput(RecordUpdate, newResource.Metadata(), newResource)
+
+	return nil
+}
+
+// UpdateStatus implements controller.StatusWriter interface.
+//
+// The controller claims the ownership of the resource status.
+func (adapter *adapter) UpdateStatus(ctx context.Context, curVersion resource.Version, newResource resource.StatusResource) error {
+	if err := adapter.checkStatusAccess(newResource.Metadata().Namespace(), newResource.Metadata().Type(), newResource.Metadata().ID()); err != nil {
+		return err
+	}
+
+	if err := adapter.faults.inject(true); err != nil {
+		return err
+	}
+
+	return state.UpdateStatus(ctx, adapter.runtime.state, curVersion, newResource, state.WithUpdateOwner(adapter.name))
 }
 
 // Modify implements controller.Runtime interface.
 func (adapter *adapter) Modify(ctx context.Context, emptyResource resource.Resource, updateFunc func(resource.Resource) error) error {
+	_, err := adapter.ModifyWithResult(ctx, emptyResource, updateFunc)
+
+	return err
+}
+
+// ModifyWithResult implements controller.ResultWriter interface.
+func (adapter *adapter) ModifyWithResult(ctx context.Context, emptyResource resource.Resource, updateFunc func(resource.Resource) error) (resource.Resource, error) { //nolint:ireturn
+	if err := adapter.checkScope(emptyResource.Metadata().Namespace()); err != nil {
+		return nil, err
+	}
+
 	if !adapter.isOutput(emptyResource.Metadata().Type()) {
-		return fmt.Errorf("resource %q/%q is not an output for controller %q, update attempted on %q",
+		return nil, fmt.Errorf("resource %q/%q is not an output for controller %q, update attempted on %q",
 			emptyResource.Metadata().Namespace(), emptyResource.Metadata().Type(), adapter.name, emptyResource.Metadata().ID())
 	}
 
+	if err := adapter.faults.inject(true); err != nil {
+		return nil, err
+	}
+
+	strategy := adapter.mergeStrategy(emptyResource.Metadata().Type())
+	owner := adapter.outputOwner(emptyResource.Metadata().Type())
+
 	_, err := adapter.runtime.state.Get(ctx, emptyResource.Metadata())
 	if err != nil {
 		if state.IsNotFoundError(err) {
 			err = updateFunc(emptyResource)
 			if err != nil {
-				return err
+				return nil, err
+			}
+
+			if strategy != nil {
+				if err = strategy.CheckMerge(adapter.name, nil, emptyResource); err != nil {
+					return nil, err
+				}
+			}
+
+			if err = adapter.runtime.state.Create(ctx, emptyResource, state.WithCreateOwner(owner)); err != nil {
+				return nil, err
+			}
+
+			// the state stores a copy of the resource with the owner, version and generation set by the state
+			created, err := adapter.runtime.state.Get(ctx, emptyResource.Metadata())
+			if err != nil {
+				return nil, fmt.Errorf("error querying created object state: %w", err)
 			}
 
-			return adapter.runtime.state.Create(ctx, emptyResource, state.WithCreateOwner(adapter.name))
+			adapter.recordOutput(RecordModify, emptyResource.Metadata(), created)
+
+			return created, nil
 		}
 
-		return fmt.Errorf("error querying current object state: %w", err)
+		return nil, fmt.Errorf("error querying current object state: %w", err)
 	}
 
-	_, err = adapter.runtime.state.UpdateWithConflicts(ctx, emptyResource.Metadata(), updateFunc, state.WithUpdateOwner(adapter.name))
+	var modified resource.Resource
 
-	return err
+	_, err = adapter.runtime.state.UpdateWithConflicts(ctx, emptyResource.Metadata(), func(r resource.Resource) error {
+		modified = r
+
+		if strategy == nil {
+			return updateFunc(r)
+		}
+
+		old := r.DeepCopy()
+
+		if err := updateFunc(r); err != nil {
+			return err
+		}
+
+		return strategy.CheckMerge(adapter.name, old, r)
+	}, state.WithUpdateOwner(owner))
+	if err != nil {
+		return nil, err
+	}
+
+	adapter.recordOutput(RecordModify, emptyResource.Metadata(), modified)
+
+	return modified, nil
 }
 
 // AddFinalizer implements controller.Runtime interface.
@@ -265,6 +540,10 @@ func (adapter *adapter) AddFinalizer(ctx context.Context, resourcePointer resour
 		return err
 	}
 
+	if err := adapter.faults.inject(true); err != nil {
+		return err
+	}
+
 	return adapter.runtime.state.AddFinalizer(ctx, resourcePointer, fins...)
 }
 
@@ -274,6 +553,10 @@ func (adapter *adapter) RemoveFinalizer(ctx context.Context, resourcePointer res
 		return err
 	}
 
+	if err := adapter.faults.inject(true); err != nil {
+		return err
+	}
+
 	err := adapter.runtime.state.RemoveFinalizer(ctx, resourcePointer, fins...)
 	if state.IsNotFoundError(err) {
 		err = nil
@@ -284,20 +567,65 @@ func (adapter *adapter) RemoveFinalizer(ctx context.Context, resourcePointer res
 
 // Teardown implements controller.Runtime interface.
 func (adapter *adapter) Teardown(ctx context.Context, resourcePointer resource.Pointer) (bool, error) {
+	if err := adapter.checkScope(resourcePointer.Namespace()); err != nil {
+		return false, err
+	}
+
 	if !adapter.isOutput(resourcePointer.Type()) {
 		return false, fmt.Errorf("resource %q/%q is not an output for controller %q, teardown attempted on %q", resourcePointer.Namespace(), resourcePointer.Type(), adapter.name, resourcePointer.ID())
 	}
 
-	return adapter.runtime.state.Teardown(ctx, resourcePointer, state.WithTeardownOwner(adapter.name))
+	if err := adapter.faults.inject(true); err != nil {
+		return false, err
+	}
+
+	if err := adapter.checkMerge(ctx, resourcePointer, nil, true); err != nil {
+		return false, err
+	}
+
+	ready, err := adapter.runtime.state.Teardown(ctx, resourcePointer, state.WithTeardownOwner(adapter.outputOwner(resourcePointer.Type())))
+	if err != nil {
+		return ready, err
+	}
+
+	adapter.recordOutput(RecordTeardown, resourcePointer, nil)
+
+	return ready, nil
 }
 
 // Destroy implements controller.Runtime interface.
 func (adapter *adapter) Destroy(ctx context.Context, resourcePointer resource.Pointer) error {
+	if err := adapter.checkScope(resourcePointer.Namespace()); err != nil {
+		return err
+	}
+
 	if !adapter.isOutput(resourcePointer.Type()) {
 		return fmt.Errorf("resource %q/%q is not an output for controller %q, destroy attempted on %q", resourcePointer.Namespace(), resourcePointer.Type(), adapter.name, resourcePointer.ID())
 	}
 
-	return adapter.runtime.state.Destroy(ctx, resourcePointer, state.WithDestroyOwner(adapter.name))
+	if err := adapter.faults.inject(true); err != nil {
+		return err
+	}
+
+	if err := adapter.checkMerge(ctx, resourcePointer, nil, true); err != nil {
+		return err
+	}
+
+	if err := adapter.runtime.state.Destroy(ctx, resourcePointer, state.WithDestroyOwner(adapter.outputOwner(resourcePointer.Type()))); err != nil {
+		return err
+	}
+
+	adapter.recordOutput(RecordDestroy, resourcePointer, nil)
+
+	return nil
+}
+
+func (adapter *adapter) recordOutput(op RecordOp, ptr resource.Pointer, r resource.Resource) {
+	if adapter.runtime.options.Recorder == nil {
+		return
+	}
+
+	adapter.runtime.options.Recorder.recordOutput(adapter.name, op, ptr, r)
 }
 
 func (adapter *adapter) initialize() error {
@@ -306,6 +634,10 @@ func (adapter *adapter) initialize() error {
 	adapter.outputs = append([]controller.Output(nil), adapter.ctrl.Outputs()...)
 
 	for _, output := range adapter.outputs {
+		if output.Kind == controller.OutputMerged && output.Merge == nil {
+			return fmt.Errorf("merged output %q requires merge strategy", output.Type)
+		}
+
 		if err := adapter.runtime.depDB.AddControllerOutput(adapter.name, output); err != nil {
 			return fmt.Errorf("error registering in dependency database: %w", err)
 		}
@@ -336,31 +668,320 @@ func (adapter *adapter) deleteWatchFilter(resourceNamespace resource.Namespace,
 	delete(adapter.watchFilters, watchKey{resourceNamespace, resourceType})
 }
 
+// updateSamplers rebuilds samplers for the sampled inputs.
+//
+// If there are several inputs for the same namespace and type, the input is sampled only if all of them are sampled.
+func (adapter *adapter) updateSamplers(deps []controller.Input) {
+	intervals := make(map[watchKey]time.Duration)
+
+	for _, dep := range deps {
+		key := watchKey{dep.Namespace, dep.Type}
+
+		interval, exists := intervals[key]
+
+		switch {
+		case !exists:
+			intervals[key] = dep.SampleInterval
+		case dep.SampleInterval < interval:
+			intervals[key] = dep.SampleInterval
+		}
+	}
+
+	adapter.watchFilterMu.Lock()
+	defer adapter.watchFilterMu.Unlock()
+
+	for key, sampler := range adapter.samplers {
+		if intervals[key] == 0 {
+			if sampler.timer != nil {
+				sampler.timer.Stop()
+			}
+
+			delete(adapter.samplers, key)
+		}
+	}
+
+	for key, interval := range intervals {
+		if interval == 0 {
+			continue
+		}
+
+		if adapter.samplers == nil {
+			adapter.samplers = make(map[watchKey]*sampler)
+		}
+
+		if s, exists := adapter.samplers[key]; exists {
+			s.interval = interval
+
+			continue
+		}
+
+		adapter.samplers[key] = &sampler{
+			interval: interval,
+		}
+	}
+}
+
 func (adapter *adapter) watchTrigger(md *resource.Metadata) {
 	adapter.watchFilterMu.Lock()
 	defer adapter.watchFilterMu.Unlock()
 
+	key := watchKey{md.Namespace(), md.Type()}
+
 	if adapter.watchFilters != nil {
-		if filter := adapter.watchFilters[watchKey{md.Namespace(), md.Type()}]; filter != nil && !filter(md) {
+		if filter := adapter.watchFilters[key]; filter != nil && !filter(md) {
 			// skip reconcile if the event doesn't match the filter
 			return
 		}
 	}
 
+	adapter.wakeups.inc(wakeupKey{
+		Source:    WakeupInput,
+		Namespace: md.Namespace(),
+		Type:      md.Type(),
+	})
+
+	if s := adapter.samplers[key]; s != nil {
+		adapter.triggerSampled(s)
+
+		return
+	}
+
+	adapter.deliverWatch()
+}
+
+// deliverWatch triggers reconcile on the watch event, delaying it if the watch delay fault is injected.
+func (adapter *adapter) deliverWatch() {
+	if delay := adapter.faults.watchDelay(); delay > 0 {
+		adapter.runtime.options.Clock.AfterFunc(delay, adapter.triggerReconcile)
+
+		return
+	}
+
 	adapter.triggerReconcile()
 }
 
+// triggerSampled triggers reconcile at most once per sampler interval.
+//
+// triggerSampled should be called with watchFilterMu held.
+func (adapter *adapter) triggerSampled(s *sampler) {
+	adapter.throttle(s, &adapter.watchFilterMu, adapter.deliverWatch)
+}
+
+// throttle calls fire at most once per sampler interval, coalescing the calls in between.
+//
+// throttle should be called with mu held.
+func (adapter *adapter) throttle(s *sampler, mu *sync.Mutex, fire func()) {
+	if s.timer != nil {
+		// call is already scheduled
+		return
+	}
+
+	since := adapter.runtime.options.Clock.Since(s.last)
+
+	if since >= s.interval {
+		s.last = adapter.runtime.options.Clock.Now()
+
+		fire()
+
+		return
+	}
+
+	s.timer = adapter.runtime.options.Clock.AfterFunc(s.interval-since, func() {
+		mu.Lock()
+		s.timer = nil
+		s.last = adapter.runtime.options.Clock.Now()
+		mu.Unlock()
+
+		fire()
+	})
+}
+
+// triggerReconcile schedules a reconcile, limiting the rate of reconciles if configured.
 func (adapter *adapter) triggerReconcile() {
+	adapter.rateLimitMu.Lock()
+
+	if adapter.rateLimit.interval > 0 {
+		adapter.throttle(&adapter.rateLimit, &adapter.rateLimitMu, adapter.deliverReconcile)
+		adapter.rateLimitMu.Unlock()
+
+		return
+	}
+
+	adapter.rateLimitMu.Unlock()
+
+	adapter.deliverReconcile()
+}
+
+func (adapter *adapter) deliverReconcile() {
+	adapter.pauseMu.Lock()
+
+	if adapter.paused {
+		// controller is paused, remember that reconcile is pending
+		adapter.pending = true
+		adapter.pauseMu.Unlock()
+
+		return
+	}
+
+	adapter.pauseMu.Unlock()
+
+	if adapter.runtime.stepper != nil {
+		adapter.runtime.stepper.enqueue(adapter)
+
+		return
+	}
+
 	// schedule reconcile if channel is empty
 	// otherwise channel is not empty, and reconcile is anyway scheduled
 	select {
 	case adapter.ch <- controller.ReconcileEvent{}:
+		adapter.watchdog.delivered()
 	default:
 	}
 }
 
+// pause stops delivering reconcile events to the controller, it returns false if the controller is already paused.
+//
+// Any reconcile events which arrive while the controller is paused are coalesced
+// into a single pending reconcile.
+func (adapter *adapter) pause() bool {
+	adapter.pauseMu.Lock()
+	defer adapter.pauseMu.Unlock()
+
+	if adapter.paused {
+		return false
+	}
+
+	adapter.paused = true
+
+	// pick up reconcile event which was already scheduled, but not consumed yet
+	select {
+	case <-adapter.ch:
+		adapter.pending = true
+
+		adapter.watchdog.undelivered()
+	default:
+	}
+
+	return true
+}
+
+// resume re-enables delivering reconcile events, triggering a reconcile if any events arrived while paused.
+//
+// resume returns false if the controller is not paused.
+func (adapter *adapter) resume() bool {
+	adapter.pauseMu.Lock()
+
+	if !adapter.paused {
+		adapter.pauseMu.Unlock()
+
+		return false
+	}
+
+	pending := adapter.pending
+
+	adapter.paused = false
+	adapter.pending = false
+
+	adapter.pauseMu.Unlock()
+
+	if pending {
+		adapter.triggerReconcile()
+	}
+
+	return true
+}
+
+func (adapter *adapter) isPaused() bool {
+	adapter.pauseMu.Lock()
+	defer adapter.pauseMu.Unlock()
+
+	return adapter.paused
+}
+
+// idle reports whether the controller doesn't reconcile, and it has no pending or scheduled reconciles.
+func (adapter *adapter) idle() bool {
+	if adapter.isPaused() {
+		return true
+	}
+
+	if len(adapter.ch) > 0 {
+		return false
+	}
+
+	adapter.watchFilterMu.Lock()
+
+	for _, s := range adapter.samplers {
+		if s.timer != nil {
+			adapter.watchFilterMu.Unlock()
+
+			return false
+		}
+	}
+
+	adapter.watchFilterMu.Unlock()
+
+	adapter.rateLimitMu.Lock()
+	throttled := adapter.rateLimit.timer != nil
+	adapter.rateLimitMu.Unlock()
+
+	return !throttled && adapter.watchdog.idle()
+}
+
 func (adapter *adapter) run(ctx context.Context) {
-	logger := adapter.runtime.logger.With(logging.Controller(adapter.name))
+	logger := adapter.runtime.logger.With(logging.Controller(adapter.name)).WithOptions(
+		zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return &levelCore{
+				Core:  core,
+				level: adapter.logLevel,
+			}
+		}),
+	)
+
+	if adapter.step != nil {
+		defer adapter.step.stop()
+	}
+
+	if !adapter.waitStartOrder(ctx, logger) {
+		return
+	}
+
+	// auxiliary goroutines which trigger reconciles are stopped when the controller stops
+	auxCtx, auxCancel := context.WithCancel(ctx)
+
+	var auxWg sync.WaitGroup
+
+	defer func() {
+		auxCancel()
+
+		auxWg.Wait()
+	}()
+
+	if periodic, ok := adapter.ctrl.(controller.PeriodicController); ok {
+		if schedule := periodic.ReconcileSchedule(); schedule != nil {
+			auxWg.Add(1)
+
+			go func() {
+				defer auxWg.Done()
+
+				adapter.runSchedule(auxCtx, schedule)
+			}()
+		}
+	}
+
+	if triggered, ok := adapter.ctrl.(controller.ExternalTriggerController); ok {
+		for _, trigger := range triggered.ExternalTriggers() {
+			trigger := trigger
+
+			auxWg.Add(1)
+
+			go func() {
+				defer auxWg.Done()
+
+				adapter.runExternalTrigger(auxCtx, logger, trigger)
+			}()
+		}
+	}
 
 	for {
 		err := adapter.runOnce(ctx, logger)
@@ -368,14 +989,14 @@ func (adapter *adapter) run(ctx context.Context) {
 			return
 		}
 
-		interval := adapter.backoff.NextBackOff()
+		interval := adapter.nextBackOff()
 
 		logger.Sugar().Debugf("restarting controller in %s", interval)
 
 		select {
 		case <-ctx.Done():
 			return
-		case <-time.After(interval):
+		case <-adapter.runtime.options.Clock.After(interval):
 		}
 
 		// schedule reconcile after restart
@@ -383,6 +1004,73 @@ func (adapter *adapter) run(ctx context.Context) {
 	}
 }
 
+// runSchedule triggers periodic reconciles according to the schedule.
+func (adapter *adapter) runSchedule(ctx context.Context, schedule controller.Schedule) {
+	for {
+		now := adapter.runtime.options.Clock.Now()
+
+		next := schedule.Next(now)
+		if next.IsZero() {
+			return
+		}
+
+		timer := adapter.runtime.options.Clock.NewTimer(next.Sub(now))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return
+		case <-timer.C():
+		}
+
+		adapter.wakeups.inc(wakeupKey{Source: WakeupSchedule})
+
+		adapter.triggerReconcile()
+	}
+}
+
+// runExternalTrigger runs the external trigger restarting it with a backoff on failures.
+func (adapter *adapter) runExternalTrigger(ctx context.Context, logger *zap.Logger, trigger controller.ExternalTrigger) {
+	triggerBackoff := backoff.NewExponentialBackOff()
+
+	// disable number of retries limit
+	triggerBackoff.MaxElapsedTime = 0
+	triggerBackoff.Clock = adapter.runtime.options.Clock
+
+	queueReconcile := func() {
+		adapter.wakeups.inc(wakeupKey{Source: WakeupExternal})
+
+		adapter.triggerReconcile()
+	}
+
+	for {
+		err := trigger.Run(ctx, queueReconcile)
+
+		if ctx.Err() != nil || err == nil {
+			return
+		}
+
+		interval := triggerBackoff.NextBackOff()
+
+		logger.Error("external trigger failed", zap.Error(err))
+		logger.Sugar().Debugf("restarting external trigger in %s", interval)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-adapter.runtime.options.Clock.After(interval):
+		}
+	}
+}
+
+func (adapter *adapter) nextBackOff() time.Duration {
+	adapter.backoffMu.Lock()
+	defer adapter.backoffMu.Unlock()
+
+	return adapter.backoff.NextBackOff()
+}
+
 func (adapter *adapter) runOnce(ctx context.Context, logger *zap.Logger) error {
 	var err error
 
@@ -404,9 +1092,27 @@ func (adapter *adapter) runOnce(ctx context.Context, logger *zap.Logger) error {
 		}
 	}()
 
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	var canceledByWatchdog bool
+
+	adapter.watchdog.start(runCancel)
+
 	logger.Debug("controller starting")
 
-	err = adapter.ctrl.Run(ctx, adapter, logger)
+	func() {
+		// finish the watchdog even if the controller panics
+		defer func() {
+			canceledByWatchdog = adapter.watchdog.finish()
+		}()
+
+		err = adapter.ctrl.Run(runCtx, adapter, logger)
+	}()
+
+	if canceledByWatchdog && ctx.Err() == nil {
+		err = fmt.Errorf("controller %q reconcile was canceled by the watchdog", adapter.name)
+	}
 
 	return err
 }
@@ -0,0 +1,227 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/cosi-project/runtime/pkg/clock"
+	"github.com/cosi-project/runtime/pkg/logging"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// levelCore filters log entries of the controller by the level which can be changed at runtime.
+type levelCore struct {
+	zapcore.Core
+
+	level zap.AtomicLevel
+}
+
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return c.level.Enabled(level) && c.Core.Enabled(level)
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(entry.Level) {
+		return ce
+	}
+
+	return c.Core.Check(entry, ce)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core { //nolint:ireturn
+	return &levelCore{
+		Core:  c.Core.With(fields),
+		level: c.level,
+	}
+}
+
+func runtimeConfigPointer() resource.Pointer {
+	return resource.NewMetadata(meta.NamespaceName, meta.RuntimeConfigType, meta.RuntimeConfigID, resource.VersionUndefined)
+}
+
+// loadConfig applies the current RuntimeConfig.
+//
+// loadConfig should be called with controllersMu held.
+func (runtime *Runtime) loadConfig() error {
+	r, err := runtime.state.Get(runtime.runCtx, runtimeConfigPointer())
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	runtime.updateConfig(r)
+
+	return nil
+}
+
+func (runtime *Runtime) watchConfig(ch <-chan state.Event) {
+	var metricsTicker clock.Ticker
+
+	defer func() {
+		if metricsTicker != nil {
+			metricsTicker.Stop()
+		}
+	}()
+
+	// resetMetrics restarts the metrics ticker if the interval changes
+	var metricsInterval time.Duration
+
+	resetMetrics := func(interval time.Duration) {
+		if interval == metricsInterval {
+			return
+		}
+
+		metricsInterval = interval
+
+		if metricsTicker != nil {
+			metricsTicker.Stop()
+			metricsTicker = nil
+		}
+
+		if interval > 0 {
+			metricsTicker = runtime.options.Clock.NewTicker(interval)
+		}
+	}
+
+	runtime.controllersMu.Lock()
+	resetMetrics(runtime.metricsInterval())
+	runtime.controllersMu.Unlock()
+
+	for {
+		var (
+			e         state.Event
+			metricsCh <-chan time.Time
+		)
+
+		if metricsTicker != nil {
+			metricsCh = metricsTicker.C()
+		}
+
+		select {
+		case <-runtime.runCtx.Done():
+			return
+		case <-metricsCh:
+			runtime.logWakeupStats()
+
+			continue
+		case e = <-ch:
+		}
+
+		runtime.controllersMu.Lock()
+
+		if e.Type == state.Destroyed {
+			runtime.config = nil
+
+			for _, adapter := range runtime.controllers {
+				adapter.applyConfig(nil, runtime.logger)
+			}
+		} else {
+			runtime.updateConfig(e.Resource)
+		}
+
+		resetMetrics(runtime.metricsInterval())
+
+		runtime.controllersMu.Unlock()
+	}
+}
+
+// metricsInterval should be called with controllersMu held.
+func (runtime *Runtime) metricsInterval() time.Duration {
+	if runtime.config == nil {
+		return 0
+	}
+
+	return runtime.config.MetricsInterval
+}
+
+// logWakeupStats logs the current controller wakeup stats.
+func (runtime *Runtime) logWakeupStats() {
+	for _, stat := range runtime.WakeupStats() {
+		runtime.logger.Info("controller wakeups",
+			logging.Controller(stat.Controller),
+			zap.String("source", string(stat.Source)),
+			zap.String("namespace", stat.Namespace),
+			zap.String("type", stat.Type),
+			zap.Uint64("count", stat.Count),
+		)
+	}
+}
+
+// updateConfig should be called with controllersMu held.
+func (runtime *Runtime) updateConfig(r resource.Resource) {
+	config, ok := r.(*meta.RuntimeConfig)
+	if !ok {
+		runtime.logger.Warn("unexpected runtime config resource", zap.String("type", r.Metadata().Type()))
+
+		return
+	}
+
+	runtime.config = config.TypedSpec()
+
+	for _, adapter := range runtime.controllers {
+		adapter.applyConfig(runtime.config, runtime.logger)
+	}
+}
+
+// applyConfig updates the adapter settings from the config, nil config resets the settings to the defaults.
+func (adapter *adapter) applyConfig(config *meta.RuntimeConfigSpec, logger *zap.Logger) {
+	level := zapcore.DebugLevel
+	initialInterval := backoff.DefaultInitialInterval
+	maxInterval := backoff.DefaultMaxInterval
+
+	var minReconcileInterval time.Duration
+
+	if config != nil {
+		levelName := config.LogLevel
+
+		if controllerLevel, ok := config.ControllerLogLevels[adapter.name]; ok {
+			levelName = controllerLevel
+		}
+
+		if levelName != "" {
+			if err := level.UnmarshalText([]byte(levelName)); err != nil {
+				logger.Warn("invalid controller log level", zap.String("controller", adapter.name), zap.Error(err))
+
+				level = zapcore.DebugLevel
+			}
+		}
+
+		if config.BackoffInitialInterval > 0 {
+			initialInterval = config.BackoffInitialInterval
+		}
+
+		if config.BackoffMaxInterval > 0 {
+			maxInterval = config.BackoffMaxInterval
+		}
+
+		minReconcileInterval = config.MinReconcileInterval
+
+		if controllerInterval, ok := config.ControllerMinReconcileIntervals[adapter.name]; ok {
+			minReconcileInterval = controllerInterval
+		}
+	}
+
+	adapter.rateLimitMu.Lock()
+	adapter.rateLimit.interval = minReconcileInterval
+	adapter.rateLimitMu.Unlock()
+
+	adapter.logLevel.SetLevel(level)
+
+	adapter.backoffMu.Lock()
+	defer adapter.backoffMu.Unlock()
+
+	adapter.backoff.InitialInterval = initialInterval
+	adapter.backoff.MaxInterval = maxInterval
+}
@@ -174,7 +174,7 @@ func (db *Database) AddControllerOutput(controllerName string, out controller.Ou
 		}); err != nil {
 			return fmt.Errorf("error adding controller exclusive output: %w", err)
 		}
-	case controller.OutputShared:
+	case controller.OutputShared, controller.OutputMerged:
 		obj, err = txn.First(tableSharedOutputs, "id", controllerName, out.Type)
 		if err != nil {
 			return fmt.Errorf("error quering controller outputs: %w", err)
@@ -186,6 +186,19 @@ func (db *Database) AddControllerOutput(controllerName string, out controller.Ou
 			return fmt.Errorf("duplicate shared controller output: %q -> %q", dep.Type, dep.ControllerName)
 		}
 
+		obj, err = txn.First(tableSharedOutputs, "type", out.Type)
+		if err != nil {
+			return fmt.Errorf("error quering controller outputs: %w", err)
+		}
+
+		if obj != nil {
+			dep := obj.(*ControllerOutput) //nolint:errcheck,forcetypeassert
+
+			if (dep.Kind == controller.OutputMerged) != (out.Kind == controller.OutputMerged) {
+				return fmt.Errorf("resource %q is managed by %q with different output kind", dep.Type, dep.ControllerName)
+			}
+		}
+
 		if err = txn.Insert(tableSharedOutputs, &ControllerOutput{
 			Type:           out.Type,
 			ControllerName: controllerName,
@@ -389,9 +402,15 @@ func (db *Database) Export() (*controller.DependencyGraph, error) {
 	for obj := iter.Next(); obj != nil; obj = iter.Next() {
 		model := obj.(*ControllerOutput) //nolint:errcheck,forcetypeassert
 
+		edgeType := controller.EdgeOutputShared
+
+		if model.Kind == controller.OutputMerged {
+			edgeType = controller.EdgeOutputMerged
+		}
+
 		graph.Edges = append(graph.Edges, controller.DependencyEdge{
 			ControllerName: model.ControllerName,
-			EdgeType:       controller.EdgeOutputShared,
+			EdgeType:       edgeType,
 			ResourceType:   model.Type,
 		})
 	}
@@ -0,0 +1,27 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/fork"
+)
+
+// NewDryRunRuntime initializes controller runtime which runs the controllers against a fork of the state.
+//
+// Controllers see the contents of st, but any changes they make are kept in the fork and are never
+// persisted to st. Use fork.State.Changes to report what would change.
+func NewDryRunRuntime(st state.CoreState, logger *zap.Logger, opts ...Option) (*Runtime, *fork.State, error) {
+	forked := fork.NewState(st)
+
+	runtime, err := NewRuntime(state.WrapCore(forked), logger, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return runtime, forked, nil
+}
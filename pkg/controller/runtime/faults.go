@@ -0,0 +1,93 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrInjectedFault is returned from the controller runtime calls failed by the fault injection.
+var ErrInjectedFault = errors.New("injected fault")
+
+// Faults configures failures injected into the controller runtime calls of a controller.
+//
+// Fault injection is intended for tests which verify resilience of the controller reconcile loops.
+type Faults struct {
+	// ErrorOnCalls lists the state calls (counting from 1) which fail with ErrInjectedFault.
+	ErrorOnCalls []int
+	// ConflictOnWrites lists the state writes (counting from 1) which fail with a conflict error.
+	ConflictOnWrites []int
+	// WatchDelay delays delivery of the input events to the controller.
+	WatchDelay time.Duration
+}
+
+type injectedConflictError struct {
+	error
+}
+
+func (injectedConflictError) ConflictError() {}
+
+func (err injectedConflictError) Unwrap() error {
+	return err.error
+}
+
+type faultInjector struct {
+	faults Faults
+
+	mu     sync.Mutex
+	calls  int
+	writes int
+}
+
+// inject returns an error if the state call should fail.
+//
+// inject is safe to call on nil faultInjector.
+func (injector *faultInjector) inject(write bool) error {
+	if injector == nil {
+		return nil
+	}
+
+	injector.mu.Lock()
+	defer injector.mu.Unlock()
+
+	injector.calls++
+
+	if containsCall(injector.faults.ErrorOnCalls, injector.calls) {
+		return fmt.Errorf("%w: state call #%d", ErrInjectedFault, injector.calls)
+	}
+
+	if !write {
+		return nil
+	}
+
+	injector.writes++
+
+	if containsCall(injector.faults.ConflictOnWrites, injector.writes) {
+		return injectedConflictError{fmt.Errorf("%w: conflict on state write #%d", ErrInjectedFault, injector.writes)}
+	}
+
+	return nil
+}
+
+func containsCall(calls []int, call int) bool {
+	for _, c := range calls {
+		if c == call {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (injector *faultInjector) watchDelay() time.Duration {
+	if injector == nil {
+		return 0
+	}
+
+	return injector.faults.WatchDelay
+}
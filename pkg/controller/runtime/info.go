@@ -0,0 +1,114 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"fmt"
+	goruntime "runtime"
+	"runtime/debug"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// modulePath is the path of the runtime module, it's looked up in the build info.
+const modulePath = "github.com/cosi-project/runtime"
+
+// publishInfo creates or replaces the RuntimeInfo resource.
+//
+// The resource is left in place when the runtime stops, so the start time tells when the runtime was last started.
+func (runtime *Runtime) publishInfo() error {
+	spec := meta.RuntimeInfoSpec{
+		ModuleVersion: moduleVersion(),
+		GoVersion:     goruntime.Version(),
+		StartTime:     runtime.options.Clock.Now().UTC(),
+		Features:      runtime.features(),
+	}
+
+	return runtime.publishMeta(meta.NewRuntimeInfo(runtime.options.RuntimeInfoID, spec), func(r resource.Resource) error {
+		info, ok := r.(*meta.RuntimeInfo)
+		if !ok {
+			return fmt.Errorf("unexpected resource type %T", r)
+		}
+
+		*info.TypedSpec() = spec
+
+		return nil
+	})
+}
+
+// publishMeta creates the meta resource, or updates the existing one with the updater.
+func (runtime *Runtime) publishMeta(r resource.Resource, updater state.UpdaterFunc) error {
+	err := runtime.state.Create(runtime.runCtx, r, state.WithCreateOwner(meta.Owner))
+	if err == nil || !state.IsConflictError(err) {
+		return err
+	}
+
+	_, err = runtime.state.UpdateWithConflicts(runtime.runCtx, r.Metadata(), updater, state.WithUpdateOwner(meta.Owner))
+
+	return err
+}
+
+// features lists the enabled runtime options.
+func (runtime *Runtime) features() []string {
+	var features []string
+
+	if len(runtime.options.NamespaceScopes) > 0 {
+		features = append(features, "namespaceScopes")
+	}
+
+	if runtime.options.Recorder != nil {
+		features = append(features, "recorder")
+	}
+
+	if len(runtime.options.Faults) > 0 {
+		features = append(features, "faults")
+	}
+
+	if runtime.options.ConfigReload {
+		features = append(features, "configReload")
+	}
+
+	if runtime.options.OwnershipIndex {
+		features = append(features, "ownershipIndex")
+	}
+
+	if runtime.options.WatchdogTimeout > 0 {
+		features = append(features, "watchdog")
+	}
+
+	if runtime.options.Stepping {
+		features = append(features, "stepping")
+	}
+
+	return features
+}
+
+// moduleVersion returns the version of the runtime module from the build info.
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path != modulePath {
+			continue
+		}
+
+		if dep.Replace != nil {
+			return dep.Replace.Version
+		}
+
+		return dep.Version
+	}
+
+	return ""
+}
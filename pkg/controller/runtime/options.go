@@ -0,0 +1,168 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/clock"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+)
+
+// Options configure controller runtime.
+type Options struct {
+	// NamespaceScopes maps controller name to the namespace scope the controller is confined to.
+	NamespaceScopes map[string]resource.Namespace
+
+	// Recorder records input events and controller outputs, if set.
+	Recorder *Recorder
+
+	// Faults maps controller name to the faults injected into the controller runtime calls.
+	Faults map[string]Faults
+
+	// ConfigReload enables watching meta.RuntimeConfig to tune the runtime settings.
+	ConfigReload bool
+
+	// OwnershipIndex enables publishing meta.ControllerOutputs and meta.ResourceOwners.
+	OwnershipIndex bool
+
+	// RuntimeInfoID enables publishing meta.RuntimeInfo with the ID, if set.
+	RuntimeInfoID resource.ID
+
+	// Clock is the time source of the runtime timers, backoffs and schedules.
+	Clock clock.Clock
+
+	// Stepping delivers the reconcile events only via Runtime.Step.
+	Stepping bool
+
+	// WatchdogTimeout enables detection of stuck reconciles, if set.
+	WatchdogTimeout time.Duration
+	// WatchdogCancel cancels the stuck controller, so that it's restarted.
+	WatchdogCancel bool
+}
+
+// Option applies settings to Options.
+type Option func(options *Options)
+
+// NamespaceScopeSeparator separates the scope from the rest of the namespace name for scoped controllers.
+const NamespaceScopeSeparator = "/"
+
+// WithNamespaceScope confines controllers to the namespace scope.
+//
+// Scoped controllers can only declare inputs, read and write resources in the namespace
+// equal to the scope, or in the namespaces nested under it (scope followed by NamespaceScopeSeparator),
+// so that a single runtime can host controllers for multiple tenants.
+func WithNamespaceScope(scope resource.Namespace, controllerNames ...string) Option {
+	return func(options *Options) {
+		if options.NamespaceScopes == nil {
+			options.NamespaceScopes = make(map[string]resource.Namespace, len(controllerNames))
+		}
+
+		for _, name := range controllerNames {
+			options.NamespaceScopes[name] = scope
+		}
+	}
+}
+
+// WithRecorder enables recording of input events and controller outputs.
+//
+// The recording can be replayed with Runtime.Replay to reproduce controller behavior offline.
+func WithRecorder(recorder *Recorder) Option {
+	return func(options *Options) {
+		options.Recorder = recorder
+	}
+}
+
+// WithFaults injects failures into the controller runtime calls of the controllers.
+//
+// This option is intended for tests only.
+func WithFaults(faults Faults, controllerNames ...string) Option {
+	return func(options *Options) {
+		if options.Faults == nil {
+			options.Faults = make(map[string]Faults, len(controllerNames))
+		}
+
+		for _, name := range controllerNames {
+			options.Faults[name] = faults
+		}
+	}
+}
+
+// WithConfigReload enables runtime configuration reload via meta.RuntimeConfig resource.
+//
+// The runtime watches the meta.RuntimeConfig with ID meta.RuntimeConfigID and applies
+// the settings to all controllers without restarting them.
+func WithConfigReload() Option {
+	return func(options *Options) {
+		options.ConfigReload = true
+	}
+}
+
+// WithWatchdog enables detection of the controllers which reconcile for longer than timeout.
+//
+// Reconcile lasts from the moment the controller picks up a reconcile event till it calls EventCh again.
+// Stack of the stuck controller is logged, and if cancel is set, the controller is canceled and restarted.
+//
+// Detection relies on the controller calling EventCh on every iteration of its loop:
+// controllers which wait on other channels (e.g. timers) between the reconcile events are reported as stuck.
+func WithWatchdog(timeout time.Duration, cancel bool) Option {
+	return func(options *Options) {
+		options.WatchdogTimeout = timeout
+		options.WatchdogCancel = cancel
+	}
+}
+
+// WithRuntimeInfo enables publishing the build and the features of the runtime as meta.RuntimeInfo resource.
+//
+// The resource is published with the ID on start, so that the runtime versions can be inventoried by querying the state.
+// Runtimes sharing the state should use different IDs, empty ID means meta.RuntimeInfoID.
+func WithRuntimeInfo(id resource.ID) Option {
+	return func(options *Options) {
+		if id == "" {
+			id = meta.RuntimeInfoID
+		}
+
+		options.RuntimeInfoID = id
+	}
+}
+
+// WithOwnershipIndex enables publishing the index of the controller outputs as meta resources.
+//
+// The runtime publishes meta.ControllerOutputs for each controller and meta.ResourceOwners for each output type,
+// and keeps them up to date as the controllers are registered, so that the controllers managing
+// the resource can be looked up by querying the state.
+func WithOwnershipIndex() Option {
+	return func(options *Options) {
+		options.OwnershipIndex = true
+	}
+}
+
+// WithClock sets the time source of the runtime timers, backoffs and schedules.
+//
+// This option is intended for tests which advance a fake clock instead of sleeping.
+func WithClock(c clock.Clock) Option {
+	return func(options *Options) {
+		options.Clock = c
+	}
+}
+
+// WithStepping enables the deterministic scheduling mode: the reconcile events are queued,
+// and each Runtime.Step call delivers exactly one of them, waiting for the reconcile to finish.
+//
+// This option is intended for tests which reproduce the interleavings of the controllers, the controllers
+// should call EventCh on every iteration of the reconcile loop.
+func WithStepping() Option {
+	return func(options *Options) {
+		options.Stepping = true
+	}
+}
+
+// DefaultOptions returns default value of Options.
+func DefaultOptions() Options {
+	return Options{
+		Clock: clock.Real(),
+	}
+}
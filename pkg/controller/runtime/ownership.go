@@ -0,0 +1,137 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// publishOwnership publishes the ownership index: meta.ControllerOutputs for each controller,
+// and meta.ResourceOwners for each output resource type.
+//
+// Index entries left from the previous runs which don't match the registered controllers are removed.
+// publishOwnership should be called with controllersMu held.
+func (runtime *Runtime) publishOwnership() error {
+	names := make([]string, 0, len(runtime.controllers))
+
+	for name := range runtime.controllers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	owners := map[resource.Type]*meta.ResourceOwnersSpec{}
+
+	for _, name := range names {
+		outputs, err := runtime.depDB.GetControllerOutputs(name)
+		if err != nil {
+			return fmt.Errorf("error getting controller %q outputs: %w", name, err)
+		}
+
+		spec := meta.ControllerOutputsSpec{
+			Outputs: make([]meta.ControllerOutput, 0, len(outputs)),
+		}
+
+		for _, output := range outputs {
+			owner, ok := owners[output.Type]
+			if !ok {
+				owner = &meta.ResourceOwnersSpec{}
+				owners[output.Type] = owner
+			}
+
+			var kind string
+
+			switch output.Kind {
+			case controller.OutputExclusive:
+				kind = meta.OutputKindExclusive
+				owner.ExclusiveController = name
+			case controller.OutputMerged:
+				kind = meta.OutputKindMerged
+				owner.MergedControllers = append(owner.MergedControllers, name)
+			default:
+				kind = meta.OutputKindShared
+				owner.SharedControllers = append(owner.SharedControllers, name)
+			}
+
+			spec.Outputs = append(spec.Outputs, meta.ControllerOutput{
+				Type: output.Type,
+				Kind: kind,
+			})
+		}
+
+		if err = runtime.publishMeta(meta.NewControllerOutputs(name, spec), func(r resource.Resource) error {
+			outputs, ok := r.(*meta.ControllerOutputs)
+			if !ok {
+				return fmt.Errorf("unexpected resource type %T", r)
+			}
+
+			*outputs.TypedSpec() = spec
+
+			return nil
+		}); err != nil {
+			return fmt.Errorf("error publishing controller %q outputs: %w", name, err)
+		}
+	}
+
+	for resourceType, spec := range owners {
+		spec := *spec
+
+		if err := runtime.publishMeta(meta.NewResourceOwners(resourceType, spec), func(r resource.Resource) error {
+			owners, ok := r.(*meta.ResourceOwners)
+			if !ok {
+				return fmt.Errorf("unexpected resource type %T", r)
+			}
+
+			*owners.TypedSpec() = spec
+
+			return nil
+		}); err != nil {
+			return fmt.Errorf("error publishing resource %q owners: %w", resourceType, err)
+		}
+	}
+
+	if err := runtime.cleanupOwnership(meta.ControllerOutputsType, func(id resource.ID) bool {
+		_, ok := runtime.controllers[id]
+
+		return ok
+	}); err != nil {
+		return err
+	}
+
+	return runtime.cleanupOwnership(meta.ResourceOwnersType, func(id resource.ID) bool {
+		_, ok := owners[id]
+
+		return ok
+	})
+}
+
+// cleanupOwnership removes the index entries of the type which are not current.
+func (runtime *Runtime) cleanupOwnership(resourceType resource.Type, current func(resource.ID) bool) error {
+	list, err := safe.StateList[resource.Resource](runtime.runCtx, runtime.state, resource.NewMetadata(meta.NamespaceName, resourceType, "", resource.VersionUndefined))
+	if err != nil {
+		return fmt.Errorf("error listing %s: %w", resourceType, err)
+	}
+
+	for iter := safe.IteratorFromList(list); iter.Next(); {
+		md := iter.Value().Metadata()
+
+		if current(md.ID()) || md.Owner() != meta.Owner {
+			continue
+		}
+
+		if err = runtime.state.Destroy(runtime.runCtx, md, state.WithDestroyOwner(meta.Owner)); err != nil && !state.IsNotFoundError(err) {
+			return fmt.Errorf("error removing stale %s: %w", md, err)
+		}
+	}
+
+	return nil
+}
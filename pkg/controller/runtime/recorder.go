@@ -0,0 +1,336 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/store"
+)
+
+// RecordKind is the kind of the recorded entry.
+type RecordKind string
+
+// Record kinds.
+const (
+	// RecordInput is an event on the controller input which originated outside of the runtime.
+	RecordInput RecordKind = "input"
+	// RecordOutput is a successful write by a controller to its outputs.
+	RecordOutput RecordKind = "output"
+)
+
+// RecordOp is the operation of the recorded entry.
+type RecordOp string
+
+// Record operations.
+//
+// Inputs use the state event types, outputs use the controller.Runtime write operations.
+const (
+	RecordCreated   RecordOp = "Created"
+	RecordUpdated   RecordOp = "Updated"
+	RecordDestroyed RecordOp = "Destroyed"
+
+	RecordCreate   RecordOp = "Create"
+	RecordUpdate   RecordOp = "Update"
+	RecordModify   RecordOp = "Modify"
+	RecordTeardown RecordOp = "Teardown"
+	RecordDestroy  RecordOp = "Destroy"
+)
+
+// RecordEntry is a single entry of the recording.
+type RecordEntry struct {
+	Time       time.Time          `json:"time"`
+	Kind       RecordKind         `json:"kind"`
+	Op         RecordOp           `json:"op"`
+	Controller string             `json:"controller,omitempty"`
+	Namespace  resource.Namespace `json:"namespace"`
+	Type       resource.Type      `json:"type"`
+	ID         resource.ID        `json:"id"`
+	Resource   []byte             `json:"resource,omitempty"`
+}
+
+// UnmarshalResource returns the resource stored in the entry.
+//
+// Resource types should be registered with protobuf.RegisterResource.
+func (entry *RecordEntry) UnmarshalResource() (resource.Resource, error) { //nolint:ireturn
+	if entry.Resource == nil {
+		return nil, fmt.Errorf("entry %s %s/%s/%s doesn't contain a resource", entry.Op, entry.Namespace, entry.Type, entry.ID)
+	}
+
+	return store.ProtobufMarshaler{}.UnmarshalResource(entry.Resource)
+}
+
+// Recorder writes input events and controller outputs processed by the runtime as a stream of JSON entries.
+//
+// Only input events for resources which are not owned by the controllers of the runtime (including the merged outputs)
+// are recorded, as the rest of the events are reproduced by the controllers themselves on replay.
+type Recorder struct {
+	enc *json.Encoder
+	err error
+	mu  sync.Mutex
+}
+
+// NewRecorder creates a Recorder which writes to w.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{
+		enc: json.NewEncoder(w),
+	}
+}
+
+// Err returns the first error encountered while recording.
+func (recorder *Recorder) Err() error {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	return recorder.err
+}
+
+func (recorder *Recorder) recordInput(event state.Event) {
+	var op RecordOp
+
+	switch event.Type {
+	case state.Created:
+		op = RecordCreated
+	case state.Updated:
+		op = RecordUpdated
+	case state.Destroyed:
+		op = RecordDestroyed
+	}
+
+	recorder.record(RecordInput, op, "", event.Resource.Metadata(), event.Resource)
+}
+
+func (recorder *Recorder) recordOutput(controllerName string, op RecordOp, ptr resource.Pointer, r resource.Resource) {
+	recorder.record(RecordOutput, op, controllerName, ptr, r)
+}
+
+func (recorder *Recorder) record(kind RecordKind, op RecordOp, controllerName string, ptr resource.Pointer, r resource.Resource) {
+	entry := RecordEntry{
+		Time:       time.Now(),
+		Kind:       kind,
+		Op:         op,
+		Controller: controllerName,
+		Namespace:  ptr.Namespace(),
+		Type:       ptr.Type(),
+		ID:         ptr.ID(),
+	}
+
+	var err error
+
+	if r != nil {
+		entry.Resource, err = store.ProtobufMarshaler{}.MarshalResource(r)
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	if recorder.err != nil {
+		return
+	}
+
+	if err != nil {
+		recorder.err = fmt.Errorf("error marshaling resource %s/%s/%s: %w", ptr.Namespace(), ptr.Type(), ptr.ID(), err)
+
+		return
+	}
+
+	if err = recorder.enc.Encode(entry); err != nil {
+		recorder.err = fmt.Errorf("error writing record: %w", err)
+	}
+}
+
+// ReadRecording reads all entries written by the Recorder.
+func ReadRecording(r io.Reader) ([]RecordEntry, error) {
+	var entries []RecordEntry
+
+	dec := json.NewDecoder(r)
+
+	for {
+		var entry RecordEntry
+
+		if err := dec.Decode(&entry); err != nil {
+			if errors.Is(err, io.EOF) {
+				return entries, nil
+			}
+
+			return nil, fmt.Errorf("error reading record: %w", err)
+		}
+
+		entries = append(entries, entry)
+	}
+}
+
+// ReplayOption configures Replay.
+type ReplayOption func(*replayOptions)
+
+type replayOptions struct {
+	settle time.Duration
+	timing bool
+}
+
+// WithReplayTiming keeps the relative times of the recorded inputs.
+//
+// Each input is applied no earlier than its recorded offset from the first input, as measured by the runtime clock.
+// By default, the next input is applied as soon as the runtime is idle.
+func WithReplayTiming() ReplayOption {
+	return func(options *replayOptions) {
+		options.timing = true
+	}
+}
+
+// WithReplaySettle sets the time the controllers should stay idle before the next input is applied.
+//
+// Default is 100ms.
+func WithReplaySettle(settle time.Duration) ReplayOption {
+	return func(options *replayOptions) {
+		options.settle = settle
+	}
+}
+
+// Replay feeds recorded input events back into the state in the order they were recorded.
+//
+// Replay should be called once the runtime is started: each input is applied with the controllers running,
+// and the next input is applied once the controllers have processed the previous one, so the controllers
+// see the same sequence of inputs as in the recording.
+// The runtime is considered idle once no controller reconciles or has a pending reconcile for the settle period
+// (see WithReplaySettle), as the watch events are delivered to the controllers asynchronously.
+// Output entries are skipped, they can be used to compare the recorded outputs with the outputs
+// produced by the controllers on replay.
+func (runtime *Runtime) Replay(ctx context.Context, entries []RecordEntry, opts ...ReplayOption) error {
+	options := replayOptions{
+		settle: 100 * time.Millisecond,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	runtime.controllersMu.RLock()
+	started := runtime.runCtx != nil
+	runtime.controllersMu.RUnlock()
+
+	if !started {
+		return fmt.Errorf("recording should be replayed once the runtime is started")
+	}
+
+	if runtime.stepper != nil {
+		return fmt.Errorf("recording can't be replayed in the stepping mode")
+	}
+
+	var (
+		first time.Time
+		start time.Time
+	)
+
+	for i := range entries {
+		entry := &entries[i]
+
+		if entry.Kind != RecordInput {
+			continue
+		}
+
+		if options.timing {
+			if first.IsZero() {
+				first, start = entry.Time, runtime.options.Clock.Now()
+			} else if wait := runtime.options.Clock.Until(start.Add(entry.Time.Sub(first))); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-runtime.options.Clock.After(wait):
+				}
+			}
+		}
+
+		if err := replayInput(ctx, runtime.state, entry); err != nil {
+			return fmt.Errorf("error replaying %s %s/%s/%s: %w", entry.Op, entry.Namespace, entry.Type, entry.ID, err)
+		}
+
+		if err := runtime.waitIdle(ctx, options.settle); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// waitIdle waits until all controllers stay idle for the settle period.
+//
+// The settle period is measured with the real time, as it covers the delivery of the watch events
+// between the goroutines of the runtime.
+func (runtime *Runtime) waitIdle(ctx context.Context, settle time.Duration) error {
+	ticker := time.NewTicker(settle / 10)
+	defer ticker.Stop()
+
+	var idleSince time.Time
+
+	for {
+		if runtime.idle() {
+			if idleSince.IsZero() {
+				idleSince = time.Now()
+			} else if time.Since(idleSince) >= settle {
+				return nil
+			}
+		} else {
+			idleSince = time.Time{}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (runtime *Runtime) idle() bool {
+	runtime.controllersMu.RLock()
+	defer runtime.controllersMu.RUnlock()
+
+	for _, adapter := range runtime.controllers {
+		if !adapter.idle() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func replayInput(ctx context.Context, st state.State, entry *RecordEntry) error {
+	r, err := entry.UnmarshalResource()
+	if err != nil {
+		return err
+	}
+
+	owner := r.Metadata().Owner()
+
+	switch entry.Op { //nolint:exhaustive
+	case RecordCreated:
+		return st.Create(ctx, r, state.WithCreateOwner(owner))
+	case RecordUpdated:
+		var current resource.Resource
+
+		current, err = st.Get(ctx, r.Metadata())
+		if err != nil {
+			return err
+		}
+
+		r.Metadata().SetVersion(current.Metadata().Version())
+		r.Metadata().BumpVersion()
+
+		return st.Update(ctx, current.Metadata().Version(), r, state.WithUpdateOwner(owner))
+	case RecordDestroyed:
+		return st.Destroy(ctx, r.Metadata(), state.WithDestroyOwner(owner))
+	default:
+		return fmt.Errorf("unexpected input operation %q", entry.Op)
+	}
+}
@@ -13,10 +13,12 @@ import (
 	"github.com/cenkalti/backoff/v4"
 	"github.com/siderolabs/go-pointer"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
 	"github.com/cosi-project/runtime/pkg/controller"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/dependency"
 	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
 	"github.com/cosi-project/runtime/pkg/state"
 )
 
@@ -27,6 +29,8 @@ type Runtime struct { //nolint:govet
 	state  state.State
 	logger *zap.Logger
 
+	options Options
+
 	watchCh   chan state.Event
 	watchedMu sync.Mutex
 	watched   map[watchKey]struct{}
@@ -35,8 +39,16 @@ type Runtime struct { //nolint:govet
 	controllersCond    *sync.Cond
 	controllersRunning int
 	controllers        map[string]*adapter
+	config             *meta.RuntimeConfigSpec
+
+	// pauseMu serializes pausing and resuming the controllers with the changes to the meta.PausedController records
+	pauseMu sync.Mutex
 
 	runCtx context.Context //nolint:containedctx
+
+	stepper *stepper
+
+	watchdogDone chan struct{}
 }
 
 type watchKey struct {
@@ -45,10 +57,17 @@ type watchKey struct {
 }
 
 // NewRuntime initializes controller runtime object.
-func NewRuntime(st state.State, logger *zap.Logger) (*Runtime, error) {
+func NewRuntime(st state.State, logger *zap.Logger, opts ...Option) (*Runtime, error) {
+	options := DefaultOptions()
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	runtime := &Runtime{
 		state:       st,
 		logger:      logger,
+		options:     options,
 		controllers: make(map[string]*adapter),
 		watchCh:     make(chan state.Event),
 		watched:     make(map[watchKey]struct{}),
@@ -56,6 +75,10 @@ func NewRuntime(st state.State, logger *zap.Logger) (*Runtime, error) {
 
 	runtime.controllersCond = sync.NewCond(&runtime.controllersMu)
 
+	if options.Stepping {
+		runtime.stepper = newStepper()
+	}
+
 	var err error
 
 	runtime.depDB, err = dependency.NewDatabase()
@@ -83,16 +106,34 @@ func (runtime *Runtime) RegisterController(ctrl controller.Controller) error {
 		ctrl: ctrl,
 		ch:   make(chan controller.ReconcileEvent, 1),
 
-		backoff: backoff.NewExponentialBackOff(),
+		backoff:      backoff.NewExponentialBackOff(),
+		logLevel:     zap.NewAtomicLevelAt(zapcore.DebugLevel),
+		bootstrapped: make(chan struct{}),
+		watchdog:     watchdog{clock: runtime.options.Clock},
+	}
+
+	if runtime.stepper != nil {
+		adapter.step = &stepState{notify: make(chan struct{}, 1)}
 	}
 
 	// disable number of retries limit
 	adapter.backoff.MaxElapsedTime = 0
+	adapter.backoff.Clock = runtime.options.Clock
+
+	adapter.scope, adapter.scoped = runtime.options.NamespaceScopes[name]
+
+	if faults, ok := runtime.options.Faults[name]; ok {
+		adapter.faults = &faultInjector{faults: faults}
+	}
 
 	if err := adapter.initialize(); err != nil {
 		return fmt.Errorf("error initializing controller %q adapter: %w", name, err)
 	}
 
+	if runtime.config != nil {
+		adapter.applyConfig(runtime.config, runtime.logger)
+	}
+
 	// initial reconcile
 	adapter.triggerReconcile()
 
@@ -114,6 +155,12 @@ func (runtime *Runtime) RegisterController(ctrl controller.Controller) error {
 
 			adapter.run(runtime.runCtx)
 		}()
+
+		if runtime.options.OwnershipIndex {
+			if err := runtime.publishOwnership(); err != nil {
+				runtime.logger.Error("error publishing ownership index", zap.Error(err))
+			}
+		}
 	}
 
 	return nil
@@ -129,6 +176,10 @@ func (runtime *Runtime) Run(ctx context.Context) error {
 			return fmt.Errorf("runtime has already been started")
 		}
 
+		if err := runtime.checkStartOrder(); err != nil {
+			return err
+		}
+
 		runtime.runCtx = ctx
 
 		if err := runtime.setupWatches(); err != nil {
@@ -137,6 +188,42 @@ func (runtime *Runtime) Run(ctx context.Context) error {
 
 		go runtime.processWatched()
 
+		if runtime.options.RuntimeInfoID != "" {
+			if err := runtime.publishInfo(); err != nil {
+				return fmt.Errorf("error publishing runtime info: %w", err)
+			}
+		}
+
+		if runtime.options.OwnershipIndex {
+			if err := runtime.publishOwnership(); err != nil {
+				return fmt.Errorf("error publishing ownership index: %w", err)
+			}
+		}
+
+		if runtime.options.ConfigReload {
+			if err := runtime.loadConfig(); err != nil {
+				return fmt.Errorf("error loading runtime config: %w", err)
+			}
+
+			configCh := make(chan state.Event)
+
+			if err := runtime.state.Watch(runtime.runCtx, runtimeConfigPointer(), configCh); err != nil {
+				return fmt.Errorf("error watching runtime config: %w", err)
+			}
+
+			go runtime.watchConfig(configCh)
+		}
+
+		if err := runtime.restorePaused(); err != nil {
+			return fmt.Errorf("error restoring paused controllers: %w", err)
+		}
+
+		if runtime.options.WatchdogTimeout > 0 {
+			runtime.watchdogDone = make(chan struct{})
+
+			go runtime.runWatchdog(runtime.watchdogDone)
+		}
+
 		for _, adapter := range runtime.controllers {
 			adapter := adapter
 
@@ -171,14 +258,145 @@ func (runtime *Runtime) Run(ctx context.Context) error {
 
 	runtime.controllersMu.Unlock()
 
+	if runtime.watchdogDone != nil {
+		<-runtime.watchdogDone
+	}
+
+	return nil
+}
+
+// PauseController stops running reconciles for the controller by name.
+//
+// Controller inputs are still watched while the controller is paused, and any reconcile events
+// are coalesced into a single reconcile which is delivered once the controller is resumed.
+// Paused controllers are recorded as meta.PausedController resources, and the records are followed by the runtime:
+// creating or destroying the record pauses or resumes the controller, and the records are restored when the runtime starts.
+func (runtime *Runtime) PauseController(ctx context.Context, name, reason string) error {
+	adapter, err := runtime.getController(name)
+	if err != nil {
+		return err
+	}
+
+	runtime.pauseMu.Lock()
+	defer runtime.pauseMu.Unlock()
+
+	if !adapter.pause() {
+		return nil
+	}
+
+	if err = runtime.state.Create(ctx, meta.NewPausedController(name, meta.PausedControllerSpec{
+		Reason: reason,
+	}), state.WithCreateOwner(meta.Owner)); err != nil && !state.IsConflictError(err) {
+		adapter.resume()
+
+		return fmt.Errorf("error recording paused controller %q: %w", name, err)
+	}
+
 	return nil
 }
 
+// ResumeController resumes reconciles for the controller paused with PauseController.
+func (runtime *Runtime) ResumeController(ctx context.Context, name string) error {
+	adapter, err := runtime.getController(name)
+	if err != nil {
+		return err
+	}
+
+	runtime.pauseMu.Lock()
+	defer runtime.pauseMu.Unlock()
+
+	if err = runtime.state.Destroy(ctx, pausedControllerPointer(name), state.WithDestroyOwner(meta.Owner)); err != nil && !state.IsNotFoundError(err) {
+		return fmt.Errorf("error removing paused controller %q record: %w", name, err)
+	}
+
+	adapter.resume()
+
+	return nil
+}
+
+func pausedControllerPointer(name string) resource.Pointer {
+	return resource.NewMetadata(meta.NamespaceName, meta.PausedControllerType, name, resource.VersionUndefined)
+}
+
+// restorePaused pauses the controllers which have meta.PausedController records, and follows the changes to the records.
+//
+// restorePaused should be called with controllersMu held before the controllers are started.
+func (runtime *Runtime) restorePaused() error {
+	kind := resource.NewMetadata(meta.NamespaceName, meta.PausedControllerType, "", resource.VersionUndefined)
+
+	list, err := runtime.state.List(runtime.runCtx, kind)
+	if err != nil {
+		return err
+	}
+
+	runtime.pauseMu.Lock()
+
+	for _, r := range list.Items {
+		if adapter, exists := runtime.controllers[r.Metadata().ID()]; exists {
+			adapter.pause()
+		}
+	}
+
+	runtime.pauseMu.Unlock()
+
+	ch := make(chan state.Event)
+
+	// records created between List and WatchKind are delivered with the bootstrap contents
+	if err = runtime.state.WatchKind(runtime.runCtx, kind, ch, state.WithBootstrapContents(true)); err != nil {
+		return err
+	}
+
+	go runtime.watchPaused(ch)
+
+	return nil
+}
+
+func (runtime *Runtime) watchPaused(ch <-chan state.Event) {
+	for {
+		var e state.Event
+
+		select {
+		case <-runtime.runCtx.Done():
+			return
+		case e = <-ch:
+		}
+
+		adapter, err := runtime.getController(e.Resource.Metadata().ID())
+		if err != nil {
+			// record of the controller which is not registered in this runtime
+			continue
+		}
+
+		runtime.pauseMu.Lock()
+
+		switch e.Type {
+		case state.Created, state.Updated:
+			adapter.pause()
+		case state.Destroyed:
+			adapter.resume()
+		}
+
+		runtime.pauseMu.Unlock()
+	}
+}
+
 // GetDependencyGraph returns dependency graph between resources and controllers.
 func (runtime *Runtime) GetDependencyGraph() (*controller.DependencyGraph, error) {
 	return runtime.depDB.Export()
 }
 
+func (runtime *Runtime) getController(name string) (*adapter, error) {
+	runtime.controllersMu.RLock()
+	defer runtime.controllersMu.RUnlock()
+
+	adapter, exists := runtime.controllers[name]
+	if !exists {
+		return nil, fmt.Errorf("controller %q is not registered", name)
+	}
+
+	return adapter, nil
+}
+
 func (runtime *Runtime) setupWatches() error {
 	runtime.watchedMu.Lock()
 	defer runtime.watchedMu.Unlock()
@@ -186,7 +404,7 @@ func (runtime *Runtime) setupWatches() error {
 	for key := range runtime.watched {
 		kind := resource.NewMetadata(key.Namespace, key.Type, "", resource.Version{})
 
-		if err := runtime.state.WatchKind(runtime.runCtx, kind, runtime.watchCh); err != nil {
+		if err := runtime.state.WatchKind(runtime.runCtx, kind, runtime.watchCh, runtime.watchKindOptions()...); err != nil {
 			return err
 		}
 	}
@@ -216,7 +434,39 @@ func (runtime *Runtime) watch(resourceNamespace resource.Namespace, resourceType
 
 	kind := resource.NewMetadata(resourceNamespace, resourceType, "", resource.Version{})
 
-	return runtime.state.WatchKind(runtime.runCtx, kind, runtime.watchCh)
+	return runtime.state.WatchKind(runtime.runCtx, kind, runtime.watchCh, runtime.watchKindOptions()...)
+}
+
+func (runtime *Runtime) watchKindOptions() []state.WatchKindOption {
+	if runtime.options.Recorder != nil {
+		// resources which existed before the watch was established should be recorded as well
+		return []state.WatchKindOption{state.WithBootstrapContents(true)}
+	}
+
+	return nil
+}
+
+// isControllerOwner checks whether the owner is one of the registered controllers,
+// or the common owner of a merged output declared by the registered controllers.
+//
+// isControllerOwner should be called with controllersMu held.
+func (runtime *Runtime) isControllerOwner(owner string) bool {
+	if _, ok := runtime.controllers[owner]; ok {
+		return true
+	}
+
+	resourceType, ok := controller.IsMergedOwner(owner)
+	if !ok {
+		return false
+	}
+
+	for _, adapter := range runtime.controllers {
+		if adapter.mergeStrategy(resourceType) != nil {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (runtime *Runtime) processWatched() {
@@ -243,6 +493,12 @@ func (runtime *Runtime) processWatched() {
 
 		runtime.controllersMu.RLock()
 
+		if runtime.options.Recorder != nil {
+			if !runtime.isControllerOwner(md.Owner()) {
+				runtime.options.Recorder.recordInput(e)
+			}
+		}
+
 		for _, ctrl := range controllers {
 			runtime.controllers[ctrl].watchTrigger(md)
 		}
@@ -5,17 +5,34 @@
 package runtime_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	goruntime "runtime"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	suiterunner "github.com/stretchr/testify/suite"
 	"go.uber.org/goleak"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"golang.org/x/sync/errgroup"
 
+	"github.com/cosi-project/runtime/pkg/clock"
 	"github.com/cosi-project/runtime/pkg/controller"
 	"github.com/cosi-project/runtime/pkg/controller/conformance"
 	"github.com/cosi-project/runtime/pkg/controller/runtime"
 	"github.com/cosi-project/runtime/pkg/logging"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/safe"
 	"github.com/cosi-project/runtime/pkg/state"
+	stateconformance "github.com/cosi-project/runtime/pkg/state/conformance"
 	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
 	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
 )
@@ -45,3 +62,1419 @@ func TestInterface(t *testing.T) {
 
 	assert.Implements(t, (*controller.Engine)(nil), &runtime.Runtime{})
 }
+
+func TestPauseResume(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	rt, err := runtime.NewRuntime(st, logging.DefaultLogger())
+	require.NoError(t, err)
+
+	ctrl := &conformance.IntToStrController{
+		SourceNamespace: "paused",
+		TargetNamespace: "paused",
+	}
+
+	require.NoError(t, rt.RegisterController(ctrl))
+	require.NoError(t, rt.PauseController(ctx, ctrl.Name(), "maintenance"))
+
+	paused, err := safe.StateGet[*meta.PausedController](ctx, st, meta.NewPausedController(ctrl.Name(), meta.PausedControllerSpec{}).Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, "maintenance", paused.TypedSpec().Reason)
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	var eg errgroup.Group
+
+	eg.Go(func() error {
+		return rt.Run(runCtx)
+	})
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("paused", "one", 1)))
+
+	time.Sleep(500 * time.Millisecond)
+
+	_, err = st.Get(ctx, conformance.NewStrResource("paused", "one", "").Metadata())
+	assert.True(t, state.IsNotFoundError(err))
+
+	require.NoError(t, rt.ResumeController(ctx, ctrl.Name()))
+
+	_, err = st.WatchFor(ctx, conformance.NewStrResource("paused", "one", "").Metadata(), state.WithEventTypes(state.Created, state.Updated))
+	require.NoError(t, err)
+
+	_, err = st.Get(ctx, paused.Metadata())
+	assert.True(t, state.IsNotFoundError(err))
+
+	assert.Error(t, rt.PauseController(ctx, "NoSuchController", ""))
+
+	runCancel()
+
+	require.NoError(t, eg.Wait())
+}
+
+func TestPausedControllerRecord(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	ctrl := &conformance.IntToStrController{
+		SourceNamespace: "paused",
+		TargetNamespace: "paused",
+	}
+
+	// record left by the previous run pauses the controller on start
+	record := meta.NewPausedController(ctrl.Name(), meta.PausedControllerSpec{Reason: "maintenance"})
+	require.NoError(t, st.Create(ctx, record, state.WithCreateOwner(meta.Owner)))
+
+	rt, err := runtime.NewRuntime(st, logging.DefaultLogger())
+	require.NoError(t, err)
+
+	require.NoError(t, rt.RegisterController(ctrl))
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	var eg errgroup.Group
+
+	eg.Go(func() error {
+		return rt.Run(runCtx)
+	})
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("paused", "one", 1)))
+
+	time.Sleep(500 * time.Millisecond)
+
+	_, err = st.Get(ctx, conformance.NewStrResource("paused", "one", "").Metadata())
+	assert.True(t, state.IsNotFoundError(err))
+
+	// removing the record resumes the controller
+	require.NoError(t, st.Destroy(ctx, record.Metadata(), state.WithDestroyOwner(meta.Owner)))
+
+	_, err = st.WatchFor(ctx, conformance.NewStrResource("paused", "one", "").Metadata(), state.WithEventTypes(state.Created, state.Updated))
+	require.NoError(t, err)
+
+	// creating the record pauses the controller again
+	require.NoError(t, st.Create(ctx, record, state.WithCreateOwner(meta.Owner)))
+
+	time.Sleep(100 * time.Millisecond)
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("paused", "two", 2)))
+
+	time.Sleep(500 * time.Millisecond)
+
+	_, err = st.Get(ctx, conformance.NewStrResource("paused", "two", "").Metadata())
+	assert.True(t, state.IsNotFoundError(err))
+
+	runCancel()
+
+	require.NoError(t, eg.Wait())
+}
+
+func TestNamespaceScope(t *testing.T) {
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	rt, err := runtime.NewRuntime(st, logging.DefaultLogger(),
+		runtime.WithNamespaceScope("tenant-a", "IntToStrController"),
+	)
+	require.NoError(t, err)
+
+	assert.Error(t, rt.RegisterController(&conformance.IntToStrController{
+		SourceNamespace: "tenant-b",
+		TargetNamespace: "tenant-a",
+	}))
+
+	rt, err = runtime.NewRuntime(st, logging.DefaultLogger(),
+		runtime.WithNamespaceScope("tenant-a", "IntToStrController"),
+	)
+	require.NoError(t, err)
+
+	assert.Error(t, rt.RegisterController(&conformance.IntToStrController{
+		SourceNamespace: "tenant-ab",
+		TargetNamespace: "tenant-a",
+	}))
+
+	rt, err = runtime.NewRuntime(st, logging.DefaultLogger(),
+		runtime.WithNamespaceScope("tenant-a", "IntToStrController"),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, rt.RegisterController(&conformance.IntToStrController{
+		SourceNamespace: "tenant-a/in",
+		TargetNamespace: "tenant-a",
+	}))
+}
+
+// lockedBuffer is a bytes.Buffer which is safe for concurrent use.
+type lockedBuffer struct {
+	buf bytes.Buffer
+	mu  sync.Mutex
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+var registerRecordedOnce sync.Once
+
+// registerRecordedResources registers the resources for the recording, resources can be registered only once.
+func registerRecordedResources(t *testing.T) {
+	registerRecordedOnce.Do(func() {
+		require.NoError(t, protobuf.RegisterResource(conformance.IntResourceType, &conformance.IntResource{}))
+		require.NoError(t, protobuf.RegisterResource(conformance.StrResourceType, &conformance.StrResource{}))
+	})
+}
+
+func TestRecordReplay(t *testing.T) {
+	registerRecordedResources(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	waitStr := func(st state.State, id resource.ID) {
+		_, err := st.WatchFor(ctx, conformance.NewStrResource("recorded", id, "").Metadata(), state.WithEventTypes(state.Created, state.Updated))
+		require.NoError(t, err)
+	}
+
+	runWith := func(st state.State, opts []runtime.Option, f func(rt *runtime.Runtime)) {
+		rt, err := runtime.NewRuntime(st, logging.DefaultLogger(), opts...)
+		require.NoError(t, err)
+
+		require.NoError(t, rt.RegisterController(&conformance.IntToStrController{
+			SourceNamespace: "recorded",
+			TargetNamespace: "recorded",
+		}))
+
+		runCtx, runCancel := context.WithCancel(ctx)
+		defer runCancel()
+
+		var eg errgroup.Group
+
+		eg.Go(func() error {
+			return rt.Run(runCtx)
+		})
+
+		f(rt)
+
+		runCancel()
+
+		require.NoError(t, eg.Wait())
+	}
+
+	var buf lockedBuffer
+
+	recorder := runtime.NewRecorder(&buf)
+
+	countCreated := func(entries []runtime.RecordEntry) int {
+		created := 0
+
+		for _, entry := range entries {
+			if entry.Kind == runtime.RecordInput && entry.Op == runtime.RecordCreated {
+				created++
+			}
+		}
+
+		return created
+	}
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	runWith(st, []runtime.Option{runtime.WithRecorder(recorder)}, func(*runtime.Runtime) {
+		require.NoError(t, st.Create(ctx, conformance.NewIntResource("recorded", "one", 1)))
+		waitStr(st, "one")
+
+		require.NoError(t, st.Create(ctx, conformance.NewIntResource("recorded", "two", 2)))
+		waitStr(st, "two")
+
+		// the controller might reconcile "two" before the runtime processes its input event
+		require.Eventually(t, func() bool {
+			entries, err := runtime.ReadRecording(bytes.NewReader(buf.Bytes()))
+
+			return err == nil && countCreated(entries) == 2
+		}, 10*time.Second, 10*time.Millisecond)
+	})
+
+	require.NoError(t, recorder.Err())
+
+	entries, err := runtime.ReadRecording(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+
+	var outputs int
+
+	for _, entry := range entries {
+		switch entry.Kind {
+		case runtime.RecordInput:
+			assert.Equal(t, conformance.IntResourceType, entry.Type)
+		case runtime.RecordOutput:
+			outputs++
+
+			assert.Equal(t, "IntToStrController", entry.Controller)
+			assert.Equal(t, conformance.StrResourceType, entry.Type)
+		}
+	}
+
+	assert.Equal(t, 2, countCreated(entries))
+	assert.GreaterOrEqual(t, outputs, 2)
+
+	replaySt := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	// the recording is replayed with the controllers running
+	runWith(replaySt, nil, func(rt *runtime.Runtime) {
+		// wait for the runtime to start
+		require.NoError(t, replaySt.Create(ctx, conformance.NewIntResource("recorded", "started", 0)))
+		waitStr(replaySt, "started")
+
+		var first, last time.Time
+
+		for _, entry := range entries {
+			if entry.Kind == runtime.RecordInput {
+				if first.IsZero() {
+					first = entry.Time
+				}
+
+				last = entry.Time
+			}
+		}
+
+		start := time.Now()
+
+		require.NoError(t, rt.Replay(ctx, entries, runtime.WithReplayTiming(), runtime.WithReplaySettle(10*time.Millisecond)))
+
+		// relative times of the inputs are kept
+		assert.GreaterOrEqual(t, time.Since(start), last.Sub(first))
+
+		waitStr(replaySt, "two")
+	})
+
+	str, err := safe.StateGet[*conformance.StrResource](ctx, replaySt, conformance.NewStrResource("recorded", "one", "").Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, "1", str.Value())
+}
+
+func TestRecordMergedOutputs(t *testing.T) {
+	registerRecordedResources(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("default", "merged", 0), state.WithCreateOwner(controller.MergedOwner(conformance.IntResourceType))))
+
+	var buf lockedBuffer
+
+	recorder := runtime.NewRecorder(&buf)
+
+	rt, err := runtime.NewRuntime(st, logging.DefaultLogger(), runtime.WithRecorder(recorder))
+	require.NoError(t, err)
+
+	results := make(chan error, 1)
+
+	require.NoError(t, rt.RegisterController(&mergingController{name: "merging", results: results}))
+	require.NoError(t, rt.RegisterController(&conformance.IntToStrController{
+		SourceNamespace: "default",
+		TargetNamespace: "default",
+	}))
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	var eg errgroup.Group
+
+	eg.Go(func() error {
+		return rt.Run(runCtx)
+	})
+
+	select {
+	case err = <-results:
+		require.NoError(t, err)
+	case <-ctx.Done():
+		require.FailNow(t, "timed out waiting for controller")
+	}
+
+	// events are processed in order, so once the sentinel is recorded, the merged resource events are processed as well
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("default", "sentinel", 1)))
+
+	var entries []runtime.RecordEntry
+
+	require.Eventually(t, func() bool {
+		entries, err = runtime.ReadRecording(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			return false
+		}
+
+		for _, entry := range entries {
+			if entry.Kind == runtime.RecordInput && entry.ID == "sentinel" {
+				return true
+			}
+		}
+
+		return false
+	}, 10*time.Second, 10*time.Millisecond)
+
+	runCancel()
+
+	require.NoError(t, eg.Wait())
+	require.NoError(t, recorder.Err())
+
+	for _, entry := range entries {
+		if entry.Kind == runtime.RecordInput {
+			assert.NotEqual(t, "merged", entry.ID, "merged output is recorded as an input")
+		}
+	}
+}
+
+func TestFaults(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	rt, err := runtime.NewRuntime(st, logging.DefaultLogger(),
+		runtime.WithFaults(runtime.Faults{
+			ErrorOnCalls:     []int{2, 5},
+			ConflictOnWrites: []int{3},
+			WatchDelay:       100 * time.Millisecond,
+		}, "IntToStrController"),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, rt.RegisterController(&conformance.IntToStrController{
+		SourceNamespace: "faults",
+		TargetNamespace: "faults",
+	}))
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	var eg errgroup.Group
+
+	eg.Go(func() error {
+		return rt.Run(runCtx)
+	})
+
+	for _, id := range []resource.ID{"one", "two", "three"} {
+		require.NoError(t, st.Create(ctx, conformance.NewIntResource("faults", id, 1)))
+	}
+
+	// controller converges despite injected failures
+	for _, id := range []resource.ID{"one", "two", "three"} {
+		_, err = st.WatchFor(ctx, conformance.NewStrResource("faults", id, "").Metadata(), state.WithEventTypes(state.Created, state.Updated))
+		require.NoError(t, err)
+	}
+
+	runCancel()
+
+	require.NoError(t, eg.Wait())
+
+	stats := rt.WakeupStats()
+	require.NotEmpty(t, stats)
+
+	for _, stat := range stats {
+		assert.Equal(t, "IntToStrController", stat.Controller)
+		assert.Equal(t, runtime.WakeupInput, stat.Source)
+		assert.Equal(t, resource.Namespace("faults"), stat.Namespace)
+	}
+}
+
+func TestConfigReload(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	require.NoError(t, st.Create(ctx, meta.NewRuntimeConfig(meta.RuntimeConfigID, meta.RuntimeConfigSpec{
+		ControllerLogLevels: map[string]string{
+			"IntToStrController": "error",
+		},
+		ControllerMinReconcileIntervals: map[string]time.Duration{
+			"StrToSentenceController": 10 * time.Millisecond,
+		},
+		MetricsInterval: 10 * time.Millisecond,
+	})))
+
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	rt, err := runtime.NewRuntime(st, zap.New(core), runtime.WithConfigReload())
+	require.NoError(t, err)
+
+	require.NoError(t, rt.RegisterController(&conformance.IntToStrController{
+		SourceNamespace: "config",
+		TargetNamespace: "config",
+	}))
+
+	require.NoError(t, rt.RegisterController(&conformance.StrToSentenceController{
+		SourceNamespace: "config",
+		TargetNamespace: "config",
+	}))
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	var eg errgroup.Group
+
+	eg.Go(func() error {
+		return rt.Run(runCtx)
+	})
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("config", "one", 1)))
+
+	_, err = st.WatchFor(ctx, conformance.NewStrResource("config", "one", "").Metadata(), state.WithEventTypes(state.Created, state.Updated))
+	require.NoError(t, err)
+
+	_, err = st.WatchFor(ctx, conformance.NewSentenceResource("config", "one", "").Metadata(), state.WithEventTypes(state.Created, state.Updated))
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return logs.FilterMessage("controller wakeups").Len() > 0
+	}, 10*time.Second, 10*time.Millisecond)
+
+	runCancel()
+
+	require.NoError(t, eg.Wait())
+
+	// wakeup stats are logged by the runtime, not by the controller
+	controllerLogs := logs.Filter(func(entry observer.LoggedEntry) bool {
+		return entry.Message != "controller wakeups"
+	})
+
+	assert.Empty(t, controllerLogs.FilterField(logging.Controller("IntToStrController")).All())
+	assert.NotEmpty(t, logs.FilterField(logging.Controller("StrToSentenceController")).All())
+}
+
+func TestRuntimeInfo(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	var previous resource.Version
+
+	// runtime is started twice, the record of the previous run is replaced
+	for i := 0; i < 2; i++ {
+		rt, err := runtime.NewRuntime(st, logging.DefaultLogger(), runtime.WithRuntimeInfo(""), runtime.WithConfigReload())
+		require.NoError(t, err)
+
+		runCtx, runCancel := context.WithCancel(ctx)
+
+		var eg errgroup.Group
+
+		eg.Go(func() error {
+			return rt.Run(runCtx)
+		})
+
+		r, err := st.WatchFor(ctx, meta.NewRuntimeInfo(meta.RuntimeInfoID, meta.RuntimeInfoSpec{}).Metadata(),
+			state.WithEventTypes(state.Created, state.Updated),
+			state.WithCondition(func(r resource.Resource) (bool, error) {
+				return !r.Metadata().Version().Equal(previous), nil
+			}),
+		)
+		require.NoError(t, err)
+
+		info, ok := r.(*meta.RuntimeInfo)
+		require.True(t, ok)
+
+		previous = info.Metadata().Version()
+
+		runCancel()
+
+		require.NoError(t, eg.Wait())
+
+		assert.Equal(t, goruntime.Version(), info.TypedSpec().GoVersion)
+		assert.Equal(t, []string{"configReload"}, info.TypedSpec().Features)
+		assert.False(t, info.TypedSpec().StartTime.IsZero())
+	}
+}
+
+func TestOwnershipIndex(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	// stale entry of the previous run
+	require.NoError(t, st.Create(ctx, meta.NewControllerOutputs("RemovedController", meta.ControllerOutputsSpec{}), state.WithCreateOwner(meta.Owner)))
+
+	rt, err := runtime.NewRuntime(st, logging.DefaultLogger(), runtime.WithOwnershipIndex())
+	require.NoError(t, err)
+
+	require.NoError(t, rt.RegisterController(&conformance.IntToStrController{
+		SourceNamespace: "ownership",
+		TargetNamespace: "ownership",
+	}))
+
+	require.NoError(t, rt.RegisterController(&conformance.SumController{
+		ControllerName:  "SumController1",
+		SourceNamespace: "ownership",
+		TargetNamespace: "ownership",
+		TargetID:        "sum1",
+	}))
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	var eg errgroup.Group
+
+	eg.Go(func() error {
+		return rt.Run(runCtx)
+	})
+
+	_, err = st.WatchFor(ctx, meta.NewResourceOwners(conformance.IntResourceType, meta.ResourceOwnersSpec{}).Metadata(), state.WithEventTypes(state.Created))
+	require.NoError(t, err)
+
+	// controllers registered after the start are published as well
+	require.NoError(t, rt.RegisterController(&conformance.SumController{
+		ControllerName:  "SumController2",
+		SourceNamespace: "ownership",
+		TargetNamespace: "ownership",
+		TargetID:        "sum2",
+	}))
+
+	r, err := st.WatchFor(ctx, meta.NewResourceOwners(conformance.IntResourceType, meta.ResourceOwnersSpec{}).Metadata(),
+		state.WithCondition(func(r resource.Resource) (bool, error) {
+			return len(r.(*meta.ResourceOwners).TypedSpec().SharedControllers) == 2, nil //nolint:forcetypeassert,errcheck
+		}),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"SumController1", "SumController2"}, r.(*meta.ResourceOwners).TypedSpec().SharedControllers) //nolint:forcetypeassert,errcheck
+
+	r, err = st.Get(ctx, meta.NewResourceOwners(conformance.StrResourceType, meta.ResourceOwnersSpec{}).Metadata())
+	require.NoError(t, err)
+
+	assert.Equal(t, "IntToStrController", r.(*meta.ResourceOwners).TypedSpec().ExclusiveController) //nolint:forcetypeassert,errcheck
+
+	r, err = st.Get(ctx, meta.NewControllerOutputs("IntToStrController", meta.ControllerOutputsSpec{}).Metadata())
+	require.NoError(t, err)
+
+	assert.Equal(t, []meta.ControllerOutput{
+		{
+			Type: conformance.StrResourceType,
+			Kind: meta.OutputKindExclusive,
+		},
+	}, r.(*meta.ControllerOutputs).TypedSpec().Outputs) //nolint:forcetypeassert,errcheck
+
+	_, err = st.Get(ctx, meta.NewControllerOutputs("RemovedController", meta.ControllerOutputsSpec{}).Metadata())
+	assert.True(t, state.IsNotFoundError(err))
+
+	runCancel()
+
+	require.NoError(t, eg.Wait())
+}
+
+type periodicController struct {
+	reconciles chan struct{}
+}
+
+func (ctrl *periodicController) Name() string {
+	return "PeriodicController"
+}
+
+func (ctrl *periodicController) Inputs() []controller.Input {
+	return nil
+}
+
+func (ctrl *periodicController) Outputs() []controller.Output {
+	return nil
+}
+
+func (ctrl *periodicController) ReconcileSchedule() controller.Schedule { //nolint:ireturn
+	return controller.Every(10 * time.Millisecond)
+}
+
+func (ctrl *periodicController) Run(ctx context.Context, r controller.Runtime, _ *zap.Logger) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case ctrl.reconciles <- struct{}{}:
+		}
+	}
+}
+
+func TestPeriodicReconcile(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	rt, err := runtime.NewRuntime(state.WrapCore(namespaced.NewState(inmem.Build)), logging.DefaultLogger())
+	require.NoError(t, err)
+
+	ctrl := &periodicController{
+		reconciles: make(chan struct{}),
+	}
+
+	require.NoError(t, rt.RegisterController(ctrl))
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	var eg errgroup.Group
+
+	eg.Go(func() error {
+		return rt.Run(runCtx)
+	})
+
+	// initial reconcile and at least two periodic ones
+	for i := 0; i < 3; i++ {
+		select {
+		case <-ctrl.reconciles:
+		case <-ctx.Done():
+			require.FailNow(t, "timed out waiting for periodic reconcile")
+		}
+	}
+
+	runCancel()
+
+	require.NoError(t, eg.Wait())
+}
+
+func TestPeriodicReconcileFakeClock(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	fake := clock.NewFake(time.Now())
+
+	rt, err := runtime.NewRuntime(state.WrapCore(namespaced.NewState(inmem.Build)), logging.DefaultLogger(), runtime.WithClock(fake))
+	require.NoError(t, err)
+
+	ctrl := &periodicController{
+		reconciles: make(chan struct{}),
+	}
+
+	require.NoError(t, rt.RegisterController(ctrl))
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	var eg errgroup.Group
+
+	eg.Go(func() error {
+		return rt.Run(runCtx)
+	})
+
+	// initial reconcile
+	<-ctrl.reconciles
+
+	for i := 0; i < 2; i++ {
+		// wait for the schedule timer to be armed
+		require.NoError(t, fake.BlockUntil(ctx, 1))
+
+		select {
+		case <-ctrl.reconciles:
+			require.FailNow(t, "reconcile before the clock is advanced")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		fake.Advance(10 * time.Millisecond)
+
+		select {
+		case <-ctrl.reconciles:
+		case <-ctx.Done():
+			require.FailNow(t, "timed out waiting for periodic reconcile")
+		}
+	}
+
+	runCancel()
+
+	require.NoError(t, eg.Wait())
+}
+
+type sampledController struct {
+	reconciles chan struct{}
+}
+
+func (ctrl *sampledController) Name() string {
+	return "SampledController"
+}
+
+func (ctrl *sampledController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace:      "sampled",
+			Type:           conformance.IntResourceType,
+			Kind:           controller.InputWeak,
+			SampleInterval: time.Second,
+		},
+	}
+}
+
+func (ctrl *sampledController) Outputs() []controller.Output {
+	return nil
+}
+
+func (ctrl *sampledController) Run(ctx context.Context, r controller.Runtime, _ *zap.Logger) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case ctrl.reconciles <- struct{}{}:
+		}
+	}
+}
+
+func TestSampledWatchDelay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	fake := clock.NewFake(time.Now())
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	rt, err := runtime.NewRuntime(st, logging.DefaultLogger(),
+		runtime.WithClock(fake),
+		runtime.WithFaults(runtime.Faults{
+			WatchDelay: time.Hour,
+		}, "SampledController"),
+	)
+	require.NoError(t, err)
+
+	ctrl := &sampledController{
+		reconciles: make(chan struct{}),
+	}
+
+	require.NoError(t, rt.RegisterController(ctrl))
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	var eg errgroup.Group
+
+	eg.Go(func() error {
+		return rt.Run(runCtx)
+	})
+
+	// initial reconcile
+	<-ctrl.reconciles
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("sampled", "one", 1)))
+
+	// sampled input is delayed the same way as the other inputs
+	select {
+	case <-ctrl.reconciles:
+		require.FailNow(t, "reconcile before the watch delay")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, fake.BlockUntil(ctx, 1))
+
+	fake.Advance(time.Hour)
+
+	select {
+	case <-ctrl.reconciles:
+	case <-ctx.Done():
+		require.FailNow(t, "timed out waiting for delayed reconcile")
+	}
+
+	runCancel()
+
+	require.NoError(t, eg.Wait())
+}
+
+func TestStepping(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	rt, err := runtime.NewRuntime(st, logging.DefaultLogger(), runtime.WithStepping())
+	require.NoError(t, err)
+
+	ctrl := &conformance.IntToStrController{
+		SourceNamespace: "stepping",
+		TargetNamespace: "stepping",
+	}
+
+	require.NoError(t, rt.RegisterController(ctrl))
+	assert.Equal(t, []string{ctrl.Name()}, rt.PendingReconciles())
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	var eg errgroup.Group
+
+	eg.Go(func() error {
+		return rt.Run(runCtx)
+	})
+
+	// initial reconcile
+	name, err := rt.Step(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Name(), name)
+	assert.Empty(t, rt.PendingReconciles())
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("stepping", "one", 1)))
+
+	require.Eventually(t, func() bool {
+		return len(rt.PendingReconciles()) > 0
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// reconcile is queued, but not delivered until stepped
+	_, err = st.Get(ctx, conformance.NewStrResource("stepping", "one", "").Metadata())
+	assert.True(t, state.IsNotFoundError(err))
+
+	name, err = rt.Step(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Name(), name)
+
+	// Step returns after the reconcile is done
+	str, err := safe.StateGet[*conformance.StrResource](ctx, st, conformance.NewStrResource("stepping", "one", "").Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, "1", str.Value())
+
+	// the finalizer update of the input queues one more reconcile, nothing is queued after it
+	_, err = rt.Step(ctx)
+	require.NoError(t, err)
+
+	stepCtx, stepCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer stepCancel()
+
+	_, err = rt.Step(stepCtx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	runCancel()
+
+	require.NoError(t, eg.Wait())
+
+	rt, err = runtime.NewRuntime(st, logging.DefaultLogger())
+	require.NoError(t, err)
+
+	_, err = rt.Step(ctx)
+	assert.ErrorIs(t, err, runtime.ErrNotStepping)
+}
+
+type triggeredController struct {
+	periodicController
+
+	fire chan struct{}
+}
+
+func (ctrl *triggeredController) Name() string {
+	return "TriggeredController"
+}
+
+func (ctrl *triggeredController) ReconcileSchedule() controller.Schedule { //nolint:ireturn
+	return nil
+}
+
+func (ctrl *triggeredController) ExternalTriggers() []controller.ExternalTrigger {
+	failed := false
+
+	return []controller.ExternalTrigger{
+		controller.ExternalTriggerFunc(func(ctx context.Context, queueReconcile func()) error {
+			if !failed {
+				failed = true
+
+				return errors.New("connection refused")
+			}
+
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ctrl.fire:
+					queueReconcile()
+				}
+			}
+		}),
+	}
+}
+
+func TestExternalTrigger(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	rt, err := runtime.NewRuntime(state.WrapCore(namespaced.NewState(inmem.Build)), logging.DefaultLogger())
+	require.NoError(t, err)
+
+	ctrl := &triggeredController{
+		periodicController: periodicController{
+			reconciles: make(chan struct{}),
+		},
+		fire: make(chan struct{}),
+	}
+
+	require.NoError(t, rt.RegisterController(ctrl))
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	var eg errgroup.Group
+
+	eg.Go(func() error {
+		return rt.Run(runCtx)
+	})
+
+	waitReconcile := func() {
+		select {
+		case <-ctrl.reconciles:
+		case <-ctx.Done():
+			require.FailNow(t, "timed out waiting for reconcile")
+		}
+	}
+
+	// initial reconcile
+	waitReconcile()
+
+	// the trigger is restarted after the failure
+	for i := 0; i < 2; i++ {
+		select {
+		case ctrl.fire <- struct{}{}:
+		case <-ctx.Done():
+			require.FailNow(t, "timed out waiting for trigger")
+		}
+
+		waitReconcile()
+	}
+
+	runCancel()
+
+	require.NoError(t, eg.Wait())
+
+	assert.Equal(t, []runtime.WakeupStat{
+		{
+			Controller: "TriggeredController",
+			Source:     runtime.WakeupExternal,
+			Count:      2,
+		},
+	}, rt.WakeupStats())
+}
+
+type stuckController struct {
+	runs chan struct{}
+}
+
+func (ctrl *stuckController) Name() string {
+	return "StuckController"
+}
+
+func (ctrl *stuckController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: "stuck",
+			Type:      conformance.IntResourceType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+func (ctrl *stuckController) Outputs() []controller.Output {
+	return nil
+}
+
+func (ctrl *stuckController) Run(ctx context.Context, r controller.Runtime, _ *zap.Logger) error {
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-r.EventCh():
+	}
+
+	select {
+	case ctrl.runs <- struct{}{}:
+	case <-ctx.Done():
+		return nil
+	}
+
+	// reconcile never finishes
+	<-ctx.Done()
+
+	return nil
+}
+
+func TestWatchdog(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	core, logs := observer.New(zapcore.WarnLevel)
+
+	rt, err := runtime.NewRuntime(st, zap.New(core), runtime.WithWatchdog(100*time.Millisecond, true))
+	require.NoError(t, err)
+
+	ctrl := &stuckController{
+		runs: make(chan struct{}),
+	}
+
+	require.NoError(t, rt.RegisterController(ctrl))
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	var eg errgroup.Group
+
+	eg.Go(func() error {
+		return rt.Run(runCtx)
+	})
+
+	// controller picks up the initial reconcile and gets stuck
+	<-ctrl.runs
+
+	// controller is restarted by the watchdog
+	select {
+	case <-ctrl.runs:
+	case <-ctx.Done():
+		require.FailNow(t, "timed out waiting for controller restart")
+	}
+
+	runCancel()
+
+	require.NoError(t, eg.Wait())
+
+	stuck := logs.FilterMessage("controller reconcile is stuck").All()
+	require.NotEmpty(t, stuck)
+	assert.Contains(t, stuck[0].ContextMap()["stack"], "stuckController")
+}
+
+type orderedController struct {
+	name       string
+	startAfter []string
+	events     chan<- string
+}
+
+func (ctrl *orderedController) Name() string {
+	return ctrl.name
+}
+
+func (ctrl *orderedController) Inputs() []controller.Input {
+	return nil
+}
+
+func (ctrl *orderedController) Outputs() []controller.Output {
+	return nil
+}
+
+func (ctrl *orderedController) StartAfter() []string {
+	return ctrl.startAfter
+}
+
+func (ctrl *orderedController) Run(ctx context.Context, r controller.Runtime, _ *zap.Logger) error {
+	ctrl.events <- ctrl.name + " started"
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		// simulate slow initial reconcile
+		time.Sleep(50 * time.Millisecond)
+
+		ctrl.events <- ctrl.name + " reconciled"
+	}
+}
+
+func TestStartOrder(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	rt, err := runtime.NewRuntime(state.WrapCore(namespaced.NewState(inmem.Build)), logging.DefaultLogger())
+	require.NoError(t, err)
+
+	events := make(chan string, 10)
+
+	require.NoError(t, rt.RegisterController(&orderedController{name: "second", startAfter: []string{"first"}, events: events}))
+	require.NoError(t, rt.RegisterController(&orderedController{name: "first", events: events}))
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	var eg errgroup.Group
+
+	eg.Go(func() error {
+		return rt.Run(runCtx)
+	})
+
+	var order []string
+
+	for i := 0; i < 4; i++ {
+		select {
+		case event := <-events:
+			order = append(order, event)
+		case <-ctx.Done():
+			require.FailNow(t, "timed out waiting for controllers")
+		}
+	}
+
+	assert.Equal(t, []string{"first started", "first reconciled", "second started", "second reconciled"}, order)
+
+	runCancel()
+
+	require.NoError(t, eg.Wait())
+}
+
+func TestStartOrderCycle(t *testing.T) {
+	rt, err := runtime.NewRuntime(state.WrapCore(namespaced.NewState(inmem.Build)), logging.DefaultLogger())
+	require.NoError(t, err)
+
+	events := make(chan string, 10)
+
+	require.NoError(t, rt.RegisterController(&orderedController{name: "first", startAfter: []string{"second"}, events: events}))
+	require.NoError(t, rt.RegisterController(&orderedController{name: "second", startAfter: []string{"first"}, events: events}))
+
+	assert.ErrorContains(t, rt.Run(context.Background()), "cycle")
+}
+
+type mergingController struct {
+	results chan<- error
+	name    string
+	value   int
+}
+
+func (ctrl *mergingController) Name() string {
+	return ctrl.name
+}
+
+func (ctrl *mergingController) Inputs() []controller.Input {
+	return nil
+}
+
+func (ctrl *mergingController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type:  conformance.IntResourceType,
+			Kind:  controller.OutputMerged,
+			Merge: controller.MergeLabelSubset(),
+		},
+	}
+}
+
+func (ctrl *mergingController) Run(ctx context.Context, r controller.Runtime, _ *zap.Logger) error {
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-r.EventCh():
+	}
+
+	err := r.Modify(ctx, conformance.NewIntResource("default", "merged", 0), func(res resource.Resource) error {
+		res.Metadata().Labels().Set(ctrl.name+"/ready", "true")
+
+		if ctrl.value != 0 {
+			res.(conformance.IntegerResource).SetValue(ctrl.value) //nolint:forcetypeassert
+		}
+
+		return nil
+	})
+
+	select {
+	case <-ctx.Done():
+	case ctrl.results <- err:
+	}
+
+	<-ctx.Done()
+
+	return nil
+}
+
+func TestMergedOutputs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("default", "merged", 0), state.WithCreateOwner(controller.MergedOwner(conformance.IntResourceType))))
+
+	rt, err := runtime.NewRuntime(st, logging.DefaultLogger(), runtime.WithOwnershipIndex())
+	require.NoError(t, err)
+
+	results := make(chan error, 3)
+
+	require.NoError(t, rt.RegisterController(&mergingController{name: "first", results: results}))
+	require.NoError(t, rt.RegisterController(&mergingController{name: "second", results: results}))
+	require.NoError(t, rt.RegisterController(&mergingController{name: "third", value: 42, results: results}))
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	var eg errgroup.Group
+
+	eg.Go(func() error {
+		return rt.Run(runCtx)
+	})
+
+	var failed int
+
+	for i := 0; i < 3; i++ {
+		select {
+		case err = <-results:
+			if err != nil {
+				failed++
+			}
+		case <-ctx.Done():
+			require.FailNow(t, "timed out waiting for controllers")
+		}
+	}
+
+	// the third controller is not allowed to modify the spec
+	assert.Equal(t, 1, failed)
+
+	r, err := st.Get(ctx, conformance.NewIntResource("default", "merged", 0).Metadata())
+	require.NoError(t, err)
+
+	assert.Equal(t, controller.MergedOwner(conformance.IntResourceType), r.Metadata().Owner())
+	assert.Equal(t, 0, r.(conformance.IntegerResource).Value()) //nolint:forcetypeassert
+
+	// the common owner can be resolved to the controllers via the ownership index
+	resourceType, ok := controller.IsMergedOwner(r.Metadata().Owner())
+	require.True(t, ok)
+
+	owners, err := st.Get(ctx, meta.NewResourceOwners(resourceType, meta.ResourceOwnersSpec{}).Metadata())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"first", "second", "third"}, owners.(*meta.ResourceOwners).TypedSpec().MergedControllers) //nolint:forcetypeassert,errcheck
+
+	labels := r.Metadata().Labels()
+
+	for _, key := range []string{"first/ready", "second/ready"} {
+		_, ok := labels.Get(key)
+		assert.True(t, ok, key)
+	}
+
+	runCancel()
+
+	require.NoError(t, eg.Wait())
+
+	rt, err = runtime.NewRuntime(st, logging.DefaultLogger())
+	require.NoError(t, err)
+
+	require.NoError(t, rt.RegisterController(&mergingController{name: "merging"}))
+	assert.Error(t, rt.RegisterController(&conformance.SumController{ControllerName: "sum"}))
+}
+
+type replicaStatusController struct {
+	results chan error
+}
+
+func (ctrl *replicaStatusController) Name() string {
+	return "ReplicaStatusController"
+}
+
+func (ctrl *replicaStatusController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: "default",
+			Type:      stateconformance.ReplicaResourceType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+func (ctrl *replicaStatusController) Outputs() []controller.Output {
+	return nil
+}
+
+func (ctrl *replicaStatusController) Run(ctx context.Context, r controller.Runtime, _ *zap.Logger) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		// resource is not an input of the controller
+		select {
+		case ctrl.results <- controller.UpdateStatus(ctx, r, resource.VersionUndefined, stateconformance.NewReplicaResource("other", "one", 2)):
+		case <-ctx.Done():
+			return nil
+		}
+
+		replica, err := r.Get(ctx, stateconformance.NewReplicaResource("default", "one", 0).Metadata())
+		if err != nil {
+			if state.IsNotFoundError(err) {
+				continue
+			}
+
+			return err
+		}
+
+		status := replica.DeepCopy().(*stateconformance.ReplicaResource) //nolint:forcetypeassert,errcheck
+		status.TypedSpec().Ready = status.TypedSpec().Replicas
+		status.Metadata().BumpVersion()
+
+		select {
+		case ctrl.results <- controller.UpdateStatus(ctx, r, replica.Metadata().Version(), status):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	rt, err := runtime.NewRuntime(st, logging.DefaultLogger())
+	require.NoError(t, err)
+
+	results := make(chan error)
+
+	require.NoError(t, rt.RegisterController(&replicaStatusController{results: results}))
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	var eg errgroup.Group
+
+	eg.Go(func() error {
+		return rt.Run(runCtx)
+	})
+
+	select {
+	case err = <-results:
+		assert.ErrorContains(t, err, "not input or output")
+	case <-ctx.Done():
+		require.FailNow(t, "timed out waiting for status update")
+	}
+
+	require.NoError(t, st.Create(ctx, stateconformance.NewReplicaResource("default", "one", 3)))
+
+	// the status of the input can be updated by the controller
+	for {
+		select {
+		case err = <-results:
+		case <-ctx.Done():
+			require.FailNow(t, "timed out waiting for status update")
+		}
+
+		if err == nil {
+			break
+		}
+	}
+
+	r, err := st.Get(ctx, stateconformance.NewReplicaResource("default", "one", 0).Metadata())
+	require.NoError(t, err)
+
+	assert.Equal(t, stateconformance.ReplicaSpec{Replicas: 3, Ready: 3}, *r.(*stateconformance.ReplicaResource).TypedSpec()) //nolint:forcetypeassert,errcheck
+	assert.Equal(t, "ReplicaStatusController", r.Metadata().StatusOwner())
+
+	runCancel()
+
+	require.NoError(t, eg.Wait())
+}
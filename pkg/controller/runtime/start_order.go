@@ -0,0 +1,107 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+)
+
+// markBootstrapped is called when the controller comes back for the next reconcile event.
+//
+// The controller is considered bootstrapped once it waits for reconcile events with no reconcile pending,
+// which means that the initial reconcile was processed.
+func (adapter *adapter) markBootstrapped() {
+	if len(adapter.ch) > 0 || adapter.isPaused() {
+		return
+	}
+
+	adapter.bootstrapOnce.Do(func() {
+		close(adapter.bootstrapped)
+	})
+}
+
+// waitStartOrder blocks until the controllers which this controller should start after are bootstrapped.
+//
+// waitStartOrder returns false if the context is canceled while waiting.
+func (adapter *adapter) waitStartOrder(ctx context.Context, logger *zap.Logger) bool {
+	dependent, ok := adapter.ctrl.(controller.DependentController)
+	if !ok {
+		return true
+	}
+
+	for _, name := range dependent.StartAfter() {
+		dep, err := adapter.runtime.getController(name)
+		if err != nil {
+			logger.Warn("controller to start after is not registered", zap.String("start_after", name))
+
+			continue
+		}
+
+		logger.Debug("waiting for controller to bootstrap", zap.String("start_after", name))
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-dep.bootstrapped:
+		}
+	}
+
+	return true
+}
+
+// checkStartOrder verifies that there are no cycles in the start order of the controllers.
+//
+// checkStartOrder should be called with controllersMu held.
+func (runtime *Runtime) checkStartOrder() error {
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	marks := make(map[string]int, len(runtime.controllers))
+
+	var visit func(name string) error
+
+	visit = func(name string) error {
+		switch marks[name] {
+		case visiting:
+			return fmt.Errorf("controller %q start order forms a cycle", name)
+		case visited:
+			return nil
+		}
+
+		adapter, exists := runtime.controllers[name]
+		if !exists {
+			return nil
+		}
+
+		marks[name] = visiting
+
+		if dependent, ok := adapter.ctrl.(controller.DependentController); ok {
+			for _, dep := range dependent.StartAfter() {
+				if err := visit(dep); err != nil {
+					return err
+				}
+			}
+		}
+
+		marks[name] = visited
+
+		return nil
+	}
+
+	for name := range runtime.controllers {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+)
+
+// ErrNotStepping is returned from Step if the runtime is not created WithStepping.
+var ErrNotStepping = errors.New("runtime is not in stepping mode")
+
+// stepper queues the reconcile events in the stepping mode, so that they are delivered one by one by Step.
+type stepper struct {
+	// stepMu serializes Step calls
+	stepMu sync.Mutex
+
+	// mu protects queue
+	mu     sync.Mutex
+	queue  []*adapter
+	notify chan struct{}
+}
+
+func newStepper() *stepper {
+	return &stepper{
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// enqueue schedules the reconcile of the controller, reconciles which are already queued are coalesced.
+func (stepper *stepper) enqueue(adapter *adapter) {
+	stepper.mu.Lock()
+	defer stepper.mu.Unlock()
+
+	for _, queued := range stepper.queue {
+		if queued == adapter {
+			return
+		}
+	}
+
+	stepper.queue = append(stepper.queue, adapter)
+
+	signal(stepper.notify)
+}
+
+// next waits for the queued reconcile and removes it from the queue.
+func (stepper *stepper) next(ctx context.Context) (*adapter, error) {
+	for {
+		stepper.mu.Lock()
+
+		if len(stepper.queue) > 0 {
+			adapter := stepper.queue[0]
+			stepper.queue = stepper.queue[1:]
+
+			stepper.mu.Unlock()
+
+			return adapter, nil
+		}
+
+		stepper.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-stepper.notify:
+		}
+	}
+}
+
+func (stepper *stepper) pending() []string {
+	stepper.mu.Lock()
+	defer stepper.mu.Unlock()
+
+	names := make([]string, 0, len(stepper.queue))
+
+	for _, adapter := range stepper.queue {
+		names = append(names, adapter.name)
+	}
+
+	return names
+}
+
+// stepState tracks the controller loop of the adapter in the stepping mode.
+//
+// Each EventCh call returns a fresh channel, so that the next EventCh call signals that the reconcile
+// delivered to the previous channel is done.
+type stepState struct {
+	notify chan struct{}
+
+	// mu protects ch and stopped
+	mu      sync.Mutex
+	ch      chan controller.ReconcileEvent
+	stopped bool
+}
+
+// eventCh publishes the channel the controller waits on for the next reconcile.
+func (step *stepState) eventCh() chan controller.ReconcileEvent {
+	ch := make(chan controller.ReconcileEvent, 1)
+
+	step.mu.Lock()
+	step.ch = ch
+	step.mu.Unlock()
+
+	signal(step.notify)
+
+	return ch
+}
+
+// stop marks the controller as stopped, so that the reconcile being stepped doesn't wait for it.
+func (step *stepState) stop() {
+	step.mu.Lock()
+	step.stopped = true
+	step.mu.Unlock()
+
+	signal(step.notify)
+}
+
+// wait waits for the controller to call EventCh, optionally taking over the published channel.
+func (step *stepState) wait(ctx context.Context, take bool) (chan controller.ReconcileEvent, error) {
+	for {
+		step.mu.Lock()
+
+		ch, stopped := step.ch, step.stopped
+
+		if take {
+			step.ch = nil
+		}
+
+		step.mu.Unlock()
+
+		if ch != nil {
+			return ch, nil
+		}
+
+		if stopped {
+			return nil, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-step.notify:
+		}
+	}
+}
+
+// Step delivers exactly one queued reconcile event and waits for the controller to finish the reconcile.
+//
+// Step is available only in the stepping mode (see WithStepping), where the controllers reconcile only
+// when the test steps them, one at a time in the order the reconcile events were queued.
+// If no reconcile is queued, Step waits for one. Step returns the name of the reconciled controller.
+func (runtime *Runtime) Step(ctx context.Context) (string, error) {
+	if runtime.stepper == nil {
+		return "", ErrNotStepping
+	}
+
+	runtime.stepper.stepMu.Lock()
+	defer runtime.stepper.stepMu.Unlock()
+
+	for {
+		adapter, err := runtime.stepper.next(ctx)
+		if err != nil {
+			return "", fmt.Errorf("error waiting for reconcile: %w", err)
+		}
+
+		if adapter.isPaused() {
+			// reconcile is delivered on resume
+			adapter.triggerReconcile()
+
+			continue
+		}
+
+		ch, err := adapter.step.wait(ctx, true)
+		if err != nil {
+			return "", fmt.Errorf("error waiting for controller %q: %w", adapter.name, err)
+		}
+
+		if ch == nil {
+			// controller is stopped, it can't reconcile anymore
+			continue
+		}
+
+		ch <- controller.ReconcileEvent{}
+
+		adapter.watchdog.delivered()
+
+		if _, err = adapter.step.wait(ctx, false); err != nil {
+			return "", fmt.Errorf("error waiting for controller %q to reconcile: %w", adapter.name, err)
+		}
+
+		return adapter.name, nil
+	}
+}
+
+// PendingReconciles returns the names of the controllers with the queued reconcile in the stepping mode.
+//
+// Controllers are listed in the order Step delivers the reconciles.
+func (runtime *Runtime) PendingReconciles() []string {
+	if runtime.stepper == nil {
+		return nil
+	}
+
+	return runtime.stepper.pending()
+}
+
+// signal wakes up the waiter of the notification channel without blocking.
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,107 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+// WakeupSource is the source of the request to reconcile the controller.
+type WakeupSource string
+
+// Wakeup sources.
+const (
+	// WakeupInput is an event on the controller input.
+	WakeupInput WakeupSource = "input"
+	// WakeupSchedule is a periodic reconcile.
+	WakeupSchedule WakeupSource = "schedule"
+	// WakeupExternal is a reconcile requested by an external trigger.
+	WakeupExternal WakeupSource = "external"
+	// WakeupQueue is a reconcile requested by the controller itself via QueueReconcile.
+	WakeupQueue WakeupSource = "queue"
+)
+
+// WakeupStat is the number of reconcile requests for the controller attributed to the source.
+//
+// Namespace and Type are set only for WakeupInput.
+// Several reconcile requests might be coalesced into a single reconcile.
+type WakeupStat struct {
+	Controller string
+	Source     WakeupSource
+	Namespace  resource.Namespace
+	Type       resource.Type
+	Count      uint64
+}
+
+type wakeupKey struct {
+	Source    WakeupSource
+	Namespace resource.Namespace
+	Type      resource.Type
+}
+
+type wakeupCounters struct {
+	counts map[wakeupKey]uint64
+	mu     sync.Mutex
+}
+
+func (counters *wakeupCounters) inc(key wakeupKey) {
+	counters.mu.Lock()
+	defer counters.mu.Unlock()
+
+	if counters.counts == nil {
+		counters.counts = make(map[wakeupKey]uint64)
+	}
+
+	counters.counts[key]++
+}
+
+func (counters *wakeupCounters) appendStats(stats []WakeupStat, controllerName string) []WakeupStat {
+	counters.mu.Lock()
+	defer counters.mu.Unlock()
+
+	for key, count := range counters.counts {
+		stats = append(stats, WakeupStat{
+			Controller: controllerName,
+			Source:     key.Source,
+			Namespace:  key.Namespace,
+			Type:       key.Type,
+			Count:      count,
+		})
+	}
+
+	return stats
+}
+
+// WakeupStats returns the number of reconcile requests for each controller aggregated by the source.
+//
+// Stats can be used to find out why a controller is reconciled too often.
+func (runtime *Runtime) WakeupStats() []WakeupStat {
+	runtime.controllersMu.RLock()
+	defer runtime.controllersMu.RUnlock()
+
+	var stats []WakeupStat
+
+	for name, adapter := range runtime.controllers {
+		stats = adapter.wakeups.appendStats(stats, name)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		switch {
+		case stats[i].Controller != stats[j].Controller:
+			return stats[i].Controller < stats[j].Controller
+		case stats[i].Source != stats[j].Source:
+			return stats[i].Source < stats[j].Source
+		case stats[i].Namespace != stats[j].Namespace:
+			return stats[i].Namespace < stats[j].Namespace
+		default:
+			return stats[i].Type < stats[j].Type
+		}
+	})
+
+	return stats
+}
@@ -0,0 +1,215 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"bytes"
+	"context"
+	goruntime "runtime"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/clock"
+	"github.com/cosi-project/runtime/pkg/logging"
+)
+
+// watchdog tracks the reconciles of the controller.
+//
+// Reconcile starts when the controller picks up a reconcile event, and it finishes when the controller
+// calls EventCh again to wait for the next event.
+// The controller is considered to be stuck in the reconcile if the reconcile runs for longer than the watchdog timeout.
+//
+// Reconciles are tracked only for the controllers which call EventCh on every iteration of their loop:
+// the time the controller spends away from EventCh is always counted as the reconcile.
+type watchdog struct {
+	reconcileSince time.Time
+	clock          clock.Clock
+	cancel         context.CancelFunc
+	goroutineID    []byte
+
+	mu          sync.Mutex
+	waiting     bool
+	reconciling bool
+	reported    bool
+	canceled    bool
+}
+
+// wait is called when the controller waits for the next reconcile event, so the previous reconcile is finished.
+//
+// If the reconcile event is already pending, the controller picks it up right away.
+func (w *watchdog) wait(pending bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.reconciling = false
+	w.waiting = true
+
+	if pending {
+		w.begin()
+	}
+}
+
+// delivered is called when a reconcile event is queued for the controller.
+//
+// If the controller waits for the event, it picks it up right away.
+func (w *watchdog) delivered() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.waiting {
+		w.begin()
+	}
+}
+
+// undelivered is called when a queued reconcile event is taken back before the controller picked it up.
+func (w *watchdog) undelivered() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.reconciling {
+		w.reconciling = false
+		w.waiting = true
+	}
+}
+
+// begin should be called with mu held.
+func (w *watchdog) begin() {
+	w.waiting = false
+	w.reconciling = true
+	w.reconcileSince = w.clock.Now()
+	w.reported = false
+}
+
+// idle reports whether the controller waits for the next reconcile event.
+func (w *watchdog) idle() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.waiting && !w.reconciling
+}
+
+// start is called from the controller goroutine before the controller is started.
+func (w *watchdog) start(cancel context.CancelFunc) {
+	buf := make([]byte, 64)
+	buf = buf[:goruntime.Stack(buf, false)]
+
+	// stack starts with "goroutine <id> [running]:"
+	var id []byte
+
+	if fields := bytes.Fields(buf); len(fields) > 1 {
+		id = fields[1]
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.cancel = cancel
+	w.goroutineID = id
+	w.canceled = false
+	w.waiting = false
+	w.reconciling = false
+}
+
+// finish is called once when the controller returns, it reports whether the controller was canceled by the watchdog.
+func (w *watchdog) finish() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.cancel = nil
+	w.waiting = false
+	w.reconciling = false
+
+	return w.canceled
+}
+
+// check returns the stack of the controller if the controller is stuck and it wasn't reported yet.
+//
+// The stack is captured before the controller is canceled, so it shows where the reconcile is stuck.
+func (w *watchdog) check(timeout time.Duration, cancel bool) (time.Duration, string, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.reconciling || w.reported || w.cancel == nil {
+		return 0, "", false
+	}
+
+	stuckFor := w.clock.Since(w.reconcileSince)
+	if stuckFor < timeout {
+		return 0, "", false
+	}
+
+	w.reported = true
+
+	stack := goroutineStack(w.goroutineID)
+
+	if cancel {
+		w.canceled = true
+
+		w.cancel()
+	}
+
+	return stuckFor, stack, true
+}
+
+func (runtime *Runtime) runWatchdog(done chan<- struct{}) {
+	defer close(done)
+
+	ticker := runtime.options.Clock.NewTicker(runtime.options.WatchdogTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-runtime.runCtx.Done():
+			return
+		case <-ticker.C():
+		}
+
+		runtime.controllersMu.RLock()
+
+		for _, adapter := range runtime.controllers {
+			stuckFor, stack, stuck := adapter.watchdog.check(runtime.options.WatchdogTimeout, runtime.options.WatchdogCancel)
+			if !stuck {
+				continue
+			}
+
+			runtime.logger.Warn("controller reconcile is stuck",
+				logging.Controller(adapter.name),
+				zap.Duration("stuck_for", stuckFor),
+				zap.Bool("canceled", runtime.options.WatchdogCancel),
+				zap.String("stack", stack),
+			)
+		}
+
+		runtime.controllersMu.RUnlock()
+	}
+}
+
+// goroutineStack returns the stack of the goroutine by ID.
+func goroutineStack(id []byte) string {
+	buf := make([]byte, 1<<16)
+
+	for {
+		n := goruntime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+
+			break
+		}
+
+		buf = make([]byte, 2*len(buf))
+	}
+
+	prefix := append(append([]byte("goroutine "), id...), ' ')
+
+	for _, stack := range bytes.Split(buf, []byte("\n\n")) {
+		if bytes.HasPrefix(stack, prefix) {
+			return string(stack)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,24 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+// UpdateStatus updates the status of the resource.
+//
+// The writer should implement StatusWriter, otherwise an error is returned.
+func UpdateStatus(ctx context.Context, writer Writer, curVersion resource.Version, newResource resource.StatusResource) error {
+	updater, ok := writer.(StatusWriter)
+	if !ok {
+		return fmt.Errorf("writer %T doesn't support status updates", writer)
+	}
+
+	return updater.UpdateStatus(ctx, curVersion, newResource)
+}
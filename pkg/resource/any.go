@@ -5,6 +5,10 @@
 package resource
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+
 	"gopkg.in/yaml.v3"
 )
 
@@ -24,6 +28,11 @@ func (s anySpec) MarshalYAMLBytes() ([]byte, error) {
 	return s.yaml, nil
 }
 
+// MarshalJSON implements json.Marshaler interface.
+func (s anySpec) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.value)
+}
+
 // SpecProto is a protobuf interface of resource spec.
 type SpecProto interface {
 	GetYaml() []byte
@@ -72,3 +81,82 @@ func (a *Any) DeepCopy() Resource { //nolint:ireturn
 		spec: a.spec,
 	}
 }
+
+// Get returns the value at the path in the decoded value.
+//
+// Path should match exactly one value, see ParseJSONPath for the syntax.
+func (a *Any) Get(path string) (interface{}, error) {
+	parsed, err := ParseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := parsed.Eval(a.spec.value)
+
+	switch len(values) {
+	case 0:
+		return nil, fmt.Errorf("path %q not found", path)
+	case 1:
+		return values[0], nil
+	default:
+		return nil, fmt.Errorf("path %q matches %d values", path, len(values))
+	}
+}
+
+// GetString returns the string value at the path in the decoded value.
+func (a *Any) GetString(path string) (string, error) {
+	value, err := a.Get(path)
+	if err != nil {
+		return "", err
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("value at path %q is %T, not a string", path, value)
+	}
+
+	return s, nil
+}
+
+// GetInt returns the integer value at the path in the decoded value.
+func (a *Any) GetInt(path string) (int64, error) {
+	value, err := a.Get(path)
+	if err != nil {
+		return 0, err
+	}
+
+	switch typed := value.(type) {
+	case int:
+		return int64(typed), nil
+	case int64:
+		return typed, nil
+	case uint64:
+		if typed > math.MaxInt64 {
+			return 0, fmt.Errorf("value at path %q overflows int64", path)
+		}
+
+		return int64(typed), nil
+	default:
+		return 0, fmt.Errorf("value at path %q is %T, not an integer", path, value)
+	}
+}
+
+// GetBool returns the boolean value at the path in the decoded value.
+func (a *Any) GetBool(path string) (bool, error) {
+	value, err := a.Get(path)
+	if err != nil {
+		return false, err
+	}
+
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("value at path %q is %T, not a bool", path, value)
+	}
+
+	return b, nil
+}
+
+// Decode unmarshals the spec into the Go value using YAML decoding.
+func (a *Any) Decode(into interface{}) error {
+	return yaml.Unmarshal(a.spec.yaml, into)
+}
@@ -5,13 +5,16 @@
 package resource_test
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 
 	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/resourcetest"
 )
 
 func TestAnyInterfaces(t *testing.T) {
@@ -62,3 +65,111 @@ spec:
     something: [a, b, c]
 		`)+"\n", string(out))
 }
+
+func TestAnyMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	r, err := resource.NewAnyFromProto(&protoMd{}, &protoSpec{})
+	assert.NoError(t, err)
+
+	out, err := resource.MarshalJSON(r)
+	assert.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"metadata": {
+			"namespace": "default",
+			"type": "type",
+			"id": "aaa",
+			"version": "1",
+			"owner": "FooController",
+			"phase": "running",
+			"created": "2021-06-23T19:22:29Z",
+			"updated": "2021-06-23T19:22:29Z",
+			"labels": {"app": "foo", "stage": "initial"},
+			"finalizers": ["resource1", "resource2"]
+		},
+		"spec": {"value": "xyz", "something": ["a", "b", "c"]}
+	}`, string(out))
+}
+
+type accessorSpec struct{}
+
+func (s *accessorSpec) GetYaml() []byte {
+	return []byte(`name: xyz
+replicas: 3
+enabled: true
+ports:
+  - name: http
+    port: 80
+  - name: https
+    port: 443
+`)
+}
+
+func TestAnyAccessors(t *testing.T) {
+	t.Parallel()
+
+	r, err := resource.NewAnyFromProto(&protoMd{}, &accessorSpec{})
+	require.NoError(t, err)
+
+	name, err := r.GetString(".name")
+	require.NoError(t, err)
+	assert.Equal(t, "xyz", name)
+
+	replicas, err := r.GetInt("{.replicas}")
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, replicas)
+
+	enabled, err := r.GetBool(".enabled")
+	require.NoError(t, err)
+	assert.True(t, enabled)
+
+	port, err := r.GetInt(".ports[-1].port")
+	require.NoError(t, err)
+	assert.EqualValues(t, 443, port)
+
+	_, err = r.GetString(".missing")
+	assert.EqualError(t, err, `path ".missing" not found`)
+
+	_, err = r.GetString(".ports[*].name")
+	assert.EqualError(t, err, `path ".ports[*].name" matches 2 values`)
+
+	_, err = r.GetInt(".name")
+	assert.EqualError(t, err, `value at path ".name" is string, not an integer`)
+
+	var decoded struct {
+		Ports []struct {
+			Name string `yaml:"name"`
+			Port int    `yaml:"port"`
+		} `yaml:"ports"`
+	}
+
+	require.NoError(t, r.Decode(&decoded))
+	require.Len(t, decoded.Ports, 2)
+	assert.Equal(t, "https", decoded.Ports[1].Name)
+}
+
+type yamlSpecProto string
+
+func (s yamlSpecProto) GetYaml() []byte {
+	return []byte(s)
+}
+
+func FuzzNewAnyFromProto(f *testing.F) {
+	for _, seed := range resourcetest.YAMLCorpus() {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, spec string) {
+		r, err := resource.NewAnyFromProto(&protoMd{}, yamlSpecProto(spec))
+		if err != nil {
+			return
+		}
+
+		// the decoded value is encoded back by the API servers
+		r.DeepCopy()
+
+		resource.MarshalYAML(r) //nolint:errcheck
+		json.Marshal(r.Spec())  //nolint:errcheck,errchkjson
+	})
+}
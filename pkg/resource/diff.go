@@ -0,0 +1,207 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package resource
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+)
+
+// Change describes a single changed field between resource versions.
+type Change struct {
+	// Path of the field, e.g. `metadata.labels.app` or `spec.addresses`.
+	Path string
+	// Old and New values in human-readable form, empty if the field is not set.
+	Old string
+	New string
+}
+
+// String implements fmt.Stringer.
+func (change Change) String() string {
+	return fmt.Sprintf("%s: %q -> %q", change.Path, change.Old, change.New)
+}
+
+// Changes is a list of changes between resource versions.
+type Changes []Change
+
+// String implements fmt.Stringer.
+func (changes Changes) String() string {
+	lines := make([]string, 0, len(changes))
+
+	for _, change := range changes {
+		lines = append(lines, change.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Diff returns the changes of metadata and spec between two versions of the resource.
+//
+// Creation and update timestamps are not compared.
+// Specs backed by protobuf messages are compared field by field, other specs are compared as a whole.
+func Diff(oldResource, newResource Resource) Changes {
+	var changes Changes
+
+	add := func(path, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, Change{Path: path, Old: oldValue, New: newValue})
+		}
+	}
+
+	oldMd, newMd := oldResource.Metadata(), newResource.Metadata()
+
+	add("metadata.namespace", oldMd.Namespace(), newMd.Namespace())
+	add("metadata.type", oldMd.Type(), newMd.Type())
+	add("metadata.id", oldMd.ID(), newMd.ID())
+	add("metadata.version", oldMd.Version().String(), newMd.Version().String())
+	add("metadata.owner", oldMd.Owner(), newMd.Owner())
+	add("metadata.phase", oldMd.Phase().String(), newMd.Phase().String())
+
+	for _, key := range mapKeys(oldMd.Labels().Raw(), newMd.Labels().Raw()) {
+		oldValue, _ := oldMd.Labels().Get(key)
+		newValue, _ := newMd.Labels().Get(key)
+
+		add("metadata.labels."+key, oldValue, newValue)
+	}
+
+	add("metadata.finalizers", strings.Join(*oldMd.Finalizers(), ", "), strings.Join(*newMd.Finalizers(), ", "))
+
+	if IsTombstone(oldResource) || IsTombstone(newResource) {
+		return changes
+	}
+
+	oldSpec, newSpec := oldResource.Spec(), newResource.Spec()
+
+	oldMsg, oldOk := protoSpecValue(oldSpec)
+	newMsg, newOk := protoSpecValue(newSpec)
+
+	if oldOk && newOk && oldMsg.Descriptor() == newMsg.Descriptor() {
+		diffMessage("spec", oldMsg, newMsg, add)
+
+		return changes
+	}
+
+	add("spec", renderSpec(oldSpec), renderSpec(newSpec))
+
+	return changes
+}
+
+func mapKeys(maps ...map[string]string) []string {
+	seen := map[string]struct{}{}
+
+	var keys []string
+
+	for _, m := range maps {
+		for k := range m {
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				keys = append(keys, k)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func protoSpecValue(spec interface{}) (protoreflect.Message, bool) { //nolint:ireturn
+	protoSpec, ok := spec.(interface {
+		GetValue() proto.Message
+	})
+	if !ok {
+		return nil, false
+	}
+
+	return protoSpec.GetValue().ProtoReflect(), true
+}
+
+func renderSpec(spec interface{}) string {
+	out, err := yaml.Marshal(spec)
+	if err != nil {
+		return fmt.Sprintf("%v", spec)
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+func diffMessage(path string, oldMsg, newMsg protoreflect.Message, add func(path, oldValue, newValue string)) {
+	fields := oldMsg.Descriptor().Fields()
+
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		fieldPath := path + "." + field.JSONName()
+
+		if field.Kind() == protoreflect.MessageKind && !field.IsList() && !field.IsMap() {
+			diffMessage(fieldPath, oldMsg.Get(field).Message(), newMsg.Get(field).Message(), add)
+
+			continue
+		}
+
+		add(fieldPath, renderField(field, oldMsg), renderField(field, newMsg))
+	}
+}
+
+func renderField(field protoreflect.FieldDescriptor, msg protoreflect.Message) string {
+	if !msg.Has(field) {
+		return ""
+	}
+
+	value := msg.Get(field)
+
+	switch {
+	case field.IsList():
+		list := value.List()
+		items := make([]string, 0, list.Len())
+
+		for i := 0; i < list.Len(); i++ {
+			items = append(items, renderValue(field, list.Get(i)))
+		}
+
+		return "[" + strings.Join(items, ", ") + "]"
+	case field.IsMap():
+		items := map[string]string{}
+
+		value.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			items[k.String()] = renderValue(field.MapValue(), v)
+
+			return true
+		})
+
+		keys := mapKeys(items)
+		rendered := make([]string, 0, len(keys))
+
+		for _, k := range keys {
+			rendered = append(rendered, k+": "+items[k])
+		}
+
+		return "{" + strings.Join(rendered, ", ") + "}"
+	default:
+		return renderValue(field, value)
+	}
+}
+
+func renderValue(field protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return "{" + prototext.MarshalOptions{}.Format(value.Message().Interface()) + "}"
+	case protoreflect.EnumKind:
+		if enumValue := field.Enum().Values().ByNumber(value.Enum()); enumValue != nil {
+			return string(enumValue.Name())
+		}
+
+		return fmt.Sprint(value.Enum())
+	case protoreflect.BytesKind:
+		return fmt.Sprintf("%x", value.Bytes())
+	default:
+		return value.String()
+	}
+}
@@ -0,0 +1,60 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/cosi-project/runtime/api/v1alpha1"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+)
+
+type diffSpec = protobuf.ResourceSpec[v1alpha1.Metadata, *v1alpha1.Metadata]
+
+type diffRD struct{}
+
+func (diffRD) ResourceDefinition(resource.Metadata, diffSpec) meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type: "DiffResources.test.cosi.dev",
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	oldResource := typed.NewResource[diffSpec, diffRD](resource.NewMetadata("default", "DiffResources.test.cosi.dev", "aaa", resource.VersionUndefined),
+		protobuf.NewResourceSpec(&v1alpha1.Metadata{
+			Id:         "foo",
+			Finalizers: []string{"a"},
+			Labels:     map[string]string{"app": "foo"},
+		}))
+
+	newResource := oldResource.DeepCopy()
+	newResource.Metadata().BumpVersion()
+	newResource.Metadata().Labels().Set("stage", "initial")
+
+	spec := newResource.Spec().(*diffSpec).Value //nolint:forcetypeassert
+	spec.Id = "bar"
+	spec.Finalizers = append(spec.Finalizers, "b")
+	spec.Created = timestamppb.New(ts)
+
+	changes := resource.Diff(oldResource, newResource)
+
+	assert.Equal(t, resource.Changes{
+		{Path: "metadata.version", Old: "1", New: "2"},
+		{Path: "metadata.labels.stage", Old: "", New: "initial"},
+		{Path: "spec.id", Old: "foo", New: "bar"},
+		{Path: "spec.created.seconds", Old: "", New: "1624476149"},
+		{Path: "spec.finalizers", Old: "[a]", New: "[a, b]"},
+	}, changes)
+
+	assert.Empty(t, resource.Diff(oldResource, oldResource.DeepCopy()))
+}
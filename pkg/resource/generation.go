@@ -0,0 +1,17 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package resource
+
+// ObserveGeneration records the generation of the input in the output resource.
+//
+// Controllers should call ObserveGeneration on the output when it is built from the input spec.
+func ObserveGeneration(output, input Resource) {
+	output.Metadata().SetObservedGeneration(input.Metadata().Generation())
+}
+
+// IsGenerationObserved returns true if the output was built from the latest spec of the input.
+func IsGenerationObserved(output, input Resource) bool {
+	return output.Metadata().ObservedGeneration() >= input.Metadata().Generation()
+}
@@ -0,0 +1,69 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package resource
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// SpecHash returns a stable hash of the resource spec.
+//
+// Specs backed by protobuf messages are hashed over their deterministic protobuf encoding,
+// other specs should implement MarshalProto, and their encoding is expected to be stable.
+func SpecHash(r Resource) (string, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	if msg, ok := protoSpecValue(r.Spec()); ok {
+		data, err = proto.MarshalOptions{Deterministic: true}.Marshal(msg.Interface())
+	} else if marshaler, ok := r.Spec().(interface {
+		MarshalProto() ([]byte, error)
+	}); ok {
+		data, err = marshaler.MarshalProto()
+	} else {
+		err = fmt.Errorf("spec %T doesn't support protobuf marshaling", r.Spec())
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("error marshaling spec of %s: %w", r.Metadata(), err)
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ContentEqual tests two resources for equality of metadata and spec hashes.
+//
+// ContentEqual treats specs which are different in memory but encode to the same
+// content (e.g. nil and empty slices) as equal. If the spec can't be hashed, ContentEqual
+// falls back to Equal.
+func ContentEqual(r1, r2 Resource) bool {
+	if Equal(r1, r2) {
+		return true
+	}
+
+	if !r1.Metadata().Equal(*r2.Metadata()) {
+		return false
+	}
+
+	hash1, err := SpecHash(r1)
+	if err != nil {
+		return false
+	}
+
+	hash2, err := SpecHash(r2)
+	if err != nil {
+		return false
+	}
+
+	return hash1 == hash2
+}
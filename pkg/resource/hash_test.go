@@ -0,0 +1,103 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package resource_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/api/v1alpha1"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+)
+
+type hashSpec struct {
+	Items []string `yaml:"items"`
+}
+
+func (spec hashSpec) DeepCopy() hashSpec {
+	if spec.Items == nil {
+		return spec
+	}
+
+	return hashSpec{Items: append([]string{}, spec.Items...)}
+}
+
+func (spec hashSpec) MarshalProto() ([]byte, error) {
+	return []byte(strings.Join(spec.Items, "\x00")), nil
+}
+
+type hashRD struct{}
+
+func (hashRD) ResourceDefinition(resource.Metadata, hashSpec) meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type: "HashResources.test.cosi.dev",
+	}
+}
+
+type unhashableSpec struct{}
+
+func (spec unhashableSpec) DeepCopy() unhashableSpec { return spec }
+
+type unhashableRD struct{}
+
+func (unhashableRD) ResourceDefinition(resource.Metadata, unhashableSpec) meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type: "HashResources.test.cosi.dev",
+	}
+}
+
+func TestSpecHashProto(t *testing.T) {
+	t.Parallel()
+
+	r := typed.NewResource[diffSpec, diffRD](resource.NewMetadata("default", "DiffResources.test.cosi.dev", "aaa", resource.VersionUndefined),
+		protobuf.NewResourceSpec(&v1alpha1.Metadata{
+			Id:     "foo",
+			Labels: map[string]string{"a": "1", "b": "2", "c": "3", "d": "4"},
+		}))
+
+	hash, err := r.SpecHash()
+	require.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	for i := 0; i < 10; i++ {
+		copyHash, err := resource.SpecHash(r.DeepCopy())
+		require.NoError(t, err)
+		assert.Equal(t, hash, copyHash)
+	}
+
+	r.TypedSpec().Value.Id = "bar"
+
+	changedHash, err := r.SpecHash()
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changedHash)
+}
+
+func TestContentEqual(t *testing.T) {
+	t.Parallel()
+
+	md := resource.NewMetadata("default", "HashResources.test.cosi.dev", "aaa", resource.VersionUndefined)
+
+	r1 := typed.NewResource[hashSpec, hashRD](md, hashSpec{})
+	r2 := typed.NewResource[hashSpec, hashRD](md, hashSpec{Items: []string{}})
+
+	assert.False(t, resource.Equal(r1, r2))
+	assert.True(t, resource.ContentEqual(r1, r2))
+
+	r2.TypedSpec().Items = append(r2.TypedSpec().Items, "a")
+	assert.False(t, resource.ContentEqual(r1, r2))
+
+	_, err := resource.SpecHash(typed.NewResource[unhashableSpec, unhashableRD](md, unhashableSpec{}))
+	assert.Error(t, err)
+
+	r3 := typed.NewResource[hashSpec, hashRD](md, hashSpec{})
+	r3.Metadata().BumpVersion()
+	assert.False(t, resource.ContentEqual(r1, r3))
+}
@@ -0,0 +1,142 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package resource
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPath is a parsed path in the subset of JSONPath: `.field.nested[0].list[*]`.
+//
+// JSONPath navigates the generic Go representation of the decoded YAML/JSON values.
+type JSONPath struct {
+	segments []pathSegment
+}
+
+// pathSegment is either a field name, an index, or a wildcard (all elements).
+type pathSegment struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// ParseJSONPath parses the path, enclosing `{}` are optional.
+func ParseJSONPath(path string) (JSONPath, error) {
+	expr := path
+
+	if strings.HasPrefix(expr, "{") && strings.HasSuffix(expr, "}") {
+		expr = expr[1 : len(expr)-1]
+	}
+
+	var segments []pathSegment
+
+	for len(expr) > 0 {
+		switch expr[0] {
+		case '.':
+			end := strings.IndexAny(expr[1:], ".[")
+			if end == -1 {
+				end = len(expr) - 1
+			}
+
+			field := expr[1 : end+1]
+			if field == "" {
+				return JSONPath{}, fmt.Errorf("empty field name in path %q", path)
+			}
+
+			if field == "*" {
+				segments = append(segments, pathSegment{wildcard: true})
+			} else {
+				segments = append(segments, pathSegment{field: field})
+			}
+
+			expr = expr[end+1:]
+		case '[':
+			end := strings.IndexByte(expr, ']')
+			if end == -1 {
+				return JSONPath{}, fmt.Errorf("unterminated index in path %q", path)
+			}
+
+			index := expr[1:end]
+
+			switch index {
+			case "*", ":":
+				segments = append(segments, pathSegment{wildcard: true})
+			default:
+				i, err := strconv.Atoi(index)
+				if err != nil {
+					return JSONPath{}, fmt.Errorf("invalid index %q in path %q", index, path)
+				}
+
+				segments = append(segments, pathSegment{index: i, isIndex: true})
+			}
+
+			expr = expr[end+1:]
+		default:
+			return JSONPath{}, fmt.Errorf("unexpected %q in path %q", expr[0], path)
+		}
+	}
+
+	return JSONPath{segments: segments}, nil
+}
+
+// Eval returns all values matching the path, missing values are skipped.
+func (path JSONPath) Eval(value interface{}) []interface{} {
+	values := []interface{}{value}
+
+	for _, segment := range path.segments {
+		var next []interface{}
+
+		for _, v := range values {
+			switch {
+			case segment.wildcard:
+				switch typed := v.(type) {
+				case []interface{}:
+					next = append(next, typed...)
+				case map[string]interface{}:
+					for _, key := range sortedKeys(typed) {
+						next = append(next, typed[key])
+					}
+				}
+			case segment.isIndex:
+				if list, ok := v.([]interface{}); ok {
+					i := segment.index
+					if i < 0 {
+						i += len(list)
+					}
+
+					if i >= 0 && i < len(list) {
+						next = append(next, list[i])
+					}
+				}
+			default:
+				if m, ok := v.(map[string]interface{}); ok {
+					if field, exists := m[segment.field]; exists {
+						next = append(next, field)
+					}
+				}
+			}
+		}
+
+		values = next
+	}
+
+	return values
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
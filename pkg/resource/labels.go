@@ -4,7 +4,11 @@
 
 package resource
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
 
 // Labels is a set free-form of key-value pairs.
 //
@@ -65,6 +69,72 @@ func (labels *Labels) Get(key string) (string, bool) {
 	return value, ok
 }
 
+// SetInt sets the label to the canonical (base 10) encoding of the integer.
+func (labels *Labels) SetInt(key string, value int64) {
+	labels.Set(key, strconv.FormatInt(value, 10))
+}
+
+// GetInt gets the label as an integer.
+//
+// If the label is not set, false is returned. If the label value is not an integer, error is returned.
+func (labels *Labels) GetInt(key string) (int64, bool, error) {
+	value, ok := labels.Get(key)
+	if !ok {
+		return 0, false, nil
+	}
+
+	v, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, true, fmt.Errorf("label %q is not an integer: %w", key, err)
+	}
+
+	return v, true, nil
+}
+
+// SetBool sets the label to the canonical encoding of the boolean (`true` or `false`).
+func (labels *Labels) SetBool(key string, value bool) {
+	labels.Set(key, strconv.FormatBool(value))
+}
+
+// GetBool gets the label as a boolean.
+//
+// If the label is not set, false is returned. If the label value is not a boolean, error is returned.
+func (labels *Labels) GetBool(key string) (bool, bool, error) {
+	value, ok := labels.Get(key)
+	if !ok {
+		return false, false, nil
+	}
+
+	v, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, true, fmt.Errorf("label %q is not a boolean: %w", key, err)
+	}
+
+	return v, true, nil
+}
+
+// SetDuration sets the label to the canonical encoding of the duration (e.g. `1m30s`).
+func (labels *Labels) SetDuration(key string, value time.Duration) {
+	labels.Set(key, value.String())
+}
+
+// GetDuration gets the label as a duration.
+//
+// If the label is not set, false is returned. If the label value is not a duration, error is returned.
+func (labels *Labels) GetDuration(key string) (time.Duration, bool, error) {
+	value, ok := labels.Get(key)
+	if !ok {
+		return 0, false, nil
+	}
+
+	v, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, true, fmt.Errorf("label %q is not a duration: %w", key, err)
+	}
+
+	return v, true, nil
+}
+
 // Raw returns the labels map.
 //
 // Label map should not be modified outside of the call.
@@ -72,6 +142,21 @@ func (labels *Labels) Raw() map[string]string {
 	return labels.m
 }
 
+// DeepCopy returns a copy of the labels which doesn't share the map with the original.
+func (labels Labels) DeepCopy() Labels {
+	if labels.m == nil {
+		return Labels{}
+	}
+
+	labelsCopy := make(map[string]string, len(labels.m))
+
+	for k, v := range labels.m {
+		labelsCopy[k] = v
+	}
+
+	return Labels{m: labelsCopy}
+}
+
 // Equal checks label for equality.
 func (labels Labels) Equal(other Labels) bool {
 	// shortcut for common case of having no labels
@@ -6,8 +6,10 @@ package resource_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/cosi-project/runtime/pkg/resource"
 )
@@ -82,3 +84,52 @@ func TestLabels(t *testing.T) {
 		Op:  resource.LabelOpNotExists,
 	}))
 }
+
+func TestLabelsTyped(t *testing.T) {
+	var labels resource.Labels
+
+	labels.SetInt("replicas", 3)
+	labels.SetBool("enabled", true)
+	labels.SetDuration("interval", 90*time.Second)
+
+	assert.Equal(t, map[string]string{"replicas": "3", "enabled": "true", "interval": "1m30s"}, labels.Raw())
+
+	i, ok, err := labels.GetInt("replicas")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 3, i)
+
+	b, ok, err := labels.GetBool("enabled")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, b)
+
+	d, ok, err := labels.GetDuration("interval")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 90*time.Second, d)
+
+	_, ok, err = labels.GetInt("missing")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = labels.GetInt("interval")
+	assert.True(t, ok)
+	assert.ErrorContains(t, err, `label "interval" is not an integer`)
+
+	assert.True(t, labels.Matches(resource.LabelTerm{Key: "replicas", Op: resource.LabelOpEqual, Value: "3"}))
+}
+
+func TestLabelsDeepCopy(t *testing.T) {
+	var labels resource.Labels
+
+	labels.Set("a", "b")
+
+	labelsCopy := labels.DeepCopy()
+	labelsCopy.Raw()["a"] = "c"
+
+	v, _ := labels.Get("a")
+	assert.Equal(t, "b", v)
+
+	assert.True(t, resource.Labels{}.DeepCopy().Empty())
+}
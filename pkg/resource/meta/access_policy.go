@@ -0,0 +1,94 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package meta
+
+import (
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+)
+
+// AccessPolicyType is the type of AccessPolicy.
+const AccessPolicyType = resource.Type("AccessPolicies.meta.cosi.dev")
+
+// Access policy subject prefixes.
+//
+// User and group subjects are kept apart by the prefix, so that a user can't be named after a group.
+const (
+	SubjectUserPrefix  = "user:"
+	SubjectGroupPrefix = "group:"
+)
+
+// AccessPolicy grants the subjects access to the resources.
+type AccessPolicy = typed.Resource[AccessPolicySpec, AccessPolicyRD]
+
+// NewAccessPolicy initializes an AccessPolicy resource.
+func NewAccessPolicy(id resource.ID, spec AccessPolicySpec) *AccessPolicy {
+	return typed.NewResource[AccessPolicySpec, AccessPolicyRD](
+		resource.NewMetadata(NamespaceName, AccessPolicyType, id, resource.VersionUndefined),
+		spec,
+	)
+}
+
+// AccessPolicyRD provides auxiliary methods for AccessPolicy.
+type AccessPolicyRD struct{}
+
+// ResourceDefinition implements core.ResourceDefinitionProvider interface.
+func (AccessPolicyRD) ResourceDefinition(_ resource.Metadata, _ AccessPolicySpec) ResourceDefinitionSpec {
+	return ResourceDefinitionSpec{
+		Type:             AccessPolicyType,
+		DefaultNamespace: NamespaceName,
+		Aliases:          []resource.Type{"policy", "policies"},
+		PrintColumns: []PrintColumn{
+			{
+				Name:     "Subjects",
+				JSONPath: "{.subjects[*]}",
+			},
+		},
+	}
+}
+
+// AccessPolicySpec describes the access granted to the subjects.
+//
+// Access is denied unless some rule of some policy allows it.
+type AccessPolicySpec struct {
+	// Subjects are identity names prefixed with `user:`, or groups prefixed with `group:`.
+	Subjects []string     `yaml:"subjects"`
+	Rules    []AccessRule `yaml:"rules"`
+}
+
+// AccessRule allows the verbs on the resource types in the namespaces.
+//
+// Verbs, namespaces and types match any value if they contain `*`.
+type AccessRule struct {
+	Verbs      []string             `yaml:"verbs"`
+	Namespaces []resource.Namespace `yaml:"namespaces"`
+	Types      []resource.Type      `yaml:"types"`
+	// Sensitive allows reading the specs of the sensitive resources, which are redacted otherwise.
+	Sensitive bool `yaml:"sensitive,omitempty"`
+}
+
+// DeepCopy generates a deep copy of AccessPolicySpec.
+func (spec AccessPolicySpec) DeepCopy() AccessPolicySpec {
+	cp := spec
+
+	if spec.Subjects != nil {
+		cp.Subjects = append([]string(nil), spec.Subjects...)
+	}
+
+	if spec.Rules != nil {
+		cp.Rules = make([]AccessRule, len(spec.Rules))
+
+		for i, rule := range spec.Rules {
+			cp.Rules[i] = AccessRule{
+				Verbs:      append([]string(nil), rule.Verbs...),
+				Namespaces: append([]resource.Namespace(nil), rule.Namespaces...),
+				Types:      append([]resource.Type(nil), rule.Types...),
+				Sensitive:  rule.Sensitive,
+			}
+		}
+	}
+
+	return cp
+}
@@ -0,0 +1,77 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package meta
+
+import (
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+)
+
+// ConditionType is the type of Condition.
+const ConditionType = resource.Type("Conditions.meta.cosi.dev")
+
+// ConditionStatus is the readiness status of a resource.
+type ConditionStatus string
+
+// Condition statuses.
+const (
+	ConditionPending ConditionStatus = "Pending"
+	ConditionReady   ConditionStatus = "Ready"
+	ConditionFailed  ConditionStatus = "Failed"
+)
+
+// Condition describes the readiness of a parent resource rolled up from its dependent resources.
+//
+// Resource ID is the ID of the parent resource.
+type Condition = typed.Resource[ConditionSpec, ConditionRD]
+
+// NewCondition initializes a Condition resource.
+func NewCondition(ns resource.Namespace, id resource.ID) *Condition {
+	return typed.NewResource[ConditionSpec, ConditionRD](
+		resource.NewMetadata(ns, ConditionType, id, resource.VersionUndefined),
+		ConditionSpec{},
+	)
+}
+
+// ConditionRD provides auxiliary methods for Condition.
+type ConditionRD struct{}
+
+// ResourceDefinition implements core.ResourceDefinitionProvider interface.
+func (ConditionRD) ResourceDefinition(_ resource.Metadata, _ ConditionSpec) ResourceDefinitionSpec {
+	return ResourceDefinitionSpec{
+		Type:             ConditionType,
+		DefaultNamespace: NamespaceName,
+		PrintColumns: []PrintColumn{
+			{
+				Name:     "Status",
+				JSONPath: "{.status}",
+			},
+			{
+				Name:     "Ready",
+				JSONPath: "{.ready}",
+			},
+			{
+				Name:     "Total",
+				JSONPath: "{.total}",
+			},
+		},
+	}
+}
+
+// ConditionSpec describes the rolled up status.
+type ConditionSpec struct {
+	Status ConditionStatus `yaml:"status"`
+	// Failed lists the dependent resources which failed.
+	Failed []string `yaml:"failed,omitempty"`
+	Ready  int      `yaml:"ready"`
+	Total  int      `yaml:"total"`
+}
+
+// DeepCopy generates a deep copy of ConditionSpec.
+func (c ConditionSpec) DeepCopy() ConditionSpec {
+	c.Failed = append([]string(nil), c.Failed...)
+
+	return c
+}
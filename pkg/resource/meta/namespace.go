@@ -32,12 +32,26 @@ func (NamespaceRD) ResourceDefinition(_ resource.Metadata, _ NamespaceSpec) Reso
 		Type:             NamespaceType,
 		DefaultNamespace: NamespaceName,
 		Aliases:          []resource.Type{"ns"},
+		PrintColumns: []PrintColumn{
+			{
+				Name:     "Owner",
+				JSONPath: "{.owner}",
+			},
+			{
+				Name:     "Description",
+				JSONPath: "{.description}",
+			},
+		},
 	}
 }
 
 // NamespaceSpec provides Namespace definition.
+//
+// Labels of the namespace are kept in the resource metadata.
 type NamespaceSpec struct {
 	Description string `yaml:"description"`
+	// Owner is the component or the team responsible for the namespace, it's informational only.
+	Owner string `yaml:"owner,omitempty"`
 }
 
 // DeepCopy generates a deep copy of NamespaceSpec.
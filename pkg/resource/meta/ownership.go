@@ -0,0 +1,140 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package meta
+
+import (
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+)
+
+// ControllerOutputsType is the type of ControllerOutputs.
+const ControllerOutputsType = resource.Type("ControllerOutputs.meta.cosi.dev")
+
+// ResourceOwnersType is the type of ResourceOwners.
+const ResourceOwnersType = resource.Type("ResourceOwners.meta.cosi.dev")
+
+// Output kinds of ControllerOutput.
+const (
+	OutputKindExclusive = "exclusive"
+	OutputKindShared    = "shared"
+	OutputKindMerged    = "merged"
+)
+
+// ControllerOutputs lists the resource types managed by the controller, the ID is the controller name.
+type ControllerOutputs = typed.Resource[ControllerOutputsSpec, ControllerOutputsRD]
+
+// NewControllerOutputs initializes a ControllerOutputs resource.
+func NewControllerOutputs(id resource.ID, spec ControllerOutputsSpec) *ControllerOutputs {
+	return typed.NewResource[ControllerOutputsSpec, ControllerOutputsRD](
+		resource.NewMetadata(NamespaceName, ControllerOutputsType, id, resource.VersionUndefined),
+		spec,
+	)
+}
+
+// ControllerOutputsRD provides auxiliary methods for ControllerOutputs.
+type ControllerOutputsRD struct{}
+
+// ResourceDefinition implements core.ResourceDefinitionProvider interface.
+func (ControllerOutputsRD) ResourceDefinition(_ resource.Metadata, _ ControllerOutputsSpec) ResourceDefinitionSpec {
+	return ResourceDefinitionSpec{
+		Type:             ControllerOutputsType,
+		DefaultNamespace: NamespaceName,
+		PrintColumns: []PrintColumn{
+			{
+				Name:     "Outputs",
+				JSONPath: "{.outputs[*].type}",
+			},
+		},
+	}
+}
+
+// ControllerOutput describes a single output of the controller.
+type ControllerOutput struct {
+	Type resource.Type `yaml:"type"`
+	// Kind is one of OutputKindExclusive, OutputKindShared or OutputKindMerged.
+	Kind string `yaml:"kind"`
+}
+
+// ControllerOutputsSpec describes the outputs of the controller.
+type ControllerOutputsSpec struct {
+	Outputs []ControllerOutput `yaml:"outputs"`
+}
+
+// DeepCopy generates a deep copy of ControllerOutputsSpec.
+func (spec ControllerOutputsSpec) DeepCopy() ControllerOutputsSpec {
+	cp := spec
+
+	if spec.Outputs != nil {
+		cp.Outputs = make([]ControllerOutput, len(spec.Outputs))
+		copy(cp.Outputs, spec.Outputs)
+	}
+
+	return cp
+}
+
+// ResourceOwners lists the controllers which manage the resource type, the ID is the resource type.
+type ResourceOwners = typed.Resource[ResourceOwnersSpec, ResourceOwnersRD]
+
+// NewResourceOwners initializes a ResourceOwners resource.
+func NewResourceOwners(id resource.ID, spec ResourceOwnersSpec) *ResourceOwners {
+	return typed.NewResource[ResourceOwnersSpec, ResourceOwnersRD](
+		resource.NewMetadata(NamespaceName, ResourceOwnersType, id, resource.VersionUndefined),
+		spec,
+	)
+}
+
+// ResourceOwnersRD provides auxiliary methods for ResourceOwners.
+type ResourceOwnersRD struct{}
+
+// ResourceDefinition implements core.ResourceDefinitionProvider interface.
+func (ResourceOwnersRD) ResourceDefinition(_ resource.Metadata, _ ResourceOwnersSpec) ResourceDefinitionSpec {
+	return ResourceDefinitionSpec{
+		Type:             ResourceOwnersType,
+		DefaultNamespace: NamespaceName,
+		PrintColumns: []PrintColumn{
+			{
+				Name:     "Exclusive",
+				JSONPath: "{.exclusiveController}",
+			},
+			{
+				Name:     "Shared",
+				JSONPath: "{.sharedControllers[*]}",
+			},
+			{
+				Name:     "Merged",
+				JSONPath: "{.mergedControllers[*]}",
+			},
+		},
+	}
+}
+
+// ResourceOwnersSpec describes the controllers managing the resource type.
+type ResourceOwnersSpec struct {
+	// ExclusiveController is the controller which has the resource type as the exclusive output.
+	ExclusiveController string `yaml:"exclusiveController,omitempty"`
+	// SharedControllers are the controllers which have the resource type as the shared output.
+	SharedControllers []string `yaml:"sharedControllers,omitempty"`
+	// MergedControllers are the controllers which have the resource type as the merged output.
+	//
+	// Merged resources are owned by the common owner (see controller.MergedOwner) instead of the controller name.
+	MergedControllers []string `yaml:"mergedControllers,omitempty"`
+}
+
+// DeepCopy generates a deep copy of ResourceOwnersSpec.
+func (spec ResourceOwnersSpec) DeepCopy() ResourceOwnersSpec {
+	cp := spec
+
+	if spec.SharedControllers != nil {
+		cp.SharedControllers = make([]string, len(spec.SharedControllers))
+		copy(cp.SharedControllers, spec.SharedControllers)
+	}
+
+	if spec.MergedControllers != nil {
+		cp.MergedControllers = make([]string, len(spec.MergedControllers))
+		copy(cp.MergedControllers, spec.MergedControllers)
+	}
+
+	return cp
+}
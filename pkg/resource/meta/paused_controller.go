@@ -0,0 +1,53 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package meta
+
+import (
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+)
+
+// PausedControllerType is the type of PausedController.
+const PausedControllerType = resource.Type("PausedControllers.meta.cosi.dev")
+
+// PausedController is present for each controller which is paused in the controller runtime.
+//
+// Resource ID is the name of the controller.
+type PausedController = typed.Resource[PausedControllerSpec, PausedControllerRD]
+
+// NewPausedController initializes a PausedController resource.
+func NewPausedController(id resource.ID, spec PausedControllerSpec) *PausedController {
+	return typed.NewResource[PausedControllerSpec, PausedControllerRD](
+		resource.NewMetadata(NamespaceName, PausedControllerType, id, resource.VersionUndefined),
+		spec,
+	)
+}
+
+// PausedControllerRD provides auxiliary methods for PausedController.
+type PausedControllerRD struct{}
+
+// ResourceDefinition implements core.ResourceDefinitionProvider interface.
+func (PausedControllerRD) ResourceDefinition(_ resource.Metadata, _ PausedControllerSpec) ResourceDefinitionSpec {
+	return ResourceDefinitionSpec{
+		Type:             PausedControllerType,
+		DefaultNamespace: NamespaceName,
+		PrintColumns: []PrintColumn{
+			{
+				Name:     "Reason",
+				JSONPath: "{.reason}",
+			},
+		},
+	}
+}
+
+// PausedControllerSpec describes why the controller was paused.
+type PausedControllerSpec struct {
+	Reason string `yaml:"reason"`
+}
+
+// DeepCopy generates a deep copy of PausedControllerSpec.
+func (p PausedControllerSpec) DeepCopy() PausedControllerSpec {
+	return p
+}
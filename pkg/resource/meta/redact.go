@@ -0,0 +1,87 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package meta
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+// Redacted is the value which replaces the spec of the redacted resources.
+const Redacted = "<redacted>"
+
+// IsSensitive returns true if the resource definition of the resource marks it as sensitive.
+//
+// Resources which don't provide the resource definition are considered non-sensitive.
+func IsSensitive(r resource.Resource) bool {
+	provider, ok := r.(ResourceDefinitionProvider)
+	if !ok {
+		return false
+	}
+
+	return provider.ResourceDefinition().Sensitivity == Sensitive
+}
+
+// Redact returns the resource with the spec redacted if the resource is sensitive.
+//
+// Non-sensitive resources are returned as is. Redact should be used before marshaling
+// resources for logs or any other output which is not supposed to contain secrets.
+func Redact(r resource.Resource) resource.Resource { //nolint:ireturn
+	if resource.IsTombstone(r) || !IsSensitive(r) {
+		return r
+	}
+
+	return NewRedacted(*r.Metadata())
+}
+
+// NewRedacted returns a resource with the given metadata and the spec redacted.
+//
+// Redacted resource supports YAML, JSON and protobuf marshaling, protobuf spec is empty.
+func NewRedacted(md resource.Metadata) resource.Resource { //nolint:ireturn
+	return &redactedResource{
+		md: md,
+	}
+}
+
+type redactedResource struct {
+	md resource.Metadata
+}
+
+func (r *redactedResource) String() string {
+	return fmt.Sprintf("%s(%q)", r.md.Type(), r.md.ID())
+}
+
+func (r *redactedResource) Metadata() *resource.Metadata {
+	return &r.md
+}
+
+func (r *redactedResource) Spec() interface{} {
+	return redactedSpec{}
+}
+
+func (r *redactedResource) DeepCopy() resource.Resource { //nolint:ireturn
+	return &redactedResource{
+		md: r.md,
+	}
+}
+
+type redactedSpec struct{}
+
+// MarshalYAML implements yaml.Marshaler interface.
+func (redactedSpec) MarshalYAML() (interface{}, error) {
+	return Redacted, nil
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (redactedSpec) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redacted)
+}
+
+// MarshalProto implements protobuf.ProtoMarshaler interface.
+func (redactedSpec) MarshalProto() ([]byte, error) {
+	return nil, nil
+}
@@ -0,0 +1,67 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package meta_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+)
+
+type secretSpec struct {
+	Key string `yaml:"key"`
+}
+
+func (spec secretSpec) DeepCopy() secretSpec { return spec }
+
+type secretRD struct{}
+
+func (secretRD) ResourceDefinition(resource.Metadata, secretSpec) meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:        "Secrets.test.cosi.dev",
+		Sensitivity: meta.Sensitive,
+	}
+}
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+
+	secret := typed.NewResource[secretSpec, secretRD](resource.NewMetadata("default", "Secrets.test.cosi.dev", "a", resource.VersionUndefined), secretSpec{Key: "foo"})
+
+	assert.True(t, meta.IsSensitive(secret))
+
+	redacted := meta.Redact(secret)
+	assert.True(t, redacted.Metadata().Equal(*secret.Metadata()))
+
+	out, err := resource.MarshalYAML(redacted)
+	require.NoError(t, err)
+
+	marshaled, err := yaml.Marshal(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(marshaled), "spec: <redacted>")
+	assert.NotContains(t, string(marshaled), "foo")
+
+	jsonOut, err := resource.MarshalJSON(redacted)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Spec string `json:"spec"`
+	}
+
+	require.NoError(t, json.Unmarshal(jsonOut, &decoded))
+	assert.Equal(t, meta.Redacted, decoded.Spec)
+
+	ns := meta.NewNamespace("default", meta.NamespaceSpec{})
+
+	assert.False(t, meta.IsSensitive(ns))
+	assert.Same(t, ns, meta.Redact(ns))
+}
@@ -19,6 +19,9 @@ type (
 	// PrintColumn describes extra columns to print for the resources.
 	PrintColumn = spec.PrintColumn
 
+	// CustomPhase declares a custom lifecycle phase of the resource.
+	CustomPhase = spec.CustomPhase
+
 	// ResourceDefinitionSpec provides ResourceDefinition definition.
 	ResourceDefinitionSpec = spec.ResourceDefinitionSpec
 
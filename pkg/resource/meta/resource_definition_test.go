@@ -37,6 +37,35 @@ func TestNSSpec(t *testing.T) {
 	assert.Equal(t, []string{"namespaces", "namespaces.meta", "namespaces.meta.cosi", "ns", "namespace"}, spec.AllAliases)
 }
 
+func TestRDSpecPluralOverride(t *testing.T) {
+	spec := meta.ResourceDefinitionSpec{
+		Type:     "Chaos.test.cosi.dev",
+		Singular: "Chaos",
+	}
+
+	require.NoError(t, spec.Fill())
+
+	assert.Equal(t, "Chaos", spec.DisplayType)
+	assert.Equal(t, []string{"chaos"}, spec.Aliases)
+	assert.Equal(t, []string{"chaos", "chaos.test", "chaos.test.cosi", "chaos"}, spec.AllAliases)
+
+	spec = meta.ResourceDefinitionSpec{
+		Type:     "DNS.test.cosi.dev",
+		Singular: "DNSResolver",
+		Plural:   "DNSResolvers",
+	}
+
+	require.NoError(t, spec.Fill())
+
+	assert.Equal(t, "DNSResolver", spec.DisplayType)
+	assert.Equal(t, []string{"dnsresolver", "dns"}, spec.Aliases)
+	assert.Equal(t, []string{
+		"dns", "dns.test", "dns.test.cosi",
+		"dnsresolvers", "dnsresolvers.test", "dnsresolvers.test.cosi",
+		"dnsresolver", "dns",
+	}, spec.AllAliases)
+}
+
 func TestRDSpecValidation(t *testing.T) {
 	for _, tt := range []struct {
 		name          string
@@ -90,6 +119,38 @@ func TestRDSpecValidation(t *testing.T) {
 			},
 			expectedError: "name should be plural",
 		},
+		{
+			name: "columnFormat",
+			spec: meta.ResourceDefinitionSpec{
+				Type: "Tests.cosi.dev",
+				PrintColumns: []meta.PrintColumn{
+					{
+						Name:     "Size",
+						JSONPath: "{.size}",
+						Format:   "kilobytes",
+					},
+				},
+			},
+			expectedError: "unknown format \"kilobytes\" of column \"Size\"",
+		},
+		{
+			name: "replacedByNotDeprecated",
+			spec: meta.ResourceDefinitionSpec{
+				Type:       "Tests.cosi.dev",
+				ReplacedBy: "NewTests.cosi.dev",
+			},
+			expectedError: "replacedBy and removalVersion can be set only for deprecated types",
+		},
+		{
+			name: "customPhaseTransition",
+			spec: meta.ResourceDefinitionSpec{
+				Type: "Tests.cosi.dev",
+				CustomPhases: []meta.CustomPhase{
+					{Name: "pending", Transitions: []string{"ready"}},
+				},
+			},
+			expectedError: "custom phase \"pending\" transitions to undeclared phase \"ready\"",
+		},
 	} {
 		tt := tt
 
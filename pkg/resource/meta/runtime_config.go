@@ -0,0 +1,92 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package meta
+
+import (
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+)
+
+// RuntimeConfigType is the type of RuntimeConfig.
+const RuntimeConfigType = resource.Type("RuntimeConfigs.meta.cosi.dev")
+
+// RuntimeConfigID is the ID of the RuntimeConfig watched by the controller runtime.
+const RuntimeConfigID = resource.ID("runtime")
+
+// RuntimeConfig tunes the controller runtime without restarting it.
+type RuntimeConfig = typed.Resource[RuntimeConfigSpec, RuntimeConfigRD]
+
+// NewRuntimeConfig initializes a RuntimeConfig resource.
+func NewRuntimeConfig(id resource.ID, spec RuntimeConfigSpec) *RuntimeConfig {
+	return typed.NewResource[RuntimeConfigSpec, RuntimeConfigRD](
+		resource.NewMetadata(NamespaceName, RuntimeConfigType, id, resource.VersionUndefined),
+		spec,
+	)
+}
+
+// RuntimeConfigRD provides auxiliary methods for RuntimeConfig.
+type RuntimeConfigRD struct{}
+
+// ResourceDefinition implements core.ResourceDefinitionProvider interface.
+func (RuntimeConfigRD) ResourceDefinition(_ resource.Metadata, _ RuntimeConfigSpec) ResourceDefinitionSpec {
+	return ResourceDefinitionSpec{
+		Type:             RuntimeConfigType,
+		DefaultNamespace: NamespaceName,
+		PrintColumns: []PrintColumn{
+			{
+				Name:     "LogLevel",
+				JSONPath: "{.logLevel}",
+			},
+		},
+	}
+}
+
+// RuntimeConfigSpec describes the controller runtime settings.
+//
+// Zero values keep the runtime defaults.
+type RuntimeConfigSpec struct {
+	// ControllerLogLevels overrides LogLevel for the controllers by name.
+	ControllerLogLevels map[string]string `yaml:"controllerLogLevels,omitempty"`
+	// LogLevel is the minimum level of the controller logs, e.g. "info".
+	LogLevel string `yaml:"logLevel,omitempty"`
+
+	// BackoffInitialInterval is the initial interval between controller restarts.
+	BackoffInitialInterval time.Duration `yaml:"backoffInitialInterval,omitempty"`
+	// BackoffMaxInterval is the maximum interval between controller restarts.
+	BackoffMaxInterval time.Duration `yaml:"backoffMaxInterval,omitempty"`
+
+	// ControllerMinReconcileIntervals overrides MinReconcileInterval for the controllers by name.
+	ControllerMinReconcileIntervals map[string]time.Duration `yaml:"controllerMinReconcileIntervals,omitempty"`
+	// MinReconcileInterval limits the rate of reconciles, the reconcile requests in between are coalesced.
+	MinReconcileInterval time.Duration `yaml:"minReconcileInterval,omitempty"`
+
+	// MetricsInterval is the interval of logging the controller wakeup stats, zero disables it.
+	MetricsInterval time.Duration `yaml:"metricsInterval,omitempty"`
+}
+
+// DeepCopy generates a deep copy of RuntimeConfigSpec.
+func (spec RuntimeConfigSpec) DeepCopy() RuntimeConfigSpec {
+	cp := spec
+
+	if spec.ControllerLogLevels != nil {
+		cp.ControllerLogLevels = make(map[string]string, len(spec.ControllerLogLevels))
+
+		for k, v := range spec.ControllerLogLevels {
+			cp.ControllerLogLevels[k] = v
+		}
+	}
+
+	if spec.ControllerMinReconcileIntervals != nil {
+		cp.ControllerMinReconcileIntervals = make(map[string]time.Duration, len(spec.ControllerMinReconcileIntervals))
+
+		for k, v := range spec.ControllerMinReconcileIntervals {
+			cp.ControllerMinReconcileIntervals[k] = v
+		}
+	}
+
+	return cp
+}
@@ -0,0 +1,82 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package meta
+
+import (
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+)
+
+// RuntimeInfoType is the type of RuntimeInfo.
+const RuntimeInfoType = resource.Type("RuntimeInfos.meta.cosi.dev")
+
+// RuntimeInfoID is the default ID of the RuntimeInfo published by the controller runtime.
+const RuntimeInfoID = resource.ID("runtime")
+
+// RuntimeInfo describes the build and the features of the running controller runtime.
+type RuntimeInfo = typed.Resource[RuntimeInfoSpec, RuntimeInfoRD]
+
+// NewRuntimeInfo initializes a RuntimeInfo resource.
+func NewRuntimeInfo(id resource.ID, spec RuntimeInfoSpec) *RuntimeInfo {
+	return typed.NewResource[RuntimeInfoSpec, RuntimeInfoRD](
+		resource.NewMetadata(NamespaceName, RuntimeInfoType, id, resource.VersionUndefined),
+		spec,
+	)
+}
+
+// RuntimeInfoRD provides auxiliary methods for RuntimeInfo.
+type RuntimeInfoRD struct{}
+
+// ResourceDefinition implements core.ResourceDefinitionProvider interface.
+func (RuntimeInfoRD) ResourceDefinition(_ resource.Metadata, _ RuntimeInfoSpec) ResourceDefinitionSpec {
+	return ResourceDefinitionSpec{
+		Type:             RuntimeInfoType,
+		DefaultNamespace: NamespaceName,
+		PrintColumns: []PrintColumn{
+			{
+				Name:     "Version",
+				JSONPath: "{.moduleVersion}",
+			},
+			{
+				Name:     "Go",
+				JSONPath: "{.goVersion}",
+			},
+			{
+				Name:     "Uptime",
+				JSONPath: "{.startTime}",
+				Format:   FormatDurationSince,
+			},
+			{
+				Name:     "Features",
+				JSONPath: "{.features[*]}",
+				Priority: 1,
+			},
+		},
+	}
+}
+
+// RuntimeInfoSpec describes the controller runtime build.
+type RuntimeInfoSpec struct {
+	StartTime time.Time `yaml:"startTime"`
+	// ModuleVersion is the version of the runtime module the binary is built with, "(devel)" for local builds.
+	ModuleVersion string `yaml:"moduleVersion"`
+	GoVersion     string `yaml:"goVersion"`
+	// Features lists the enabled runtime options, e.g. "configReload".
+	Features []string `yaml:"features,omitempty"`
+}
+
+// DeepCopy generates a deep copy of RuntimeInfoSpec.
+func (spec RuntimeInfoSpec) DeepCopy() RuntimeInfoSpec {
+	cp := spec
+
+	if spec.Features != nil {
+		cp.Features = make([]string, len(spec.Features))
+		copy(cp.Features, spec.Features)
+	}
+
+	return cp
+}
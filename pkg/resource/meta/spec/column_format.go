@@ -0,0 +1,28 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package spec
+
+// ColumnFormat defines how the value of the print column is formatted.
+// The empty value represents the raw value.
+type ColumnFormat string
+
+// ColumnFormat values.
+const (
+	// FormatRaw prints the value as is.
+	FormatRaw ColumnFormat = ""
+	// FormatDurationSince prints the time passed since the timestamp value.
+	FormatDurationSince ColumnFormat = "durationSince"
+	// FormatBytes prints the numeric value as a human-readable size.
+	FormatBytes ColumnFormat = "bytes"
+	// FormatBool prints the boolean value as a glyph.
+	FormatBool ColumnFormat = "bool"
+)
+
+var allColumnFormats = map[ColumnFormat]struct{}{
+	FormatRaw:           {},
+	FormatDurationSince: {},
+	FormatBytes:         {},
+	FormatBool:          {},
+}
@@ -0,0 +1,99 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package spec
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// CustomPhase declares a custom lifecycle phase of the resource.
+type CustomPhase struct {
+	Name string `yaml:"name"`
+	// Phases the resource can transition to from this phase.
+	Transitions []string `yaml:"transitions,omitempty"`
+}
+
+// DeepCopy generates a deep copy of CustomPhase.
+func (phase CustomPhase) DeepCopy() CustomPhase {
+	cp := phase
+
+	if phase.Transitions != nil {
+		cp.Transitions = make([]string, len(phase.Transitions))
+		copy(cp.Transitions, phase.Transitions)
+	}
+
+	return cp
+}
+
+var customPhaseRegexp = regexp.MustCompile(`^[a-z][A-Za-z0-9]*$`)
+
+func (spec *ResourceDefinitionSpec) validateCustomPhases() error {
+	declared := make(map[string]struct{}, len(spec.CustomPhases))
+
+	for _, phase := range spec.CustomPhases {
+		if !customPhaseRegexp.MatchString(phase.Name) {
+			return fmt.Errorf("custom phase %q doesn't match %q", phase.Name, customPhaseRegexp.String())
+		}
+
+		if _, ok := declared[phase.Name]; ok {
+			return fmt.Errorf("custom phase %q is declared more than once", phase.Name)
+		}
+
+		declared[phase.Name] = struct{}{}
+	}
+
+	for _, phase := range spec.CustomPhases {
+		for _, transition := range phase.Transitions {
+			if _, ok := declared[transition]; !ok {
+				return fmt.Errorf("custom phase %q transitions to undeclared phase %q", phase.Name, transition)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateCustomPhaseTransition checks that the resource can transition between the custom phases.
+//
+// Empty phase means no custom phase: resources can enter any declared phase from it, and
+// leave any phase to it. Otherwise the transition should be declared by the source phase.
+func (spec *ResourceDefinitionSpec) ValidateCustomPhaseTransition(from, to string) error {
+	if from == to || to == "" {
+		return nil
+	}
+
+	var target *CustomPhase
+
+	for i := range spec.CustomPhases {
+		if spec.CustomPhases[i].Name == to {
+			target = &spec.CustomPhases[i]
+
+			break
+		}
+	}
+
+	if target == nil {
+		return fmt.Errorf("custom phase %q is not declared for %q", to, spec.Type)
+	}
+
+	if from == "" {
+		return nil
+	}
+
+	for _, phase := range spec.CustomPhases {
+		if phase.Name != from {
+			continue
+		}
+
+		for _, transition := range phase.Transitions {
+			if transition == to {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("transition from custom phase %q to %q is not allowed for %q", from, to, spec.Type)
+}
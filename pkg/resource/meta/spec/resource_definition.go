@@ -23,6 +23,13 @@ type ResourceDefinitionSpec struct { //nolint:govet
 	// Displayed human-readable type name.
 	DisplayType string `yaml:"displayType"`
 
+	// Singular and plural forms of the type name.
+	//
+	// If set, they bypass the pluralization heuristics: the name of the type is not required
+	// to be plural, and the forms are used as is to build the display type and the aliases.
+	Singular string `yaml:"singular,omitempty"`
+	Plural   string `yaml:"plural,omitempty"`
+
 	// Default namespace to look for the resource if no namespace is given.
 	DefaultNamespace resource.Namespace `yaml:"defaultNamespace"`
 
@@ -31,12 +38,30 @@ type ResourceDefinitionSpec struct { //nolint:govet
 	// All aliases for automatic matching.
 	AllAliases []resource.Type `yaml:"allAliases"`
 
+	// Categories the resource type belongs to (e.g. `all`, `networking`).
+	//
+	// Tooling can act on all types of a category at once.
+	Categories []string `yaml:"categories,omitempty"`
+
 	// Additional columns to print in table output.
 	PrintColumns []PrintColumn `yaml:"printColumns"`
 
+	// Custom lifecycle phases of the resource with the allowed transitions between them.
+	CustomPhases []CustomPhase `yaml:"customPhases,omitempty"`
+
 	// Sensitivity indicates how secret resource of this type is.
 	// The empty value represents a non-sensitive resource.
 	Sensitivity Sensitivity `yaml:"sensitivity,omitempty"`
+
+	// Deprecated marks the resource type as deprecated, reads and writes of the type produce warnings.
+	Deprecated bool `yaml:"deprecated,omitempty"`
+	// ReplacedBy is the type which replaces the deprecated type.
+	ReplacedBy resource.Type `yaml:"replacedBy,omitempty"`
+	// RemovalVersion is the version in which the deprecated type is going to be removed.
+	RemovalVersion string `yaml:"removalVersion,omitempty"`
+
+	// Label keys to be indexed by the state, so that queries on label values are not full scans.
+	IndexedLabels []string `yaml:"indexedLabels,omitempty"`
 }
 
 // ID computes id of the resource definition.
@@ -44,6 +69,27 @@ func (spec *ResourceDefinitionSpec) ID() resource.ID {
 	return strings.ToLower(spec.Type)
 }
 
+// DeprecationWarning returns the warning for the deprecated resource type.
+//
+// If the type is not deprecated, empty string is returned.
+func (spec *ResourceDefinitionSpec) DeprecationWarning() string {
+	if !spec.Deprecated {
+		return ""
+	}
+
+	warning := fmt.Sprintf("resource type %q is deprecated", spec.Type)
+
+	if spec.ReplacedBy != "" {
+		warning += fmt.Sprintf(", use %q instead", spec.ReplacedBy)
+	}
+
+	if spec.RemovalVersion != "" {
+		warning += fmt.Sprintf(", it will be removed in %s", spec.RemovalVersion)
+	}
+
+	return warning
+}
+
 // Fill the spec while validating any missing items.
 func (spec *ResourceDefinitionSpec) Fill() error {
 	parts := strings.SplitN(spec.Type, ".", 2)
@@ -73,19 +119,37 @@ func (spec *ResourceDefinitionSpec) Fill() error {
 		return fmt.Errorf("suffix doesn't match %q", suffixRegexp.String())
 	}
 
-	if !pluralizeClient.IsPlural(name) {
+	singular, plural := spec.Singular, spec.Plural
+
+	if singular == "" && plural == "" && !pluralizeClient.IsPlural(name) {
 		return fmt.Errorf("name should be plural")
 	}
 
-	spec.DisplayType = pluralizeClient.Singular(name)
+	if plural == "" {
+		plural = name
+	}
+
+	if singular == "" {
+		singular = pluralizeClient.Singular(plural)
+	}
+
+	spec.DisplayType = singular
 	spec.Aliases = append(spec.Aliases, strings.ToLower(spec.DisplayType))
 
-	spec.AllAliases = append(spec.AllAliases, strings.ToLower(name))
+	pluralAliases := []string{strings.ToLower(name)}
+
+	if !strings.EqualFold(plural, name) {
+		pluralAliases = append(pluralAliases, strings.ToLower(plural))
+	}
 
 	suffixElements := strings.Split(suffix, ".")
 
-	for i := 1; i < len(suffixElements); i++ {
-		spec.AllAliases = append(spec.AllAliases, strings.Join(append([]string{strings.ToLower(name)}, suffixElements[:i]...), "."))
+	for _, pluralAlias := range pluralAliases {
+		spec.AllAliases = append(spec.AllAliases, pluralAlias)
+
+		for i := 1; i < len(suffixElements); i++ {
+			spec.AllAliases = append(spec.AllAliases, strings.Join(append([]string{pluralAlias}, suffixElements[:i]...), "."))
+		}
 	}
 
 	upperLetters := strings.Map(func(ch rune) rune {
@@ -110,6 +174,36 @@ func (spec *ResourceDefinitionSpec) Fill() error {
 		return fmt.Errorf("unknown sensitivity %q", spec.Sensitivity)
 	}
 
+	for _, column := range spec.PrintColumns {
+		if _, ok := allColumnFormats[column.Format]; !ok {
+			return fmt.Errorf("unknown format %q of column %q", column.Format, column.Name)
+		}
+
+		if column.Priority < 0 || column.Width < 0 {
+			return fmt.Errorf("column %q priority and width should be non-negative", column.Name)
+		}
+	}
+
+	if !spec.Deprecated && (spec.ReplacedBy != "" || spec.RemovalVersion != "") {
+		return fmt.Errorf("replacedBy and removalVersion can be set only for deprecated types")
+	}
+
+	if err := spec.validateCustomPhases(); err != nil {
+		return err
+	}
+
+	for _, category := range spec.Categories {
+		if !categoryRegexp.MatchString(category) {
+			return fmt.Errorf("category %q doesn't match %q", category, categoryRegexp.String())
+		}
+	}
+
+	for _, key := range spec.IndexedLabels {
+		if key == "" {
+			return fmt.Errorf("indexed label key is empty")
+		}
+	}
+
 	return nil
 }
 
@@ -127,22 +221,50 @@ func (spec ResourceDefinitionSpec) DeepCopy() ResourceDefinitionSpec {
 		copy(cp.AllAliases, spec.AllAliases)
 	}
 
+	if spec.Categories != nil {
+		cp.Categories = make([]string, len(spec.Categories))
+		copy(cp.Categories, spec.Categories)
+	}
+
 	if spec.PrintColumns != nil {
 		cp.PrintColumns = make([]PrintColumn, len(spec.PrintColumns))
 		copy(cp.PrintColumns, spec.PrintColumns)
 	}
 
+	if spec.CustomPhases != nil {
+		cp.CustomPhases = make([]CustomPhase, len(spec.CustomPhases))
+
+		for i := range spec.CustomPhases {
+			cp.CustomPhases[i] = spec.CustomPhases[i].DeepCopy()
+		}
+	}
+
+	if spec.IndexedLabels != nil {
+		cp.IndexedLabels = make([]string, len(spec.IndexedLabels))
+		copy(cp.IndexedLabels, spec.IndexedLabels)
+	}
+
 	return cp
 }
 
 var (
 	nameRegexp      = regexp.MustCompile(`^[A-Z][A-Za-z0-9-]+$`)
 	suffixRegexp    = regexp.MustCompile(`^[a-z][a-z0-9-]+(\.[a-z][a-z0-9-]+)*$`)
+	categoryRegexp  = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
 	pluralizeClient = pluralize.NewClient()
 )
 
 // PrintColumn describes extra columns to print for the resources.
-type PrintColumn struct {
+type PrintColumn struct { //nolint:govet
 	Name     string `yaml:"name"`
 	JSONPath string `yaml:"jsonPath"`
+
+	// Format of the value, the empty value prints the value as is.
+	Format ColumnFormat `yaml:"format,omitempty"`
+	// Priority of the column: columns with priority 0 are always printed,
+	// columns with higher priority are printed only in the wide output.
+	Priority int `yaml:"priority,omitempty"`
+	// Width limits the width of the column, longer values are truncated.
+	// The zero value means no limit.
+	Width int `yaml:"width,omitempty"`
 }
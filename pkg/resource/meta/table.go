@@ -0,0 +1,248 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package meta
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+// Table is a table representation of the list of resources.
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// TableOptions configure RenderTable.
+type TableOptions struct {
+	Now         time.Time
+	MaxPriority int
+}
+
+// TableOption applies settings to TableOptions.
+type TableOption func(options *TableOptions)
+
+// WithMaxPriority includes the print columns up to the given priority (wide output).
+//
+// Default value is 0 (only columns with priority 0 are rendered).
+func WithMaxPriority(priority int) TableOption {
+	return func(options *TableOptions) {
+		options.MaxPriority = priority
+	}
+}
+
+// WithNow sets the current time used to format durations.
+//
+// Default value is time.Now().
+func WithNow(now time.Time) TableOption {
+	return func(options *TableOptions) {
+		options.Now = now
+	}
+}
+
+// RenderTable renders the list of resources as a table.
+//
+// Table contains namespace, ID and version of each resource followed by the print columns of the resource definition.
+func RenderTable(definition ResourceDefinitionSpec, resources []resource.Resource, opts ...TableOption) (Table, error) {
+	options := TableOptions{
+		Now: time.Now(),
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var columns []PrintColumn
+
+	for _, column := range definition.PrintColumns {
+		if column.Priority <= options.MaxPriority {
+			columns = append(columns, column)
+		}
+	}
+
+	paths := make([]resource.JSONPath, len(columns))
+
+	table := Table{
+		Header: []string{"NAMESPACE", "ID", "VERSION"},
+		Rows:   make([][]string, 0, len(resources)),
+	}
+
+	for i, column := range columns {
+		path, err := parseJSONPath(column.JSONPath)
+		if err != nil {
+			return Table{}, fmt.Errorf("error parsing column %q: %w", column.Name, err)
+		}
+
+		paths[i] = path
+
+		table.Header = append(table.Header, strings.ToUpper(column.Name))
+	}
+
+	for _, r := range resources {
+		md := r.Metadata()
+		row := []string{md.Namespace(), md.ID(), md.Version().String()}
+
+		spec, err := specValue(r)
+		if err != nil {
+			return Table{}, fmt.Errorf("error decoding spec of %s: %w", md, err)
+		}
+
+		for i, column := range columns {
+			values := paths[i].Eval(spec)
+			formatted := make([]string, 0, len(values))
+
+			for _, value := range values {
+				formatted = append(formatted, formatValue(column.Format, value, options.Now))
+			}
+
+			row = append(row, truncate(strings.Join(formatted, ","), column.Width))
+		}
+
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table, nil
+}
+
+// specValue decodes the spec into generic Go types via the YAML representation.
+func specValue(r resource.Resource) (interface{}, error) {
+	var (
+		out []byte
+		err error
+	)
+
+	if raw, ok := r.Spec().(interface {
+		MarshalYAMLBytes() ([]byte, error)
+	}); ok {
+		out, err = raw.MarshalYAMLBytes()
+	} else {
+		out, err = yaml.Marshal(r.Spec())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+
+	if err = yaml.Unmarshal(out, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// parseJSONPath parses the path of the print column, which should be enclosed in `{}`.
+func parseJSONPath(path string) (resource.JSONPath, error) {
+	if !strings.HasPrefix(path, "{") || !strings.HasSuffix(path, "}") {
+		return resource.JSONPath{}, fmt.Errorf("path %q should be enclosed in {}", path)
+	}
+
+	return resource.ParseJSONPath(path)
+}
+
+func formatValue(format ColumnFormat, value interface{}, now time.Time) string {
+	switch format {
+	case FormatDurationSince:
+		var (
+			timestamp time.Time
+			err       error
+		)
+
+		switch typed := value.(type) {
+		case time.Time:
+			timestamp = typed
+		case string:
+			timestamp, err = time.Parse(time.RFC3339, typed)
+		default:
+			err = fmt.Errorf("not a timestamp")
+		}
+
+		if err != nil || timestamp.IsZero() {
+			break
+		}
+
+		return now.Sub(timestamp).Truncate(time.Second).String()
+	case FormatBytes:
+		switch typed := value.(type) {
+		case int:
+			return formatBytes(float64(typed))
+		case uint64:
+			return formatBytes(float64(typed))
+		case float64:
+			return formatBytes(typed)
+		}
+	case FormatBool:
+		if b, ok := value.(bool); ok {
+			if b {
+				return "✓"
+			}
+
+			return "✗"
+		}
+	case FormatRaw:
+	}
+
+	return formatRaw(value)
+}
+
+func formatRaw(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case time.Time:
+		return typed.Format(time.RFC3339)
+	case []interface{}, map[string]interface{}:
+		out, err := yaml.Marshal(typed)
+		if err != nil {
+			return fmt.Sprintf("%v", typed)
+		}
+
+		return strings.TrimSpace(string(out))
+	default:
+		return fmt.Sprintf("%v", typed)
+	}
+}
+
+func formatBytes(size float64) string {
+	const unit = 1024
+
+	if size < unit {
+		return fmt.Sprintf("%.0f B", size)
+	}
+
+	exp := 0
+
+	for size >= unit && exp < 6 {
+		size /= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", size, "KMGTPE"[exp-1])
+}
+
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+
+	if width == 1 {
+		return "…"
+	}
+
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,93 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package meta_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+)
+
+type diskSpec struct { //nolint:govet
+	Size    int      `yaml:"size"`
+	Ready   bool     `yaml:"ready"`
+	Started string   `yaml:"started"`
+	Paths   []string `yaml:"paths"`
+	Model   string   `yaml:"model"`
+}
+
+func (spec diskSpec) DeepCopy() diskSpec {
+	spec.Paths = append([]string(nil), spec.Paths...)
+
+	return spec
+}
+
+type diskRD struct{}
+
+func (diskRD) ResourceDefinition(resource.Metadata, diskSpec) meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type: "Disks.test.cosi.dev",
+		PrintColumns: []meta.PrintColumn{
+			{Name: "Size", JSONPath: "{.size}", Format: meta.FormatBytes},
+			{Name: "Ready", JSONPath: "{.ready}", Format: meta.FormatBool},
+			{Name: "Age", JSONPath: "{.started}", Format: meta.FormatDurationSince},
+			{Name: "Paths", JSONPath: "{.paths[*]}"},
+			{Name: "Model", JSONPath: "{.model}", Priority: 1, Width: 8},
+		},
+	}
+}
+
+func TestRenderTable(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	disks := []resource.Resource{
+		typed.NewResource[diskSpec, diskRD](resource.NewMetadata("default", "Disks.test.cosi.dev", "sda", resource.VersionUndefined), diskSpec{
+			Size:    3 * 1024 * 1024 * 1024 / 2,
+			Ready:   true,
+			Started: now.Add(-90 * time.Second).Format(time.RFC3339),
+			Paths:   []string{"/dev/sda", "/dev/disk/by-id/a"},
+			Model:   "Samsung SSD 970",
+		}),
+		typed.NewResource[diskSpec, diskRD](resource.NewMetadata("default", "Disks.test.cosi.dev", "sdb", resource.VersionUndefined), diskSpec{
+			Size:  512,
+			Model: "QEMU",
+		}),
+	}
+
+	var rd diskRD
+
+	definition := rd.ResourceDefinition(resource.Metadata{}, diskSpec{})
+
+	table, err := meta.RenderTable(definition, disks, meta.WithNow(now))
+	require.NoError(t, err)
+
+	assert.Equal(t, meta.Table{
+		Header: []string{"NAMESPACE", "ID", "VERSION", "SIZE", "READY", "AGE", "PATHS"},
+		Rows: [][]string{
+			{"default", "sda", "1", "1.5 GiB", "✓", "1m30s", "/dev/sda,/dev/disk/by-id/a"},
+			{"default", "sdb", "1", "512 B", "✗", "", ""},
+		},
+	}, table)
+
+	table, err = meta.RenderTable(definition, disks, meta.WithNow(now), meta.WithMaxPriority(1))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"NAMESPACE", "ID", "VERSION", "SIZE", "READY", "AGE", "PATHS", "MODEL"}, table.Header)
+	assert.Equal(t, "Samsung…", table.Rows[0][7])
+	assert.Equal(t, "QEMU", table.Rows[1][7])
+
+	definition.PrintColumns = []meta.PrintColumn{{Name: "Broken", JSONPath: ".size"}}
+
+	_, err = meta.RenderTable(definition, disks)
+	assert.EqualError(t, err, "error parsing column \"Broken\": path \".size\" should be enclosed in {}")
+}
@@ -5,6 +5,7 @@
 package resource
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"time"
@@ -25,6 +26,11 @@ type Metadata struct {
 	labels  Labels
 	fins    Finalizers
 	phase   Phase
+
+	statusOwner Owner
+	customPhase string
+	gen         uint64
+	observedGen uint64
 }
 
 // NewMetadata builds new metadata.
@@ -62,6 +68,15 @@ func (md Metadata) Copy() Metadata {
 	return md
 }
 
+// WithType returns metadata copy with the resource type replaced.
+//
+// WithType is used to convert resources between the versions of the type.
+func (md Metadata) WithType(typ Type) Metadata {
+	md.typ = typ
+
+	return md
+}
+
 // Version returns resource version.
 func (md Metadata) Version() Version {
 	return md.ver
@@ -96,6 +111,43 @@ func (md *Metadata) BumpVersion() {
 	md.updated = time.Now()
 }
 
+// Generation returns resource generation.
+//
+// Generation is maintained by the state, it starts at 1 and increments only on spec changes.
+func (md Metadata) Generation() uint64 {
+	return md.gen
+}
+
+// SetGeneration updates resource generation.
+//
+// SetGeneration is used by state implementations, controllers should never set the generation.
+func (md *Metadata) SetGeneration(gen uint64) {
+	md.gen = gen
+}
+
+// ObservedGeneration returns the generation of the input last processed by the controller.
+func (md Metadata) ObservedGeneration() uint64 {
+	return md.observedGen
+}
+
+// SetObservedGeneration records the generation of the input processed by the controller.
+func (md *Metadata) SetObservedGeneration(gen uint64) {
+	md.observedGen = gen
+}
+
+// CustomPhase returns the custom lifecycle phase of the resource.
+//
+// Custom phases are declared by the resource definition, and they refine the running phase
+// of the resource (e.g. `pending`, `failed`). Empty value means no custom phase.
+func (md Metadata) CustomPhase() string {
+	return md.customPhase
+}
+
+// SetCustomPhase sets the custom lifecycle phase of the resource.
+func (md *Metadata) SetCustomPhase(phase string) {
+	md.customPhase = phase
+}
+
 // Finalizers returns a reference to the finalizers.
 func (md *Metadata) Finalizers() *Finalizers {
 	return &md.fins
@@ -134,14 +186,31 @@ func (md *Metadata) SetOwner(owner Owner) error {
 	return fmt.Errorf("owner is already set to %q", md.owner)
 }
 
+// StatusOwner returns the owner of the resource status.
+//
+// Status owner is independent of the resource owner, see StatusResource.
+func (md Metadata) StatusOwner() Owner {
+	return md.statusOwner
+}
+
+// SetStatusOwner sets the owner of the resource status.
+//
+// SetStatusOwner is used by state implementations, status owner is claimed by the first status update.
+func (md *Metadata) SetStatusOwner(owner Owner) {
+	md.statusOwner = owner
+}
+
 // String implements fmt.Stringer.
 func (md Metadata) String() string {
 	return fmt.Sprintf("%s(%s/%s@%s)", md.typ, md.ns, md.id, md.ver)
 }
 
 // Equal tests two metadata objects for equality.
+//
+// Timestamps, generation and status owner are maintained by the state, so they are not compared.
 func (md Metadata) Equal(other Metadata) bool {
-	equal := md.ns == other.ns && md.typ == other.typ && md.id == other.id && md.phase == other.phase && md.owner == other.owner && md.ver.Equal(other.ver)
+	equal := md.ns == other.ns && md.typ == other.typ && md.id == other.id && md.phase == other.phase && md.owner == other.owner && md.ver.Equal(other.ver) &&
+		md.observedGen == other.observedGen && md.customPhase == other.customPhase
 	if !equal {
 		return false
 	}
@@ -174,6 +243,10 @@ func (md Metadata) Equal(other Metadata) bool {
 }
 
 // MarshalYAML implements yaml.Marshaller interface.
+//
+// Free-form strings are tagged explicitly, so that the values like "null" or "true" are quoted.
+// Empty strings are kept untagged, as they decode back to the empty string, except for the finalizers,
+// as the empty items of the sequence are skipped on decoding.
 func (md *Metadata) MarshalYAML() (interface{}, error) {
 	var finalizers []*yaml.Node
 
@@ -192,6 +265,7 @@ func (md *Metadata) MarshalYAML() (interface{}, error) {
 		for _, fin := range md.fins {
 			finalizers[1].Content = append(finalizers[1].Content, &yaml.Node{
 				Kind:  yaml.ScalarNode,
+				Tag:   "!!str",
 				Value: fin,
 			})
 		}
@@ -222,9 +296,11 @@ func (md *Metadata) MarshalYAML() (interface{}, error) {
 		for _, k := range keys {
 			labels[1].Content = append(labels[1].Content, &yaml.Node{
 				Kind:  yaml.ScalarNode,
+				Tag:   strTag(k),
 				Value: k,
 			}, &yaml.Node{
 				Kind:  yaml.ScalarNode,
+				Tag:   strTag(md.labels.m[k]),
 				Value: md.labels.m[k],
 			})
 		}
@@ -240,6 +316,7 @@ func (md *Metadata) MarshalYAML() (interface{}, error) {
 				},
 				{
 					Kind:  yaml.ScalarNode,
+					Tag:   strTag(md.ns),
 					Value: md.ns,
 				},
 				{
@@ -248,6 +325,7 @@ func (md *Metadata) MarshalYAML() (interface{}, error) {
 				},
 				{
 					Kind:  yaml.ScalarNode,
+					Tag:   strTag(md.typ),
 					Value: md.typ,
 				},
 				{
@@ -256,6 +334,7 @@ func (md *Metadata) MarshalYAML() (interface{}, error) {
 				},
 				{
 					Kind:  yaml.ScalarNode,
+					Tag:   strTag(md.id),
 					Value: md.id,
 				},
 				{
@@ -272,6 +351,7 @@ func (md *Metadata) MarshalYAML() (interface{}, error) {
 				},
 				{
 					Kind:  yaml.ScalarNode,
+					Tag:   strTag(md.owner),
 					Value: md.owner,
 				},
 				{
@@ -303,6 +383,48 @@ func (md *Metadata) MarshalYAML() (interface{}, error) {
 	}, nil
 }
 
+func strTag(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	return "!!str"
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (md *Metadata) MarshalJSON() ([]byte, error) {
+	var labels map[string]string
+
+	if !md.labels.Empty() {
+		labels = md.labels.m
+	}
+
+	// fields are in the same order as in the YAML definition
+	return json.Marshal(&struct { //nolint:govet
+		Namespace  string            `json:"namespace"`
+		Type       string            `json:"type"`
+		ID         string            `json:"id"`
+		Version    string            `json:"version"`
+		Owner      string            `json:"owner"`
+		Phase      string            `json:"phase"`
+		Created    string            `json:"created"`
+		Updated    string            `json:"updated"`
+		Labels     map[string]string `json:"labels,omitempty"`
+		Finalizers []string          `json:"finalizers,omitempty"`
+	}{
+		Namespace:  md.ns,
+		Type:       md.typ,
+		ID:         md.id,
+		Version:    md.ver.String(),
+		Owner:      md.owner,
+		Phase:      md.phase.String(),
+		Created:    md.created.Format(time.RFC3339),
+		Updated:    md.updated.Format(time.RFC3339),
+		Labels:     labels,
+		Finalizers: md.fins,
+	})
+}
+
 // MetadataProto is an interface for protobuf serialization of Metadata.
 type MetadataProto interface {
 	GetNamespace() string
@@ -315,6 +437,10 @@ type MetadataProto interface {
 	GetCreated() *timestamp.Timestamp
 	GetUpdated() *timestamp.Timestamp
 	GetLabels() map[string]string
+	GetGeneration() uint64
+	GetObservedGeneration() uint64
+	GetStatusOwner() string
+	GetCustomPhase() string
 }
 
 // NewMetadataFromProto builds Metadata object from ProtoMetadata interface data.
@@ -333,6 +459,10 @@ func NewMetadataFromProto(proto MetadataProto) (Metadata, error) {
 	md.SetPhase(phase)
 	md.created = proto.GetCreated().AsTime()
 	md.updated = proto.GetUpdated().AsTime()
+	md.gen = proto.GetGeneration()
+	md.observedGen = proto.GetObservedGeneration()
+	md.statusOwner = proto.GetStatusOwner()
+	md.customPhase = proto.GetCustomPhase()
 
 	if err := md.SetOwner(proto.GetOwner()); err != nil {
 		return md, err
@@ -5,6 +5,7 @@
 package resource_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
@@ -140,6 +141,47 @@ finalizers:
     - '"resource1'
     - resource2
 `, string(out))
+
+	// strings which look like other YAML types are quoted
+	md = resource.NewMetadata("default", "type", "null", resource.VersionUndefined)
+	md.Finalizers().Add("~")
+	md.Labels().Set("true", "1")
+
+	out, err = yaml.Marshal(&md)
+	assert.NoError(t, err)
+	assert.Equal(t, `namespace: default
+type: type
+id: "null"
+version: undefined
+owner:
+phase: running
+`+timestamps+`labels:
+    "true": "1"
+finalizers:
+    - "~"
+`, string(out))
+}
+
+func TestMetadataMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	md := resource.NewMetadata("default", "type", "aaa", resource.VersionUndefined)
+	md.BumpVersion()
+
+	timestamps := fmt.Sprintf(`"created":%q,"updated":%q`, md.Created().Format(time.RFC3339), md.Updated().Format(time.RFC3339))
+
+	out, err := json.Marshal(&md)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"namespace":"default","type":"type","id":"aaa","version":"1","owner":"","phase":"running",`+timestamps+`}`, string(out))
+
+	md.Finalizers().Add("resource1")
+	md.Labels().Set("app", "foo")
+	assert.NoError(t, md.SetOwner("FooController"))
+
+	out, err = json.Marshal(&md)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"namespace":"default","type":"type","id":"aaa","version":"1","owner":"FooController","phase":"running",`+
+		timestamps+`,"labels":{"app":"foo"},"finalizers":["resource1"]}`, string(out))
 }
 
 var ts, _ = time.Parse(time.RFC3339, "2021-06-23T19:22:29Z")
@@ -187,6 +229,22 @@ func (p *protoMd) GetLabels() map[string]string {
 	return map[string]string{"stage": "initial", "app": "foo"}
 }
 
+func (p *protoMd) GetGeneration() uint64 {
+	return 3
+}
+
+func (p *protoMd) GetObservedGeneration() uint64 {
+	return 2
+}
+
+func (p *protoMd) GetStatusOwner() string {
+	return "StatusController"
+}
+
+func (p *protoMd) GetCustomPhase() string {
+	return "pending"
+}
+
 func TestNewMedataFromProto(t *testing.T) {
 	md, err := resource.NewMetadataFromProto(&protoMd{})
 	assert.NoError(t, err)
@@ -201,5 +259,10 @@ func TestNewMedataFromProto(t *testing.T) {
 	other.Labels().Set("stage", "initial")
 	other.Labels().Set("app", "foo")
 
+	other.SetObservedGeneration(2)
+	other.SetCustomPhase("pending")
+
 	assert.True(t, md.Equal(other))
+	assert.EqualValues(t, 3, md.Generation())
+	assert.Equal(t, "StatusController", md.StatusOwner())
 }
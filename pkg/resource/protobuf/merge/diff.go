@@ -0,0 +1,217 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package merge
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Delta is a field-level difference between two versions of the protobuf message.
+//
+// Delta is applied by clearing the Cleared fields first, and then merging the Patch with the default strategies.
+type Delta struct {
+	// Patch holds the fields which were set or changed, nested messages hold only the changed fields.
+	Patch proto.Message
+	// Cleared lists the paths of the fields which were unset, maps which lost keys are cleared and sent in full.
+	Cleared []string
+}
+
+// Diff computes the delta which turns the from message into the to message.
+func Diff(from, to proto.Message) (Delta, error) {
+	oldMsg, newMsg := from.ProtoReflect(), to.ProtoReflect()
+
+	if oldMsg.Descriptor().FullName() != newMsg.Descriptor().FullName() {
+		return Delta{}, fmt.Errorf("message type mismatch: %s and %s", oldMsg.Descriptor().FullName(), newMsg.Descriptor().FullName())
+	}
+
+	patch := newMsg.New()
+
+	var cleared []string
+
+	diffMessage("", oldMsg, newMsg, patch, &cleared)
+
+	return Delta{
+		Patch:   patch.Interface(),
+		Cleared: cleared,
+	}, nil
+}
+
+// Apply the delta to the message, dst is modified in place.
+func Apply(dst proto.Message, delta Delta) error {
+	dstMsg := dst.ProtoReflect()
+
+	for _, path := range delta.Cleared {
+		if err := clearPath(dstMsg, path); err != nil {
+			return err
+		}
+	}
+
+	return Merge(dst, delta.Patch)
+}
+
+func diffMessage(path string, from, to, patch protoreflect.Message, cleared *[]string) {
+	fields := to.Descriptor().Fields()
+
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+
+		fieldPath := string(field.Name())
+
+		if path != "" {
+			fieldPath = path + "." + fieldPath
+		}
+
+		oldSet, newSet := from.Has(field), to.Has(field)
+
+		switch {
+		case !oldSet && !newSet:
+		case !newSet:
+			*cleared = append(*cleared, fieldPath)
+		case !oldSet:
+			patch.Set(field, cloneValue(field, to.Get(field)))
+		case field.IsMap():
+			oldMap, newMap := from.Get(field).Map(), to.Get(field).Map()
+
+			if mapHasRemovedKeys(oldMap, newMap) {
+				*cleared = append(*cleared, fieldPath)
+
+				patch.Set(field, cloneMap(patch, field, newMap))
+
+				continue
+			}
+
+			newMap.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				if !oldMap.Has(k) || !valueEqual(field.MapValue(), oldMap.Get(k), v) {
+					patch.Mutable(field).Map().Set(k, cloneValue(field.MapValue(), v))
+				}
+
+				return true
+			})
+		case field.IsList():
+			if !listEqual(field, from.Get(field).List(), to.Get(field).List()) {
+				patch.Set(field, cloneList(patch, field, to.Get(field).List()))
+			}
+		case field.Message() != nil:
+			nested := patch.Mutable(field).Message()
+
+			diffMessage(fieldPath, from.Get(field).Message(), to.Get(field).Message(), nested, cleared)
+
+			if isEmpty(nested) {
+				patch.Clear(field)
+			}
+		default:
+			if !valueEqual(field, from.Get(field), to.Get(field)) {
+				patch.Set(field, to.Get(field))
+			}
+		}
+	}
+}
+
+func valueEqual(field protoreflect.FieldDescriptor, a, b protoreflect.Value) bool {
+	switch {
+	case field.Message() != nil:
+		return proto.Equal(a.Message().Interface(), b.Message().Interface())
+	case field.Kind() == protoreflect.BytesKind:
+		return bytes.Equal(a.Bytes(), b.Bytes())
+	default:
+		return a.Interface() == b.Interface()
+	}
+}
+
+func listEqual(field protoreflect.FieldDescriptor, a, b protoreflect.List) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+
+	for i := 0; i < a.Len(); i++ {
+		if !valueEqual(field, a.Get(i), b.Get(i)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isEmpty(msg protoreflect.Message) bool {
+	empty := true
+
+	msg.Range(func(protoreflect.FieldDescriptor, protoreflect.Value) bool {
+		empty = false
+
+		return false
+	})
+
+	return empty
+}
+
+func mapHasRemovedKeys(from, to protoreflect.Map) bool {
+	removed := false
+
+	from.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		removed = !to.Has(k)
+
+		return !removed
+	})
+
+	return removed
+}
+
+func cloneMap(msg protoreflect.Message, field protoreflect.FieldDescriptor, src protoreflect.Map) protoreflect.Value {
+	value := msg.NewField(field)
+	dst := value.Map()
+
+	src.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+		dst.Set(k, cloneValue(field.MapValue(), v))
+
+		return true
+	})
+
+	return value
+}
+
+func cloneList(msg protoreflect.Message, field protoreflect.FieldDescriptor, src protoreflect.List) protoreflect.Value {
+	value := msg.NewField(field)
+	dst := value.List()
+
+	for i := 0; i < src.Len(); i++ {
+		dst.Append(cloneValue(field, src.Get(i)))
+	}
+
+	return value
+}
+
+func clearPath(msg protoreflect.Message, path string) error {
+	names := strings.Split(path, ".")
+
+	for i, name := range names {
+		field := msg.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if field == nil {
+			return fmt.Errorf("field %q not found in %s", path, msg.Descriptor().FullName())
+		}
+
+		if i == len(names)-1 {
+			msg.Clear(field)
+
+			return nil
+		}
+
+		if field.Message() == nil || field.IsList() || field.IsMap() {
+			return fmt.Errorf("field %q: %s is not a message", path, name)
+		}
+
+		if !msg.Has(field) {
+			return nil
+		}
+
+		msg = msg.Mutable(field).Message()
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package merge implements merging of protobuf specs with per-field strategies.
+//
+// By default, fields which are set in the source override the destination fields,
+// nested messages and maps are merged recursively, and repeated fields are replaced.
+package merge
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Strategy defines how the field is merged.
+type Strategy int
+
+// Merge strategies.
+const (
+	// Default strategy merges messages and maps recursively, and replaces other fields.
+	Default Strategy = iota
+	// Replace the destination field with the source field.
+	Replace
+	// Append source list items to the destination list.
+	Append
+	// MergeByKey merges list items of message type with the same key field value, and appends new items.
+	MergeByKey
+)
+
+type fieldStrategy struct {
+	key      protoreflect.Name
+	strategy Strategy
+}
+
+// Merger merges protobuf messages.
+type Merger struct {
+	strategies map[string]fieldStrategy
+}
+
+// Option configures Merger.
+type Option func(*Merger)
+
+// WithStrategy sets the strategy for the field.
+//
+// Path is the dot-separated list of protobuf field names starting from the root message, e.g. `network.interfaces`.
+// Items of repeated messages merged by key use the path of the repeated field.
+func WithStrategy(path string, strategy Strategy) Option {
+	return func(merger *Merger) {
+		merger.strategies[path] = fieldStrategy{strategy: strategy}
+	}
+}
+
+// WithMergeKey sets MergeByKey strategy for the repeated message field, items are matched by the key field.
+func WithMergeKey(path, keyField string) Option {
+	return func(merger *Merger) {
+		merger.strategies[path] = fieldStrategy{strategy: MergeByKey, key: protoreflect.Name(keyField)}
+	}
+}
+
+// NewMerger creates new Merger.
+func NewMerger(opts ...Option) *Merger {
+	merger := &Merger{
+		strategies: map[string]fieldStrategy{},
+	}
+
+	for _, opt := range opts {
+		opt(merger)
+	}
+
+	return merger
+}
+
+// Merge src into dst, dst is modified in place.
+func (merger *Merger) Merge(dst, src proto.Message) error {
+	dstMsg, srcMsg := dst.ProtoReflect(), src.ProtoReflect()
+
+	if dstMsg.Descriptor().FullName() != srcMsg.Descriptor().FullName() {
+		return fmt.Errorf("message type mismatch: %s and %s", dstMsg.Descriptor().FullName(), srcMsg.Descriptor().FullName())
+	}
+
+	return merger.mergeMessage("", dstMsg, srcMsg)
+}
+
+// Merge src into dst with the options.
+func Merge(dst, src proto.Message, opts ...Option) error {
+	return NewMerger(opts...).Merge(dst, src)
+}
+
+func (merger *Merger) mergeMessage(path string, dst, src protoreflect.Message) error {
+	var err error
+
+	src.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		fieldPath := string(field.Name())
+
+		if path != "" {
+			fieldPath = path + "." + fieldPath
+		}
+
+		err = merger.mergeField(fieldPath, dst, field, value)
+
+		return err == nil
+	})
+
+	return err
+}
+
+func (merger *Merger) mergeField(path string, dst protoreflect.Message, field protoreflect.FieldDescriptor, value protoreflect.Value) error {
+	strategy := merger.strategies[path]
+
+	switch {
+	case field.IsList():
+		return merger.mergeList(path, dst, field, value.List(), strategy)
+	case field.IsMap():
+		switch strategy.strategy {
+		case Default:
+		case Replace:
+			dst.Clear(field)
+		default:
+			return fmt.Errorf("field %q: strategy %d is not supported for maps", path, strategy.strategy)
+		}
+
+		dstMap := dst.Mutable(field).Map()
+
+		value.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			dstMap.Set(k, cloneValue(field.MapValue(), v))
+
+			return true
+		})
+	case field.Message() != nil:
+		switch strategy.strategy {
+		case Default:
+			return merger.mergeMessage(path, dst.Mutable(field).Message(), value.Message())
+		case Replace:
+			dst.Set(field, cloneValue(field, value))
+		default:
+			return fmt.Errorf("field %q: strategy %d is not supported for messages", path, strategy.strategy)
+		}
+	default:
+		if strategy.strategy != Default && strategy.strategy != Replace {
+			return fmt.Errorf("field %q: strategy %d is not supported for scalar fields", path, strategy.strategy)
+		}
+
+		dst.Set(field, value)
+	}
+
+	return nil
+}
+
+// cloneValue makes a deep copy of the message values, other values are immutable.
+func cloneValue(field protoreflect.FieldDescriptor, value protoreflect.Value) protoreflect.Value {
+	if field.Message() == nil {
+		return value
+	}
+
+	return protoreflect.ValueOfMessage(proto.Clone(value.Message().Interface()).ProtoReflect())
+}
+
+func (merger *Merger) mergeList(path string, dst protoreflect.Message, field protoreflect.FieldDescriptor, src protoreflect.List, strategy fieldStrategy) error {
+	switch strategy.strategy {
+	case Default, Replace:
+		dst.Clear(field)
+
+		fallthrough
+	case Append:
+		dstList := dst.Mutable(field).List()
+
+		for i := 0; i < src.Len(); i++ {
+			dstList.Append(cloneValue(field, src.Get(i)))
+		}
+	case MergeByKey:
+		if field.Message() == nil {
+			return fmt.Errorf("field %q: merge by key requires list of messages", path)
+		}
+
+		keyField := field.Message().Fields().ByName(strategy.key)
+		if keyField == nil || keyField.IsList() || keyField.IsMap() || keyField.Message() != nil || keyField.Kind() == protoreflect.BytesKind {
+			return fmt.Errorf("field %q: key %q is not a scalar field of %s", path, strategy.key, field.Message().FullName())
+		}
+
+		dstList := dst.Mutable(field).List()
+
+		for i := 0; i < src.Len(); i++ {
+			srcItem := src.Get(i).Message()
+			key := srcItem.Get(keyField).Interface()
+
+			found := false
+
+			for j := 0; j < dstList.Len(); j++ {
+				dstItem := dstList.Get(j).Message()
+
+				if dstItem.Get(keyField).Interface() == key {
+					if err := merger.mergeMessage(path, dstItem, srcItem); err != nil {
+						return err
+					}
+
+					found = true
+
+					break
+				}
+			}
+
+			if !found {
+				dstList.Append(cloneValue(field, src.Get(i)))
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package merge_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/cosi-project/runtime/api/v1alpha1"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf/merge"
+)
+
+func TestMergeDefault(t *testing.T) {
+	t.Parallel()
+
+	dst := &v1alpha1.Resource{
+		Metadata: &v1alpha1.Metadata{
+			Namespace:  "default",
+			Id:         "aaa",
+			Finalizers: []string{"a"},
+			Labels:     map[string]string{"app": "foo", "stage": "initial"},
+		},
+	}
+
+	src := &v1alpha1.Resource{
+		Metadata: &v1alpha1.Metadata{
+			Id:         "bbb",
+			Finalizers: []string{"b"},
+			Labels:     map[string]string{"stage": "final"},
+		},
+	}
+
+	require.NoError(t, merge.Merge(dst, src))
+
+	assert.True(t, proto.Equal(&v1alpha1.Resource{
+		Metadata: &v1alpha1.Metadata{
+			Namespace:  "default",
+			Id:         "bbb",
+			Finalizers: []string{"b"},
+			Labels:     map[string]string{"app": "foo", "stage": "final"},
+		},
+	}, dst), "%v", dst)
+
+	// source is not aliased
+	src.Metadata.Finalizers[0] = "c"
+	assert.Equal(t, []string{"b"}, dst.Metadata.Finalizers)
+}
+
+func TestMergeStrategies(t *testing.T) {
+	t.Parallel()
+
+	dst := &v1alpha1.RegisterControllerRequest{
+		ControllerName: "foo",
+		Inputs: []*v1alpha1.ControllerInput{
+			{Type: "A", Namespace: "ns1"},
+			{Type: "B", Namespace: "ns1"},
+		},
+		Outputs: []*v1alpha1.ControllerOutput{
+			{Type: "X"},
+		},
+	}
+
+	src := &v1alpha1.RegisterControllerRequest{
+		Inputs: []*v1alpha1.ControllerInput{
+			{Type: "B", Namespace: "ns2"},
+			{Type: "C", Namespace: "ns2"},
+		},
+		Outputs: []*v1alpha1.ControllerOutput{
+			{Type: "Y", Kind: v1alpha1.ControllerOutputKind_SHARED},
+		},
+	}
+
+	require.NoError(t, merge.Merge(dst, src,
+		merge.WithMergeKey("inputs", "type"),
+		merge.WithStrategy("outputs", merge.Append),
+	))
+
+	assert.True(t, proto.Equal(&v1alpha1.RegisterControllerRequest{
+		ControllerName: "foo",
+		Inputs: []*v1alpha1.ControllerInput{
+			{Type: "A", Namespace: "ns1"},
+			{Type: "B", Namespace: "ns2"},
+			{Type: "C", Namespace: "ns2"},
+		},
+		Outputs: []*v1alpha1.ControllerOutput{
+			{Type: "X"},
+			{Type: "Y", Kind: v1alpha1.ControllerOutputKind_SHARED},
+		},
+	}, dst), "%v", dst)
+
+	assert.Error(t, merge.Merge(dst, src, merge.WithMergeKey("inputs", "missing")))
+	assert.Error(t, merge.Merge(dst, &v1alpha1.RegisterControllerRequest{ControllerName: "bar"}, merge.WithStrategy("controller_name", merge.Append)))
+	assert.Error(t, merge.Merge(dst, &v1alpha1.Resource{}))
+}
+
+func TestMergeReplace(t *testing.T) {
+	t.Parallel()
+
+	dst := &v1alpha1.Metadata{
+		Labels: map[string]string{"app": "foo", "stage": "initial"},
+	}
+
+	require.NoError(t, merge.Merge(dst, &v1alpha1.Metadata{
+		Labels: map[string]string{"stage": "final"},
+	}, merge.WithStrategy("labels", merge.Replace)))
+
+	assert.Equal(t, map[string]string{"stage": "final"}, dst.Labels)
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	old := &v1alpha1.Resource{
+		Metadata: &v1alpha1.Metadata{
+			Namespace:  "default",
+			Id:         "aaa",
+			Version:    "1",
+			Owner:      "controller",
+			Finalizers: []string{"a", "b"},
+			Labels:     map[string]string{"app": "foo", "stage": "initial"},
+		},
+		Spec: &v1alpha1.Spec{
+			YamlSpec: "value: 1",
+		},
+	}
+
+	for _, test := range []struct {
+		modify  func(*v1alpha1.Resource)
+		name    string
+		patch   *v1alpha1.Resource
+		cleared []string
+	}{
+		{
+			name:   "no changes",
+			modify: func(*v1alpha1.Resource) {},
+			patch:  &v1alpha1.Resource{},
+		},
+		{
+			name: "scalars and map keys",
+			modify: func(r *v1alpha1.Resource) {
+				r.Metadata.Version = "2"
+				r.Metadata.Labels["stage"] = "final"
+				r.Metadata.Owner = ""
+			},
+			patch: &v1alpha1.Resource{
+				Metadata: &v1alpha1.Metadata{
+					Version: "2",
+					Labels:  map[string]string{"stage": "final"},
+				},
+			},
+			cleared: []string{"metadata.owner"},
+		},
+		{
+			name: "removed map key and list",
+			modify: func(r *v1alpha1.Resource) {
+				delete(r.Metadata.Labels, "app")
+				r.Metadata.Finalizers = []string{"b"}
+				r.Spec = nil
+			},
+			patch: &v1alpha1.Resource{
+				Metadata: &v1alpha1.Metadata{
+					Finalizers: []string{"b"},
+					Labels:     map[string]string{"stage": "initial"},
+				},
+			},
+			cleared: []string{"metadata.labels", "spec"},
+		},
+	} {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			newR := proto.Clone(old).(*v1alpha1.Resource) //nolint:forcetypeassert,errcheck
+			test.modify(newR)
+
+			delta, err := merge.Diff(old, newR)
+			require.NoError(t, err)
+
+			assert.True(t, proto.Equal(test.patch, delta.Patch), "%v", delta.Patch)
+			assert.Equal(t, test.cleared, delta.Cleared)
+
+			applied := proto.Clone(old)
+			require.NoError(t, merge.Apply(applied, delta))
+
+			assert.True(t, proto.Equal(newR, applied), "%v", applied)
+		})
+	}
+}
@@ -18,8 +18,37 @@ type vtprotoMessage interface {
 	UnmarshalVT([]byte) error
 }
 
+// MarshalOptions configure the protobuf marshaling.
+type MarshalOptions struct {
+	Deterministic bool
+}
+
+// MarshalOption applies settings to MarshalOptions.
+type MarshalOption func(options *MarshalOptions)
+
+// WithDeterministic marshals the map entries in the sorted order, so that the encoding of the equal messages
+// is the same across the processes.
+//
+// Deterministic marshaling doesn't use the vtproto fast path, as vtproto encodes the map entries in the random order.
+// Encoding is stable for the same version of the protobuf library and message definitions only.
+func WithDeterministic() MarshalOption {
+	return func(options *MarshalOptions) {
+		options.Deterministic = true
+	}
+}
+
 // ProtoMarshal returns the wire-format encoding of m.
-func ProtoMarshal(m proto.Message) ([]byte, error) {
+func ProtoMarshal(m proto.Message, opts ...MarshalOption) ([]byte, error) {
+	var options MarshalOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.Deterministic {
+		return proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	}
+
 	if vm, ok := m.(vtprotoMessage); ok {
 		return vm.MarshalVT()
 	}
@@ -84,5 +84,9 @@ func UnmarshalResource(r *Resource) (resource.Resource, error) { //nolint:iretur
 		return nil, err
 	}
 
+	if err := r.unmarshalStatus(unmarshaler); err != nil {
+		return nil, err
+	}
+
 	return resourceInstance, nil
 }
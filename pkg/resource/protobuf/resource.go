@@ -5,8 +5,10 @@
 package protobuf
 
 import (
+	"encoding/json"
 	"fmt"
 
+	"google.golang.org/protobuf/proto"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	"gopkg.in/yaml.v3"
 
@@ -15,9 +17,12 @@ import (
 )
 
 // Resource which can be marshaled and unmarshaled from protobuf.
+//
+// Resource implements resource.StatusResource, the status is empty for the resources which don't have one.
 type Resource struct {
-	spec protoSpec
-	md   resource.Metadata
+	spec   protoSpec
+	status protoSpec
+	md     resource.Metadata
 }
 
 type protoSpec struct {
@@ -34,6 +39,19 @@ func (s protoSpec) MarshalYAMLBytes() ([]byte, error) {
 	return []byte(s.yaml), nil
 }
 
+// MarshalJSON implements json.Marshaler interface.
+//
+// JSON spec is built from the YAML spec, as the protobuf spec can't be decoded without the resource type.
+func (s protoSpec) MarshalJSON() ([]byte, error) {
+	var value interface{}
+
+	if err := yaml.Unmarshal([]byte(s.yaml), &value); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(value)
+}
+
 func (r *Resource) String() string {
 	return fmt.Sprintf("%s(%q)", r.md.Type(), r.md.ID())
 }
@@ -48,24 +66,59 @@ func (r *Resource) Spec() interface{} {
 	return &r.spec
 }
 
+// Status of the resource.
+func (r *Resource) Status() interface{} {
+	return r.status.deepCopy()
+}
+
+// SetStatus replaces the status of the resource with the status of another protobuf.Resource.
+func (r *Resource) SetStatus(status interface{}) error {
+	s, ok := status.(protoSpec)
+	if !ok {
+		return fmt.Errorf("unexpected status %T", status)
+	}
+
+	r.status = s.deepCopy()
+
+	return nil
+}
+
 // DeepCopy of the resource.
 func (r *Resource) DeepCopy() resource.Resource { //nolint:ireturn
-	specCopy := protoSpec{
-		yaml: r.spec.yaml,
+	return &Resource{
+		md:     r.md.Copy(),
+		spec:   r.spec.deepCopy(),
+		status: r.status.deepCopy(),
 	}
+}
 
-	if r.spec.protobuf != nil {
-		specCopy.protobuf = append([]byte(nil), r.spec.protobuf...)
+func (s protoSpec) deepCopy() protoSpec {
+	specCopy := protoSpec{
+		yaml: s.yaml,
 	}
 
-	return &Resource{
-		md:   r.md.Copy(),
-		spec: specCopy,
+	if s.protobuf != nil {
+		specCopy.protobuf = append([]byte(nil), s.protobuf...)
 	}
+
+	return specCopy
+}
+
+func (s protoSpec) empty() bool {
+	return s.protobuf == nil && s.yaml == ""
 }
 
 // Marshal into protobuf resource.
 func (r *Resource) Marshal() (*v1alpha1.Resource, error) {
+	var status *v1alpha1.Spec
+
+	if !r.status.empty() {
+		status = &v1alpha1.Spec{
+			ProtoSpec: r.status.protobuf,
+			YamlSpec:  r.status.yaml,
+		}
+	}
+
 	return &v1alpha1.Resource{
 		Metadata: &v1alpha1.Metadata{
 			Namespace:  r.md.Namespace(),
@@ -78,11 +131,17 @@ func (r *Resource) Marshal() (*v1alpha1.Resource, error) {
 			Updated:    timestamppb.New(r.md.Updated()),
 			Finalizers: *r.md.Finalizers(),
 			Labels:     r.md.Labels().Raw(),
+
+			Generation:         r.md.Generation(),
+			ObservedGeneration: r.md.ObservedGeneration(),
+			StatusOwner:        r.md.StatusOwner(),
+			CustomPhase:        r.md.CustomPhase(),
 		},
 		Spec: &v1alpha1.Spec{
 			ProtoSpec: r.spec.protobuf,
 			YamlSpec:  r.spec.yaml,
 		},
+		Status: status,
 	}, nil
 }
 
@@ -91,9 +150,27 @@ type ResourceUnmarshaler interface {
 	UnmarshalProto(*resource.Metadata, []byte) error
 }
 
+// StatusUnmarshaler is an interface which should be implemented by Resource with a status to support conversion from protobuf.Resource.
+type StatusUnmarshaler interface {
+	UnmarshalStatusProto([]byte) error
+}
+
 // Unmarshal into specific Resource instance.
 func (r *Resource) Unmarshal(res ResourceUnmarshaler) error {
-	return res.UnmarshalProto(&r.md, r.spec.protobuf)
+	if err := res.UnmarshalProto(&r.md, r.spec.protobuf); err != nil {
+		return err
+	}
+
+	return r.unmarshalStatus(res)
+}
+
+func (r *Resource) unmarshalStatus(res ResourceUnmarshaler) error {
+	statusUnmarshaler, ok := res.(StatusUnmarshaler)
+	if !ok || r.status.protobuf == nil {
+		return nil
+	}
+
+	return statusUnmarshaler.UnmarshalStatusProto(r.status.protobuf)
 }
 
 // ProtoMarshaler is an interface which should be implemented by Resource spec to support conversion to protobuf.Resource.
@@ -107,7 +184,10 @@ type ProtoUnmarshaler interface {
 }
 
 // FromResource converts a resource which supports spec protobuf marshaling to protobuf.Resource.
-func FromResource(r resource.Resource) (*Resource, error) {
+//
+// With WithDeterministic, the specs backed by protobuf messages (see ResourceSpec) are marshaled deterministically,
+// other specs are expected to implement stable MarshalProto.
+func FromResource(r resource.Resource, opts ...MarshalOption) (*Resource, error) {
 	if protoR, ok := r.(*Resource); ok {
 		return protoR, nil
 	}
@@ -124,7 +204,7 @@ func FromResource(r resource.Resource) (*Resource, error) {
 		return nil, fmt.Errorf("resource %s doesn't support protobuf marshaling", r)
 	}
 
-	protoBytes, err := protoMarshaler.MarshalProto()
+	protoBytes, err := marshalSpec(protoMarshaler, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -134,15 +214,65 @@ func FromResource(r resource.Resource) (*Resource, error) {
 		return nil, err
 	}
 
+	status, err := marshalStatus(r, opts...)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Resource{
 		md: r.Metadata().Copy(),
 		spec: protoSpec{
 			protobuf: protoBytes,
 			yaml:     string(yamlBytes),
 		},
+		status: status,
+	}, nil
+}
+
+// marshalStatus marshals the status of the resource if the status supports protobuf marshaling.
+//
+// Resources which keep the status in the spec don't have a separate status.
+func marshalStatus(r resource.Resource, opts ...MarshalOption) (protoSpec, error) {
+	statusResource, ok := r.(resource.StatusResource)
+	if !ok {
+		return protoSpec{}, nil
+	}
+
+	protoMarshaler, ok := statusResource.Status().(ProtoMarshaler)
+	if !ok {
+		return protoSpec{}, nil
+	}
+
+	protoBytes, err := marshalSpec(protoMarshaler, opts...)
+	if err != nil {
+		return protoSpec{}, err
+	}
+
+	yamlBytes, err := yaml.Marshal(protoMarshaler)
+	if err != nil {
+		return protoSpec{}, err
+	}
+
+	return protoSpec{
+		protobuf: protoBytes,
+		yaml:     string(yamlBytes),
 	}, nil
 }
 
+func marshalSpec(spec ProtoMarshaler, opts ...MarshalOption) ([]byte, error) {
+	if len(opts) == 0 {
+		return spec.MarshalProto()
+	}
+
+	if valueSpec, ok := spec.(interface {
+		GetValue() proto.Message
+	}); ok {
+		return ProtoMarshal(valueSpec.GetValue(), opts...)
+	}
+
+	return spec.MarshalProto()
+}
+
 // Unmarshal protobuf marshaled resource into Resource.
 func Unmarshal(protoResource *v1alpha1.Resource) (*Resource, error) {
 	if protoResource.GetMetadata() == nil {
@@ -164,5 +294,9 @@ func Unmarshal(protoResource *v1alpha1.Resource) (*Resource, error) {
 			yaml:     protoResource.GetSpec().GetYamlSpec(),
 			protobuf: protoResource.GetSpec().GetProtoSpec(),
 		},
+		status: protoSpec{
+			yaml:     protoResource.GetStatus().GetYamlSpec(),
+			protobuf: protoResource.GetStatus().GetProtoSpec(),
+		},
 	}, nil
 }
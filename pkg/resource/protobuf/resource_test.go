@@ -17,6 +17,7 @@ import (
 	"github.com/cosi-project/runtime/api/v1alpha1"
 	"github.com/cosi-project/runtime/pkg/resource"
 	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/resource/resourcetest"
 )
 
 func TestInterfaces(t *testing.T) {
@@ -94,3 +95,33 @@ spec:
 `,
 		string(yy))
 }
+
+func FuzzMetadataRoundTrip(f *testing.F) {
+	for _, seed := range resourcetest.MetadataCorpus() {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var protoMd v1alpha1.Metadata
+
+		if err := proto.Unmarshal(data, &protoMd); err != nil {
+			return
+		}
+
+		r, err := protobuf.Unmarshal(&v1alpha1.Resource{
+			Metadata: &protoMd,
+			Spec:     &v1alpha1.Spec{},
+		})
+		if err != nil {
+			return
+		}
+
+		marshaled, err := r.Marshal()
+		require.NoError(t, err)
+
+		roundTripped, err := protobuf.Unmarshal(marshaled)
+		require.NoError(t, err)
+
+		assert.True(t, r.Metadata().Equal(*roundTripped.Metadata()))
+	})
+}
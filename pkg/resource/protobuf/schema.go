@@ -0,0 +1,247 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package protobuf
+
+import (
+	"sort"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+// Schema is a JSON Schema document.
+type Schema map[string]interface{}
+
+// jsonSchemaDraft is the JSON Schema version of the generated schemas.
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// MessageSchema builds JSON Schema of the protobuf message.
+//
+// Schema follows the canonical protobuf JSON mapping, nested messages are placed into `$defs`.
+func MessageSchema(desc protoreflect.MessageDescriptor) Schema {
+	builder := newSchemaBuilder("#/$defs/")
+
+	root := builder.message(desc)
+
+	root["$schema"] = jsonSchemaDraft
+	root["$defs"] = builder.defs
+
+	return root
+}
+
+// ResourceSchemas returns JSON Schemas of the specs of registered resource types.
+//
+// Only resources with specs backed by protobuf messages (see ResourceSpec) are included.
+func ResourceSchemas() (map[resource.Type]Schema, error) {
+	schemas := map[resource.Type]Schema{}
+
+	for _, resourceType := range registeredTypes() {
+		desc, err := specDescriptor(resourceType)
+		if err != nil {
+			return nil, err
+		}
+
+		if desc != nil {
+			schemas[resourceType] = MessageSchema(desc)
+		}
+	}
+
+	return schemas, nil
+}
+
+// OpenAPI builds OpenAPI 3 document which describes the specs of registered resource types.
+//
+// Spec schemas are placed into `components.schemas` keyed by the resource type.
+func OpenAPI(title, version string) (Schema, error) {
+	builder := newSchemaBuilder("#/components/schemas/")
+
+	for _, resourceType := range registeredTypes() {
+		desc, err := specDescriptor(resourceType)
+		if err != nil {
+			return nil, err
+		}
+
+		if desc != nil {
+			builder.defs[resourceType] = builder.message(desc)
+		}
+	}
+
+	return Schema{
+		"openapi": "3.1.0",
+		"info": Schema{
+			"title":   title,
+			"version": version,
+		},
+		"paths": Schema{},
+		"components": Schema{
+			"schemas": builder.defs,
+		},
+	}, nil
+}
+
+func registeredTypes() []resource.Type {
+	initOnce.Do(initRegistry)
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	types := make([]resource.Type, 0, len(registry.registry))
+
+	for resourceType := range registry.registry {
+		types = append(types, resourceType)
+	}
+
+	sort.Strings(types)
+
+	return types
+}
+
+// specDescriptor returns the descriptor of the protobuf spec of the resource type, or nil if spec is not a protobuf message.
+func specDescriptor(resourceType resource.Type) (protoreflect.MessageDescriptor, error) { //nolint:ireturn
+	r, err := CreateResource(resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, ok := r.Spec().(interface {
+		GetValue() proto.Message
+	})
+	if !ok {
+		return nil, nil //nolint:nilnil
+	}
+
+	return spec.GetValue().ProtoReflect().Descriptor(), nil
+}
+
+type schemaBuilder struct {
+	defs      Schema
+	refPrefix string
+}
+
+func newSchemaBuilder(refPrefix string) *schemaBuilder {
+	return &schemaBuilder{
+		defs:      Schema{},
+		refPrefix: refPrefix,
+	}
+}
+
+// message builds the schema of the message fields.
+func (builder *schemaBuilder) message(desc protoreflect.MessageDescriptor) Schema {
+	properties := Schema{}
+
+	fields := desc.Fields()
+
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+
+		properties[field.JSONName()] = builder.field(field)
+	}
+
+	return Schema{
+		"type":                 "object",
+		"title":                string(desc.Name()),
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// ref returns the reference to the message schema, adding the message to definitions.
+func (builder *schemaBuilder) ref(desc protoreflect.MessageDescriptor) Schema {
+	if schema, ok := wellKnownSchema(desc); ok {
+		return schema
+	}
+
+	name := string(desc.FullName())
+
+	if _, ok := builder.defs[name]; !ok {
+		// placeholder breaks the recursion for self-referencing messages
+		builder.defs[name] = Schema{}
+		builder.defs[name] = builder.message(desc)
+	}
+
+	return Schema{"$ref": builder.refPrefix + name}
+}
+
+func (builder *schemaBuilder) field(field protoreflect.FieldDescriptor) Schema {
+	switch {
+	case field.IsMap():
+		return Schema{
+			"type":                 "object",
+			"additionalProperties": builder.singular(field.MapValue()),
+		}
+	case field.IsList():
+		return Schema{
+			"type":  "array",
+			"items": builder.singular(field),
+		}
+	default:
+		return builder.singular(field)
+	}
+}
+
+func (builder *schemaBuilder) singular(field protoreflect.FieldDescriptor) Schema {
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		return Schema{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return Schema{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return Schema{"type": "integer", "format": "uint32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		// 64-bit integers are encoded as strings
+		return Schema{"type": "string", "format": "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return Schema{"type": "string", "format": "uint64"}
+	case protoreflect.FloatKind:
+		return Schema{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return Schema{"type": "number", "format": "double"}
+	case protoreflect.StringKind:
+		return Schema{"type": "string"}
+	case protoreflect.BytesKind:
+		return Schema{"type": "string", "contentEncoding": "base64"}
+	case protoreflect.EnumKind:
+		values := field.Enum().Values()
+		names := make([]string, 0, values.Len())
+
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, string(values.Get(i).Name()))
+		}
+
+		return Schema{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return builder.ref(field.Message())
+	}
+
+	return Schema{}
+}
+
+// wellKnownSchema returns the schema of well-known types which have special JSON encoding.
+func wellKnownSchema(desc protoreflect.MessageDescriptor) (Schema, bool) {
+	switch desc.FullName() {
+	case "google.protobuf.Timestamp":
+		return Schema{"type": "string", "format": "date-time"}, true
+	case "google.protobuf.Duration", "google.protobuf.FieldMask":
+		return Schema{"type": "string"}, true
+	case "google.protobuf.Struct", "google.protobuf.Any", "google.protobuf.Empty":
+		return Schema{"type": "object"}, true
+	case "google.protobuf.ListValue":
+		return Schema{"type": "array"}, true
+	case "google.protobuf.Value":
+		return Schema{}, true
+	case "google.protobuf.BoolValue":
+		return Schema{"type": "boolean"}, true
+	case "google.protobuf.Int32Value", "google.protobuf.UInt32Value":
+		return Schema{"type": "integer"}, true
+	case "google.protobuf.Int64Value", "google.protobuf.UInt64Value", "google.protobuf.StringValue", "google.protobuf.BytesValue":
+		return Schema{"type": "string"}, true
+	case "google.protobuf.FloatValue", "google.protobuf.DoubleValue":
+		return Schema{"type": "number"}, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,54 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package protobuf_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/api/v1alpha1"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+)
+
+func TestMessageSchema(t *testing.T) {
+	t.Parallel()
+
+	schema := protobuf.MessageSchema((&v1alpha1.Resource{}).ProtoReflect().Descriptor())
+
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", schema["$schema"])
+	assert.Equal(t, "object", schema["type"])
+
+	properties := schema["properties"].(protobuf.Schema) //nolint:forcetypeassert
+	assert.Equal(t, protobuf.Schema{"$ref": "#/$defs/cosi.resource.Metadata"}, properties["metadata"])
+
+	defs := schema["$defs"].(protobuf.Schema)                      //nolint:forcetypeassert
+	metadata := defs["cosi.resource.Metadata"].(protobuf.Schema)   //nolint:forcetypeassert
+	metadataProperties := metadata["properties"].(protobuf.Schema) //nolint:forcetypeassert
+
+	assert.Equal(t, protobuf.Schema{"type": "string"}, metadataProperties["namespace"])
+	assert.Equal(t, protobuf.Schema{"type": "string", "format": "date-time"}, metadataProperties["created"])
+	assert.Equal(t, protobuf.Schema{"type": "array", "items": protobuf.Schema{"type": "string"}}, metadataProperties["finalizers"])
+	assert.Equal(t, protobuf.Schema{"type": "object", "additionalProperties": protobuf.Schema{"type": "string"}}, metadataProperties["labels"])
+}
+
+func TestResourceSchemas(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, protobuf.RegisterResource("schemaTestResources.test.cosi.dev", &testResource{}))
+
+	schemas, err := protobuf.ResourceSchemas()
+	require.NoError(t, err)
+
+	require.Contains(t, schemas, "schemaTestResources.test.cosi.dev")
+	assert.Equal(t, "Metadata", schemas["schemaTestResources.test.cosi.dev"]["title"])
+
+	doc, err := protobuf.OpenAPI("test", "v1")
+	require.NoError(t, err)
+
+	schemaDefs := doc["components"].(protobuf.Schema)["schemas"].(protobuf.Schema) //nolint:forcetypeassert
+	assert.Contains(t, schemaDefs, "schemaTestResources.test.cosi.dev")
+}
@@ -0,0 +1,54 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package resource
+
+import "fmt"
+
+// Ref is a reference to another resource which can be stored in the resource spec.
+//
+// Unlike Reference, Ref is a plain value with YAML and JSON representation.
+// Version is optional: if set, the reference points to the specific version of the resource.
+type Ref struct {
+	Namespace Namespace `yaml:"namespace" json:"namespace"`
+	Type      Type      `yaml:"type" json:"type"`
+	ID        ID        `yaml:"id" json:"id"`
+	Version   string    `yaml:"version,omitempty" json:"version,omitempty"`
+}
+
+// NewRef creates a Ref to the resource pointer.
+//
+// If the pointer is a Reference with a defined version, the version is recorded as well.
+func NewRef(ptr Pointer) Ref {
+	ref := Ref{
+		Namespace: ptr.Namespace(),
+		Type:      ptr.Type(),
+		ID:        ptr.ID(),
+	}
+
+	if reference, ok := ptr.(Reference); ok && !reference.Version().Equal(VersionUndefined) {
+		ref.Version = reference.Version().String()
+	}
+
+	return ref
+}
+
+// Pointer returns the pointer to the referenced resource.
+func (ref Ref) Pointer() Pointer { //nolint:ireturn
+	return NewMetadata(ref.Namespace, ref.Type, ref.ID, VersionUndefined)
+}
+
+// IsZero returns true if the reference is not set.
+func (ref Ref) IsZero() bool {
+	return ref == Ref{}
+}
+
+// String implements fmt.Stringer.
+func (ref Ref) String() string {
+	if ref.Version == "" {
+		return fmt.Sprintf("%s(%s/%s)", ref.Type, ref.Namespace, ref.ID)
+	}
+
+	return fmt.Sprintf("%s(%s/%s@%s)", ref.Type, ref.Namespace, ref.ID, ref.Version)
+}
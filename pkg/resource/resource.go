@@ -6,8 +6,11 @@
 package resource
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
+
+	"google.golang.org/protobuf/proto"
 )
 
 type (
@@ -39,12 +42,60 @@ type Resource interface {
 	DeepCopy() Resource
 }
 
+// EqualOptions configure Equal.
+type EqualOptions struct {
+	IgnoreVersion     bool
+	CompareTimestamps bool
+}
+
+// EqualOption applies settings to EqualOptions.
+type EqualOption func(options *EqualOptions)
+
+// WithIgnoreVersion ignores resource versions in the comparison.
+func WithIgnoreVersion() EqualOption {
+	return func(options *EqualOptions) {
+		options.IgnoreVersion = true
+	}
+}
+
+// WithCompareTimestamps compares created and updated timestamps of the resources.
+//
+// By default timestamps are ignored.
+func WithCompareTimestamps() EqualOption {
+	return func(options *EqualOptions) {
+		options.CompareTimestamps = true
+	}
+}
+
 // Equal tests two resources for equality.
-func Equal(r1, r2 Resource) bool {
-	if !r1.Metadata().Equal(*r2.Metadata()) {
+func Equal(r1, r2 Resource, opts ...EqualOption) bool {
+	var options EqualOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	md1, md2 := *r1.Metadata(), *r2.Metadata()
+
+	if options.IgnoreVersion {
+		md2.ver = md1.ver
+	}
+
+	if !md1.Equal(md2) {
+		return false
+	}
+
+	if options.CompareTimestamps && (!md1.created.Equal(md2.created) || !md1.updated.Equal(md2.updated)) {
 		return false
 	}
 
+	return SpecEqual(r1, r2)
+}
+
+// SpecEqual tests specs of two resources for equality.
+//
+// Specs backed by protobuf messages are compared with proto.Equal.
+func SpecEqual(r1, r2 Resource) bool {
 	spec1, spec2 := r1.Spec(), r2.Spec()
 
 	if equality, ok := spec1.(interface {
@@ -53,9 +104,28 @@ func Equal(r1, r2 Resource) bool {
 		return equality.Equal(spec2)
 	}
 
+	if msg1, ok := protoSpecValue(spec1); ok {
+		if msg2, ok := protoSpecValue(spec2); ok {
+			return proto.Equal(msg1.Interface(), msg2.Interface())
+		}
+	}
+
 	return reflect.DeepEqual(spec1, spec2)
 }
 
+// MarshalJSON marshals resource to JSON definition.
+//
+// JSON definition has the same structure as the YAML definition.
+func MarshalJSON(r Resource) ([]byte, error) {
+	return json.Marshal(&struct {
+		Metadata *Metadata   `json:"metadata"`
+		Spec     interface{} `json:"spec"`
+	}{
+		Metadata: r.Metadata(),
+		Spec:     r.Spec(),
+	})
+}
+
 // MarshalYAML marshals resource to YAML definition.
 func MarshalYAML(r Resource) (interface{}, error) {
 	return &struct {
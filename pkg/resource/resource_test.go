@@ -6,10 +6,15 @@ package resource_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
 
+	"github.com/cosi-project/runtime/api/v1alpha1"
 	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
 )
 
 func TestInterfaces(t *testing.T) {
@@ -25,3 +30,29 @@ func TestIsTombstone(t *testing.T) {
 	assert.True(t, resource.IsTombstone(new(resource.Tombstone)))
 	assert.False(t, resource.IsTombstone((resource.Resource)(nil)))
 }
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	r1 := typed.NewResource[diffSpec, diffRD](resource.NewMetadata("default", "DiffResources.test.cosi.dev", "aaa", resource.VersionUndefined),
+		protobuf.NewResourceSpec(&v1alpha1.Metadata{Id: "foo"}))
+	r2 := r1.DeepCopy().(*typed.Resource[diffSpec, diffRD]) //nolint:forcetypeassert
+
+	// populate the internal size cache of the message
+	proto.Size(r2.TypedSpec().Value)
+
+	assert.True(t, resource.Equal(r1, r2))
+
+	time.Sleep(10 * time.Millisecond)
+
+	// bumps both the version and the updated timestamp
+	r2.Metadata().BumpVersion()
+
+	assert.False(t, resource.Equal(r1, r2))
+	assert.True(t, resource.Equal(r1, r2, resource.WithIgnoreVersion()))
+	assert.False(t, resource.Equal(r1, r2, resource.WithIgnoreVersion(), resource.WithCompareTimestamps()))
+
+	r2.TypedSpec().Value.Id = "bar"
+
+	assert.False(t, resource.Equal(r1, r2, resource.WithIgnoreVersion()))
+}
@@ -0,0 +1,136 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package resourcetest provides seed corpora for fuzzing the code which decodes resources from the remote input,
+// and the property-based checks of the resource encodings (see CheckRoundTrip).
+//
+// The corpora are shared with the downstream projects, so that their fuzz targets start from the inputs
+// which are known to reach the interesting decoding paths:
+//
+//	func FuzzDecode(f *testing.F) {
+//		for _, seed := range resourcetest.MetadataCorpus() {
+//			f.Add(seed)
+//		}
+//
+//		f.Fuzz(func(t *testing.T, data []byte) { ... })
+//	}
+package resourcetest
+
+import (
+	"math"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/cosi-project/runtime/api/v1alpha1"
+)
+
+// LabelTermCorpus returns the label query terms in the text format: `key=value`, `key` or `!key`.
+func LabelTermCorpus() []string {
+	return []string{
+		"app=web",
+		"app",
+		"!app",
+		"app=",
+		"=web",
+		"!",
+		"",
+		"!app=web",
+		"a=b=c",
+		"app=\x00",
+		"é=ü",
+	}
+}
+
+// LabelQueryCorpus returns the protobuf encoded v1alpha1.LabelQuery messages.
+func LabelQueryCorpus() [][]byte {
+	return marshal(
+		&v1alpha1.LabelQuery{},
+		&v1alpha1.LabelQuery{
+			Terms: []*v1alpha1.LabelTerm{
+				{Key: "app", Op: v1alpha1.LabelTerm_EQUAL, Value: "web"},
+				{Key: "tier", Op: v1alpha1.LabelTerm_EXISTS},
+				{Key: "canary", Op: v1alpha1.LabelTerm_NOT_EXISTS},
+			},
+		},
+		&v1alpha1.LabelQuery{
+			Terms: []*v1alpha1.LabelTerm{
+				{Op: v1alpha1.LabelTerm_EQUAL},
+				{Key: "app", Op: v1alpha1.LabelTerm_Operation(math.MaxInt32)},
+			},
+		},
+	)
+}
+
+// MetadataCorpus returns the protobuf encoded v1alpha1.Metadata messages.
+//
+// The corpus covers valid metadata, and the metadata with invalid version, phase and timestamps.
+func MetadataCorpus() [][]byte {
+	return marshal(
+		&v1alpha1.Metadata{},
+		&v1alpha1.Metadata{
+			Namespace:  "default",
+			Type:       "Path.test.cosi.dev",
+			Id:         "var/run",
+			Version:    "3",
+			Owner:      "PathController",
+			Phase:      "running",
+			Created:    &timestamppb.Timestamp{Seconds: 1700000000},
+			Updated:    &timestamppb.Timestamp{Seconds: 1700000000},
+			Finalizers: []string{"cleanup", "cleanup"},
+			Labels:     map[string]string{"app": "os", "": ""},
+		},
+		&v1alpha1.Metadata{
+			Version: "-1",
+			Phase:   "tearingDown",
+		},
+		&v1alpha1.Metadata{
+			Version: "undefined",
+			Phase:   "unknown",
+		},
+		&v1alpha1.Metadata{
+			Version: "18446744073709551616",
+			Created: &timestamppb.Timestamp{Seconds: math.MaxInt64, Nanos: -1},
+			Updated: &timestamppb.Timestamp{Seconds: math.MinInt64, Nanos: math.MaxInt32},
+		},
+	)
+}
+
+// YAMLCorpus returns the YAML documents used as the resource specs.
+//
+// The corpus covers scalars, nested collections, non-string keys, anchors and malformed documents.
+func YAMLCorpus() []string {
+	return []string{
+		"",
+		"null",
+		"value: xyz\nsomething: [a, b, c]\n",
+		"- 1\n- 2.5\n- true\n- ~\n",
+		"1: one\n2.5: two\ntrue: three\n",
+		"? [a, b]\n: complex key\n",
+		"base: &base {a: 1}\nderived:\n  <<: *base\n  b: 2\n",
+		"a: &a [*a]\n",
+		"nan: .nan\ninf: -.inf\nbig: 1e400\n",
+		"binary: !!binary aGVsbG8=\n",
+		"time: 2001-12-14t21:59:43.10-05:00\n",
+		"key: [unclosed\n",
+		"\t- tab\n",
+		"--- a\n--- b\n",
+		"{{{{{{{{{{{{{{{{{{{{{{{{{{{{{{{{",
+	}
+}
+
+func marshal(msgs ...proto.Message) [][]byte {
+	corpus := make([][]byte, 0, len(msgs))
+
+	for _, msg := range msgs {
+		data, err := proto.Marshal(msg)
+		if err != nil {
+			panic(err)
+		}
+
+		corpus = append(corpus, data)
+	}
+
+	return corpus
+}
@@ -0,0 +1,475 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package resourcetest
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gopkg.in/yaml.v3"
+
+	"github.com/cosi-project/runtime/api/v1alpha1"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+)
+
+// trickyStrings are the values which are easily mangled by the text encodings.
+var trickyStrings = []string{
+	"",
+	"true",
+	"yes",
+	"null",
+	"~",
+	"1",
+	"0x1f",
+	"1e3",
+	"2006-01-02",
+	"-",
+	"a: b",
+	"#comment",
+	" leading",
+	"trailing ",
+	"multi\nline",
+	"\"quoted\"",
+	"'single'",
+	"[list]",
+	"{map}",
+	"*alias",
+	"!tag",
+	"é",
+}
+
+const randomAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789-_./"
+
+// RandomString returns a random string, which is either made of the letters, digits and punctuation,
+// or is one of the values which are easily mangled by the text encodings, like "null", "yes" or "a: b".
+func RandomString(rnd *rand.Rand) string {
+	if rnd.Intn(4) == 0 {
+		return trickyStrings[rnd.Intn(len(trickyStrings))]
+	}
+
+	b := make([]byte, 1+rnd.Intn(16))
+
+	for i := range b {
+		b[i] = randomAlphabet[rnd.Intn(len(randomAlphabet))]
+	}
+
+	return string(b)
+}
+
+// RandomMetadata returns the metadata with the random ID, version, owner, phase, labels, finalizers and timestamps.
+//
+// Timestamps are in UTC and have the second precision of the YAML and JSON definitions.
+func RandomMetadata(rnd *rand.Rand, ns resource.Namespace, typ resource.Type) resource.Metadata {
+	// any second of this century
+	created := time.Unix(946684800+rnd.Int63n(100*365*24*60*60), 0).UTC()
+	updated := created.Add(time.Duration(rnd.Int63n(24*60*60)) * time.Second)
+
+	protoMd := &v1alpha1.Metadata{
+		Namespace: ns,
+		Type:      typ,
+		Id:        RandomString(rnd),
+		Version:   "undefined",
+		Phase:     resource.PhaseRunning.String(),
+		Created:   timestamppb.New(created),
+		Updated:   timestamppb.New(updated),
+	}
+
+	if rnd.Intn(8) != 0 {
+		protoMd.Version = strconv.FormatUint(rnd.Uint64()>>rnd.Intn(64), 10)
+	}
+
+	if rnd.Intn(4) == 0 {
+		protoMd.Phase = resource.PhaseTearingDown.String()
+	}
+
+	if rnd.Intn(2) == 0 {
+		protoMd.Owner = RandomString(rnd)
+	}
+
+	if n := rnd.Intn(4); n > 0 {
+		protoMd.Labels = make(map[string]string, n)
+
+		for i := 0; i < n; i++ {
+			protoMd.Labels[RandomString(rnd)] = RandomString(rnd)
+		}
+	}
+
+	for i := rnd.Intn(4); i > 0; i-- {
+		protoMd.Finalizers = append(protoMd.Finalizers, RandomString(rnd))
+	}
+
+	md, err := resource.NewMetadataFromProto(protoMd)
+	if err != nil {
+		panic(err)
+	}
+
+	return md
+}
+
+// Generator builds a random resource.
+type Generator func(rnd *rand.Rand) resource.Resource
+
+// Assertion checks the resource, and reports the failures to t.
+type Assertion func(t testing.TB, r resource.Resource) bool
+
+// PropertyOptions configure CheckRoundTrip.
+type PropertyOptions struct {
+	Assertions []Assertion
+	Seed       int64
+	Iterations int
+}
+
+// PropertyOption applies settings to PropertyOptions.
+type PropertyOption func(options *PropertyOptions)
+
+// WithSeed sets the seed of the generator, so that the failure can be reproduced.
+func WithSeed(seed int64) PropertyOption {
+	return func(options *PropertyOptions) {
+		options.Seed = seed
+	}
+}
+
+// WithIterations sets the number of the generated resources.
+func WithIterations(iterations int) PropertyOption {
+	return func(options *PropertyOptions) {
+		options.Iterations = iterations
+	}
+}
+
+// WithAssertions replaces the assertions run for each generated resource.
+//
+// Resources which don't support protobuf marshaling are checked with AssertYAMLRoundTrip and AssertAnyRoundTrip only.
+func WithAssertions(assertions ...Assertion) PropertyOption {
+	return func(options *PropertyOptions) {
+		options.Assertions = assertions
+	}
+}
+
+// DefaultPropertyOptions returns default value of PropertyOptions.
+//
+// The seed is random, it is logged if the check fails.
+func DefaultPropertyOptions() PropertyOptions {
+	return PropertyOptions{
+		Assertions: []Assertion{AssertProtobufRoundTrip, AssertYAMLRoundTrip, AssertAnyRoundTrip},
+		Seed:       time.Now().UnixNano(),
+		Iterations: 100,
+	}
+}
+
+// CheckRoundTrip generates the resources and checks that they survive the marshal/unmarshal round-trips.
+//
+// CheckRoundTrip stops at the first failing resource:
+//
+//	func TestRoundTrip(t *testing.T) {
+//		resourcetest.CheckRoundTrip(t, func(rnd *rand.Rand) resource.Resource {
+//			r := NewMyResource(resourcetest.RandomMetadata(rnd, "default", MyResourceType))
+//			r.TypedSpec().Value = resourcetest.RandomString(rnd)
+//
+//			return r
+//		})
+//	}
+func CheckRoundTrip(t testing.TB, gen Generator, opts ...PropertyOption) bool {
+	t.Helper()
+
+	options := DefaultPropertyOptions()
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	rnd := rand.New(rand.NewSource(options.Seed)) //nolint:gosec
+
+	for i := 0; i < options.Iterations; i++ {
+		r := gen(rnd)
+
+		for _, assertion := range options.Assertions {
+			if !assertion(t, r) {
+				t.Errorf("round-trip failed for resource %d, reproduce with resourcetest.WithSeed(%d)", i, options.Seed)
+
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// AssertRoundTrip checks that the resource survives the protobuf, YAML and Any round-trips.
+func AssertRoundTrip(t testing.TB, r resource.Resource) bool {
+	t.Helper()
+
+	return AssertProtobufRoundTrip(t, r) && AssertYAMLRoundTrip(t, r) && AssertAnyRoundTrip(t, r)
+}
+
+// AssertProtobufRoundTrip checks that the resource is unmarshaled back from the protobuf wire format.
+//
+// The resource type should be registered with protobuf.RegisterResource.
+func AssertProtobufRoundTrip(t testing.TB, r resource.Resource) bool {
+	t.Helper()
+
+	decoded, err := protobufRoundTrip(r)
+	if err != nil {
+		t.Errorf("protobuf round-trip of %s failed: %s", r.Metadata(), err)
+
+		return false
+	}
+
+	return assertEqual(t, "protobuf", r, decoded, resource.WithCompareTimestamps())
+}
+
+// AssertYAMLRoundTrip checks that the resource is decoded back from the YAML definition (see resource.MarshalYAML).
+//
+// The spec is decoded into the zero value of the spec of the same resource type, so Spec() should return a pointer.
+// Timestamps are compared with the second precision of the YAML definition.
+func AssertYAMLRoundTrip(t testing.TB, r resource.Resource) bool {
+	t.Helper()
+
+	decoded, err := yamlRoundTrip(r)
+	if err != nil {
+		t.Errorf("YAML round-trip of %s failed: %s", r.Metadata(), err)
+
+		return false
+	}
+
+	return assertEqual(t, "YAML", r, decoded) && assertTimestamps(t, "YAML", r, decoded, time.Second)
+}
+
+// AssertAnyRoundTrip checks that the resource is decoded back from resource.Any built from the protobuf metadata and the spec YAML.
+//
+// resource.Any is what the generic clients see, so the spec is checked both as decoded from the YAML and
+// as re-encoded from the generic value of resource.Any.
+func AssertAnyRoundTrip(t testing.TB, r resource.Resource) bool {
+	t.Helper()
+
+	decoded, reencoded, err := anyRoundTrip(r)
+	if err != nil {
+		t.Errorf("Any round-trip of %s failed: %s", r.Metadata(), err)
+
+		return false
+	}
+
+	return assertEqual(t, "Any", r, decoded, resource.WithCompareTimestamps()) &&
+		assertEqual(t, "re-encoded Any", r, reencoded, resource.WithCompareTimestamps())
+}
+
+func assertEqual(t testing.TB, encoding string, expected, actual resource.Resource, opts ...resource.EqualOption) bool {
+	t.Helper()
+
+	if resource.Equal(expected, actual, opts...) {
+		return true
+	}
+
+	t.Errorf("%s round-trip of %s doesn't match the original resource:\n%s", encoding, expected.Metadata(), resource.Diff(expected, actual))
+
+	return false
+}
+
+func assertTimestamps(t testing.TB, encoding string, expected, actual resource.Resource, precision time.Duration) bool {
+	t.Helper()
+
+	md, decodedMd := expected.Metadata(), actual.Metadata()
+
+	if md.Created().Truncate(precision).Equal(decodedMd.Created()) && md.Updated().Truncate(precision).Equal(decodedMd.Updated()) {
+		return true
+	}
+
+	t.Errorf("%s round-trip of %s doesn't match the original timestamps: created %s != %s, updated %s != %s",
+		encoding, md, md.Created(), decodedMd.Created(), md.Updated(), decodedMd.Updated())
+
+	return false
+}
+
+func protobufRoundTrip(r resource.Resource) (resource.Resource, error) { //nolint:ireturn
+	protoR, err := protobuf.FromResource(r)
+	if err != nil {
+		return nil, err
+	}
+
+	wire, err := protoR.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := proto.Marshal(wire)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling resource: %w", err)
+	}
+
+	var decodedWire v1alpha1.Resource
+
+	if err = proto.Unmarshal(data, &decodedWire); err != nil {
+		return nil, fmt.Errorf("error unmarshaling resource: %w", err)
+	}
+
+	if protoR, err = protobuf.Unmarshal(&decodedWire); err != nil {
+		return nil, err
+	}
+
+	decoded, err := protobuf.UnmarshalResource(protoR)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, isProto := r.(*protobuf.Resource); !isProto && decoded == resource.Resource(protoR) {
+		return nil, fmt.Errorf("resource type %q is not registered with protobuf.RegisterResource", r.Metadata().Type())
+	}
+
+	return decoded, nil
+}
+
+// yamlDefinition is the YAML definition produced by resource.MarshalYAML.
+type yamlDefinition struct {
+	Metadata struct { //nolint:govet
+		Namespace  string            `yaml:"namespace"`
+		Type       string            `yaml:"type"`
+		ID         string            `yaml:"id"`
+		Version    string            `yaml:"version"`
+		Owner      string            `yaml:"owner"`
+		Phase      string            `yaml:"phase"`
+		Created    string            `yaml:"created"`
+		Updated    string            `yaml:"updated"`
+		Labels     map[string]string `yaml:"labels"`
+		Finalizers []string          `yaml:"finalizers"`
+	} `yaml:"metadata"`
+	Spec yaml.Node `yaml:"spec"`
+}
+
+func yamlRoundTrip(r resource.Resource) (resource.Resource, error) { //nolint:ireturn
+	definition, err := resource.MarshalYAML(r)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := yaml.Marshal(definition)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling resource: %w", err)
+	}
+
+	var decodedDefinition yamlDefinition
+
+	if err = yaml.Unmarshal(data, &decodedDefinition); err != nil {
+		return nil, fmt.Errorf("error unmarshaling resource: %w", err)
+	}
+
+	protoMd := &v1alpha1.Metadata{
+		Namespace:  decodedDefinition.Metadata.Namespace,
+		Type:       decodedDefinition.Metadata.Type,
+		Id:         decodedDefinition.Metadata.ID,
+		Version:    decodedDefinition.Metadata.Version,
+		Owner:      decodedDefinition.Metadata.Owner,
+		Phase:      decodedDefinition.Metadata.Phase,
+		Labels:     decodedDefinition.Metadata.Labels,
+		Finalizers: decodedDefinition.Metadata.Finalizers,
+	}
+
+	for _, timestamp := range []struct {
+		value string
+		into  **timestamppb.Timestamp
+	}{
+		{decodedDefinition.Metadata.Created, &protoMd.Created},
+		{decodedDefinition.Metadata.Updated, &protoMd.Updated},
+	} {
+		parsed, parseErr := time.Parse(time.RFC3339, timestamp.value)
+		if parseErr != nil {
+			return nil, fmt.Errorf("error parsing timestamp: %w", parseErr)
+		}
+
+		*timestamp.into = timestamppb.New(parsed)
+	}
+
+	md, err := resource.NewMetadataFromProto(protoMd)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, err := emptyCopy(r, md)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = decodedDefinition.Spec.Decode(decoded.Spec()); err != nil {
+		return nil, fmt.Errorf("error decoding spec: %w", err)
+	}
+
+	return decoded, nil
+}
+
+type yamlSpec string
+
+func (spec yamlSpec) GetYaml() []byte {
+	return []byte(spec)
+}
+
+func anyRoundTrip(r resource.Resource) (decoded, reencoded resource.Resource, err error) {
+	md := r.Metadata()
+
+	protoMd := &v1alpha1.Metadata{
+		Namespace:  md.Namespace(),
+		Type:       md.Type(),
+		Id:         md.ID(),
+		Version:    md.Version().String(),
+		Owner:      md.Owner(),
+		Phase:      md.Phase().String(),
+		Created:    timestamppb.New(md.Created()),
+		Updated:    timestamppb.New(md.Updated()),
+		Finalizers: *md.Finalizers(),
+		Labels:     md.Labels().Raw(),
+	}
+
+	specYAML, err := yaml.Marshal(r.Spec())
+	if err != nil {
+		return nil, nil, fmt.Errorf("error marshaling spec: %w", err)
+	}
+
+	anyR, err := resource.NewAnyFromProto(protoMd, yamlSpec(specYAML))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if decoded, err = emptyCopy(r, *anyR.Metadata()); err != nil {
+		return nil, nil, err
+	}
+
+	if err = anyR.Decode(decoded.Spec()); err != nil {
+		return nil, nil, fmt.Errorf("error decoding spec: %w", err)
+	}
+
+	valueYAML, err := yaml.Marshal(anyR.Value())
+	if err != nil {
+		return nil, nil, fmt.Errorf("error marshaling value: %w", err)
+	}
+
+	if reencoded, err = emptyCopy(r, *anyR.Metadata()); err != nil {
+		return nil, nil, err
+	}
+
+	if err = yaml.Unmarshal(valueYAML, reencoded.Spec()); err != nil {
+		return nil, nil, fmt.Errorf("error decoding value: %w", err)
+	}
+
+	return decoded, reencoded, nil
+}
+
+// emptyCopy returns a copy of the resource with the zero spec and the metadata.
+func emptyCopy(r resource.Resource, md resource.Metadata) (resource.Resource, error) { //nolint:ireturn
+	decoded := r.DeepCopy()
+
+	spec := reflect.ValueOf(decoded.Spec())
+	if spec.Kind() != reflect.Pointer || spec.IsNil() {
+		return nil, fmt.Errorf("spec %T is not a pointer, it can't be decoded", decoded.Spec())
+	}
+
+	spec.Elem().Set(reflect.Zero(spec.Elem().Type()))
+	*decoded.Metadata() = md
+
+	return decoded, nil
+}
@@ -0,0 +1,160 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package resourcetest_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/cosi-project/runtime/api/v1alpha1"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/resource/resourcetest"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+)
+
+type protoSpec = protobuf.ResourceSpec[v1alpha1.Metadata, *v1alpha1.Metadata]
+
+type protoResource = typed.Resource[protoSpec, protoRD]
+
+const protoResourceType = resource.Type("ProtoResources.test.cosi.dev")
+
+type protoRD struct{}
+
+func (protoRD) ResourceDefinition(resource.Metadata, protoSpec) meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type: protoResourceType,
+	}
+}
+
+func init() {
+	if err := protobuf.RegisterResource(protoResourceType, &protoResource{}); err != nil {
+		panic(err)
+	}
+}
+
+// lossySpec drops the field which is not tagged for YAML.
+type lossySpec struct {
+	Kept string `yaml:"kept"`
+	Lost string `yaml:"-"`
+}
+
+func (spec lossySpec) DeepCopy() lossySpec {
+	return spec
+}
+
+type lossyRD struct{}
+
+func (lossyRD) ResourceDefinition(resource.Metadata, lossySpec) meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{}
+}
+
+// recorder captures the failures reported by the assertions.
+type recorder struct {
+	testing.TB
+
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRandomMetadata(t *testing.T) {
+	t.Parallel()
+
+	rnd1, rnd2 := rand.New(rand.NewSource(1)), rand.New(rand.NewSource(1)) //nolint:gosec
+
+	for i := 0; i < 100; i++ {
+		md1, md2 := resourcetest.RandomMetadata(rnd1, "default", protoResourceType), resourcetest.RandomMetadata(rnd2, "default", protoResourceType)
+
+		assert.True(t, md1.Equal(md2))
+		assert.Equal(t, md1.Created(), md2.Created())
+		assert.Equal(t, "default", md1.Namespace())
+		assert.Equal(t, protoResourceType, md1.Type())
+		assert.False(t, md1.Updated().Before(md1.Created()))
+	}
+}
+
+func TestCheckRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	resourcetest.CheckRoundTrip(t, func(rnd *rand.Rand) resource.Resource {
+		specMd := resourcetest.RandomMetadata(rnd, resourcetest.RandomString(rnd), resourcetest.RandomString(rnd))
+
+		return typed.NewResource[protoSpec, protoRD](
+			resourcetest.RandomMetadata(rnd, "default", protoResourceType),
+			protobuf.NewResourceSpec(&v1alpha1.Metadata{
+				Namespace:  specMd.Namespace(),
+				Type:       specMd.Type(),
+				Id:         specMd.ID(),
+				Owner:      specMd.Owner(),
+				Created:    timestamppb.New(specMd.Created()),
+				Finalizers: *specMd.Finalizers(),
+				Labels:     specMd.Labels().Raw(),
+			}),
+		)
+	}, resourcetest.WithSeed(1))
+
+	resourcetest.CheckRoundTrip(t, func(rnd *rand.Rand) resource.Resource {
+		spec := meta.ConditionSpec{
+			Status: meta.ConditionStatus(resourcetest.RandomString(rnd)),
+			Ready:  rnd.Intn(10),
+			Total:  rnd.Intn(10),
+		}
+
+		for i := rnd.Intn(3); i > 0; i-- {
+			spec.Failed = append(spec.Failed, resourcetest.RandomString(rnd))
+		}
+
+		return typed.NewResource[meta.ConditionSpec, meta.ConditionRD](resourcetest.RandomMetadata(rnd, "default", meta.ConditionType), spec)
+	}, resourcetest.WithAssertions(resourcetest.AssertYAMLRoundTrip, resourcetest.AssertAnyRoundTrip))
+}
+
+func TestRoundTripFailures(t *testing.T) {
+	t.Parallel()
+
+	md := resource.NewMetadata("default", "Lossy.test.cosi.dev", "lossy", resource.VersionUndefined)
+	lossy := typed.NewResource[lossySpec, lossyRD](md, lossySpec{Kept: "a", Lost: "b"})
+
+	for _, assertion := range []resourcetest.Assertion{resourcetest.AssertYAMLRoundTrip, resourcetest.AssertAnyRoundTrip} {
+		rec := &recorder{TB: t}
+
+		assert.False(t, assertion(rec, lossy))
+		require.Len(t, rec.errors, 1)
+		assert.Contains(t, rec.errors[0], "doesn't match the original resource")
+	}
+
+	// the spec doesn't support protobuf marshaling
+	rec := &recorder{TB: t}
+
+	assert.False(t, resourcetest.AssertProtobufRoundTrip(rec, lossy))
+	require.Len(t, rec.errors, 1)
+	assert.Contains(t, rec.errors[0], "doesn't support protobuf marshaling")
+
+	// the type is not registered
+	rec = &recorder{TB: t}
+	unregistered := typed.NewResource[protoSpec, protoRD](resource.NewMetadata("default", "Unregistered.test.cosi.dev", "a", resource.VersionUndefined),
+		protobuf.NewResourceSpec(&v1alpha1.Metadata{}))
+
+	assert.False(t, resourcetest.AssertProtobufRoundTrip(rec, unregistered))
+	require.Len(t, rec.errors, 1)
+	assert.Contains(t, rec.errors[0], "is not registered")
+
+	// the failing seed is reported
+	rec = &recorder{TB: t}
+
+	assert.False(t, resourcetest.CheckRoundTrip(rec, func(rnd *rand.Rand) resource.Resource {
+		return typed.NewResource[lossySpec, lossyRD](resourcetest.RandomMetadata(rnd, "default", "Lossy.test.cosi.dev"), lossySpec{Lost: "b"})
+	}, resourcetest.WithSeed(42), resourcetest.WithAssertions(resourcetest.AssertYAMLRoundTrip)))
+	require.Len(t, rec.errors, 2)
+	assert.Contains(t, rec.errors[1], "resourcetest.WithSeed(42)")
+}
@@ -0,0 +1,19 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package resource
+
+// StatusResource is implemented by resources which have a status subresource.
+//
+// Status is updated separately from the rest of the resource (see state.UpdateStatus),
+// status changes don't bump the generation, and the status has its own owner.
+type StatusResource interface {
+	Resource
+
+	// Status returns the status portion of the resource.
+	Status() interface{}
+
+	// SetStatus replaces the status portion of the resource.
+	SetStatus(status interface{}) error
+}
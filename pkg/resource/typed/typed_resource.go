@@ -51,6 +51,11 @@ func (t *Resource[T, RD]) DeepCopy() resource.Resource { //nolint:ireturn
 	return &Resource[T, RD]{t.spec.DeepCopy(), t.md}
 }
 
+// SpecHash returns a stable hash of the spec, see resource.SpecHash.
+func (t *Resource[T, RD]) SpecHash() (string, error) {
+	return resource.SpecHash(t)
+}
+
 // ResourceDefinition implements spec.ResourceDefinitionProvider interface.
 func (t *Resource[T, RD]) ResourceDefinition() spec.ResourceDefinitionSpec {
 	var zero RD
@@ -94,3 +94,53 @@ func TestUnmarshalJSON(t *testing.T) {
 	assert.NotNil(t, res.TypedSpec().Value)
 	assert.Equal(t, "1", res.TypedSpec().Value.Id)
 }
+
+type statusSpec = protobuf.ResourceSpec[v1alpha1.Metadata, *v1alpha1.Metadata]
+
+type statusRD struct{}
+
+func (statusRD) ResourceDefinition(resource.Metadata, statusSpec) meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{}
+}
+
+type StatusTest = typed.ResourceWithStatus[statusSpec, statusSpec, statusRD]
+
+var _ resource.StatusResource = (*StatusTest)(nil)
+
+func TestTypedResourceWithStatus(t *testing.T) {
+	t.Parallel()
+
+	res := typed.NewResourceWithStatus[statusSpec, statusSpec, statusRD](
+		resource.NewMetadata("default", "type", "aaa", resource.VersionUndefined),
+		protobuf.NewResourceSpec(&v1alpha1.Metadata{Id: "spec"}),
+		protobuf.NewResourceSpec(&v1alpha1.Metadata{Id: "status"}),
+	)
+
+	// status is not a part of the spec
+	resCopy := res.DeepCopy().(*StatusTest) //nolint:forcetypeassert
+	resCopy.TypedStatus().Value.Id = "updated"
+
+	assert.True(t, resource.SpecEqual(res, resCopy))
+	assert.Equal(t, "status", res.TypedStatus().Value.Id)
+
+	assert.NoError(t, res.SetStatus(resCopy.Status()))
+	assert.Equal(t, "updated", res.TypedStatus().Value.Id)
+	assert.Error(t, res.SetStatus(42))
+
+	// status is marshaled separately from the spec
+	protoR, err := protobuf.FromResource(res)
+	assert.NoError(t, err)
+
+	marshaled, err := protoR.Marshal()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, marshaled.GetStatus().GetProtoSpec())
+
+	unmarshaledProto, err := protobuf.Unmarshal(marshaled)
+	assert.NoError(t, err)
+
+	var unmarshaled StatusTest
+
+	assert.NoError(t, unmarshaledProto.Unmarshal(&unmarshaled))
+	assert.Equal(t, "spec", unmarshaled.TypedSpec().Value.Id)
+	assert.Equal(t, "updated", unmarshaled.TypedStatus().Value.Id)
+}
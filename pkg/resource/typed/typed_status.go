@@ -0,0 +1,117 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package typed
+
+import (
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta/spec"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+)
+
+// ResourceWithStatus is a Resource with the status split from the spec.
+//
+// ResourceWithStatus implements resource.StatusResource: the status is updated separately from the spec
+// (see state.UpdateStatus), and status changes don't bump the generation of the resource.
+type ResourceWithStatus[T DeepCopyable[T], S DeepCopyable[S], RD ResourceDefinition[T]] struct {
+	spec   T
+	status S
+	md     resource.Metadata
+}
+
+// Metadata implements Resource.
+func (t *ResourceWithStatus[T, S, RD]) Metadata() *resource.Metadata {
+	return &t.md
+}
+
+// Spec implements resource.Resource.
+func (t *ResourceWithStatus[T, S, RD]) Spec() interface{} {
+	return &t.spec
+}
+
+// TypedSpec returns a pointer to spec field.
+func (t *ResourceWithStatus[T, S, RD]) TypedSpec() *T {
+	return &t.spec
+}
+
+// Status implements resource.StatusResource.
+func (t *ResourceWithStatus[T, S, RD]) Status() interface{} {
+	return &t.status
+}
+
+// SetStatus implements resource.StatusResource.
+func (t *ResourceWithStatus[T, S, RD]) SetStatus(status interface{}) error {
+	switch s := status.(type) {
+	case *S:
+		t.status = (*s).DeepCopy()
+	case S:
+		t.status = s.DeepCopy()
+	default:
+		return fmt.Errorf("unexpected status %T", status)
+	}
+
+	return nil
+}
+
+// TypedStatus returns a pointer to status field.
+func (t *ResourceWithStatus[T, S, RD]) TypedStatus() *S {
+	return &t.status
+}
+
+// DeepCopy returns a deep copy of ResourceWithStatus.
+func (t *ResourceWithStatus[T, S, RD]) DeepCopy() resource.Resource { //nolint:ireturn
+	return &ResourceWithStatus[T, S, RD]{t.spec.DeepCopy(), t.status.DeepCopy(), t.md}
+}
+
+// SpecHash returns a stable hash of the spec, see resource.SpecHash.
+func (t *ResourceWithStatus[T, S, RD]) SpecHash() (string, error) {
+	return resource.SpecHash(t)
+}
+
+// ResourceDefinition implements spec.ResourceDefinitionProvider interface.
+func (t *ResourceWithStatus[T, S, RD]) ResourceDefinition() spec.ResourceDefinitionSpec {
+	var zero RD
+
+	return zero.ResourceDefinition(t.md, t.spec)
+}
+
+// UnmarshalProto implements protobuf.Unmarshaler interface in a generic way.
+//
+// UnmarshalProto requires that the spec implements the protobuf.ProtoUnmarshaller interface.
+func (t *ResourceWithStatus[T, S, RD]) UnmarshalProto(md *resource.Metadata, protoBytes []byte) error {
+	protoSpec, ok := any(&t.spec).(protobuf.ProtoUnmarshaler)
+	if !ok {
+		return fmt.Errorf("spec does not implement ProtoUnmarshaler")
+	}
+
+	if err := protoSpec.UnmarshalProto(protoBytes); err != nil {
+		return err
+	}
+
+	t.md = *md
+
+	return nil
+}
+
+// UnmarshalStatusProto implements protobuf.StatusUnmarshaler interface in a generic way.
+//
+// UnmarshalStatusProto requires that the status implements the protobuf.ProtoUnmarshaller interface.
+func (t *ResourceWithStatus[T, S, RD]) UnmarshalStatusProto(protoBytes []byte) error {
+	protoStatus, ok := any(&t.status).(protobuf.ProtoUnmarshaler)
+	if !ok {
+		return fmt.Errorf("status does not implement ProtoUnmarshaler")
+	}
+
+	return protoStatus.UnmarshalProto(protoBytes)
+}
+
+// NewResourceWithStatus initializes and returns a new instance of ResourceWithStatus with typed spec and status fields.
+func NewResourceWithStatus[T DeepCopyable[T], S DeepCopyable[S], RD ResourceDefinition[T]](md resource.Metadata, spec T, status S) *ResourceWithStatus[T, S, RD] {
+	result := ResourceWithStatus[T, S, RD]{md: md, spec: spec, status: status}
+	result.md.BumpVersion()
+
+	return &result
+}
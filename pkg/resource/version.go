@@ -49,12 +49,12 @@ func ParseVersion(ver string) (Version, error) {
 		return VersionUndefined, nil
 	}
 
-	intVersion, err := strconv.ParseInt(ver, 10, 64)
+	uintVersion, err := strconv.ParseUint(ver, 10, 64)
 	if err != nil {
 		return VersionUndefined, fmt.Errorf("error parsing version: %w", err)
 	}
 
 	return Version{
-		uint64: pointer.To(uint64(intVersion)),
+		uint64: pointer.To(uintVersion),
 	}, nil
 }
@@ -0,0 +1,87 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package safe
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// BatchError aggregates errors of the batch operation.
+//
+// Each error is wrapped with the pointer of the resource it belongs to, so errors can be inspected
+// with errors.Is, state.IsNotFoundError and similar helpers.
+type BatchError struct {
+	Errors []error
+}
+
+// Error implements error interface.
+func (err *BatchError) Error() string {
+	messages := make([]string, 0, len(err.Errors))
+
+	for _, e := range err.Errors {
+		messages = append(messages, e.Error())
+	}
+
+	return fmt.Sprintf("%d errors occurred: %s", len(err.Errors), strings.Join(messages, "; "))
+}
+
+// batchErrors collects per-resource errors of the batch operation.
+type batchErrors []error
+
+func (errs *batchErrors) add(ptr resource.Pointer, err error) {
+	*errs = append(*errs, fmt.Errorf("%s/%s/%s: %w", ptr.Namespace(), ptr.Type(), ptr.ID(), err))
+}
+
+func (errs batchErrors) err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &BatchError{Errors: errs}
+}
+
+// StateGetMultiple gets the resources for the pointers.
+//
+// Results are returned in the order of the pointers, resources which failed to be fetched are returned as zero value,
+// and the errors are aggregated into BatchError.
+func StateGetMultiple[T resource.Resource](ctx context.Context, st state.State, ptrs []resource.Pointer, options ...state.GetOption) ([]T, error) {
+	var errs batchErrors
+
+	results := make([]T, len(ptrs))
+
+	for i, ptr := range ptrs {
+		got, err := StateGet[T](ctx, st, ptr, options...)
+		if err != nil {
+			errs.add(ptr, err)
+
+			continue
+		}
+
+		results[i] = got
+	}
+
+	return results, errs.err()
+}
+
+// WriterModifyMultiple modifies the resources with the function.
+//
+// All resources are modified even if some of them fail, and the errors are aggregated into BatchError.
+func WriterModifyMultiple[T resource.Resource](ctx context.Context, writer controller.Writer, resources []T, fn func(T) error) error {
+	var errs batchErrors
+
+	for _, r := range resources {
+		if err := WriterModify(ctx, writer, r, fn); err != nil {
+			errs.add(r.Metadata(), err)
+		}
+	}
+
+	return errs.err()
+}
@@ -0,0 +1,79 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package safe_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+// stateWriter implements Modify of controller.Writer directly on top of the state.
+type stateWriter struct {
+	controller.Writer
+
+	st state.State
+}
+
+func (w stateWriter) Modify(ctx context.Context, r resource.Resource, fn func(resource.Resource) error) error {
+	return safe.StateModify(ctx, w.st, r, fn)
+}
+
+func TestBatchHelpers(t *testing.T) {
+	ctx := context.Background()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	errOdd := errors.New("odd value")
+
+	err := safe.WriterModifyMultiple(ctx, stateWriter{st: st}, []*conformance.IntResource{
+		conformance.NewIntResource("default", "one", 0),
+		conformance.NewIntResource("default", "two", 0),
+		conformance.NewIntResource("default", "three", 0),
+	}, func(r *conformance.IntResource) error {
+		switch r.Metadata().ID() {
+		case "one":
+			r.SetValue(1)
+		case "two":
+			r.SetValue(2)
+		case "three":
+			return errOdd
+		}
+
+		return nil
+	})
+
+	var batchErr *safe.BatchError
+
+	require.ErrorAs(t, err, &batchErr)
+	require.Len(t, batchErr.Errors, 1)
+	assert.ErrorIs(t, batchErr.Errors[0], errOdd)
+
+	got, err := safe.StateGetMultiple[*conformance.IntResource](ctx, st, []resource.Pointer{
+		conformance.NewIntResource("default", "one", 0).Metadata(),
+		conformance.NewIntResource("default", "three", 0).Metadata(),
+		conformance.NewIntResource("default", "two", 0).Metadata(),
+	})
+
+	require.ErrorAs(t, err, &batchErr)
+	require.Len(t, batchErr.Errors, 1)
+	assert.True(t, state.IsNotFoundError(batchErr.Errors[0]))
+
+	require.Len(t, got, 3)
+	assert.Equal(t, 1, got[0].Value())
+	assert.Nil(t, got[1])
+	assert.Equal(t, 2, got[2].Value())
+}
@@ -0,0 +1,92 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package safe
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// SpecMismatchError is returned by StateCreateIfNotExists when the existing resource has a different spec.
+type SpecMismatchError struct {
+	Namespace resource.Namespace
+	Type      resource.Type
+	ID        resource.ID
+}
+
+// Error implements error interface.
+func (err *SpecMismatchError) Error() string {
+	return fmt.Sprintf("resource %s/%s/%s already exists with a different spec", err.Namespace, err.Type, err.ID)
+}
+
+// StateGetOrCreate creates the resource, or returns the existing resource if it already exists.
+//
+// The existing resource is returned as is, even if its spec is different.
+func StateGetOrCreate[T resource.Resource](ctx context.Context, st state.State, r T, options ...state.CreateOption) (T, error) { //nolint:ireturn
+	got, _, err := getOrCreate(ctx, st, r, options...)
+
+	return got, err
+}
+
+// StateCreateIfNotExists creates the resource if it doesn't exist, and reports whether it was created.
+//
+// If the resource already exists, its spec should be equal to the spec of r, otherwise SpecMismatchError is returned.
+func StateCreateIfNotExists[T resource.Resource](ctx context.Context, st state.State, r T, options ...state.CreateOption) (bool, error) {
+	got, created, err := getOrCreate(ctx, st, r, options...)
+	if err != nil || created {
+		return created, err
+	}
+
+	if !specEqual(got.Spec(), r.Spec()) {
+		return false, &SpecMismatchError{
+			Namespace: r.Metadata().Namespace(),
+			Type:      r.Metadata().Type(),
+			ID:        r.Metadata().ID(),
+		}
+	}
+
+	return false, nil
+}
+
+func getOrCreate[T resource.Resource](ctx context.Context, st state.State, r T, options ...state.CreateOption) (T, bool, error) { //nolint:ireturn
+	var zero T
+
+	for {
+		err := st.Create(ctx, r, options...)
+		if err == nil {
+			return r, true, nil
+		}
+
+		if !state.IsConflictError(err) {
+			return zero, false, err
+		}
+
+		existing, err := StateGet[T](ctx, st, r.Metadata())
+		if err != nil {
+			// resource was destroyed in between, try to create it again
+			if state.IsNotFoundError(err) {
+				continue
+			}
+
+			return zero, false, err
+		}
+
+		return existing, false, nil
+	}
+}
+
+func specEqual(spec1, spec2 interface{}) bool {
+	if equality, ok := spec1.(interface {
+		Equal(interface{}) bool
+	}); ok {
+		return equality.Equal(spec2)
+	}
+
+	return reflect.DeepEqual(spec1, spec2)
+}
@@ -0,0 +1,48 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package safe_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestStateGetOrCreate(t *testing.T) {
+	ctx := context.Background()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	got, err := safe.StateGetOrCreate(ctx, st, conformance.NewIntResource("default", "one", 1))
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Value())
+
+	got, err = safe.StateGetOrCreate(ctx, st, conformance.NewIntResource("default", "one", 2))
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Value())
+
+	created, err := safe.StateCreateIfNotExists(ctx, st, conformance.NewIntResource("default", "two", 2))
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	created, err = safe.StateCreateIfNotExists(ctx, st, conformance.NewIntResource("default", "two", 2))
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	_, err = safe.StateCreateIfNotExists(ctx, st, conformance.NewIntResource("default", "two", 3))
+
+	var mismatch *safe.SpecMismatchError
+
+	require.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, "two", mismatch.ID)
+}
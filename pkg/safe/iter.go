@@ -0,0 +1,86 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package safe
+
+import (
+	"context"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// Iterator adapters below have the signatures of iter.Seq and iter.Seq2, so they can be used with range-over-func:
+//
+//	for event, err := range safe.StateWatchKindSeq[*MyResource](ctx, st, kind) { ... }
+//
+// The adapters are declared as plain function types to keep compatibility with the Go version of the module.
+
+// ChannelSeq returns an iterator over the values received from the channel.
+//
+// Iteration stops when the channel is closed, the context is canceled, or the consumer stops the iteration.
+func ChannelSeq[T any](ctx context.Context, ch <-chan T) func(yield func(T) bool) {
+	return func(yield func(T) bool) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-ch:
+				if !ok || !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// IteratorSeq returns an iterator over the ListIterator values.
+func IteratorSeq[T any](it ListIterator[T]) func(yield func(T) bool) {
+	return func(yield func(T) bool) {
+		for it.Next() {
+			if !yield(it.Value()) {
+				return
+			}
+		}
+	}
+}
+
+// StateWatchSeq returns an iterator over the watch events of the resource.
+//
+// Watch is established when the iteration starts, and it is canceled when the iteration stops.
+// Watch setup error is yielded as the only element of the iteration.
+func StateWatchSeq[T resource.Resource](ctx context.Context, st state.CoreState, ptr resource.Pointer, opts ...state.WatchOption) func(yield func(WrappedStateEvent[T], error) bool) {
+	return watchSeq[T](ctx, func(ctx context.Context, ch chan<- state.Event) error {
+		return st.Watch(ctx, ptr, ch, opts...)
+	})
+}
+
+// StateWatchKindSeq returns an iterator over the watch events of the resource kind.
+//
+// Watch is established when the iteration starts, and it is canceled when the iteration stops.
+// Watch setup error is yielded as the only element of the iteration.
+func StateWatchKindSeq[T resource.Resource](ctx context.Context, st state.CoreState, kind resource.Kind, opts ...state.WatchKindOption) func(yield func(WrappedStateEvent[T], error) bool) {
+	return watchSeq[T](ctx, func(ctx context.Context, ch chan<- state.Event) error {
+		return st.WatchKind(ctx, kind, ch, opts...)
+	})
+}
+
+func watchSeq[T resource.Resource](ctx context.Context, watch func(context.Context, chan<- state.Event) error) func(yield func(WrappedStateEvent[T], error) bool) {
+	return func(yield func(WrappedStateEvent[T], error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		ch := make(chan state.Event)
+
+		if err := watch(ctx, ch); err != nil {
+			yield(WrappedStateEvent[T]{}, err)
+
+			return
+		}
+
+		ChannelSeq(ctx, (<-chan state.Event)(ch))(func(event state.Event) bool {
+			return yield(NewWrappedStateEvent[T](event), nil)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package safe_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestChannelSeq(t *testing.T) {
+	ch := make(chan int, 3)
+
+	ch <- 1
+	ch <- 2
+	ch <- 3
+
+	close(ch)
+
+	var values []int
+
+	safe.ChannelSeq(context.Background(), (<-chan int)(ch))(func(v int) bool {
+		values = append(values, v)
+
+		return true
+	})
+
+	assert.Equal(t, []int{1, 2, 3}, values)
+}
+
+func TestStateWatchKindSeq(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, st.Create(ctx, conformance.NewIntResource("default", fmt.Sprintf("r%d", i), i)))
+	}
+
+	var values []int
+
+	safe.StateWatchKindSeq[*conformance.IntResource](ctx, st, resource.NewMetadata("default", conformance.IntResourceType, "", resource.VersionUndefined),
+		state.WithBootstrapContents(true))(func(event safe.WrappedStateEvent[*conformance.IntResource], err error) bool {
+		require.NoError(t, err)
+
+		r, err := event.Resource()
+		require.NoError(t, err)
+
+		values = append(values, r.Value())
+
+		return len(values) < 3
+	})
+
+	assert.Equal(t, []int{0, 1, 2}, values)
+}
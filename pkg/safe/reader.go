@@ -11,6 +11,7 @@ import (
 
 	"github.com/cosi-project/runtime/pkg/controller"
 	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
 	"github.com/cosi-project/runtime/pkg/state"
 )
 
@@ -81,3 +82,32 @@ func ReaderWatchFor[T resource.Resource](ctx context.Context, rdr controller.Rea
 func ReaderWatchForResource[T resource.Resource](ctx context.Context, rdr controller.Reader, r T, conds ...state.WatchForConditionFunc) (T, error) { //nolint:ireturn
 	return ReaderWatchFor[T](ctx, rdr, r.Metadata(), conds...)
 }
+
+// ReaderListAll lists resources of the type across all registered namespaces.
+//
+// Namespaces are discovered via meta.Namespace resources, so the reader should have access to them
+// (and to the resource type in each namespace).
+// Results are keyed by namespace, namespaces without resources of the type are omitted.
+func ReaderListAll[T resource.Resource](ctx context.Context, rdr controller.Reader, resourceType resource.Type, opts ...state.ListOption) (map[resource.Namespace]List[T], error) {
+	namespaces, err := ReaderList[*meta.Namespace](ctx, rdr, resource.NewMetadata(meta.NamespaceName, meta.NamespaceType, "", resource.VersionUndefined))
+	if err != nil {
+		return nil, fmt.Errorf("error listing namespaces: %w", err)
+	}
+
+	result := map[resource.Namespace]List[T]{}
+
+	for iter := IteratorFromList(namespaces); iter.Next(); {
+		ns := iter.Value().Metadata().ID()
+
+		list, err := ReaderList[T](ctx, rdr, resource.NewMetadata(ns, resourceType, "", resource.VersionUndefined), opts...)
+		if err != nil {
+			return nil, fmt.Errorf("error listing resources in namespace %q: %w", ns, err)
+		}
+
+		if list.Len() > 0 {
+			result[ns] = list
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,108 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package safe_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/cosi-project/runtime/pkg/state/registry"
+)
+
+// stateReader implements controller.Reader directly on top of the state.
+type stateReader struct {
+	st state.State
+}
+
+func (r stateReader) Get(ctx context.Context, ptr resource.Pointer) (resource.Resource, error) { //nolint:ireturn
+	return r.st.Get(ctx, ptr)
+}
+
+func (r stateReader) List(ctx context.Context, kind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	return r.st.List(ctx, kind, opts...)
+}
+
+func (r stateReader) WatchFor(ctx context.Context, ptr resource.Pointer, opts ...state.WatchForConditionFunc) (resource.Resource, error) { //nolint:ireturn
+	return r.st.WatchFor(ctx, ptr, opts...)
+}
+
+func TestReaderListAll(t *testing.T) {
+	ctx := context.Background()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	namespaces := registry.NewNamespaceRegistry(st)
+	require.NoError(t, namespaces.RegisterDefault(ctx))
+
+	for _, ns := range []resource.Namespace{"ns1", "ns2", "empty"} {
+		require.NoError(t, namespaces.Register(ctx, ns, ""))
+	}
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("ns1", "one", 1)))
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("ns1", "two", 2)))
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("ns2", "three", 3)))
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("unregistered", "four", 4)))
+
+	all, err := safe.ReaderListAll[*conformance.IntResource](ctx, stateReader{st: st}, conformance.IntResourceType)
+	require.NoError(t, err)
+
+	require.Len(t, all, 2)
+
+	ns1 := all["ns1"]
+	ns2 := all["ns2"]
+
+	assert.Equal(t, []int{1, 2}, safe.Map(ns1, (*conformance.IntResource).Value))
+	assert.Equal(t, []int{3}, safe.Map(ns2, (*conformance.IntResource).Value))
+}
+
+func TestReaderResolve(t *testing.T) {
+	ctx := context.Background()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	one := conformance.NewIntResource("default", "one", 1)
+	require.NoError(t, st.Create(ctx, one))
+
+	ref := resource.NewRef(one.Metadata())
+	assert.Equal(t, "test/int(default/one@1)", ref.String())
+
+	r, err := safe.ReaderResolve[*conformance.IntResource](ctx, stateReader{st: st}, ref)
+	require.NoError(t, err)
+	assert.Equal(t, 1, r.Value())
+
+	_, err = safe.StateUpdateWithConflicts(ctx, st, one.Metadata(), func(r *conformance.IntResource) error {
+		r.SetValue(2)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, err = safe.ReaderResolve[*conformance.IntResource](ctx, stateReader{st: st}, ref)
+
+	var staleErr *safe.StaleReferenceError
+
+	require.ErrorAs(t, err, &staleErr)
+	assert.Equal(t, "2", staleErr.Version.String())
+
+	ref.Version = ""
+
+	r, err = safe.ReaderResolve[*conformance.IntResource](ctx, stateReader{st: st}, ref)
+	require.NoError(t, err)
+	assert.Equal(t, 2, r.Value())
+
+	ref.ID = "two"
+
+	_, err = safe.ReaderResolve[*conformance.IntResource](ctx, stateReader{st: st}, ref)
+	assert.True(t, state.IsNotFoundError(err))
+}
@@ -0,0 +1,46 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package safe
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+// StaleReferenceError is returned by ReaderResolve when the referenced resource has a different version.
+type StaleReferenceError struct {
+	Ref     resource.Ref
+	Version resource.Version
+}
+
+// Error implements error interface.
+func (err *StaleReferenceError) Error() string {
+	return fmt.Sprintf("reference %s is stale: resource version is %s", err.Ref, err.Version)
+}
+
+// ReaderResolve gets the resource the reference points to.
+//
+// If the reference has a version, the resource should have the same version, otherwise StaleReferenceError is returned.
+// If the resource doesn't exist, the not found error of the reader is returned.
+func ReaderResolve[T resource.Resource](ctx context.Context, rdr controller.Reader, ref resource.Ref) (T, error) { //nolint:ireturn
+	result, err := ReaderGet[T](ctx, rdr, ref.Pointer())
+	if err != nil {
+		return result, err
+	}
+
+	if ref.Version != "" && result.Metadata().Version().String() != ref.Version {
+		var zero T
+
+		return zero, &StaleReferenceError{
+			Ref:     ref,
+			Version: result.Metadata().Version(),
+		}
+	}
+
+	return result, nil
+}
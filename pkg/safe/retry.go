@@ -0,0 +1,102 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package safe
+
+import (
+	"context"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// IsRetryableConflict checks if the error is a conflict which might go away on retry.
+//
+// Owner and phase conflicts are not retryable, as they don't go away by re-reading the resource.
+func IsRetryableConflict(err error) bool {
+	return state.IsConflictError(err) && !state.IsOwnerConflictError(err) && !state.IsPhaseConflictError(err)
+}
+
+// RetryOnConflict calls fn until it succeeds or returns an error which is not a retryable conflict.
+//
+// The interval between the attempts is defined by the backoff, and the last error is returned once the backoff stops.
+// If backoff is nil, exponential backoff with default settings is used.
+func RetryOnConflict(ctx context.Context, fn func(ctx context.Context) error, b backoff.BackOff) error {
+	if b == nil {
+		b = backoff.NewExponentialBackOff()
+	}
+
+	b.Reset()
+
+	for {
+		err := fn(ctx)
+		if err == nil || !IsRetryableConflict(err) {
+			return err
+		}
+
+		interval := b.NextBackOff()
+		if interval == backoff.Stop {
+			return err
+		}
+
+		timer := time.NewTimer(interval)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// StateUpdateWithConflictsBackoff is like StateUpdateWithConflicts, but it waits according to the backoff
+// before re-reading the resource on version conflict.
+//
+// Unlike StateUpdateWithConflicts, it returns the resource as it was written to the state.
+func StateUpdateWithConflictsBackoff[T resource.Resource](
+	ctx context.Context, st state.State, ptr resource.Pointer, updateFn func(T) error, b backoff.BackOff, options ...state.UpdateOption,
+) (T, error) { //nolint:ireturn
+	var result T
+
+	err := RetryOnConflict(ctx, func(ctx context.Context) error {
+		current, err := StateGet[T](ctx, st, ptr)
+		if err != nil {
+			return err
+		}
+
+		updated := current.DeepCopy().(T) //nolint:forcetypeassert
+
+		if err = updateFn(updated); err != nil {
+			return err
+		}
+
+		if resource.ContentEqual(current, updated) {
+			result = current
+
+			return nil
+		}
+
+		updated.Metadata().BumpVersion()
+
+		if err = st.Update(ctx, current.Metadata().Version(), updated, options...); err != nil {
+			return err
+		}
+
+		result = updated
+
+		return nil
+	}, b)
+	if err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,123 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package safe_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestRetryOnConflict(t *testing.T) {
+	ctx := context.Background()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("default", "one", 1)))
+
+	attempts := 0
+
+	// the first attempt conflicts with the resource which already exists
+	err := safe.RetryOnConflict(ctx, func(ctx context.Context) error {
+		attempts++
+
+		if attempts == 1 {
+			return st.Create(ctx, conformance.NewIntResource("default", "one", 1))
+		}
+
+		return nil
+	}, backoff.NewConstantBackOff(time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+
+	attempts = 0
+	errSomething := errors.New("something")
+
+	err = safe.RetryOnConflict(ctx, func(context.Context) error {
+		attempts++
+
+		return errSomething
+	}, nil)
+	require.ErrorIs(t, err, errSomething)
+	assert.Equal(t, 1, attempts)
+
+	attempts = 0
+
+	err = safe.RetryOnConflict(ctx, func(ctx context.Context) error {
+		attempts++
+
+		return st.Create(ctx, conformance.NewIntResource("default", "one", 1))
+	}, backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Millisecond), 2))
+	assert.True(t, state.IsConflictError(err))
+	assert.Equal(t, 3, attempts)
+}
+
+func TestStateUpdateWithConflictsBackoff(t *testing.T) {
+	ctx := context.Background()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	r := conformance.NewIntResource("default", "one", 1)
+	require.NoError(t, st.Create(ctx, r))
+
+	attempts := 0
+
+	updated, err := safe.StateUpdateWithConflictsBackoff(ctx, st, r.Metadata(), func(r *conformance.IntResource) error {
+		attempts++
+
+		if attempts == 1 {
+			// concurrent update causes a version conflict
+			concurrent := conformance.NewIntResource("default", "one", 5)
+			*concurrent.Metadata() = r.Metadata().Copy()
+			concurrent.Metadata().BumpVersion()
+
+			if err := st.Update(ctx, r.Metadata().Version(), concurrent); err != nil {
+				return err
+			}
+		}
+
+		r.SetValue(r.Value() + 1)
+
+		return nil
+	}, backoff.NewConstantBackOff(time.Millisecond))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, 6, updated.Value())
+
+	current, err := safe.StateGet[*conformance.IntResource](ctx, st, r.Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, current.Metadata().Version(), updated.Metadata().Version())
+	assert.Equal(t, 6, current.Value())
+
+	_, err = st.Teardown(ctx, r.Metadata())
+	require.NoError(t, err)
+
+	_, err = safe.StateUpdateWithConflictsBackoff(ctx, st, r.Metadata(), func(r *conformance.IntResource) error {
+		r.SetValue(10)
+
+		return nil
+	}, nil)
+	assert.True(t, state.IsPhaseConflictError(err))
+
+	_, err = safe.StateUpdateWithConflictsBackoff(ctx, st, r.Metadata(), func(r *conformance.IntResource) error {
+		r.SetValue(10)
+
+		return nil
+	}, nil, state.WithExpectedPhase(resource.PhaseTearingDown))
+	require.NoError(t, err)
+}
@@ -62,6 +62,56 @@ func StateUpdateWithConflicts[T resource.Resource](ctx context.Context, st state
 	return result, nil
 }
 
+// StateModify creates the resource if it doesn't exist, or updates it with conflict handling.
+//
+// The resource is created with the owner set by state.WithUpdateOwner.
+func StateModify[T resource.Resource](ctx context.Context, st state.State, r T, fn func(T) error, options ...state.UpdateOption) error {
+	_, err := StateModifyWithResult(ctx, st, r, fn, options...)
+
+	return err
+}
+
+// StateModifyWithResult is like StateModify, but it returns the resource as it was written to the state.
+func StateModifyWithResult[T resource.Resource](ctx context.Context, st state.State, r T, fn func(T) error, options ...state.UpdateOption) (T, error) { //nolint:ireturn
+	var zero T
+
+	opts := state.DefaultUpdateOptions()
+
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	_, err := st.Get(ctx, r.Metadata())
+	if err != nil {
+		if !state.IsNotFoundError(err) {
+			return zero, err
+		}
+
+		if err = fn(r); err != nil {
+			return zero, err
+		}
+
+		if err = st.Create(ctx, r, state.WithCreateOwner(opts.Owner)); err != nil {
+			return zero, err
+		}
+
+		// the state stores a copy of the resource with the owner, version and generation set by the state
+		return StateGet[T](ctx, st, r.Metadata())
+	}
+
+	var modified T
+
+	if _, err = StateUpdateWithConflicts(ctx, st, r.Metadata(), func(arg T) error {
+		modified = arg
+
+		return fn(arg)
+	}, options...); err != nil {
+		return zero, err
+	}
+
+	return modified, nil
+}
+
 // StateList is a type safe wrapper around state.List.
 func StateList[T resource.Resource](ctx context.Context, st state.State, ptr resource.Pointer, options ...state.ListOption) (List[T], error) {
 	got, err := st.List(ctx, ptr, options...)
@@ -107,6 +157,107 @@ func (l *List[T]) Len() int {
 	return len(l.list.Items)
 }
 
+// All returns an iterator over the list items.
+//
+// The iterator can be used with range-over-func: for item := range list.All() { ... }.
+func (l *List[T]) All() func(yield func(T) bool) {
+	return func(yield func(T) bool) {
+		for i := 0; i < l.Len(); i++ {
+			if !yield(l.Get(i)) {
+				return
+			}
+		}
+	}
+}
+
+// Filter returns a new list with the items which match the predicate.
+func (l *List[T]) Filter(fn func(T) bool) List[T] {
+	var filtered resource.List
+
+	for i := 0; i < l.Len(); i++ {
+		if fn(l.Get(i)) {
+			filtered.Items = append(filtered.Items, l.list.Items[i])
+		}
+	}
+
+	return NewList[T](filtered)
+}
+
+// Find returns the first item which matches the predicate.
+func (l *List[T]) Find(fn func(T) bool) (T, bool) { //nolint:ireturn
+	for i := 0; i < l.Len(); i++ {
+		if item := l.Get(i); fn(item) {
+			return item, true
+		}
+	}
+
+	var zero T
+
+	return zero, false
+}
+
+// Map converts the list items with the function.
+func Map[T, U any](l List[T], fn func(T) U) []U {
+	result := make([]U, 0, l.Len())
+
+	for i := 0; i < l.Len(); i++ {
+		result = append(result, fn(l.Get(i)))
+	}
+
+	return result
+}
+
+// StateListIterator returns an iterator which streams resources of the kind from the state.
+//
+// If the state supports state.ListStreamer, the full list is never built, otherwise the resources are listed first.
+// An error is yielded as the last element of the iteration.
+func StateListIterator[T resource.Resource](ctx context.Context, st state.CoreState, kind resource.Kind, options ...state.ListOption) func(yield func(T, error) bool) {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		stopped := false
+
+		consume := func(r resource.Resource) bool {
+			arg, ok := r.(T)
+			if !ok {
+				stopped = true
+
+				yield(zero, fmt.Errorf("type mismatch: expected %T, got %T", arg, r))
+
+				return false
+			}
+
+			if !yield(arg, nil) {
+				stopped = true
+
+				return false
+			}
+
+			return true
+		}
+
+		var err error
+
+		if streamer, ok := st.(state.ListStreamer); ok {
+			err = streamer.ListStream(ctx, kind, consume, options...)
+		} else {
+			var list resource.List
+
+			list, err = st.List(ctx, kind, options...)
+
+			for _, r := range list.Items {
+				if err != nil || !consume(r) {
+					break
+				}
+			}
+		}
+
+		if err != nil && !stopped {
+			yield(zero, err)
+		}
+	}
+}
+
 // ListIterator is a generic iterator over resource.Resource slice.
 type ListIterator[T any] struct {
 	list List[T]
@@ -0,0 +1,121 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package safe_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestStateModifyWithResult(t *testing.T) {
+	ctx := context.Background()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	setValue := func(value int) func(*conformance.IntResource) error {
+		return func(r *conformance.IntResource) error {
+			r.SetValue(value)
+
+			return nil
+		}
+	}
+
+	created, err := safe.StateModifyWithResult(ctx, st, conformance.NewIntResource("default", "one", 0), setValue(1), state.WithUpdateOwner("owner"))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, created.Value())
+	assert.Equal(t, "owner", created.Metadata().Owner())
+
+	stored, err := safe.StateGet[*conformance.IntResource](ctx, st, created.Metadata())
+	require.NoError(t, err)
+
+	assert.Equal(t, stored.Metadata().Version(), created.Metadata().Version())
+	assert.Equal(t, stored.Metadata().Generation(), created.Metadata().Generation())
+
+	updated, err := safe.StateModifyWithResult(ctx, st, conformance.NewIntResource("default", "one", 0), setValue(2), state.WithUpdateOwner("owner"))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, updated.Value())
+	assert.False(t, updated.Metadata().Version().Equal(created.Metadata().Version()))
+
+	current, err := safe.StateGet[*conformance.IntResource](ctx, st, updated.Metadata())
+	require.NoError(t, err)
+
+	assert.Equal(t, current.Metadata().Version(), updated.Metadata().Version())
+	assert.Equal(t, current.Value(), updated.Value())
+
+	_, err = safe.StateModifyWithResult(ctx, st, conformance.NewIntResource("default", "one", 0), setValue(3))
+	assert.True(t, state.IsOwnerConflictError(err))
+}
+
+func TestListHelpers(t *testing.T) {
+	ctx := context.Background()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, st.Create(ctx, conformance.NewIntResource("default", fmt.Sprintf("r%d", i), i)))
+	}
+
+	kind := resource.NewMetadata("default", conformance.IntResourceType, "", resource.VersionUndefined)
+
+	list, err := safe.StateList[*conformance.IntResource](ctx, st, kind)
+	require.NoError(t, err)
+
+	value := func(r *conformance.IntResource) int { return r.Value() }
+
+	odd := list.Filter(func(r *conformance.IntResource) bool { return r.Value()%2 == 1 })
+	assert.Equal(t, []int{1, 3, 5}, safe.Map(odd, value))
+
+	found, ok := list.Find(func(r *conformance.IntResource) bool { return r.Value() > 3 })
+	require.True(t, ok)
+	assert.Equal(t, 4, found.Value())
+
+	_, ok = list.Find(func(r *conformance.IntResource) bool { return r.Value() > 5 })
+	assert.False(t, ok)
+
+	var all []int
+
+	list.All()(func(r *conformance.IntResource) bool {
+		all = append(all, r.Value())
+
+		return len(all) < 2
+	})
+
+	assert.Equal(t, []int{1, 2}, all)
+
+	var streamed []int
+
+	safe.StateListIterator[*conformance.IntResource](ctx, st, kind)(func(r *conformance.IntResource, err error) bool {
+		require.NoError(t, err)
+
+		streamed = append(streamed, r.Value())
+
+		return true
+	})
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, streamed)
+
+	var iterErr error
+
+	safe.StateListIterator[*conformance.StrResource](ctx, st, kind)(func(_ *conformance.StrResource, err error) bool {
+		iterErr = err
+
+		return true
+	})
+
+	assert.Error(t, iterErr)
+}
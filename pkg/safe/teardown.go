@@ -0,0 +1,80 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package safe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// FinalizersTimeoutError is returned by StateTeardownAndDestroy when the finalizers are not removed in time.
+type FinalizersTimeoutError struct {
+	Namespace  resource.Namespace
+	Type       resource.Type
+	ID         resource.ID
+	Finalizers resource.Finalizers
+}
+
+// Error implements error interface.
+func (err *FinalizersTimeoutError) Error() string {
+	return fmt.Sprintf("timed out waiting for finalizers on %s/%s/%s: %s", err.Namespace, err.Type, err.ID, strings.Join(err.Finalizers, ", "))
+}
+
+// StateTeardownAndDestroy tears down the resource, waits for the finalizers to be removed, and destroys it.
+//
+// If finalizers are not removed within the timeout, FinalizersTimeoutError lists the blocking finalizers.
+// It's not an error if the resource is already destroyed.
+func StateTeardownAndDestroy(ctx context.Context, st state.State, ptr resource.Pointer, timeout time.Duration, owner string) error {
+	ready, err := st.Teardown(ctx, ptr, state.WithTeardownOwner(owner))
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if !ready {
+		waitCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		_, err = st.WatchFor(waitCtx, ptr, state.WithCondition(func(r resource.Resource) (bool, error) {
+			return r.Metadata().Finalizers().Empty(), nil
+		}))
+		if err != nil {
+			if !errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil {
+				return err
+			}
+
+			return finalizersTimeoutError(ctx, st, ptr)
+		}
+	}
+
+	if err = st.Destroy(ctx, ptr, state.WithDestroyOwner(owner)); err != nil && !state.IsNotFoundError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func finalizersTimeoutError(ctx context.Context, st state.State, ptr resource.Pointer) error {
+	r, err := st.Get(ctx, ptr)
+	if err != nil {
+		return err
+	}
+
+	return &FinalizersTimeoutError{
+		Namespace:  ptr.Namespace(),
+		Type:       ptr.Type(),
+		ID:         ptr.ID(),
+		Finalizers: append(resource.Finalizers(nil), *r.Metadata().Finalizers()...),
+	}
+}
@@ -0,0 +1,55 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package safe_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestStateTeardownAndDestroy(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	r := conformance.NewIntResource("default", "one", 1)
+
+	require.NoError(t, st.Create(ctx, r))
+	require.NoError(t, st.AddFinalizer(ctx, r.Metadata(), "a", "b"))
+
+	err := safe.StateTeardownAndDestroy(ctx, st, r.Metadata(), 100*time.Millisecond, "")
+
+	var timeoutErr *safe.FinalizersTimeoutError
+
+	require.True(t, errors.As(err, &timeoutErr))
+	assert.Equal(t, resource.Finalizers{"a", "b"}, timeoutErr.Finalizers)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+
+		assert.NoError(t, st.RemoveFinalizer(ctx, r.Metadata(), "a", "b"))
+	}()
+
+	require.NoError(t, safe.StateTeardownAndDestroy(ctx, st, r.Metadata(), 5*time.Second, ""))
+
+	_, err = st.Get(ctx, r.Metadata())
+	assert.True(t, state.IsNotFoundError(err))
+
+	// destroyed resource is not an error
+	require.NoError(t, safe.StateTeardownAndDestroy(ctx, st, r.Metadata(), time.Second, ""))
+}
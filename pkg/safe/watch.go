@@ -0,0 +1,122 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package safe
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// WrappedStateEvent is a type safe wrapper around state.Event.
+type WrappedStateEvent[T resource.Resource] struct {
+	event state.Event
+}
+
+// NewWrappedStateEvent wraps the state event.
+func NewWrappedStateEvent[T resource.Resource](event state.Event) WrappedStateEvent[T] {
+	return WrappedStateEvent[T]{event: event}
+}
+
+// Type returns the event type.
+func (e WrappedStateEvent[T]) Type() state.EventType {
+	return e.event.Type
+}
+
+// Resource returns the resource of the event.
+func (e WrappedStateEvent[T]) Resource() (T, error) { //nolint:ireturn
+	return eventResource[T](e.event.Resource)
+}
+
+// Old returns the previous version of the resource for the update events.
+func (e WrappedStateEvent[T]) Old() (T, error) { //nolint:ireturn
+	if e.event.Old == nil {
+		var zero T
+
+		return zero, fmt.Errorf("event %s doesn't have old resource", e.event.Type)
+	}
+
+	return eventResource[T](e.event.Old)
+}
+
+func eventResource[T resource.Resource](r resource.Resource) (T, error) { //nolint:ireturn
+	result, ok := r.(T)
+	if !ok {
+		var zero T
+
+		return zero, fmt.Errorf("type mismatch: expected %T, got %T", result, r)
+	}
+
+	return result, nil
+}
+
+// StateWatchFor is a type safe wrapper around state.WatchFor.
+//
+// The predicate is evaluated on the typed resource of each event, events which don't carry the resource of type T
+// (e.g. tombstones for the missing resource) don't match.
+// Other conditions (event types, phases) can be passed as options, the predicate replaces state.WithCondition.
+func StateWatchFor[T resource.Resource](
+	ctx context.Context, st state.State, ptr resource.Pointer, predicate func(T) (bool, error), opts ...state.WatchForConditionFunc,
+) (T, error) { //nolint:ireturn
+	opts = append(opts, state.WithCondition(func(r resource.Resource) (bool, error) {
+		arg, ok := r.(T)
+		if !ok {
+			return false, nil
+		}
+
+		return predicate(arg)
+	}))
+
+	got, err := st.WatchFor(ctx, ptr, opts...)
+	if err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	return eventResource[T](got)
+}
+
+// StateWatchKindAggregated watches resources of the kind and delivers events in batches.
+//
+// Events which arrive while the consumer is busy are aggregated into a single batch, so that the consumer
+// which is slower than the event rate (or which bootstraps the contents with state.WithBootstrapContents)
+// processes the events in bulk.
+// Watch is canceled when context gets canceled.
+func StateWatchKindAggregated[T resource.Resource](
+	ctx context.Context, st state.CoreState, kind resource.Kind, ch chan<- []WrappedStateEvent[T], opts ...state.WatchKindOption,
+) error {
+	eventCh := make(chan state.Event)
+
+	if err := st.WatchKind(ctx, kind, eventCh, opts...); err != nil {
+		return err
+	}
+
+	go func() {
+		var batch []WrappedStateEvent[T]
+
+		for {
+			// send only when there are pending events, and keep aggregating while the consumer is busy
+			var out chan<- []WrappedStateEvent[T]
+
+			if len(batch) > 0 {
+				out = ch
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-eventCh:
+				batch = append(batch, NewWrappedStateEvent[T](event))
+			case out <- batch:
+				batch = nil
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,119 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package safe_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestStateWatchKindAggregated(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, st.Create(ctx, conformance.NewIntResource("default", fmt.Sprintf("r%d", i), i)))
+	}
+
+	ch := make(chan []safe.WrappedStateEvent[*conformance.IntResource])
+
+	require.NoError(t, safe.StateWatchKindAggregated(ctx, st, resource.NewMetadata("default", conformance.IntResourceType, "", resource.VersionUndefined), ch,
+		state.WithBootstrapContents(true)))
+
+	var values []int
+
+	for len(values) < 10 {
+		select {
+		case batch := <-ch:
+			for _, event := range batch {
+				assert.Equal(t, state.Created, event.Type())
+
+				r, err := event.Resource()
+				require.NoError(t, err)
+
+				values = append(values, r.Value())
+			}
+		case <-ctx.Done():
+			require.FailNow(t, "timed out waiting for events")
+		}
+	}
+
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, values)
+
+	_, err := safe.StateUpdateWithConflicts(ctx, st, conformance.NewIntResource("default", "r0", 0).Metadata(), func(r *conformance.IntResource) error {
+		r.SetValue(100)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	select {
+	case batch := <-ch:
+		require.Len(t, batch, 1)
+
+		r, err := batch[0].Resource()
+		require.NoError(t, err)
+		assert.Equal(t, 100, r.Value())
+
+		old, err := batch[0].Old()
+		require.NoError(t, err)
+		assert.Equal(t, 0, old.Value())
+	case <-ctx.Done():
+		require.FailNow(t, "timed out waiting for events")
+	}
+}
+
+func TestStateWatchFor(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	r := conformance.NewIntResource("default", "one", 1)
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- func() error {
+			if err := st.Create(ctx, r); err != nil {
+				return err
+			}
+
+			for value := 2; value <= 3; value++ {
+				if _, err := safe.StateUpdateWithConflicts(ctx, st, r.Metadata(), func(r *conformance.IntResource) error {
+					r.SetValue(value)
+
+					return nil
+				}); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}()
+	}()
+
+	got, err := safe.StateWatchFor(ctx, st, r.Metadata(), func(r *conformance.IntResource) (bool, error) {
+		return r.Value() == 3, nil
+	}, state.WithEventTypes(state.Created, state.Updated))
+	require.NoError(t, err)
+	require.NoError(t, <-errCh)
+
+	assert.Equal(t, 3, got.Value())
+}
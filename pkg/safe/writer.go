@@ -23,3 +23,39 @@ func WriterModify[T resource.Resource](ctx context.Context, writer controller.Wr
 		return fn(arg)
 	})
 }
+
+// WriterModifyWithResult is a type safe wrapper around writer.ModifyWithResult.
+//
+// It returns the resource as it was written to the state.
+// The writer should implement controller.ResultWriter.
+func WriterModifyWithResult[T resource.Resource](ctx context.Context, writer controller.Writer, r T, fn func(T) error) (T, error) { //nolint:ireturn
+	resultWriter, ok := writer.(controller.ResultWriter)
+	if !ok {
+		var zero T
+
+		return zero, fmt.Errorf("writer %T doesn't support ModifyWithResult", writer)
+	}
+
+	got, err := resultWriter.ModifyWithResult(ctx, r, func(r resource.Resource) error {
+		arg, ok := r.(T)
+		if !ok {
+			return fmt.Errorf("type mismatch: expected %T, got %T", arg, r)
+		}
+
+		return fn(arg)
+	})
+	if err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	result, ok := got.(T)
+	if !ok {
+		var zero T
+
+		return zero, fmt.Errorf("type mismatch: expected %T, got %T", result, got)
+	}
+
+	return result, nil
+}
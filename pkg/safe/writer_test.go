@@ -0,0 +1,43 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package safe_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/controller/controllertest"
+	"github.com/cosi-project/runtime/pkg/safe"
+)
+
+// plainWriter hides the optional interfaces of the writer.
+type plainWriter struct {
+	controller.Writer
+}
+
+func TestWriterModifyWithResult(t *testing.T) {
+	ctx := context.Background()
+
+	setValue := func(r *conformance.IntResource) error {
+		r.SetValue(1)
+
+		return nil
+	}
+
+	mock := &controllertest.MockRuntime{}
+
+	modified, err := safe.WriterModifyWithResult(ctx, mock, conformance.NewIntResource("default", "one", 0), setValue)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, modified.Value())
+
+	_, err = safe.WriterModifyWithResult(ctx, plainWriter{mock}, conformance.NewIntResource("default", "one", 0), setValue)
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package state
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+// BatchCreateItem is a single create operation of the batch.
+type BatchCreateItem struct {
+	Resource resource.Resource
+	Options  []CreateOption
+}
+
+// BatchUpdateItem is a single update operation of the batch.
+type BatchUpdateItem struct {
+	CurrentVersion resource.Version
+	Resource       resource.Resource
+	Options        []UpdateOption
+}
+
+// BatchDestroyItem is a single destroy operation of the batch.
+type BatchDestroyItem struct {
+	Pointer resource.Pointer
+	Options []DestroyOption
+}
+
+// Batcher is implemented by the states which can apply a batch of writes at once.
+//
+// Batches are applied in a single round-trip for the remote states, and atomically if the backend supports it:
+// either all operations of the batch succeed, or none of them is applied.
+type Batcher interface {
+	BatchCreate(ctx context.Context, items []BatchCreateItem) error
+	BatchUpdate(ctx context.Context, items []BatchUpdateItem) error
+	BatchDestroy(ctx context.Context, items []BatchDestroyItem) error
+}
+
+// BatchCreate creates the resources in the batch.
+//
+// If the state doesn't implement Batcher, the resources are created one by one stopping on the first error,
+// so the operations preceding the failed one stay applied.
+func BatchCreate(ctx context.Context, st CoreState, items []BatchCreateItem) error {
+	if batcher, ok := st.(Batcher); ok {
+		return batcher.BatchCreate(ctx, items)
+	}
+
+	for i, item := range items {
+		if err := st.Create(ctx, item.Resource, item.Options...); err != nil {
+			return fmt.Errorf("batch item %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// BatchUpdate updates the resources in the batch.
+//
+// If the state doesn't implement Batcher, the resources are updated one by one stopping on the first error,
+// so the operations preceding the failed one stay applied.
+func BatchUpdate(ctx context.Context, st CoreState, items []BatchUpdateItem) error {
+	if batcher, ok := st.(Batcher); ok {
+		return batcher.BatchUpdate(ctx, items)
+	}
+
+	for i, item := range items {
+		if err := st.Update(ctx, item.CurrentVersion, item.Resource, item.Options...); err != nil {
+			return fmt.Errorf("batch item %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// BatchDestroy destroys the resources in the batch.
+//
+// If the state doesn't implement Batcher, the resources are destroyed one by one stopping on the first error,
+// so the operations preceding the failed one stay applied.
+func BatchDestroy(ctx context.Context, st CoreState, items []BatchDestroyItem) error {
+	if batcher, ok := st.(Batcher); ok {
+		return batcher.BatchDestroy(ctx, items)
+	}
+
+	for i, item := range items {
+		if err := st.Destroy(ctx, item.Pointer, item.Options...); err != nil {
+			return fmt.Errorf("batch item %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
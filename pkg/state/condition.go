@@ -17,6 +17,8 @@ type WatchForCondition struct {
 	Condition ResourceConditionFunc
 	// If set, wait for resource phase to be one of the specified.
 	Phases []resource.Phase
+	// If set, wait for resource custom phase to be one of the specified.
+	CustomPhases []string
 	// If set, watch only for specified event types.
 	EventTypes []EventType
 	// If true, wait for the finalizers to empty
@@ -78,6 +80,22 @@ func (condition *WatchForCondition) Matches(event Event) (bool, error) {
 		}
 	}
 
+	if condition.CustomPhases != nil {
+		matched := false
+
+		for _, phase := range condition.CustomPhases {
+			if event.Resource.Metadata().CustomPhase() == phase {
+				matched = true
+
+				break
+			}
+		}
+
+		if !matched {
+			return false, nil
+		}
+	}
+
 	// no conditions denied the event, consider it matching
 	return true, nil
 }
@@ -120,3 +138,12 @@ func WithPhases(phases ...resource.Phase) WatchForConditionFunc {
 		return nil
 	}
 }
+
+// WithCustomPhases watches for specified resource custom phases.
+func WithCustomPhases(phases ...string) WatchForConditionFunc {
+	return func(condition *WatchForCondition) error {
+		condition.CustomPhases = append(condition.CustomPhases, phases...)
+
+		return nil
+	}
+}
@@ -0,0 +1,220 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// BenchBuilder builds the state implementation under benchmark.
+//
+// The state should be empty, resources which need cleanup should be released with b.Cleanup.
+// Backends which marshal the resources should handle BlobResource.
+type BenchBuilder func(b *testing.B) state.CoreState
+
+// BenchOptions configure Bench.
+type BenchOptions struct {
+	Namespace       resource.Namespace
+	CollectionSizes []int
+	SpecSizes       []int
+	Watchers        []int
+}
+
+// BenchOption applies settings to BenchOptions.
+type BenchOption func(options *BenchOptions)
+
+// WithBenchNamespace sets the namespace the resources are created in.
+func WithBenchNamespace(namespace resource.Namespace) BenchOption {
+	return func(options *BenchOptions) {
+		options.Namespace = namespace
+	}
+}
+
+// WithCollectionSizes sets the number of resources in the collection for the get and list benchmarks.
+func WithCollectionSizes(sizes ...int) BenchOption {
+	return func(options *BenchOptions) {
+		options.CollectionSizes = sizes
+	}
+}
+
+// WithSpecSizes sets the sizes of the resource specs in bytes.
+func WithSpecSizes(sizes ...int) BenchOption {
+	return func(options *BenchOptions) {
+		options.SpecSizes = sizes
+	}
+}
+
+// WithWatchers sets the number of the concurrent watches for the watch fan-out benchmark.
+func WithWatchers(watchers ...int) BenchOption {
+	return func(options *BenchOptions) {
+		options.Watchers = watchers
+	}
+}
+
+// DefaultBenchOptions returns default value of BenchOptions.
+func DefaultBenchOptions() BenchOptions {
+	return BenchOptions{
+		Namespace:       "default",
+		CollectionSizes: []int{10, 100, 1000},
+		SpecSizes:       []int{16, 1024, 16384},
+		Watchers:        []int{1, 10, 100},
+	}
+}
+
+// Bench measures the performance of the state implementation.
+//
+// Bench runs create, get, list and watch fan-out benchmarks with the collection and spec sizes
+// from the options, each sub-benchmark builds a fresh state, so that the results of different
+// backends (and of the same backend over time) are comparable:
+//
+//	func BenchmarkState(b *testing.B) {
+//		conformance.Bench(b, func(b *testing.B) state.CoreState {
+//			return mybackend.NewState(b.TempDir())
+//		})
+//	}
+func Bench(b *testing.B, builder BenchBuilder, opts ...BenchOption) {
+	options := DefaultBenchOptions()
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	for _, specSize := range options.SpecSizes {
+		specSize := specSize
+
+		b.Run(fmt.Sprintf("Create/spec=%d", specSize), func(b *testing.B) {
+			benchCreate(b, state.WrapCore(builder(b)), options.Namespace, specSize)
+		})
+	}
+
+	for _, items := range options.CollectionSizes {
+		for _, specSize := range options.SpecSizes {
+			items, specSize := items, specSize
+
+			b.Run(fmt.Sprintf("Get/items=%d/spec=%d", items, specSize), func(b *testing.B) {
+				benchGet(b, state.WrapCore(builder(b)), options.Namespace, items, specSize)
+			})
+
+			b.Run(fmt.Sprintf("List/items=%d/spec=%d", items, specSize), func(b *testing.B) {
+				benchList(b, state.WrapCore(builder(b)), options.Namespace, items, specSize)
+			})
+		}
+	}
+
+	for _, watchers := range options.Watchers {
+		for _, specSize := range options.SpecSizes {
+			watchers, specSize := watchers, specSize
+
+			b.Run(fmt.Sprintf("WatchFanOut/watchers=%d/spec=%d", watchers, specSize), func(b *testing.B) {
+				benchWatchFanOut(b, state.WrapCore(builder(b)), options.Namespace, watchers, specSize)
+			})
+		}
+	}
+}
+
+func benchCreate(b *testing.B, st state.State, ns resource.Namespace, specSize int) {
+	ctx := context.Background()
+	data := make([]byte, specSize)
+
+	b.SetBytes(int64(specSize))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := st.Create(ctx, NewBlobResource(ns, strconv.Itoa(i), data)); err != nil {
+			b.Fatalf("error creating resource: %s", err)
+		}
+	}
+}
+
+func benchGet(b *testing.B, st state.State, ns resource.Namespace, items, specSize int) {
+	ctx := context.Background()
+
+	fill(b, st, ns, items, specSize)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := st.Get(ctx, resource.NewMetadata(ns, BlobResourceType, strconv.Itoa(i%items), resource.VersionUndefined)); err != nil {
+			b.Fatalf("error getting resource: %s", err)
+		}
+	}
+}
+
+func benchList(b *testing.B, st state.State, ns resource.Namespace, items, specSize int) {
+	ctx := context.Background()
+
+	fill(b, st, ns, items, specSize)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		list, err := st.List(ctx, resource.NewMetadata(ns, BlobResourceType, "", resource.VersionUndefined))
+		if err != nil {
+			b.Fatalf("error listing resources: %s", err)
+		}
+
+		if len(list.Items) != items {
+			b.Fatalf("unexpected number of resources: %d != %d", len(list.Items), items)
+		}
+	}
+}
+
+func benchWatchFanOut(b *testing.B, st state.State, ns resource.Namespace, watchers, specSize int) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	chs := make([]chan state.Event, watchers)
+
+	for i := range chs {
+		chs[i] = make(chan state.Event)
+
+		if err := st.WatchKind(ctx, resource.NewMetadata(ns, BlobResourceType, "", resource.VersionUndefined), chs[i]); err != nil {
+			b.Fatalf("error watching resources: %s", err)
+		}
+	}
+
+	data := make([]byte, specSize)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := st.Create(ctx, NewBlobResource(ns, strconv.Itoa(i), data)); err != nil {
+			b.Fatalf("error creating resource: %s", err)
+		}
+
+		// the create is complete once every watcher received the event
+		for _, ch := range chs {
+			event := <-ch
+
+			if event.Type != state.Created {
+				b.Fatalf("unexpected event: %s", event.Type)
+			}
+		}
+	}
+}
+
+// fill creates the collection of the resources to be read by the benchmark.
+func fill(b *testing.B, st state.State, ns resource.Namespace, items, specSize int) {
+	b.Helper()
+
+	ctx := context.Background()
+	data := make([]byte, specSize)
+
+	for i := 0; i < items; i++ {
+		if err := st.Create(ctx, NewBlobResource(ns, strconv.Itoa(i), data)); err != nil {
+			b.Fatalf("error creating resource: %s", err)
+		}
+	}
+}
@@ -3,4 +3,6 @@
 // file, You can obtain one at http://mozilla.org/MPL/2.0/.
 
 // Package conformance implements tests which verify conformance of the implementation with the spec.
+//
+// State implementations should be verified with Run, see StateSuite for the test cases.
 package conformance
@@ -7,6 +7,8 @@ package conformance
 import (
 	"fmt"
 
+	"google.golang.org/protobuf/encoding/protowire"
+
 	"github.com/cosi-project/runtime/pkg/resource"
 )
 
@@ -63,3 +65,168 @@ func (path *PathResource) UnmarshalProto(md *resource.Metadata, protoSpec []byte
 
 	return nil
 }
+
+// BlobResourceType is the type of BlobResource.
+const BlobResourceType = resource.Type("test/blob")
+
+// BlobResource carries an opaque payload of arbitrary size.
+//
+// BlobResource is used to measure the performance of the state with different resource sizes.
+type BlobResource struct {
+	md   resource.Metadata
+	data []byte
+}
+
+type blobSpec []byte
+
+func (spec blobSpec) MarshalProto() ([]byte, error) {
+	return spec, nil
+}
+
+// NewBlobResource creates new BlobResource.
+func NewBlobResource(ns resource.Namespace, id resource.ID, data []byte) *BlobResource {
+	r := &BlobResource{
+		md:   resource.NewMetadata(ns, BlobResourceType, id, resource.VersionUndefined),
+		data: data,
+	}
+	r.md.BumpVersion()
+
+	return r
+}
+
+// Metadata implements resource.Resource.
+func (blob *BlobResource) Metadata() *resource.Metadata {
+	return &blob.md
+}
+
+// Spec implements resource.Resource.
+func (blob *BlobResource) Spec() interface{} {
+	return blobSpec(blob.data)
+}
+
+// DeepCopy implements resource.Resource.
+func (blob *BlobResource) DeepCopy() resource.Resource { //nolint:ireturn
+	return &BlobResource{
+		md:   blob.md,
+		data: append([]byte(nil), blob.data...),
+	}
+}
+
+// UnmarshalProto implements protobuf.ResourceUnmarshaler.
+func (blob *BlobResource) UnmarshalProto(md *resource.Metadata, protoSpec []byte) error {
+	blob.md = *md
+	blob.data = append([]byte(nil), protoSpec...)
+
+	return nil
+}
+
+// ReplicaResourceType is the type of ReplicaResource.
+const ReplicaResourceType = resource.Type("test/replica")
+
+// ReplicaResource has the desired number of replicas in the spec, and the number of ready replicas in the status.
+//
+// ReplicaResource implements resource.StatusResource.
+type ReplicaResource struct {
+	md   resource.Metadata
+	spec ReplicaSpec
+}
+
+// ReplicaSpec is the spec of ReplicaResource, Ready is the status portion of it.
+type ReplicaSpec struct {
+	Replicas int `yaml:"replicas"`
+	Ready    int `yaml:"ready"`
+}
+
+// MarshalProto implements protobuf.ProtoMarshaler.
+func (spec ReplicaSpec) MarshalProto() ([]byte, error) {
+	var data []byte
+
+	data = protowire.AppendTag(data, 1, protowire.VarintType)
+	data = protowire.AppendVarint(data, uint64(spec.Replicas))
+	data = protowire.AppendTag(data, 2, protowire.VarintType)
+	data = protowire.AppendVarint(data, uint64(spec.Ready))
+
+	return data, nil
+}
+
+// NewReplicaResource creates new ReplicaResource.
+func NewReplicaResource(ns resource.Namespace, id resource.ID, replicas int) *ReplicaResource {
+	r := &ReplicaResource{
+		md:   resource.NewMetadata(ns, ReplicaResourceType, id, resource.VersionUndefined),
+		spec: ReplicaSpec{Replicas: replicas},
+	}
+	r.md.BumpVersion()
+
+	return r
+}
+
+// Metadata implements resource.Resource.
+func (replica *ReplicaResource) Metadata() *resource.Metadata {
+	return &replica.md
+}
+
+// Spec implements resource.Resource.
+func (replica *ReplicaResource) Spec() interface{} {
+	return replica.spec
+}
+
+// TypedSpec returns the spec of the resource.
+func (replica *ReplicaResource) TypedSpec() *ReplicaSpec {
+	return &replica.spec
+}
+
+// DeepCopy implements resource.Resource.
+func (replica *ReplicaResource) DeepCopy() resource.Resource { //nolint:ireturn
+	return &ReplicaResource{
+		md:   replica.md,
+		spec: replica.spec,
+	}
+}
+
+// Status implements resource.StatusResource.
+func (replica *ReplicaResource) Status() interface{} {
+	return replica.spec.Ready
+}
+
+// SetStatus implements resource.StatusResource.
+func (replica *ReplicaResource) SetStatus(status interface{}) error {
+	ready, ok := status.(int)
+	if !ok {
+		return fmt.Errorf("unexpected status %T", status)
+	}
+
+	replica.spec.Ready = ready
+
+	return nil
+}
+
+// UnmarshalProto implements protobuf.ResourceUnmarshaler.
+func (replica *ReplicaResource) UnmarshalProto(md *resource.Metadata, protoSpec []byte) error {
+	replica.md = *md
+	replica.spec = ReplicaSpec{}
+
+	for len(protoSpec) > 0 {
+		num, typ, n := protowire.ConsumeTag(protoSpec)
+		if n < 0 || typ != protowire.VarintType {
+			return fmt.Errorf("unexpected replica spec encoding")
+		}
+
+		protoSpec = protoSpec[n:]
+
+		value, n := protowire.ConsumeVarint(protoSpec)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+
+		protoSpec = protoSpec[n:]
+
+		switch num {
+		case 1:
+			replica.spec.Replicas = int(value)
+		case 2:
+			replica.spec.Ready = int(value)
+		}
+	}
+
+	return nil
+}
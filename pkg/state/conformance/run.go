@@ -0,0 +1,66 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package conformance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// StateBuilder builds the state implementation under test.
+//
+// The state should be empty, resources which need cleanup (e.g. database files) should be released with t.Cleanup.
+type StateBuilder func(t *testing.T) state.CoreState
+
+// RunOptions configure Run.
+type RunOptions struct {
+	Namespaces []resource.Namespace
+}
+
+// RunOption applies settings to RunOptions.
+type RunOption func(options *RunOptions)
+
+// WithNamespaces sets the namespaces the resources are created in.
+//
+// Default value is "default", "controller", "system", "runtime".
+func WithNamespaces(namespaces ...resource.Namespace) RunOption {
+	return func(options *RunOptions) {
+		options.Namespaces = namespaces
+	}
+}
+
+// DefaultRunOptions returns default value of RunOptions.
+func DefaultRunOptions() RunOptions {
+	return RunOptions{
+		Namespaces: []resource.Namespace{"default", "controller", "system", "runtime"},
+	}
+}
+
+// Run verifies that the state implementation conforms to the state.CoreState semantics.
+//
+// Run covers create, update, destroy, watches, finalizers and teardown, so that the authors of
+// new backends can verify compliance with a single call from their tests:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run(t, func(t *testing.T) state.CoreState {
+//			return mybackend.NewState(t.TempDir())
+//		})
+//	}
+func Run(t *testing.T, builder StateBuilder, opts ...RunOption) {
+	options := DefaultRunOptions()
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	suite.Run(t, &StateSuite{
+		State:      state.WrapCore(builder(t)),
+		Namespaces: options.Namespaces,
+	})
+}
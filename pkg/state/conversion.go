@@ -0,0 +1,324 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package state
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+// ConvertFunc converts the resource to another version of the resource type.
+//
+// The function should convert the spec, the metadata of the converted resource
+// is set by the conversion state, so that only the resource type changes.
+type ConvertFunc func(resource.Resource) (resource.Resource, error)
+
+type typeVersion struct {
+	toCanonical   ConvertFunc
+	fromCanonical ConvertFunc
+	canonical     resource.Type
+}
+
+// ConversionRegistry holds versions of resource types with conversion functions to the canonical version.
+//
+// ConversionRegistry is safe for concurrent use.
+type ConversionRegistry struct {
+	versions map[resource.Type]typeVersion
+	mu       sync.RWMutex
+}
+
+// NewConversionRegistry creates new ConversionRegistry.
+func NewConversionRegistry() *ConversionRegistry {
+	return &ConversionRegistry{
+		versions: map[resource.Type]typeVersion{},
+	}
+}
+
+// Register a version of the canonical resource type, e.g. `Machines.example.org/v1alpha1` of `Machines.example.org`.
+//
+// Resources of the version are stored as resources of the canonical type.
+func (registry *ConversionRegistry) Register(versionType, canonicalType resource.Type, toCanonical, fromCanonical ConvertFunc) error {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if versionType == canonicalType {
+		return fmt.Errorf("version type %q should be different from the canonical type", versionType)
+	}
+
+	if _, ok := registry.versions[versionType]; ok {
+		return fmt.Errorf("version type %q is already registered", versionType)
+	}
+
+	if _, ok := registry.versions[canonicalType]; ok {
+		return fmt.Errorf("canonical type %q is registered as a version of another type", canonicalType)
+	}
+
+	for typ, version := range registry.versions {
+		if version.canonical == versionType {
+			return fmt.Errorf("version type %q is the canonical type of %q", versionType, typ)
+		}
+	}
+
+	registry.versions[versionType] = typeVersion{
+		canonical:     canonicalType,
+		toCanonical:   toCanonical,
+		fromCanonical: fromCanonical,
+	}
+
+	return nil
+}
+
+func (registry *ConversionRegistry) lookup(typ resource.Type) (typeVersion, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	version, ok := registry.versions[typ]
+
+	return version, ok
+}
+
+// Convert wraps the state to serve the registered versions of resource types.
+//
+// Requests for a version type are served by converting the resources of the canonical type, so that
+// only the canonical version is stored in the underlying state.
+// Watch events which fail conversion are skipped.
+func Convert(coreState CoreState, registry *ConversionRegistry) CoreState { //nolint:ireturn
+	return &conversionState{
+		state:    coreState,
+		registry: registry,
+	}
+}
+
+type conversionState struct {
+	state    CoreState
+	registry *ConversionRegistry
+}
+
+func convert(r resource.Resource, typ resource.Type, fn ConvertFunc) (resource.Resource, error) { //nolint:ireturn
+	if resource.IsTombstone(r) {
+		return resource.NewTombstone(r.Metadata().WithType(typ)), nil
+	}
+
+	converted, err := fn(r)
+	if err != nil {
+		return nil, fmt.Errorf("error converting %s to %q: %w", r.Metadata(), typ, err)
+	}
+
+	*converted.Metadata() = r.Metadata().WithType(typ)
+
+	return converted, nil
+}
+
+func canonicalPointer(ptr resource.Pointer, version typeVersion) resource.Metadata {
+	return resource.NewMetadata(ptr.Namespace(), version.canonical, ptr.ID(), resource.VersionUndefined)
+}
+
+// Get a resource by type and ID.
+//
+// If a resource is not found, error is returned.
+func (st *conversionState) Get(ctx context.Context, resourcePointer resource.Pointer, opts ...GetOption) (resource.Resource, error) { //nolint:ireturn
+	version, ok := st.registry.lookup(resourcePointer.Type())
+	if !ok {
+		return st.state.Get(ctx, resourcePointer, opts...)
+	}
+
+	r, err := st.state.Get(ctx, canonicalPointer(resourcePointer, version), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(r, resourcePointer.Type(), version.fromCanonical)
+}
+
+// List resources by type.
+func (st *conversionState) List(ctx context.Context, resourceKind resource.Kind, opts ...ListOption) (resource.List, error) {
+	version, ok := st.registry.lookup(resourceKind.Type())
+	if !ok {
+		return st.state.List(ctx, resourceKind, opts...)
+	}
+
+	list, err := st.state.List(ctx, resource.NewMetadata(resourceKind.Namespace(), version.canonical, "", resource.VersionUndefined), opts...)
+	if err != nil {
+		return resource.List{}, err
+	}
+
+	for i, r := range list.Items {
+		if list.Items[i], err = convert(r, resourceKind.Type(), version.fromCanonical); err != nil {
+			return resource.List{}, err
+		}
+	}
+
+	return list, nil
+}
+
+// Create a resource.
+//
+// If a resource already exists, Create returns an error.
+func (st *conversionState) Create(ctx context.Context, res resource.Resource, opts ...CreateOption) error {
+	version, ok := st.registry.lookup(res.Metadata().Type())
+	if !ok {
+		return st.state.Create(ctx, res, opts...)
+	}
+
+	converted, err := convert(res, version.canonical, version.toCanonical)
+	if err != nil {
+		return err
+	}
+
+	return st.state.Create(ctx, converted, opts...)
+}
+
+// Update a resource.
+//
+// If a resource doesn't exist, error is returned.
+// On update current version of resource `new` in the state should match
+// curVersion, otherwise conflict error is returned.
+func (st *conversionState) Update(ctx context.Context, curVersion resource.Version, newResource resource.Resource, opts ...UpdateOption) error {
+	version, ok := st.registry.lookup(newResource.Metadata().Type())
+	if !ok {
+		return st.state.Update(ctx, curVersion, newResource, opts...)
+	}
+
+	converted, err := convert(newResource, version.canonical, version.toCanonical)
+	if err != nil {
+		return err
+	}
+
+	return st.state.Update(ctx, curVersion, converted, opts...)
+}
+
+// Destroy a resource.
+//
+// If a resource doesn't exist, error is returned.
+// If a resource has pending finalizers, error is returned.
+func (st *conversionState) Destroy(ctx context.Context, resourcePointer resource.Pointer, opts ...DestroyOption) error {
+	if version, ok := st.registry.lookup(resourcePointer.Type()); ok {
+		resourcePointer = canonicalPointer(resourcePointer, version)
+	}
+
+	return st.state.Destroy(ctx, resourcePointer, opts...)
+}
+
+// UpdateStatus implements StatusUpdater interface.
+//
+// The canonical version of the resource type should have a status as well.
+func (st *conversionState) UpdateStatus(ctx context.Context, curVersion resource.Version, newResource resource.StatusResource, opts ...UpdateOption) error {
+	version, ok := st.registry.lookup(newResource.Metadata().Type())
+	if !ok {
+		return UpdateStatus(ctx, st.state, curVersion, newResource, opts...)
+	}
+
+	converted, err := convert(newResource, version.canonical, version.toCanonical)
+	if err != nil {
+		return err
+	}
+
+	statusResource, ok := converted.(resource.StatusResource)
+	if !ok {
+		return fmt.Errorf("resource %s doesn't have a status", converted.Metadata())
+	}
+
+	return UpdateStatus(ctx, st.state, curVersion, statusResource, opts...)
+}
+
+// ListStream implements ListStreamer interface.
+//
+// Streaming stops on the first resource which fails conversion.
+func (st *conversionState) ListStream(ctx context.Context, resourceKind resource.Kind, yield func(resource.Resource) bool, opts ...ListOption) error {
+	version, ok := st.registry.lookup(resourceKind.Type())
+	if !ok {
+		return ListStream(ctx, st.state, resourceKind, yield, opts...)
+	}
+
+	var convertErr error
+
+	err := ListStream(ctx, st.state, resource.NewMetadata(resourceKind.Namespace(), version.canonical, "", resource.VersionUndefined), func(r resource.Resource) bool {
+		r, convertErr = convert(r, resourceKind.Type(), version.fromCanonical)
+		if convertErr != nil {
+			return false
+		}
+
+		return yield(r)
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	return convertErr
+}
+
+// Watch state of a resource by type.
+//
+// It's fine to watch for a resource which doesn't exist yet.
+// Watch is canceled when context gets canceled.
+// Watch sends initial resource state as the very first event on the channel,
+// and then sends any updates to the resource as events.
+func (st *conversionState) Watch(ctx context.Context, resourcePointer resource.Pointer, ch chan<- Event, opts ...WatchOption) error {
+	version, ok := st.registry.lookup(resourcePointer.Type())
+	if !ok {
+		return st.state.Watch(ctx, resourcePointer, ch, opts...)
+	}
+
+	canonicalCh := make(chan Event)
+
+	if err := st.state.Watch(ctx, canonicalPointer(resourcePointer, version), canonicalCh, opts...); err != nil {
+		return err
+	}
+
+	go convertEvents(ctx, canonicalCh, ch, resourcePointer.Type(), version)
+
+	return nil
+}
+
+// WatchKind watches resources of specific kind (namespace and type).
+func (st *conversionState) WatchKind(ctx context.Context, resourceKind resource.Kind, ch chan<- Event, opts ...WatchKindOption) error {
+	version, ok := st.registry.lookup(resourceKind.Type())
+	if !ok {
+		return st.state.WatchKind(ctx, resourceKind, ch, opts...)
+	}
+
+	canonicalCh := make(chan Event)
+
+	if err := st.state.WatchKind(ctx, resource.NewMetadata(resourceKind.Namespace(), version.canonical, "", resource.VersionUndefined), canonicalCh, opts...); err != nil {
+		return err
+	}
+
+	go convertEvents(ctx, canonicalCh, ch, resourceKind.Type(), version)
+
+	return nil
+}
+
+func convertEvents(ctx context.Context, in <-chan Event, out chan<- Event, typ resource.Type, version typeVersion) {
+	for {
+		var event Event
+
+		select {
+		case <-ctx.Done():
+			return
+		case event = <-in:
+		}
+
+		var err error
+
+		if event.Resource, err = convert(event.Resource, typ, version.fromCanonical); err != nil {
+			continue
+		}
+
+		if event.Old != nil {
+			if event.Old, err = convert(event.Old, typ, version.fromCanonical); err != nil {
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case out <- event:
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package state_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	ctrlconformance "github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/conformance"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+// intStrType is the version of ctrlconformance.IntResourceType which represents the value as a string.
+const intStrType = resource.Type("test/int/str")
+
+func newConversionRegistry(t *testing.T) *state.ConversionRegistry {
+	registry := state.NewConversionRegistry()
+
+	require.NoError(t, registry.Register(intStrType, ctrlconformance.IntResourceType,
+		func(r resource.Resource) (resource.Resource, error) {
+			value, err := strconv.Atoi(r.(*ctrlconformance.StrResource).Value()) //nolint:forcetypeassert
+			if err != nil {
+				return nil, err
+			}
+
+			return ctrlconformance.NewIntResource(r.Metadata().Namespace(), r.Metadata().ID(), value), nil
+		},
+		func(r resource.Resource) (resource.Resource, error) {
+			return ctrlconformance.NewStrResource(r.Metadata().Namespace(), r.Metadata().ID(), strconv.Itoa(r.(*ctrlconformance.IntResource).Value())), nil //nolint:forcetypeassert
+		},
+	))
+
+	return registry
+}
+
+func newIntStr(ns resource.Namespace, id resource.ID, value string) *ctrlconformance.StrResource {
+	r := ctrlconformance.NewStrResource(ns, id, value)
+	*r.Metadata() = r.Metadata().WithType(intStrType)
+
+	return r
+}
+
+func TestConvertPasshtroughConformance(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &conformance.StateSuite{
+		State:      state.WrapCore(state.Convert(namespaced.NewState(inmem.Build), newConversionRegistry(t))),
+		Namespaces: []resource.Namespace{"default", "controller", "system", "runtime"},
+	})
+}
+
+func TestConvertRegister(t *testing.T) {
+	t.Parallel()
+
+	registry := newConversionRegistry(t)
+
+	assert.Error(t, registry.Register(intStrType, ctrlconformance.IntResourceType, nil, nil))
+	assert.Error(t, registry.Register("test/other", intStrType, nil, nil))
+	assert.Error(t, registry.Register(ctrlconformance.IntResourceType, "test/other", nil, nil))
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	canonical := state.WrapCore(namespaced.NewState(inmem.Build))
+	st := state.WrapCore(state.Convert(canonical, newConversionRegistry(t)))
+
+	ch := make(chan state.Event)
+
+	require.NoError(t, st.WatchKind(ctx, resource.NewMetadata("default", intStrType, "", resource.VersionUndefined), ch))
+
+	// written as a version, stored as canonical
+	require.NoError(t, st.Create(ctx, newIntStr("default", "one", "1")))
+
+	stored, err := canonical.Get(ctx, ctrlconformance.NewIntResource("default", "one", 0).Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored.(*ctrlconformance.IntResource).Value()) //nolint:forcetypeassert
+
+	select {
+	case event := <-ch:
+		assert.Equal(t, state.Created, event.Type)
+		assert.Equal(t, intStrType, event.Resource.Metadata().Type())
+		assert.Equal(t, "1", event.Resource.(*ctrlconformance.StrResource).Value()) //nolint:forcetypeassert
+	case <-ctx.Done():
+		require.FailNow(t, "timeout")
+	}
+
+	// written as canonical, read as a version
+	require.NoError(t, canonical.Create(ctx, ctrlconformance.NewIntResource("default", "two", 2)))
+
+	got, err := st.Get(ctx, newIntStr("default", "two", "").Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, intStrType, got.Metadata().Type())
+	assert.Equal(t, "2", got.(*ctrlconformance.StrResource).Value()) //nolint:forcetypeassert
+
+	_, err = st.UpdateWithConflicts(ctx, got.Metadata(), func(r resource.Resource) error {
+		r.(*ctrlconformance.StrResource).SetValue("3") //nolint:forcetypeassert
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	list, err := st.List(ctx, resource.NewMetadata("default", intStrType, "", resource.VersionUndefined))
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+	assert.Equal(t, "1", list.Items[0].(*ctrlconformance.StrResource).Value()) //nolint:forcetypeassert
+	assert.Equal(t, "3", list.Items[1].(*ctrlconformance.StrResource).Value()) //nolint:forcetypeassert
+
+	assert.Error(t, st.Create(ctx, newIntStr("default", "invalid", "not a number")))
+
+	require.NoError(t, st.Destroy(ctx, newIntStr("default", "one", "").Metadata()))
+
+	_, err = canonical.Get(ctx, ctrlconformance.NewIntResource("default", "one", 0).Metadata())
+	assert.True(t, state.IsNotFoundError(err))
+}
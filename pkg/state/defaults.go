@@ -0,0 +1,96 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package state
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+// Defaulter fills unset spec fields of the resource in place.
+type Defaulter func(resource.Resource) error
+
+// DefaulterRegistry holds defaulters attached to resource types.
+//
+// DefaulterRegistry is safe for concurrent use.
+type DefaulterRegistry struct {
+	defaulters map[resource.Type][]Defaulter
+	mu         sync.RWMutex
+}
+
+// NewDefaulterRegistry creates new DefaulterRegistry.
+func NewDefaulterRegistry() *DefaulterRegistry {
+	return &DefaulterRegistry{
+		defaulters: map[resource.Type][]Defaulter{},
+	}
+}
+
+// Register attaches the defaulter to the resource type.
+//
+// Several defaulters can be attached to the same type, they are applied in the order of registration.
+func (registry *DefaulterRegistry) Register(resourceType resource.Type, defaulter Defaulter) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.defaulters[resourceType] = append(registry.defaulters[resourceType], defaulter)
+}
+
+// Default applies the defaulters of the resource type to the resource in place.
+func (registry *DefaulterRegistry) Default(r resource.Resource) error {
+	registry.mu.RLock()
+	defaulters := registry.defaulters[r.Metadata().Type()]
+	registry.mu.RUnlock()
+
+	for _, defaulter := range defaulters {
+		if err := defaulter(r); err != nil {
+			return fmt.Errorf("error applying defaults to %s: %w", r.Metadata(), err)
+		}
+	}
+
+	return nil
+}
+
+// Default wraps the state to apply defaults to resources on Create.
+//
+// Defaults are applied to a copy of the resource, so the resource passed to Create is not modified.
+// Wrap the state with Validate first to validate the resources with the defaults applied.
+func Default(coreState CoreState, registry *DefaulterRegistry) CoreState { //nolint:ireturn
+	return &defaultingState{
+		CoreState: coreState,
+		registry:  registry,
+	}
+}
+
+type defaultingState struct {
+	CoreState
+
+	registry *DefaulterRegistry
+}
+
+// Create a resource.
+//
+// Defaults are applied before the resource is persisted.
+func (st *defaultingState) Create(ctx context.Context, res resource.Resource, opts ...CreateOption) error {
+	res = res.DeepCopy()
+
+	if err := st.registry.Default(res); err != nil {
+		return err
+	}
+
+	return st.CoreState.Create(ctx, res, opts...)
+}
+
+// UpdateStatus implements StatusUpdater interface.
+func (st *defaultingState) UpdateStatus(ctx context.Context, curVersion resource.Version, newResource resource.StatusResource, opts ...UpdateOption) error {
+	return UpdateStatus(ctx, st.CoreState, curVersion, newResource, opts...)
+}
+
+// ListStream implements ListStreamer interface.
+func (st *defaultingState) ListStream(ctx context.Context, kind resource.Kind, yield func(resource.Resource) bool, opts ...ListOption) error {
+	return ListStream(ctx, st.CoreState, kind, yield, opts...)
+}
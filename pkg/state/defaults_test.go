@@ -0,0 +1,61 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package state_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ctrlconformance "github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestDefault(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	defaulters := state.NewDefaulterRegistry()
+	defaulters.Register(ctrlconformance.IntResourceType, func(r resource.Resource) error {
+		if r.(*ctrlconformance.IntResource).Value() == 0 { //nolint:forcetypeassert
+			r.(*ctrlconformance.IntResource).SetValue(42) //nolint:forcetypeassert
+		}
+
+		return nil
+	})
+
+	validators := state.NewValidatorRegistry()
+	validators.Register(ctrlconformance.IntResourceType, func(r resource.Resource) []state.FieldError {
+		if r.(*ctrlconformance.IntResource).Value() == 0 { //nolint:forcetypeassert
+			return []state.FieldError{{Field: "value", Message: "should be set"}}
+		}
+
+		return nil
+	})
+
+	st := state.WrapCore(state.Default(state.Validate(namespaced.NewState(inmem.Build), validators), defaulters))
+
+	r := ctrlconformance.NewIntResource("default", "unset", 0)
+	require.NoError(t, st.Create(ctx, r))
+
+	// the original resource is not modified
+	assert.Equal(t, 0, r.Value())
+
+	got, err := st.Get(ctx, r.Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, 42, got.(*ctrlconformance.IntResource).Value()) //nolint:forcetypeassert
+
+	require.NoError(t, st.Create(ctx, ctrlconformance.NewIntResource("default", "set", 1)))
+
+	got, err = st.Get(ctx, ctrlconformance.NewIntResource("default", "set", 0).Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.(*ctrlconformance.IntResource).Value()) //nolint:forcetypeassert
+}
@@ -79,3 +79,15 @@ func errPhaseConflict(r resource.Reference, expectedPhase resource.Phase) error
 		},
 	}
 }
+
+// ErrValidation should be implemented by resource validation errors.
+type ErrValidation interface {
+	ValidationError()
+}
+
+// IsValidationError checks if err is resource validation error.
+func IsValidationError(err error) bool {
+	var i ErrValidation
+
+	return errors.As(err, &i)
+}
@@ -143,6 +143,35 @@ func (filter *stateFilter) Destroy(ctx context.Context, resourcePointer resource
 	return filter.state.Destroy(ctx, resourcePointer, opts...)
 }
 
+// UpdateStatus implements StatusUpdater interface.
+func (filter *stateFilter) UpdateStatus(ctx context.Context, curVersion resource.Version, newResource resource.StatusResource, opts ...UpdateOption) error {
+	if err := filter.rule(ctx, Access{
+		ResourceNamespace: newResource.Metadata().Namespace(),
+		ResourceType:      newResource.Metadata().Type(),
+		ResourceID:        newResource.Metadata().ID(),
+
+		Verb: Update,
+	}); err != nil {
+		return err
+	}
+
+	return UpdateStatus(ctx, filter.state, curVersion, newResource, opts...)
+}
+
+// ListStream implements ListStreamer interface.
+func (filter *stateFilter) ListStream(ctx context.Context, resourceKind resource.Kind, yield func(resource.Resource) bool, opts ...ListOption) error {
+	if err := filter.rule(ctx, Access{
+		ResourceNamespace: resourceKind.Namespace(),
+		ResourceType:      resourceKind.Type(),
+
+		Verb: List,
+	}); err != nil {
+		return err
+	}
+
+	return ListStream(ctx, filter.state, resourceKind, yield, opts...)
+}
+
 // Watch state of a resource by type.
 //
 // It's fine to watch for a resource which doesn't exist yet.
@@ -0,0 +1,379 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"strings"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/registry"
+)
+
+// Gateway implements http.Handler exposing the state as JSON REST endpoints.
+//
+// Resources are encoded with resource.MarshalJSON, the specs of sensitive resources are redacted.
+// Resources can be created and updated only if the resource type is registered with protobuf.RegisterResource
+// and the spec can be decoded from JSON.
+type Gateway struct {
+	state    state.CoreState
+	registry *registry.ResourceRegistry
+}
+
+// NewGateway creates new Gateway.
+func NewGateway(st state.CoreState) *Gateway {
+	return &Gateway{
+		state:    st,
+		registry: registry.NewResourceRegistry(state.WrapCore(st)),
+	}
+}
+
+// request is a parsed request to the gateway.
+type request struct {
+	namespace  resource.Namespace
+	id         resource.ID
+	definition meta.ResourceDefinitionSpec
+}
+
+// ServeHTTP implements http.Handler.
+func (gateway *Gateway) ServeHTTP(w nethttp.ResponseWriter, req *nethttp.Request) {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+
+	if len(segments) < 3 || len(segments) > 4 || segments[0] != "namespaces" {
+		writeError(w, nethttp.StatusNotFound, fmt.Errorf("path %q not found", req.URL.Path))
+
+		return
+	}
+
+	definitions, err := gateway.registry.Resolve(req.Context(), segments[2])
+	if err != nil {
+		writeError(w, nethttp.StatusNotFound, err)
+
+		return
+	}
+
+	if len(definitions) != 1 {
+		writeError(w, nethttp.StatusBadRequest, fmt.Errorf("%q refers to multiple resource types", segments[2]))
+
+		return
+	}
+
+	r := request{
+		namespace:  segments[1],
+		definition: definitions[0],
+	}
+
+	if len(segments) == 4 {
+		r.id = segments[3]
+	}
+
+	switch {
+	case req.Method == nethttp.MethodGet && r.id == "" && req.URL.Query().Get("watch") == "true":
+		gateway.watchKind(w, req, r)
+	case req.Method == nethttp.MethodGet && r.id == "":
+		gateway.list(w, req, r)
+	case req.Method == nethttp.MethodGet && req.URL.Query().Get("watch") == "true":
+		gateway.watch(w, req, r)
+	case req.Method == nethttp.MethodGet:
+		gateway.get(w, req, r)
+	case req.Method == nethttp.MethodPost && r.id == "":
+		gateway.create(w, req, r)
+	case req.Method == nethttp.MethodPut && r.id != "":
+		gateway.update(w, req, r)
+	case req.Method == nethttp.MethodDelete && r.id != "":
+		gateway.destroy(w, req, r)
+	default:
+		writeError(w, nethttp.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", req.Method))
+	}
+}
+
+func (gateway *Gateway) get(w nethttp.ResponseWriter, req *nethttp.Request, r request) {
+	res, err := gateway.state.Get(req.Context(), resource.NewMetadata(r.namespace, r.definition.Type, r.id, resource.VersionUndefined))
+	if err != nil {
+		writeStateError(w, err)
+
+		return
+	}
+
+	writeResource(w, nethttp.StatusOK, r.definition, res)
+}
+
+func (gateway *Gateway) list(w nethttp.ResponseWriter, req *nethttp.Request, r request) {
+	labelQuery, err := parseLabelQuery(req)
+	if err != nil {
+		writeError(w, nethttp.StatusBadRequest, err)
+
+		return
+	}
+
+	list, err := gateway.state.List(req.Context(), resource.NewMetadata(r.namespace, r.definition.Type, "", resource.VersionUndefined),
+		state.WithLabelQuery(labelQuery...))
+	if err != nil {
+		writeStateError(w, err)
+
+		return
+	}
+
+	items := make([]json.RawMessage, 0, len(list.Items))
+
+	for _, res := range list.Items {
+		encoded, err := encodeResource(r.definition, res)
+		if err != nil {
+			writeError(w, nethttp.StatusInternalServerError, err)
+
+			return
+		}
+
+		items = append(items, encoded)
+	}
+
+	writeJSON(w, nethttp.StatusOK, struct {
+		Items []json.RawMessage `json:"items"`
+	}{
+		Items: items,
+	})
+}
+
+func (gateway *Gateway) create(w nethttp.ResponseWriter, req *nethttp.Request, r request) {
+	res, owner, err := decodeResource(req.Body, r)
+	if err != nil {
+		writeError(w, nethttp.StatusBadRequest, err)
+
+		return
+	}
+
+	// versions start from 1, whatever the version in the request is
+	res.Metadata().SetVersion(resource.VersionUndefined)
+	res.Metadata().BumpVersion()
+
+	if err = gateway.state.Create(req.Context(), res, state.WithCreateOwner(owner)); err != nil {
+		writeStateError(w, err)
+
+		return
+	}
+
+	gateway.writeCurrent(req.Context(), w, nethttp.StatusCreated, r.definition, res.Metadata())
+}
+
+func (gateway *Gateway) update(w nethttp.ResponseWriter, req *nethttp.Request, r request) {
+	res, owner, err := decodeResource(req.Body, r)
+	if err != nil {
+		writeError(w, nethttp.StatusBadRequest, err)
+
+		return
+	}
+
+	curVersion := res.Metadata().Version()
+	if curVersion.Equal(resource.VersionUndefined) {
+		writeError(w, nethttp.StatusBadRequest, fmt.Errorf("current version of the resource should be set"))
+
+		return
+	}
+
+	res.Metadata().BumpVersion()
+
+	if err = gateway.state.Update(req.Context(), curVersion, res, state.WithUpdateOwner(owner)); err != nil {
+		writeStateError(w, err)
+
+		return
+	}
+
+	gateway.writeCurrent(req.Context(), w, nethttp.StatusOK, r.definition, res.Metadata())
+}
+
+func (gateway *Gateway) destroy(w nethttp.ResponseWriter, req *nethttp.Request, r request) {
+	if err := gateway.state.Destroy(req.Context(), resource.NewMetadata(r.namespace, r.definition.Type, r.id, resource.VersionUndefined),
+		state.WithDestroyOwner(req.URL.Query().Get("owner"))); err != nil {
+		writeStateError(w, err)
+
+		return
+	}
+
+	w.WriteHeader(nethttp.StatusNoContent)
+}
+
+// writeCurrent writes the resource as it is stored in the state after the write.
+func (gateway *Gateway) writeCurrent(ctx context.Context, w nethttp.ResponseWriter, status int, definition meta.ResourceDefinitionSpec, ptr resource.Pointer) {
+	res, err := gateway.state.Get(ctx, ptr)
+	if err != nil {
+		writeStateError(w, err)
+
+		return
+	}
+
+	writeResource(w, status, definition, res)
+}
+
+// decodeResource decodes the resource from the JSON definition.
+//
+// Returned resource carries the version from the request body, and the owner of the resource is returned separately.
+func decodeResource(body io.Reader, r request) (resource.Resource, string, error) {
+	var definition struct {
+		Metadata struct {
+			Labels     map[string]string `json:"labels"`
+			ID         string            `json:"id"`
+			Version    string            `json:"version"`
+			Owner      string            `json:"owner"`
+			Phase      string            `json:"phase"`
+			Finalizers []string          `json:"finalizers"`
+		} `json:"metadata"`
+		Spec json.RawMessage `json:"spec"`
+	}
+
+	if err := json.NewDecoder(body).Decode(&definition); err != nil {
+		return nil, "", fmt.Errorf("error decoding request: %w", err)
+	}
+
+	id := definition.Metadata.ID
+
+	switch {
+	case r.id == "" && id == "":
+		return nil, "", fmt.Errorf("resource ID should be set")
+	case r.id != "" && id != "" && id != r.id:
+		return nil, "", fmt.Errorf("resource ID %q doesn't match the path", id)
+	case r.id != "":
+		id = r.id
+	}
+
+	ver := resource.VersionUndefined
+
+	if definition.Metadata.Version != "" {
+		var err error
+
+		if ver, err = resource.ParseVersion(definition.Metadata.Version); err != nil {
+			return nil, "", err
+		}
+	}
+
+	md := resource.NewMetadata(r.namespace, r.definition.Type, id, ver)
+
+	if definition.Metadata.Phase != "" {
+		phase, err := resource.ParsePhase(definition.Metadata.Phase)
+		if err != nil {
+			return nil, "", err
+		}
+
+		md.SetPhase(phase)
+	}
+
+	for k, v := range definition.Metadata.Labels {
+		md.Labels().Set(k, v)
+	}
+
+	for _, fin := range definition.Metadata.Finalizers {
+		md.Finalizers().Add(fin)
+	}
+
+	res, err := protobuf.CreateResource(r.definition.Type)
+	if err != nil {
+		return nil, "", err
+	}
+
+	*res.Metadata() = md
+
+	if len(definition.Spec) > 0 {
+		if err = json.Unmarshal(definition.Spec, res.Spec()); err != nil {
+			return nil, "", fmt.Errorf("error decoding spec: %w", err)
+		}
+	}
+
+	return res, definition.Metadata.Owner, nil
+}
+
+// encodeResource marshals the resource to JSON, redacting the spec of sensitive resources.
+func encodeResource(definition meta.ResourceDefinitionSpec, res resource.Resource) (json.RawMessage, error) {
+	if resource.IsTombstone(res) {
+		return json.Marshal(&struct {
+			Metadata *resource.Metadata `json:"metadata"`
+		}{
+			Metadata: res.Metadata(),
+		})
+	}
+
+	if definition.Sensitivity == meta.Sensitive {
+		res = meta.NewRedacted(*res.Metadata())
+	}
+
+	return resource.MarshalJSON(res)
+}
+
+// parseLabelQuery builds the label query from the `label` query parameters.
+//
+// Each parameter is either `key=value` (label equals value), `key` (label exists) or `!key` (label doesn't exist).
+func parseLabelQuery(req *nethttp.Request) ([]resource.LabelQueryOption, error) {
+	terms := req.URL.Query()["label"]
+	opts := make([]resource.LabelQueryOption, 0, len(terms))
+
+	for _, term := range terms {
+		key, value, isEqual := strings.Cut(term, "=")
+
+		switch {
+		case isEqual:
+			opts = append(opts, resource.LabelEqual(key, value))
+		case strings.HasPrefix(key, "!"):
+			opts = append(opts, resource.LabelNotExists(key[1:]))
+		default:
+			opts = append(opts, resource.LabelExists(key))
+		}
+
+		if strings.TrimPrefix(key, "!") == "" {
+			return nil, fmt.Errorf("invalid label query term %q", term)
+		}
+	}
+
+	return opts, nil
+}
+
+func writeResource(w nethttp.ResponseWriter, status int, definition meta.ResourceDefinitionSpec, res resource.Resource) {
+	encoded, err := encodeResource(definition, res)
+	if err != nil {
+		writeError(w, nethttp.StatusInternalServerError, err)
+
+		return
+	}
+
+	writeJSON(w, status, encoded)
+}
+
+func writeJSON(w nethttp.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(v) //nolint:errcheck
+}
+
+func writeError(w nethttp.ResponseWriter, status int, err error) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{
+		Error: err.Error(),
+	})
+}
+
+// writeStateError maps the state errors to the HTTP status codes.
+func writeStateError(w nethttp.ResponseWriter, err error) {
+	status := nethttp.StatusInternalServerError
+
+	switch {
+	case state.IsNotFoundError(err):
+		status = nethttp.StatusNotFound
+	case state.IsConflictError(err), state.IsOwnerConflictError(err), state.IsPhaseConflictError(err):
+		status = nethttp.StatusConflict
+	case state.IsValidationError(err):
+		status = nethttp.StatusUnprocessableEntity
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		status = nethttp.StatusServiceUnavailable
+	}
+
+	writeError(w, status, err)
+}
@@ -0,0 +1,293 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package http_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/resource/resourcetest"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+	"github.com/cosi-project/runtime/pkg/state"
+	statehttp "github.com/cosi-project/runtime/pkg/state/http"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/cosi-project/runtime/pkg/state/registry"
+)
+
+const noteType = resource.Type("Notes.test.cosi.dev")
+
+type noteSpec struct {
+	Text string `json:"text" yaml:"text"`
+}
+
+func (spec noteSpec) DeepCopy() noteSpec { return spec }
+
+type noteRD struct{}
+
+func (noteRD) ResourceDefinition(resource.Metadata, noteSpec) meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             noteType,
+		DefaultNamespace: "default",
+	}
+}
+
+type note = typed.Resource[noteSpec, noteRD]
+
+type resourceJSON struct {
+	Metadata struct {
+		Labels  map[string]string `json:"labels"`
+		ID      string            `json:"id"`
+		Version string            `json:"version"`
+	} `json:"metadata"`
+	Spec noteSpec `json:"spec"`
+}
+
+func do(t *testing.T, method, url, body string) (int, []byte) {
+	t.Helper()
+
+	req, err := nethttp.NewRequest(method, url, strings.NewReader(body)) //nolint:noctx
+	require.NoError(t, err)
+
+	resp, err := nethttp.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	var raw json.RawMessage
+
+	if resp.StatusCode != nethttp.StatusNoContent {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&raw))
+	}
+
+	return resp.StatusCode, raw
+}
+
+func init() {
+	if err := protobuf.RegisterResource(noteType, &note{}); err != nil {
+		panic(err)
+	}
+}
+
+func setup(ctx context.Context, t *testing.T) (state.State, *httptest.Server) {
+	t.Helper()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+	require.NoError(t, registry.NewResourceRegistry(st).Register(ctx, typed.NewResource[noteSpec, noteRD](resource.Metadata{}, noteSpec{})))
+
+	srv := httptest.NewServer(statehttp.NewGateway(st))
+	t.Cleanup(srv.Close)
+
+	return st, srv
+}
+
+func TestGateway(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, srv := setup(ctx, t)
+
+	base := srv.URL + "/namespaces/default/notes"
+
+	status, body := do(t, nethttp.MethodPost, base, `{"metadata":{"id":"a","labels":{"color":"red"}},"spec":{"text":"hello"}}`)
+	require.Equal(t, nethttp.StatusCreated, status, string(body))
+
+	var r resourceJSON
+
+	require.NoError(t, json.Unmarshal(body, &r))
+	assert.Equal(t, "1", r.Metadata.Version)
+	assert.Equal(t, "hello", r.Spec.Text)
+
+	// watch with bootstrap
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodGet, base+"?watch=true&bootstrap=true", nil)
+	require.NoError(t, err)
+
+	resp, err := nethttp.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	events := bufio.NewScanner(resp.Body)
+
+	nextEvent := func() statehttp.Event {
+		var event statehttp.Event
+
+		for events.Scan() {
+			if line := events.Text(); strings.HasPrefix(line, "data: ") {
+				require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event))
+
+				return event
+			}
+		}
+
+		require.NoError(t, events.Err())
+		t.Fatal("stream closed")
+
+		return event
+	}
+
+	event := nextEvent()
+	assert.Equal(t, statehttp.EventCreated, event.Type)
+
+	event = nextEvent()
+	assert.Equal(t, statehttp.EventBootstrapped, event.Type)
+
+	status, body = do(t, nethttp.MethodPut, base+"/a", `{"metadata":{"version":"1"},"spec":{"text":"world"}}`)
+	require.Equal(t, nethttp.StatusOK, status, string(body))
+
+	r = resourceJSON{}
+
+	require.NoError(t, json.Unmarshal(body, &r))
+	assert.Equal(t, "2", r.Metadata.Version)
+	assert.Equal(t, "world", r.Spec.Text)
+	assert.Empty(t, r.Metadata.Labels)
+
+	event = nextEvent()
+	assert.Equal(t, statehttp.EventUpdated, event.Type)
+	require.NoError(t, json.Unmarshal(event.Resource, &r))
+	assert.Equal(t, "world", r.Spec.Text)
+
+	status, _ = do(t, nethttp.MethodPut, base+"/a", `{"metadata":{"version":"1"},"spec":{"text":"stale"}}`)
+	assert.Equal(t, nethttp.StatusConflict, status)
+
+	status, body = do(t, nethttp.MethodGet, base+"/a", "")
+	require.Equal(t, nethttp.StatusOK, status)
+	require.NoError(t, json.Unmarshal(body, &r))
+	assert.Equal(t, "world", r.Spec.Text)
+
+	var list struct {
+		Items []resourceJSON `json:"items"`
+	}
+
+	status, body = do(t, nethttp.MethodGet, base+"?label=!color", "")
+	require.Equal(t, nethttp.StatusOK, status)
+	require.NoError(t, json.Unmarshal(body, &list))
+	assert.Len(t, list.Items, 1)
+
+	status, body = do(t, nethttp.MethodGet, base+"?label=color=red", "")
+	require.Equal(t, nethttp.StatusOK, status)
+	require.NoError(t, json.Unmarshal(body, &list))
+	assert.Empty(t, list.Items)
+
+	status, _ = do(t, nethttp.MethodDelete, base+"/a", "")
+	assert.Equal(t, nethttp.StatusNoContent, status)
+
+	event = nextEvent()
+	assert.Equal(t, statehttp.EventDestroyed, event.Type)
+
+	status, _ = do(t, nethttp.MethodGet, base+"/a", "")
+	assert.Equal(t, nethttp.StatusNotFound, status)
+
+	status, _ = do(t, nethttp.MethodGet, srv.URL+"/namespaces/default/unknown", "")
+	assert.Equal(t, nethttp.StatusNotFound, status)
+}
+
+func TestWebSocketWatch(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	st, srv := setup(ctx, t)
+
+	for _, id := range []resource.ID{"a", "b"} {
+		r := typed.NewResource[noteSpec, noteRD](resource.NewMetadata("default", noteType, id, resource.VersionUndefined), noteSpec{})
+		r.Metadata().Labels().Set("app", id)
+
+		require.NoError(t, st.Create(ctx, r))
+	}
+
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/namespaces/default/notes?watch=true&bootstrap=true&label=app=b",
+		"", "http://localhost/")
+	require.NoError(t, err)
+
+	defer conn.Close() //nolint:errcheck
+
+	require.NoError(t, conn.SetDeadline(time.Now().Add(10*time.Second)))
+
+	var r resourceJSON
+
+	for _, expected := range []struct {
+		typ string
+		id  resource.ID
+	}{
+		{statehttp.EventCreated, "b"},
+		{statehttp.EventBootstrapped, ""},
+		{statehttp.EventUpdated, "b"},
+	} {
+		var event statehttp.Event
+
+		require.NoError(t, websocket.JSON.Receive(conn, &event))
+		assert.Equal(t, expected.typ, event.Type)
+
+		if expected.id == "" {
+			assert.Empty(t, event.Resource)
+
+			// updates of both resources, only one matches the label query
+			for _, id := range []resource.ID{"a", "b"} {
+				_, err = st.UpdateWithConflicts(ctx, resource.NewMetadata("default", noteType, id, resource.VersionUndefined), func(r resource.Resource) error {
+					r.(*note).TypedSpec().Text = "updated" //nolint:forcetypeassert
+
+					return nil
+				})
+				require.NoError(t, err)
+			}
+
+			continue
+		}
+
+		require.NoError(t, json.Unmarshal(event.Resource, &r))
+		assert.Equal(t, expected.id, r.Metadata.ID)
+	}
+
+	assert.Equal(t, "updated", r.Spec.Text)
+}
+
+func FuzzLabelQuery(f *testing.F) {
+	ctx := context.Background()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+	require.NoError(f, registry.NewResourceRegistry(st).Register(ctx, typed.NewResource[noteSpec, noteRD](resource.Metadata{}, noteSpec{})))
+
+	note := typed.NewResource[noteSpec, noteRD](resource.NewMetadata("default", noteType, "a", resource.VersionUndefined), noteSpec{})
+	note.Metadata().Labels().Set("app", "web")
+	require.NoError(f, st.Create(ctx, note))
+
+	gateway := statehttp.NewGateway(st)
+
+	for _, seed := range resourcetest.LabelTermCorpus() {
+		f.Add(seed)
+	}
+
+	// terms are separated by newlines, so that the queries with multiple terms are covered
+	f.Fuzz(func(t *testing.T, terms string) {
+		query := url.Values{"label": strings.Split(terms, "\n")}
+
+		req := httptest.NewRequest(nethttp.MethodGet, "/namespaces/default/notes?"+query.Encode(), nil)
+		w := httptest.NewRecorder()
+
+		gateway.ServeHTTP(w, req)
+
+		assert.Contains(t, []int{nethttp.StatusOK, nethttp.StatusBadRequest}, w.Code, w.Body.String())
+	})
+}
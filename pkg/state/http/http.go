@@ -0,0 +1,18 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package http provides a JSON REST gateway to the state.
+//
+// The gateway serves the following endpoints, where the type is resolved via the registered resource definitions
+// (canonical type name or any alias is accepted):
+//
+//	GET    /namespaces/{namespace}/{type}         list resources
+//	GET    /namespaces/{namespace}/{type}/{id}    get a resource
+//	POST   /namespaces/{namespace}/{type}         create a resource
+//	PUT    /namespaces/{namespace}/{type}/{id}    update a resource
+//	DELETE /namespaces/{namespace}/{type}/{id}    destroy a resource
+//
+// List and get requests with the `watch=true` query parameter turn into watches, and the events are streamed
+// as server-sent events, or over WebSocket if the request asks for the connection upgrade.
+package http
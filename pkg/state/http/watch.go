@@ -0,0 +1,289 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// Event types.
+const (
+	EventCreated   = "created"
+	EventUpdated   = "updated"
+	EventDestroyed = "destroyed"
+	// EventBootstrapped is sent once all existing resources were sent for watches with bootstrap enabled.
+	EventBootstrapped = "bootstrapped"
+)
+
+// Event is the JSON representation of the watch event.
+type Event struct {
+	// Type is one of the event types.
+	Type string `json:"type"`
+	// Resource is not set for bootstrapped events.
+	Resource json.RawMessage `json:"resource,omitempty"`
+	Old      json.RawMessage `json:"old,omitempty"`
+}
+
+func encodeEvent(definition meta.ResourceDefinitionSpec, event state.Event) (Event, error) {
+	encoded := Event{
+		Type: strings.ToLower(event.Type.String()),
+	}
+
+	var err error
+
+	if encoded.Resource, err = encodeResource(definition, event.Resource); err != nil {
+		return Event{}, err
+	}
+
+	if event.Old != nil {
+		if encoded.Old, err = encodeResource(definition, event.Old); err != nil {
+			return Event{}, err
+		}
+	}
+
+	return encoded, nil
+}
+
+// watch streams the events of a single resource.
+//
+// Query parameter `tail` sets the number of past events to replay.
+func (gateway *Gateway) watch(w nethttp.ResponseWriter, req *nethttp.Request, r request) {
+	var opts []state.WatchOption
+
+	if tail := req.URL.Query().Get("tail"); tail != "" {
+		n, err := strconv.Atoi(tail)
+		if err != nil {
+			writeError(w, nethttp.StatusBadRequest, fmt.Errorf("invalid tail value: %w", err))
+
+			return
+		}
+
+		opts = append(opts, state.WithTailEvents(n))
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+
+	source := &eventSource{
+		ch:         make(chan state.Event),
+		definition: r.definition,
+	}
+
+	if err := gateway.state.Watch(ctx, resource.NewMetadata(r.namespace, r.definition.Type, r.id, resource.VersionUndefined), source.ch, opts...); err != nil {
+		writeStateError(w, err)
+
+		return
+	}
+
+	serveEvents(ctx, cancel, w, req, source)
+}
+
+// watchKind streams the events of all resources of the kind.
+//
+// Query parameters: `bootstrap=true` sends the existing resources as `created` events first followed by
+// the `bootstrapped` event, `tail` sets the number of past events to replay, `label` filters resources
+// as in list requests.
+func (gateway *Gateway) watchKind(w nethttp.ResponseWriter, req *nethttp.Request, r request) {
+	labelQuery, err := parseLabelQuery(req)
+	if err != nil {
+		writeError(w, nethttp.StatusBadRequest, err)
+
+		return
+	}
+
+	opts := []state.WatchKindOption{
+		state.WatchWithLabelQuery(labelQuery...),
+	}
+
+	if tail := req.URL.Query().Get("tail"); tail != "" {
+		n, err := strconv.Atoi(tail)
+		if err != nil {
+			writeError(w, nethttp.StatusBadRequest, fmt.Errorf("invalid tail value: %w", err))
+
+			return
+		}
+
+		opts = append(opts, state.WithKindTailEvents(n))
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+
+	kind := resource.NewMetadata(r.namespace, r.definition.Type, "", resource.VersionUndefined)
+	source := &eventSource{
+		ch:         make(chan state.Event),
+		definition: r.definition,
+	}
+
+	if err = gateway.state.WatchKind(ctx, kind, source.ch, opts...); err != nil {
+		writeStateError(w, err)
+
+		return
+	}
+
+	// the state doesn't signal the end of the bootstrap contents, so the contents are listed
+	// after the watch is established, and the events already reflected in the list are skipped
+	if req.URL.Query().Get("bootstrap") == "true" {
+		list, err := gateway.state.List(ctx, kind, state.WithLabelQuery(labelQuery...))
+		if err != nil {
+			writeStateError(w, err)
+
+			return
+		}
+
+		source.bootstrap = list.Items
+		source.listed = make(map[resource.ID]uint64, len(list.Items))
+
+		for _, res := range list.Items {
+			source.listed[res.Metadata().ID()] = versionOf(res)
+		}
+	}
+
+	serveEvents(ctx, cancel, w, req, source)
+}
+
+// eventSource converts the state events into the gateway events.
+type eventSource struct {
+	ch         chan state.Event
+	listed     map[resource.ID]uint64
+	definition meta.ResourceDefinitionSpec
+	bootstrap  []resource.Resource
+}
+
+func (source *eventSource) run(ctx context.Context, send func(Event) error) error {
+	if source.listed != nil {
+		for _, res := range source.bootstrap {
+			event, err := encodeEvent(source.definition, state.Event{Type: state.Created, Resource: res})
+			if err != nil {
+				return err
+			}
+
+			if err = send(event); err != nil {
+				return err
+			}
+		}
+
+		source.bootstrap = nil
+
+		if err := send(Event{Type: EventBootstrapped}); err != nil {
+			return err
+		}
+	}
+
+	for {
+		var event state.Event
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case event = <-source.ch:
+		}
+
+		if !source.filter(event) {
+			continue
+		}
+
+		encoded, err := encodeEvent(source.definition, event)
+		if err != nil {
+			return err
+		}
+
+		if err = send(encoded); err != nil {
+			return err
+		}
+	}
+}
+
+// filter skips the events which happened before the bootstrap list was taken.
+func (source *eventSource) filter(event state.Event) bool {
+	id := event.Resource.Metadata().ID()
+
+	listedVersion, ok := source.listed[id]
+	if !ok {
+		return true
+	}
+
+	if event.Type != state.Destroyed && versionOf(event.Resource) <= listedVersion {
+		return false
+	}
+
+	// any later event of the resource is newer than the list
+	delete(source.listed, id)
+
+	return true
+}
+
+func versionOf(res resource.Resource) uint64 {
+	v, err := strconv.ParseUint(res.Metadata().Version().String(), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return v
+}
+
+// serveEvents streams the events over WebSocket if the request asks for the upgrade, and as server-sent events otherwise.
+//
+// Over WebSocket each event is sent as a JSON text message. As server-sent events, each event has the event type
+// as the name, and the Event as JSON data.
+func serveEvents(ctx context.Context, cancel context.CancelFunc, w nethttp.ResponseWriter, req *nethttp.Request, source *eventSource) {
+	if strings.EqualFold(req.Header.Get("Upgrade"), "websocket") {
+		websocket.Server{
+			Handler: func(conn *websocket.Conn) {
+				// hijacked connections don't cancel the request context, so the closing of the connection is detected by reading it
+				go func() {
+					io.Copy(io.Discard, conn) //nolint:errcheck
+
+					cancel()
+				}()
+
+				source.run(ctx, func(event Event) error { //nolint:errcheck
+					return websocket.JSON.Send(conn, event)
+				})
+			},
+		}.ServeHTTP(w, req)
+
+		return
+	}
+
+	flusher, ok := w.(nethttp.Flusher)
+	if !ok {
+		writeError(w, nethttp.StatusInternalServerError, fmt.Errorf("streaming is not supported"))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(nethttp.StatusOK)
+	flusher.Flush()
+
+	source.run(ctx, func(event Event) error { //nolint:errcheck
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		if _, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+			return err
+		}
+
+		flusher.Flush()
+
+		return nil
+	})
+}
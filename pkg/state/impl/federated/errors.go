@@ -0,0 +1,34 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package federated
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+type eUnknownNamespace struct {
+	error
+}
+
+func (eUnknownNamespace) UnknownNamespaceError() {}
+
+// ErrUnknownNamespace generates error for the namespace which is not owned by any member.
+func ErrUnknownNamespace(ns resource.Namespace) error {
+	return eUnknownNamespace{
+		fmt.Errorf("namespace %q is not owned by any federation member", ns),
+	}
+}
+
+// IsUnknownNamespaceError checks if err is caused by the namespace which is not owned by any member.
+func IsUnknownNamespaceError(err error) bool {
+	var i interface {
+		UnknownNamespaceError()
+	}
+
+	return errors.As(err, &i)
+}
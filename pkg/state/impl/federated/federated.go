@@ -0,0 +1,203 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package federated provides an implementation of state which federates multiple states owning disjoint namespaces.
+package federated
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// Member is a state (usually a remote one) which owns a set of namespaces.
+type Member struct {
+	State state.CoreState
+
+	// Name identifies the member in the error messages, e.g. the name of the edge cluster.
+	Name string
+
+	Namespaces []resource.Namespace
+}
+
+// State routes each request to the member owning the namespace.
+type State struct {
+	routes map[resource.Namespace]Member
+}
+
+// NewState initializes new federated State.
+//
+// Each namespace should be owned by exactly one member.
+func NewState(members ...Member) (*State, error) {
+	routes := map[resource.Namespace]Member{}
+
+	for _, member := range members {
+		for _, ns := range member.Namespaces {
+			if owner, exists := routes[ns]; exists {
+				return nil, fmt.Errorf("namespace %q is owned by both %q and %q", ns, owner.Name, member.Name)
+			}
+
+			routes[ns] = member
+		}
+	}
+
+	return &State{
+		routes: routes,
+	}, nil
+}
+
+// Namespaces returns the sorted list of the federated namespaces.
+func (st *State) Namespaces() []resource.Namespace {
+	namespaces := make([]resource.Namespace, 0, len(st.routes))
+
+	for ns := range st.routes {
+		namespaces = append(namespaces, ns)
+	}
+
+	sort.Strings(namespaces)
+
+	return namespaces
+}
+
+func (st *State) route(ns resource.Namespace) (state.CoreState, error) { //nolint:ireturn
+	member, ok := st.routes[ns]
+	if !ok {
+		return nil, ErrUnknownNamespace(ns)
+	}
+
+	return member.State, nil
+}
+
+// Get a resource by type and ID.
+//
+// If a resource is not found, error is returned.
+func (st *State) Get(ctx context.Context, ptr resource.Pointer, opts ...state.GetOption) (resource.Resource, error) { //nolint:ireturn
+	s, err := st.route(ptr.Namespace())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Get(ctx, ptr, opts...)
+}
+
+// List resources by kind.
+func (st *State) List(ctx context.Context, kind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	s, err := st.route(kind.Namespace())
+	if err != nil {
+		return resource.List{}, err
+	}
+
+	return s.List(ctx, kind, opts...)
+}
+
+// ListStream implements state.ListStreamer interface.
+func (st *State) ListStream(ctx context.Context, kind resource.Kind, yield func(resource.Resource) bool, opts ...state.ListOption) error {
+	s, err := st.route(kind.Namespace())
+	if err != nil {
+		return err
+	}
+
+	return state.ListStream(ctx, s, kind, yield, opts...)
+}
+
+// Create a resource.
+//
+// If a resource already exists, Create returns an error.
+func (st *State) Create(ctx context.Context, res resource.Resource, opts ...state.CreateOption) error {
+	s, err := st.route(res.Metadata().Namespace())
+	if err != nil {
+		return err
+	}
+
+	return s.Create(ctx, res, opts...)
+}
+
+// Update a resource.
+//
+// If a resource doesn't exist, error is returned.
+// On update current version of resource `new` in the state should match
+// curVersion, otherwise conflict error is returned.
+func (st *State) Update(ctx context.Context, curVersion resource.Version, newResource resource.Resource, opts ...state.UpdateOption) error {
+	s, err := st.route(newResource.Metadata().Namespace())
+	if err != nil {
+		return err
+	}
+
+	return s.Update(ctx, curVersion, newResource, opts...)
+}
+
+// UpdateStatus updates the status of a resource.
+//
+// If the member owning the namespace doesn't support status updates, error is returned.
+func (st *State) UpdateStatus(ctx context.Context, curVersion resource.Version, newResource resource.StatusResource, opts ...state.UpdateOption) error {
+	s, err := st.route(newResource.Metadata().Namespace())
+	if err != nil {
+		return err
+	}
+
+	return state.UpdateStatus(ctx, s, curVersion, newResource, opts...)
+}
+
+// Destroy a resource.
+//
+// If a resource doesn't exist, error is returned.
+func (st *State) Destroy(ctx context.Context, ptr resource.Pointer, opts ...state.DestroyOption) error {
+	s, err := st.route(ptr.Namespace())
+	if err != nil {
+		return err
+	}
+
+	return s.Destroy(ctx, ptr, opts...)
+}
+
+// Watch state of a resource by type.
+//
+// It's fine to watch for a resource which doesn't exist yet.
+// Watch is canceled when context gets canceled.
+// Watch sends initial resource state as the very first event on the channel,
+// and then sends any updates to the resource as events.
+func (st *State) Watch(ctx context.Context, ptr resource.Pointer, ch chan<- state.Event, opts ...state.WatchOption) error {
+	s, err := st.route(ptr.Namespace())
+	if err != nil {
+		return err
+	}
+
+	return s.Watch(ctx, ptr, ch, opts...)
+}
+
+// WatchKind watches resources of specific kind (namespace and type).
+func (st *State) WatchKind(ctx context.Context, kind resource.Kind, ch chan<- state.Event, opts ...state.WatchKindOption) error {
+	s, err := st.route(kind.Namespace())
+	if err != nil {
+		return err
+	}
+
+	return s.WatchKind(ctx, kind, ch, opts...)
+}
+
+// WatchKindAll watches resources of the type in all federated namespaces, events are merged into a single channel.
+//
+// Events of the same namespace are delivered in order, there is no ordering between the namespaces.
+// If the watch fails to start in any namespace, the watches which were already started are canceled.
+func (st *State) WatchKindAll(ctx context.Context, resourceType resource.Type, ch chan<- state.Event, opts ...state.WatchKindOption) error {
+	watchCtx, cancel := context.WithCancel(ctx)
+
+	for _, ns := range st.Namespaces() {
+		if err := st.routes[ns].State.WatchKind(watchCtx, resource.NewMetadata(ns, resourceType, "", resource.VersionUndefined), ch, opts...); err != nil {
+			cancel()
+
+			return fmt.Errorf("error watching %s/%s on %q: %w", ns, resourceType, st.routes[ns].Name, err)
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+		cancel()
+	}()
+
+	return nil
+}
@@ -0,0 +1,115 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package federated_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/conformance"
+	"github.com/cosi-project/runtime/pkg/state/impl/federated"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestInterfaces(t *testing.T) {
+	t.Parallel()
+
+	assert.Implements(t, (*state.CoreState)(nil), new(federated.State))
+}
+
+func newState(t *testing.T) *federated.State {
+	st, err := federated.NewState(
+		federated.Member{
+			Name:       "edge-1",
+			State:      namespaced.NewState(inmem.Build),
+			Namespaces: []resource.Namespace{"default", "controller"},
+		},
+		federated.Member{
+			Name:       "edge-2",
+			State:      namespaced.NewState(inmem.Build),
+			Namespaces: []resource.Namespace{"system", "runtime"},
+		},
+	)
+	require.NoError(t, err)
+
+	return st
+}
+
+func TestFederatedConformance(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &conformance.StateSuite{
+		State:      state.WrapCore(newState(t)),
+		Namespaces: []resource.Namespace{"default", "controller", "system", "runtime"},
+	})
+}
+
+func TestOverlappingNamespaces(t *testing.T) {
+	t.Parallel()
+
+	_, err := federated.NewState(
+		federated.Member{Name: "edge-1", State: namespaced.NewState(inmem.Build), Namespaces: []resource.Namespace{"default"}},
+		federated.Member{Name: "edge-2", State: namespaced.NewState(inmem.Build), Namespaces: []resource.Namespace{"system", "default"}},
+	)
+	assert.EqualError(t, err, `namespace "default" is owned by both "edge-1" and "edge-2"`)
+}
+
+func TestUnknownNamespace(t *testing.T) {
+	t.Parallel()
+
+	st := newState(t)
+
+	_, err := st.Get(context.Background(), conformance.NewPathResource("other", "var/run").Metadata())
+	assert.True(t, federated.IsUnknownNamespaceError(err))
+	assert.False(t, state.IsNotFoundError(err))
+
+	err = st.Create(context.Background(), conformance.NewPathResource("other", "var/run"))
+	assert.True(t, federated.IsUnknownNamespaceError(err))
+}
+
+func TestWatchKindAll(t *testing.T) {
+	t.Parallel()
+
+	st := newState(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	require.NoError(t, st.Create(ctx, conformance.NewPathResource("default", "etc")))
+
+	ch := make(chan state.Event)
+
+	require.NoError(t, st.WatchKindAll(ctx, conformance.PathResourceType, ch, state.WithBootstrapContents(true)))
+
+	require.NoError(t, st.Create(ctx, conformance.NewPathResource("runtime", "var/run")))
+	require.NoError(t, st.Create(ctx, conformance.NewPathResource("controller", "var/lib")))
+
+	seen := map[string]state.EventType{}
+
+	for len(seen) < 3 {
+		select {
+		case event := <-ch:
+			seen[event.Resource.Metadata().Namespace()+"/"+event.Resource.Metadata().ID()] = event.Type
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for events, seen %v", seen)
+		}
+	}
+
+	assert.Equal(t, map[string]state.EventType{
+		"default/etc":        state.Created,
+		"runtime/var/run":    state.Created,
+		"controller/var/lib": state.Created,
+	}, seen)
+
+	assert.Equal(t, []resource.Namespace{"controller", "default", "runtime", "system"}, st.Namespaces())
+}
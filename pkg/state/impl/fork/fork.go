@@ -0,0 +1,269 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package fork provides a copy-on-write fork of the state which never modifies the original state.
+package fork
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+// State implements a fork of the base state.
+//
+// Resources of each kind (namespace and type) are copied from the base state on first access,
+// after that all reads and writes for the kind go to the in-memory copy.
+// Changes in the base state which happen after the kind is copied are not reflected in the fork.
+type State struct {
+	base    state.CoreState
+	overlay state.CoreState
+
+	// namespaces holds the in-memory states of the overlay by namespace
+	namespaces sync.Map
+
+	snapshots map[kindKey]map[resource.ID]resource.Resource
+	mu        sync.Mutex
+}
+
+type kindKey struct {
+	Namespace resource.Namespace
+	Type      resource.Type
+}
+
+// NewState initializes new fork of the base state.
+func NewState(base state.CoreState) *State {
+	st := &State{
+		base:      base,
+		snapshots: make(map[kindKey]map[resource.ID]resource.Resource),
+	}
+
+	st.overlay = namespaced.NewState(func(ns resource.Namespace) state.CoreState {
+		return st.namespace(ns)
+	})
+
+	return st
+}
+
+// namespace returns the in-memory state of the overlay for the namespace.
+func (st *State) namespace(ns resource.Namespace) *inmem.State {
+	if s, ok := st.namespaces.Load(ns); ok {
+		return s.(*inmem.State) //nolint:forcetypeassert
+	}
+
+	s, _ := st.namespaces.LoadOrStore(ns, inmem.NewState(ns))
+
+	return s.(*inmem.State) //nolint:forcetypeassert
+}
+
+func (st *State) copied(key kindKey) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	_, copied := st.snapshots[key]
+
+	return copied
+}
+
+// ensure copies the resources of the kind from the base state if that wasn't done yet.
+//
+// The base state is listed without holding the lock, so that slow base state doesn't block other kinds.
+// The copies keep the metadata of the base state resources.
+func (st *State) ensure(ctx context.Context, kind resource.Kind) error {
+	key := kindKey{kind.Namespace(), kind.Type()}
+
+	if st.copied(key) {
+		return nil
+	}
+
+	list, err := st.base.List(ctx, resource.NewMetadata(key.Namespace, key.Type, "", resource.VersionUndefined))
+	if err != nil {
+		return fmt.Errorf("error listing base state resources %s/%s: %w", key.Namespace, key.Type, err)
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if _, copied := st.snapshots[key]; copied {
+		// copied concurrently
+		return nil
+	}
+
+	snapshot := make(map[resource.ID]resource.Resource, len(list.Items))
+
+	for _, r := range list.Items {
+		if err = st.namespace(key.Namespace).Load(ctx, r); err != nil {
+			return fmt.Errorf("error copying resource %s: %w", r.Metadata(), err)
+		}
+
+		snapshot[r.Metadata().ID()] = r
+	}
+
+	st.snapshots[key] = snapshot
+
+	return nil
+}
+
+// Get a resource by type and ID.
+//
+// If a resource is not found, error is returned.
+func (st *State) Get(ctx context.Context, ptr resource.Pointer, opts ...state.GetOption) (resource.Resource, error) { //nolint:ireturn
+	if err := st.ensure(ctx, ptr); err != nil {
+		return nil, err
+	}
+
+	return st.overlay.Get(ctx, ptr, opts...)
+}
+
+// List resources by kind.
+func (st *State) List(ctx context.Context, kind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	if err := st.ensure(ctx, kind); err != nil {
+		return resource.List{}, err
+	}
+
+	return st.overlay.List(ctx, kind, opts...)
+}
+
+// ListStream implements state.ListStreamer interface.
+func (st *State) ListStream(ctx context.Context, kind resource.Kind, yield func(resource.Resource) bool, opts ...state.ListOption) error {
+	if err := st.ensure(ctx, kind); err != nil {
+		return err
+	}
+
+	return state.ListStream(ctx, st.overlay, kind, yield, opts...)
+}
+
+// Create a resource.
+//
+// If a resource already exists, Create returns an error.
+func (st *State) Create(ctx context.Context, res resource.Resource, opts ...state.CreateOption) error {
+	if err := st.ensure(ctx, res.Metadata()); err != nil {
+		return err
+	}
+
+	return st.overlay.Create(ctx, res, opts...)
+}
+
+// Update a resource.
+//
+// If a resource doesn't exist, error is returned.
+// On update current version of resource `new` in the state should match
+// curVersion, otherwise conflict error is returned.
+func (st *State) Update(ctx context.Context, curVersion resource.Version, newResource resource.Resource, opts ...state.UpdateOption) error {
+	if err := st.ensure(ctx, newResource.Metadata()); err != nil {
+		return err
+	}
+
+	return st.overlay.Update(ctx, curVersion, newResource, opts...)
+}
+
+// UpdateStatus implements state.StatusUpdater interface.
+func (st *State) UpdateStatus(ctx context.Context, curVersion resource.Version, newResource resource.StatusResource, opts ...state.UpdateOption) error {
+	if err := st.ensure(ctx, newResource.Metadata()); err != nil {
+		return err
+	}
+
+	return state.UpdateStatus(ctx, st.overlay, curVersion, newResource, opts...)
+}
+
+// Destroy a resource.
+//
+// If a resource doesn't exist, error is returned.
+func (st *State) Destroy(ctx context.Context, ptr resource.Pointer, opts ...state.DestroyOption) error {
+	if err := st.ensure(ctx, ptr); err != nil {
+		return err
+	}
+
+	return st.overlay.Destroy(ctx, ptr, opts...)
+}
+
+// Watch state of a resource by type.
+//
+// It's fine to watch for a resource which doesn't exist yet.
+// Watch is canceled when context gets canceled.
+// Watch sends initial resource state as the very first event on the channel,
+// and then sends any updates to the resource as events.
+func (st *State) Watch(ctx context.Context, ptr resource.Pointer, ch chan<- state.Event, opts ...state.WatchOption) error {
+	if err := st.ensure(ctx, ptr); err != nil {
+		return err
+	}
+
+	return st.overlay.Watch(ctx, ptr, ch, opts...)
+}
+
+// WatchKind watches resources of specific kind (namespace and type).
+func (st *State) WatchKind(ctx context.Context, kind resource.Kind, ch chan<- state.Event, opts ...state.WatchKindOption) error {
+	if err := st.ensure(ctx, kind); err != nil {
+		return err
+	}
+
+	return st.overlay.WatchKind(ctx, kind, ch, opts...)
+}
+
+// Change describes a difference between the fork and the base state.
+//
+// Old is nil for created resources, New is nil for destroyed resources.
+type Change struct {
+	Old  resource.Resource
+	New  resource.Resource
+	Type state.EventType
+}
+
+// Changes returns the list of changes made in the fork compared to the base state.
+//
+// Changes are sorted by namespace, type and ID.
+func (st *State) Changes(ctx context.Context) ([]Change, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	var changes []Change
+
+	for key, snapshot := range st.snapshots {
+		list, err := st.overlay.List(ctx, resource.NewMetadata(key.Namespace, key.Type, "", resource.VersionUndefined))
+		if err != nil {
+			return nil, fmt.Errorf("error listing fork resources %s/%s: %w", key.Namespace, key.Type, err)
+		}
+
+		seen := make(map[resource.ID]struct{}, len(list.Items))
+
+		for _, r := range list.Items {
+			seen[r.Metadata().ID()] = struct{}{}
+
+			old, exists := snapshot[r.Metadata().ID()]
+
+			switch {
+			case !exists:
+				changes = append(changes, Change{Type: state.Created, New: r})
+			case !resource.Equal(old, r):
+				changes = append(changes, Change{Type: state.Updated, Old: old, New: r})
+			}
+		}
+
+		for id, old := range snapshot {
+			if _, exists := seen[id]; !exists {
+				changes = append(changes, Change{Type: state.Destroyed, Old: old})
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changePointer(changes[i]).String() < changePointer(changes[j]).String()
+	})
+
+	return changes, nil
+}
+
+func changePointer(change Change) *resource.Metadata {
+	if change.New != nil {
+		return change.New.Metadata()
+	}
+
+	return change.Old.Metadata()
+}
@@ -0,0 +1,152 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fork_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/conformance"
+	"github.com/cosi-project/runtime/pkg/state/impl/fork"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestInterfaces(t *testing.T) {
+	t.Parallel()
+
+	assert.Implements(t, (*state.CoreState)(nil), new(fork.State))
+	assert.Implements(t, (*state.ListStreamer)(nil), new(fork.State))
+	assert.Implements(t, (*state.StatusUpdater)(nil), new(fork.State))
+}
+
+func TestUpdateStatus(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	base := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	replica := conformance.NewReplicaResource("default", "one", 3)
+	require.NoError(t, base.Create(ctx, replica))
+
+	st := fork.NewState(base)
+
+	status := replica.DeepCopy().(*conformance.ReplicaResource) //nolint:forcetypeassert
+	status.TypedSpec().Ready = 2
+	status.Metadata().BumpVersion()
+
+	require.NoError(t, state.UpdateStatus(ctx, st, replica.Metadata().Version(), status, state.WithUpdateOwner("controller")))
+
+	r, err := st.Get(ctx, replica.Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, 2, r.(*conformance.ReplicaResource).TypedSpec().Ready) //nolint:forcetypeassert
+
+	// the base state is not modified
+	r, err = base.Get(ctx, replica.Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, 0, r.(*conformance.ReplicaResource).TypedSpec().Ready) //nolint:forcetypeassert
+}
+
+func TestCopyMetadata(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	base := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	replica := conformance.NewReplicaResource("default", "one", 3)
+	require.NoError(t, base.Create(ctx, replica, state.WithCreateOwner("owner")))
+
+	_, err := base.UpdateWithConflicts(ctx, replica.Metadata(), func(r resource.Resource) error {
+		r.(*conformance.ReplicaResource).TypedSpec().Replicas = 4 //nolint:forcetypeassert
+
+		return nil
+	}, state.WithUpdateOwner("owner"))
+	require.NoError(t, err)
+
+	current, err := base.Get(ctx, replica.Metadata())
+	require.NoError(t, err)
+
+	status := current.DeepCopy().(*conformance.ReplicaResource) //nolint:forcetypeassert
+	status.TypedSpec().Ready = 2
+	status.Metadata().BumpVersion()
+
+	require.NoError(t, state.UpdateStatus(ctx, base, current.Metadata().Version(), status, state.WithUpdateOwner("controller")))
+
+	expected, err := base.Get(ctx, replica.Metadata())
+	require.NoError(t, err)
+
+	st := fork.NewState(base)
+
+	// copies in the fork keep the metadata of the base state resources
+	r, err := st.Get(ctx, replica.Metadata())
+	require.NoError(t, err)
+
+	assert.True(t, expected.Metadata().Equal(*r.Metadata()))
+	assert.EqualValues(t, 2, r.Metadata().Generation())
+	assert.Equal(t, "controller", r.Metadata().StatusOwner())
+
+	changes, err := st.Changes(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestForkConformance(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &conformance.StateSuite{
+		State:      state.WrapCore(fork.NewState(namespaced.NewState(inmem.Build))),
+		Namespaces: []resource.Namespace{"default", "controller", "system", "runtime"},
+	})
+}
+
+func TestChanges(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	base := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	require.NoError(t, base.Create(ctx, conformance.NewPathResource("default", "var/run")))
+	require.NoError(t, base.Create(ctx, conformance.NewPathResource("default", "var/lib")))
+	require.NoError(t, base.Create(ctx, conformance.NewPathResource("default", "etc")))
+
+	forked := fork.NewState(base)
+	st := state.WrapCore(forked)
+
+	require.NoError(t, st.Create(ctx, conformance.NewPathResource("default", "tmp")))
+	require.NoError(t, st.Destroy(ctx, conformance.NewPathResource("default", "var/run").Metadata()))
+	require.NoError(t, st.AddFinalizer(ctx, conformance.NewPathResource("default", "var/lib").Metadata(), "fin"))
+
+	changes, err := forked.Changes(ctx)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+
+	assert.Equal(t, state.Created, changes[0].Type)
+	assert.Equal(t, resource.ID("tmp"), changes[0].New.Metadata().ID())
+
+	assert.Equal(t, state.Updated, changes[1].Type)
+	assert.Equal(t, resource.ID("var/lib"), changes[1].New.Metadata().ID())
+	assert.True(t, changes[1].Old.Metadata().Finalizers().Empty())
+
+	assert.Equal(t, state.Destroyed, changes[2].Type)
+	assert.Equal(t, resource.ID("var/run"), changes[2].Old.Metadata().ID())
+
+	// base state is not modified
+	list, err := base.List(ctx, conformance.NewPathResource("default", "").Metadata())
+	require.NoError(t, err)
+	assert.Len(t, list.Items, 3)
+
+	r, err := base.Get(ctx, conformance.NewPathResource("default", "var/lib").Metadata())
+	require.NoError(t, err)
+	assert.True(t, r.Metadata().Finalizers().Empty())
+}
@@ -6,9 +6,11 @@ package inmem
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"sync"
 
+	"github.com/cosi-project/runtime/pkg/clock"
 	"github.com/cosi-project/runtime/pkg/resource"
 	"github.com/cosi-project/runtime/pkg/state"
 )
@@ -19,11 +21,16 @@ type ResourceCollection struct {
 
 	storage map[resource.ID]resource.Resource
 
+	index            labelIndex
+	indexInitialized bool
+
 	ns  resource.Namespace
 	typ resource.Type
 
 	store BackingStore
 
+	clock clock.Clock
+
 	stream []state.Event
 
 	mu sync.Mutex
@@ -44,6 +51,7 @@ func NewResourceCollection(ns resource.Namespace, typ resource.Type, capacity, g
 		storage:  make(map[resource.ID]resource.Resource),
 		stream:   make([]state.Event, capacity),
 		store:    store,
+		clock:    clock.Real(),
 	}
 
 	collection.c = sync.NewCond(&collection.mu)
@@ -74,31 +82,85 @@ func (collection *ResourceCollection) Get(resourceID resource.ID) (resource.Reso
 
 // List resources.
 func (collection *ResourceCollection) List(options *state.ListOptions) (resource.List, error) {
-	collection.mu.Lock()
+	items := collection.snapshot(options)
 
-	result := resource.List{
-		Items: make([]resource.Resource, 0, len(collection.storage)),
+	for i := range items {
+		items[i] = items[i].DeepCopy()
 	}
 
-	for _, res := range collection.storage {
-		if !options.LabelQuery.Matches(*res.Metadata().Labels()) {
-			continue
+	return resource.List{
+		Items: items,
+	}, nil
+}
+
+// ListStream yields copies of the resources one by one, so that the full list of copies is never built.
+func (collection *ResourceCollection) ListStream(options *state.ListOptions, yield func(resource.Resource) bool) error {
+	for _, res := range collection.snapshot(options) {
+		if !yield(res.DeepCopy()) {
+			return nil
 		}
+	}
+
+	return nil
+}
+
+// snapshot returns the resources matching the options sorted by ID.
+//
+// Stored resources are never modified in place, so they can be copied after the lock is released.
+func (collection *ResourceCollection) snapshot(options *state.ListOptions) []resource.Resource {
+	collection.mu.Lock()
 
-		result.Items = append(result.Items, res.DeepCopy())
+	items := make([]resource.Resource, 0, len(collection.storage))
+
+	if ids, ok := collection.index.lookup(options.LabelQuery); ok {
+		for id := range ids {
+			res := collection.storage[id]
+
+			if !options.LabelQuery.Matches(*res.Metadata().Labels()) {
+				continue
+			}
+
+			items = append(items, res)
+		}
+	} else {
+		for _, res := range collection.storage {
+			if !options.LabelQuery.Matches(*res.Metadata().Labels()) {
+				continue
+			}
+
+			items = append(items, res)
+		}
 	}
 
 	collection.mu.Unlock()
 
-	sort.Slice(result.Items, func(i, j int) bool {
-		return result.Items[i].Metadata().ID() < result.Items[j].Metadata().ID()
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Metadata().ID() < items[j].Metadata().ID()
 	})
 
-	return result, nil
+	return items
+}
+
+// put should be called only with collection.mu held.
+//
+// If old is not nil, it is replaced in the storage and in the label index.
+func (collection *ResourceCollection) put(r, old resource.Resource) {
+	if !collection.indexInitialized {
+		collection.index = newLabelIndex(r)
+		collection.indexInitialized = true
+	}
+
+	if old != nil {
+		collection.index.remove(old)
+	}
+
+	collection.index.add(r)
+
+	collection.storage[r.Metadata().ID()] = r
 }
 
 func (collection *ResourceCollection) inject(resource resource.Resource) {
-	collection.storage[resource.Metadata().ID()] = resource
+	collection.put(resource, nil)
 	collection.publish(state.Event{
 		Type:     state.Created,
 		Resource: resource,
@@ -113,6 +175,30 @@ func (collection *ResourceCollection) Create(ctx context.Context, resource resou
 		return err
 	}
 
+	resource.Metadata().SetGeneration(1)
+
+	collection.mu.Lock()
+	defer collection.mu.Unlock()
+
+	if _, exists := collection.storage[resource.Metadata().ID()]; exists {
+		return ErrAlreadyExists(resource.Metadata())
+	}
+
+	if collection.store != nil {
+		if err := collection.store.Put(ctx, collection.typ, resource); err != nil {
+			return err
+		}
+	}
+
+	collection.inject(resource)
+
+	return nil
+}
+
+// Load inserts a copy of the resource keeping its metadata.
+func (collection *ResourceCollection) Load(ctx context.Context, resource resource.Resource) error {
+	resource = resource.DeepCopy()
+
 	collection.mu.Lock()
 	defer collection.mu.Unlock()
 
@@ -160,13 +246,30 @@ func (collection *ResourceCollection) Update(ctx context.Context, curVersion res
 		return ErrPhaseConflict(curResource.Metadata(), *options.ExpectedPhase)
 	}
 
+	if statusResource, ok := newResource.(resource.StatusResource); ok {
+		// status is updated only via UpdateStatus
+		if err := statusResource.SetStatus(curResource.DeepCopy().(resource.StatusResource).Status()); err != nil { //nolint:forcetypeassert
+			return err
+		}
+
+		newResource.Metadata().SetStatusOwner(curResource.Metadata().StatusOwner())
+	}
+
+	generation := curResource.Metadata().Generation()
+
+	if !resource.SpecEqual(curResource, newResource) {
+		generation++
+	}
+
+	newResource.Metadata().SetGeneration(generation)
+
 	if collection.store != nil {
 		if err := collection.store.Put(ctx, collection.typ, newResource); err != nil {
 			return err
 		}
 	}
 
-	collection.storage[id] = newResource
+	collection.put(newResource, curResource)
 
 	collection.publish(state.Event{
 		Type:     state.Updated,
@@ -177,8 +280,66 @@ func (collection *ResourceCollection) Update(ctx context.Context, curVersion res
 	return nil
 }
 
+// UpdateStatus updates the status of a resource.
+func (collection *ResourceCollection) UpdateStatus(ctx context.Context, curVersion resource.Version, newResource resource.StatusResource, options *state.UpdateOptions) error {
+	status := newResource.DeepCopy().(resource.StatusResource).Status() //nolint:forcetypeassert
+	id := newResource.Metadata().ID()
+
+	collection.mu.Lock()
+	defer collection.mu.Unlock()
+
+	curResource, exists := collection.storage[id]
+	if !exists {
+		return ErrNotFound(newResource.Metadata())
+	}
+
+	if statusOwner := curResource.Metadata().StatusOwner(); statusOwner != "" && statusOwner != options.Owner {
+		return ErrOwnerConflict(curResource.Metadata(), statusOwner)
+	}
+
+	if newResource.Metadata().Version().Equal(curVersion) {
+		return ErrUpdateSameVersion(curResource.Metadata(), curVersion)
+	}
+
+	if !curResource.Metadata().Version().Equal(curVersion) {
+		return ErrVersionConflict(curResource.Metadata(), curVersion, curResource.Metadata().Version())
+	}
+
+	if options.ExpectedPhase != nil && curResource.Metadata().Phase() != *options.ExpectedPhase {
+		return ErrPhaseConflict(curResource.Metadata(), *options.ExpectedPhase)
+	}
+
+	updated, ok := curResource.DeepCopy().(resource.StatusResource)
+	if !ok {
+		return fmt.Errorf("resource %s doesn't have a status", curResource.Metadata())
+	}
+
+	if err := updated.SetStatus(status); err != nil {
+		return err
+	}
+
+	updated.Metadata().SetVersion(newResource.Metadata().Version())
+	updated.Metadata().SetStatusOwner(options.Owner)
+
+	if collection.store != nil {
+		if err := collection.store.Put(ctx, collection.typ, updated); err != nil {
+			return err
+		}
+	}
+
+	collection.put(updated, curResource)
+
+	collection.publish(state.Event{
+		Type:     state.Updated,
+		Resource: updated,
+		Old:      curResource,
+	})
+
+	return nil
+}
+
 // Destroy a resource.
-func (collection *ResourceCollection) Destroy(ctx context.Context, ptr resource.Pointer, owner string) error {
+func (collection *ResourceCollection) Destroy(ctx context.Context, ptr resource.Pointer, owner, reason string) error {
 	id := ptr.ID()
 
 	collection.mu.Lock()
@@ -203,11 +364,17 @@ func (collection *ResourceCollection) Destroy(ctx context.Context, ptr resource.
 		}
 	}
 
+	collection.index.remove(resource)
 	delete(collection.storage, id)
 
 	collection.publish(state.Event{
 		Type:     state.Destroyed,
 		Resource: resource,
+		Destroy: &state.DestroyInfo{
+			Timestamp: collection.clock.Now(),
+			Owner:     owner,
+			Reason:    reason,
+		},
 	})
 
 	return nil
@@ -255,6 +422,8 @@ func (collection *ResourceCollection) Watch(ctx context.Context, id resource.ID,
 		}
 	}
 
+	collection.wakeOnDone(ctx)
+
 	go func() {
 		if options.TailEvents <= 0 {
 			select {
@@ -269,8 +438,7 @@ func (collection *ResourceCollection) Watch(ctx context.Context, id resource.ID,
 			// while there's no data to consume (pos == e.writePos), wait for Condition variable signal,
 			// then recheck the condition to be true.
 			for pos == collection.writePos {
-				collection.c.Wait()
-
+				// context is checked before waiting, as wakeOnDone might have already broadcasted
 				select {
 				case <-ctx.Done():
 					collection.mu.Unlock()
@@ -278,6 +446,8 @@ func (collection *ResourceCollection) Watch(ctx context.Context, id resource.ID,
 					return
 				default:
 				}
+
+				collection.c.Wait()
 			}
 
 			if collection.writePos-pos >= int64(collection.capacity) {
@@ -317,6 +487,18 @@ func (collection *ResourceCollection) Watch(ctx context.Context, id resource.ID,
 	return nil
 }
 
+// wakeOnDone wakes up the watchers waiting for the new events when the context is canceled,
+// so that the watch goroutine returns even if the collection is not written to anymore.
+func (collection *ResourceCollection) wakeOnDone(ctx context.Context) {
+	go func() {
+		<-ctx.Done()
+
+		collection.mu.Lock()
+		collection.c.Broadcast()
+		collection.mu.Unlock()
+	}()
+}
+
 // WatchAll for any resource change stored in this collection.
 //
 //nolint:gocognit,gocyclo,cyclop
@@ -363,6 +545,8 @@ func (collection *ResourceCollection) WatchAll(ctx context.Context, ch chan<- st
 		}
 	}
 
+	collection.wakeOnDone(ctx)
+
 	go func() {
 		// send initial contents if they were captured
 		for _, res := range bootstrapList {
@@ -383,8 +567,7 @@ func (collection *ResourceCollection) WatchAll(ctx context.Context, ch chan<- st
 			// while there's no data to consume (pos == e.writePos), wait for Condition variable signal,
 			// then recheck the condition to be true.
 			for pos == collection.writePos {
-				collection.c.Wait()
-
+				// context is checked before waiting, as wakeOnDone might have already broadcasted
 				select {
 				case <-ctx.Done():
 					collection.mu.Unlock()
@@ -392,6 +575,8 @@ func (collection *ResourceCollection) WatchAll(ctx context.Context, ch chan<- st
 					return
 				default:
 				}
+
+				collection.c.Wait()
 			}
 
 			if collection.writePos-pos >= int64(collection.capacity) {
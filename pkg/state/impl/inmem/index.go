@@ -0,0 +1,85 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package inmem
+
+import (
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+)
+
+// labelIndex maps indexed label keys and values to the IDs of the resources.
+type labelIndex map[string]map[string]map[resource.ID]struct{}
+
+// newLabelIndex builds an empty index for the label keys declared in the resource definition.
+//
+// If the resource doesn't declare indexed labels, nil index is returned.
+func newLabelIndex(r resource.Resource) labelIndex {
+	provider, ok := r.(meta.ResourceDefinitionProvider)
+	if !ok {
+		return nil
+	}
+
+	keys := provider.ResourceDefinition().IndexedLabels
+	if len(keys) == 0 {
+		return nil
+	}
+
+	index := make(labelIndex, len(keys))
+
+	for _, key := range keys {
+		index[key] = map[string]map[resource.ID]struct{}{}
+	}
+
+	return index
+}
+
+func (index labelIndex) add(r resource.Resource) {
+	for key, values := range index {
+		value, ok := r.Metadata().Labels().Get(key)
+		if !ok {
+			continue
+		}
+
+		ids := values[value]
+		if ids == nil {
+			ids = map[resource.ID]struct{}{}
+			values[value] = ids
+		}
+
+		ids[r.Metadata().ID()] = struct{}{}
+	}
+}
+
+func (index labelIndex) remove(r resource.Resource) {
+	for key, values := range index {
+		value, ok := r.Metadata().Labels().Get(key)
+		if !ok {
+			continue
+		}
+
+		delete(values[value], r.Metadata().ID())
+
+		if len(values[value]) == 0 {
+			delete(values, value)
+		}
+	}
+}
+
+// lookup returns the IDs of the resources matching the first equality term on an indexed label.
+//
+// If the query has no such terms, lookup returns false, and the full scan is required.
+func (index labelIndex) lookup(query resource.LabelQuery) (map[resource.ID]struct{}, bool) {
+	for _, term := range query.Terms {
+		if term.Op != resource.LabelOpEqual {
+			continue
+		}
+
+		if values, ok := index[term.Key]; ok {
+			return values[term.Value], true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,85 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package inmem_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+)
+
+type indexedSpec struct{}
+
+func (spec indexedSpec) DeepCopy() indexedSpec { return spec }
+
+type indexedRD struct{}
+
+func (indexedRD) ResourceDefinition(resource.Metadata, indexedSpec) meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:          "Indexeds.test.cosi.dev",
+		IndexedLabels: []string{"app"},
+	}
+}
+
+func TestLabelIndex(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	st := state.WrapCore(inmem.NewState("default"))
+
+	create := func(id, app, tier string) {
+		r := typed.NewResource[indexedSpec, indexedRD](resource.NewMetadata("default", "Indexeds.test.cosi.dev", id, resource.VersionUndefined), indexedSpec{})
+		r.Metadata().Labels().Set("app", app)
+		r.Metadata().Labels().Set("tier", tier)
+
+		require.NoError(t, st.Create(ctx, r))
+	}
+
+	list := func(opts ...resource.LabelQueryOption) []resource.ID {
+		items, err := st.List(ctx, resource.NewMetadata("default", "Indexeds.test.cosi.dev", "", resource.VersionUndefined), state.WithLabelQuery(opts...))
+		require.NoError(t, err)
+
+		ids := make([]resource.ID, 0, len(items.Items))
+
+		for _, item := range items.Items {
+			ids = append(ids, item.Metadata().ID())
+		}
+
+		return ids
+	}
+
+	create("1", "web", "frontend")
+	create("2", "web", "backend")
+	create("3", "db", "backend")
+
+	assert.Equal(t, []resource.ID{"1", "2"}, list(resource.LabelEqual("app", "web")))
+	assert.Equal(t, []resource.ID{"2"}, list(resource.LabelEqual("app", "web"), resource.LabelEqual("tier", "backend")))
+	assert.Equal(t, []resource.ID{"2", "3"}, list(resource.LabelEqual("tier", "backend")))
+	assert.Empty(t, list(resource.LabelEqual("app", "cache")))
+
+	_, err := st.UpdateWithConflicts(ctx, resource.NewMetadata("default", "Indexeds.test.cosi.dev", "2", resource.VersionUndefined), func(r resource.Resource) error {
+		r.Metadata().Labels().Set("app", "db")
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []resource.ID{"1"}, list(resource.LabelEqual("app", "web")))
+	assert.Equal(t, []resource.ID{"2", "3"}, list(resource.LabelEqual("app", "db")))
+
+	require.NoError(t, st.Destroy(ctx, resource.NewMetadata("default", "Indexeds.test.cosi.dev", "3", resource.VersionUndefined)))
+
+	assert.Equal(t, []resource.ID{"2"}, list(resource.LabelEqual("app", "db")))
+}
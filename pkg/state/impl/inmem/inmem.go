@@ -10,6 +10,7 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/cosi-project/runtime/pkg/clock"
 	"github.com/cosi-project/runtime/pkg/resource"
 	"github.com/cosi-project/runtime/pkg/state"
 )
@@ -18,6 +19,7 @@ import (
 type State struct {
 	collections sync.Map
 	store       BackingStore
+	clock       clock.Clock
 
 	ns resource.Namespace
 
@@ -44,6 +46,7 @@ func NewStateWithOptions(opts ...StateOption) func(ns resource.Namespace) *State
 			capacity: options.HistoryCapacity,
 			gap:      options.HistoryGap,
 			store:    options.BackingStore,
+			clock:    options.Clock,
 		}
 	}
 }
@@ -54,6 +57,7 @@ func (st *State) getCollection(typ resource.Type) *ResourceCollection {
 	}
 
 	collection := NewResourceCollection(st.ns, typ, st.capacity, st.gap, st.store)
+	collection.clock = st.clock
 
 	r, _ := st.collections.LoadOrStore(typ, collection)
 
@@ -117,6 +121,21 @@ func (st *State) List(ctx context.Context, resourceKind resource.Kind, opts ...s
 	return st.getCollection(resourceKind.Type()).List(&options)
 }
 
+// ListStream implements state.ListStreamer interface.
+func (st *State) ListStream(ctx context.Context, resourceKind resource.Kind, yield func(resource.Resource) bool, opts ...state.ListOption) error {
+	if err := st.loadStore(ctx); err != nil {
+		return err
+	}
+
+	var options state.ListOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return st.getCollection(resourceKind.Type()).ListStream(&options, yield)
+}
+
 // Create a resource.
 func (st *State) Create(ctx context.Context, resource resource.Resource, opts ...state.CreateOption) error {
 	if err := st.loadStore(ctx); err != nil {
@@ -132,6 +151,18 @@ func (st *State) Create(ctx context.Context, resource resource.Resource, opts ..
 	return st.getCollection(resource.Metadata().Type()).Create(ctx, resource, options.Owner)
 }
 
+// Load inserts a copy of the resource keeping its metadata as is (version, generation, owners, etc.).
+//
+// Load is used to copy resources from another state (e.g. see fork.State), unlike Create it doesn't
+// reset the metadata of the resource. If a resource already exists, Load returns an error.
+func (st *State) Load(ctx context.Context, resource resource.Resource) error {
+	if err := st.loadStore(ctx); err != nil {
+		return err
+	}
+
+	return st.getCollection(resource.Metadata().Type()).Load(ctx, resource)
+}
+
 // Update a resource.
 func (st *State) Update(ctx context.Context, curVersion resource.Version, newResource resource.Resource, opts ...state.UpdateOption) error {
 	if err := st.loadStore(ctx); err != nil {
@@ -147,6 +178,21 @@ func (st *State) Update(ctx context.Context, curVersion resource.Version, newRes
 	return st.getCollection(newResource.Metadata().Type()).Update(ctx, curVersion, newResource, &options)
 }
 
+// UpdateStatus implements state.StatusUpdater.
+func (st *State) UpdateStatus(ctx context.Context, curVersion resource.Version, newResource resource.StatusResource, opts ...state.UpdateOption) error {
+	if err := st.loadStore(ctx); err != nil {
+		return err
+	}
+
+	options := state.DefaultUpdateOptions()
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return st.getCollection(newResource.Metadata().Type()).UpdateStatus(ctx, curVersion, newResource, &options)
+}
+
 // Destroy a resource.
 func (st *State) Destroy(ctx context.Context, resourcePointer resource.Pointer, opts ...state.DestroyOption) error {
 	if err := st.loadStore(ctx); err != nil {
@@ -159,7 +205,7 @@ func (st *State) Destroy(ctx context.Context, resourcePointer resource.Pointer,
 		opt(&options)
 	}
 
-	return st.getCollection(resourcePointer.Type()).Destroy(ctx, resourcePointer, options.Owner)
+	return st.getCollection(resourcePointer.Type()).Destroy(ctx, resourcePointer, options.Owner, options.Reason)
 }
 
 // Watch a resource.
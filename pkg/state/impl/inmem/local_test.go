@@ -5,11 +5,18 @@
 package inmem_test
 
 import (
+	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"go.uber.org/goleak"
 
+	"github.com/cosi-project/runtime/pkg/clock"
+	controllerconformance "github.com/cosi-project/runtime/pkg/controller/conformance"
 	"github.com/cosi-project/runtime/pkg/resource"
 	"github.com/cosi-project/runtime/pkg/state"
 	"github.com/cosi-project/runtime/pkg/state/conformance"
@@ -30,3 +37,233 @@ func TestLocalConformance(t *testing.T) {
 		Namespaces: []resource.Namespace{"default"},
 	})
 }
+
+func TestGeneration(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	st := state.WrapCore(inmem.NewState("default"))
+
+	input := controllerconformance.NewIntResource("default", "one", 1)
+	require.NoError(t, st.Create(ctx, input))
+
+	r, err := st.Get(ctx, input.Metadata())
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, r.Metadata().Generation())
+
+	// metadata-only change doesn't bump the generation
+	_, err = st.UpdateWithConflicts(ctx, input.Metadata(), func(r resource.Resource) error {
+		r.Metadata().Labels().Set("app", "foo")
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	r, err = st.Get(ctx, input.Metadata())
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, r.Metadata().Generation())
+
+	_, err = st.UpdateWithConflicts(ctx, input.Metadata(), func(r resource.Resource) error {
+		r.(*controllerconformance.IntResource).SetValue(2) //nolint:forcetypeassert
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	r, err = st.Get(ctx, input.Metadata())
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, r.Metadata().Generation())
+	assert.Equal(t, "3", r.Metadata().Version().String())
+
+	output := controllerconformance.NewStrResource("default", "one", "2")
+	assert.False(t, resource.IsGenerationObserved(output, r))
+
+	resource.ObserveGeneration(output, r)
+	assert.True(t, resource.IsGenerationObserved(output, r))
+	assert.EqualValues(t, 2, output.Metadata().ObservedGeneration())
+}
+
+type replicaSpec struct {
+	Replicas int `yaml:"replicas"`
+	Ready    int `yaml:"ready"`
+}
+
+type replicaResource struct {
+	md   resource.Metadata
+	spec replicaSpec
+}
+
+func newReplicaResource(id resource.ID, replicas int) *replicaResource {
+	return &replicaResource{
+		md:   resource.NewMetadata("default", "test/replica", id, resource.VersionUndefined),
+		spec: replicaSpec{Replicas: replicas},
+	}
+}
+
+func (r *replicaResource) Metadata() *resource.Metadata { return &r.md }
+func (r *replicaResource) Spec() interface{}            { return &r.spec }
+func (r *replicaResource) Status() interface{}          { return r.spec.Ready }
+
+func (r *replicaResource) DeepCopy() resource.Resource { //nolint:ireturn
+	return &replicaResource{
+		md:   r.md,
+		spec: r.spec,
+	}
+}
+
+func (r *replicaResource) SetStatus(status interface{}) error {
+	ready, ok := status.(int)
+	if !ok {
+		return fmt.Errorf("unexpected status %T", status)
+	}
+
+	r.spec.Ready = ready
+
+	return nil
+}
+
+func TestUpdateStatus(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	st := state.WrapCore(inmem.NewState("default"))
+
+	res := newReplicaResource("one", 3)
+	require.NoError(t, st.Create(ctx, res))
+
+	// status update by the controller doesn't touch the spec or the generation
+	r, err := st.Get(ctx, res.Metadata())
+	require.NoError(t, err)
+
+	status := r.DeepCopy().(*replicaResource) //nolint:forcetypeassert
+	status.spec.Replicas = 5
+	status.spec.Ready = 2
+	status.Metadata().BumpVersion()
+
+	require.NoError(t, state.UpdateStatus(ctx, st, r.Metadata().Version(), status, state.WithUpdateOwner("controller")))
+
+	r, err = st.Get(ctx, res.Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, replicaSpec{Replicas: 3, Ready: 2}, r.(*replicaResource).spec) //nolint:forcetypeassert
+	assert.EqualValues(t, 1, r.Metadata().Generation())
+	assert.Equal(t, "controller", r.Metadata().StatusOwner())
+
+	// status is owned by the controller
+	other := r.DeepCopy().(*replicaResource) //nolint:forcetypeassert
+	other.spec.Ready = 3
+	other.Metadata().BumpVersion()
+
+	err = state.UpdateStatus(ctx, st, r.Metadata().Version(), other)
+	require.Error(t, err)
+	assert.True(t, state.IsOwnerConflictError(err))
+
+	// spec update by the user doesn't touch the status
+	_, err = st.UpdateWithConflicts(ctx, res.Metadata(), func(r resource.Resource) error {
+		r.(*replicaResource).spec.Replicas = 4 //nolint:forcetypeassert
+		r.(*replicaResource).spec.Ready = 0    //nolint:forcetypeassert
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	r, err = st.Get(ctx, res.Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, replicaSpec{Replicas: 4, Ready: 2}, r.(*replicaResource).spec) //nolint:forcetypeassert
+	assert.EqualValues(t, 2, r.Metadata().Generation())
+	assert.Equal(t, "controller", r.Metadata().StatusOwner())
+
+	// states without status updates support
+	require.Error(t, state.UpdateStatus(ctx, statelessState{st}, r.Metadata().Version(), other))
+}
+
+// statelessState hides StatusUpdater implementation of the wrapped state.
+type statelessState struct {
+	state.CoreState
+}
+
+func TestDestroyInfo(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fake := clock.NewFake(time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	st := state.WrapCore(inmem.NewStateWithOptions(inmem.WithClock(fake))("default"))
+
+	r := controllerconformance.NewIntResource("default", "one", 1)
+	require.NoError(t, st.Create(ctx, r, state.WithCreateOwner("gc")))
+
+	ch := make(chan state.Event)
+
+	require.NoError(t, st.Watch(ctx, r.Metadata(), ch))
+
+	event := <-ch
+	assert.Equal(t, state.Created, event.Type)
+	assert.Nil(t, event.Destroy)
+
+	fake.Advance(time.Minute)
+
+	require.NoError(t, st.Destroy(ctx, r.Metadata(), state.WithDestroyOwner("gc"), state.WithDestroyReason("orphaned")))
+
+	event = <-ch
+	assert.Equal(t, state.Destroyed, event.Type)
+	require.NotNil(t, event.Destroy)
+	assert.Equal(t, "gc", event.Destroy.Owner)
+	assert.Equal(t, "orphaned", event.Destroy.Reason)
+	assert.Equal(t, fake.Now(), event.Destroy.Timestamp)
+}
+
+func TestListStream(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	st := inmem.NewState("default")
+
+	for _, id := range []resource.ID{"c", "a", "d", "b"} {
+		require.NoError(t, st.Create(ctx, controllerconformance.NewIntResource("default", id, 1)))
+	}
+
+	var ids []resource.ID
+
+	require.NoError(t, st.ListStream(ctx, resource.NewMetadata("default", controllerconformance.IntResourceType, "", resource.VersionUndefined),
+		func(r resource.Resource) bool {
+			ids = append(ids, r.Metadata().ID())
+
+			return len(ids) < 3
+		}))
+
+	assert.Equal(t, []resource.ID{"a", "b", "c"}, ids)
+}
+
+func TestWatchCancel(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	st := inmem.NewState("default")
+
+	r := controllerconformance.NewIntResource("default", "one", 1)
+	require.NoError(t, st.Create(ctx, r))
+
+	watchCtx, watchCancel := context.WithCancel(ctx)
+
+	ch := make(chan state.Event)
+
+	require.NoError(t, st.Watch(watchCtx, r.Metadata(), ch))
+	require.NoError(t, st.WatchKind(watchCtx, r.Metadata(), ch))
+
+	// initial event of the resource watch
+	<-ch
+
+	// watch goroutines are waiting for the new events, and should return once the context is canceled
+	// even if the collection is not written to anymore
+	watchCancel()
+}
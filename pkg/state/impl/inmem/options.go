@@ -4,9 +4,12 @@
 
 package inmem
 
+import "github.com/cosi-project/runtime/pkg/clock"
+
 // StateOptions configure inmem.State.
 type StateOptions struct {
 	BackingStore    BackingStore
+	Clock           clock.Clock
 	HistoryCapacity int
 	HistoryGap      int
 }
@@ -44,9 +47,19 @@ func WithBackingStore(store BackingStore) StateOption {
 	}
 }
 
+// WithClock sets the time source of the timestamps recorded by the state (e.g. state.DestroyInfo).
+//
+// Default value is the real clock.
+func WithClock(c clock.Clock) StateOption {
+	return func(options *StateOptions) {
+		options.Clock = c
+	}
+}
+
 // DefaultStateOptions returns default value of StateOptions.
 func DefaultStateOptions() StateOptions {
 	return StateOptions{
+		Clock:           clock.Real(),
 		HistoryCapacity: 100,
 		HistoryGap:      5,
 	}
@@ -52,6 +52,11 @@ func (st *State) List(ctx context.Context, kind resource.Kind, opts ...state.Lis
 	return st.getNamespace(kind.Namespace()).List(ctx, kind, opts...)
 }
 
+// ListStream implements state.ListStreamer interface.
+func (st *State) ListStream(ctx context.Context, kind resource.Kind, yield func(resource.Resource) bool, opts ...state.ListOption) error {
+	return state.ListStream(ctx, st.getNamespace(kind.Namespace()), kind, yield, opts...)
+}
+
 // Create a resource.
 //
 // If a resource already exists, Create returns an error.
@@ -68,6 +73,13 @@ func (st *State) Update(ctx context.Context, curVersion resource.Version, newRes
 	return st.getNamespace(newResource.Metadata().Namespace()).Update(ctx, curVersion, newResource, opts...)
 }
 
+// UpdateStatus updates the status of a resource.
+//
+// If the state of the namespace doesn't support status updates, error is returned.
+func (st *State) UpdateStatus(ctx context.Context, curVersion resource.Version, newResource resource.StatusResource, opts ...state.UpdateOption) error {
+	return state.UpdateStatus(ctx, st.getNamespace(newResource.Metadata().Namespace()), curVersion, newResource, opts...)
+}
+
 // Destroy a resource.
 //
 // If a resource doesn't exist, error is returned.
@@ -8,9 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/suite"
 
-	"github.com/cosi-project/runtime/pkg/resource"
 	"github.com/cosi-project/runtime/pkg/state"
 	"github.com/cosi-project/runtime/pkg/state/conformance"
 	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
@@ -26,8 +24,13 @@ func TestInterfaces(t *testing.T) {
 func TestNamespacedConformance(t *testing.T) {
 	t.Parallel()
 
-	suite.Run(t, &conformance.StateSuite{
-		State:      state.WrapCore(namespaced.NewState(inmem.Build)),
-		Namespaces: []resource.Namespace{"default", "controller", "system", "runtime"},
+	conformance.Run(t, func(*testing.T) state.CoreState {
+		return namespaced.NewState(inmem.Build)
+	})
+}
+
+func BenchmarkNamespaced(b *testing.B) {
+	conformance.Bench(b, func(*testing.B) state.CoreState {
+		return namespaced.NewState(inmem.Build)
 	})
 }
@@ -6,14 +6,15 @@ package bolt_test
 
 import (
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"github.com/stretchr/testify/suite"
 	"go.etcd.io/bbolt"
 
 	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
 	"github.com/cosi-project/runtime/pkg/state"
 	"github.com/cosi-project/runtime/pkg/state/conformance"
 	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
@@ -25,7 +26,25 @@ import (
 func TestBboltConformance(t *testing.T) {
 	t.Parallel()
 
-	tmpDir := t.TempDir()
+	conformance.Run(t, func(t *testing.T) state.CoreState {
+		return newState(t)
+	})
+}
+
+var registerBlobOnce sync.Once
+
+func BenchmarkBbolt(b *testing.B) {
+	registerBlobOnce.Do(func() {
+		require.NoError(b, protobuf.RegisterResource(conformance.BlobResourceType, &conformance.BlobResource{}))
+	})
+
+	conformance.Bench(b, func(b *testing.B) state.CoreState {
+		return newState(b)
+	})
+}
+
+func newState(tb testing.TB) state.CoreState {
+	tmpDir := tb.TempDir()
 
 	marshaler := store.ProtobufMarshaler{}
 
@@ -35,20 +54,17 @@ func TestBboltConformance(t *testing.T) {
 		},
 		marshaler,
 	)
-	require.NoError(t, err)
+	require.NoError(tb, err)
 
-	t.Cleanup(func() {
-		assert.NoError(t, store.Close())
+	tb.Cleanup(func() {
+		assert.NoError(tb, store.Close())
 	})
 
-	suite.Run(t, &conformance.StateSuite{
-		State: state.WrapCore(namespaced.NewState(
-			func(ns resource.Namespace) state.CoreState {
-				return inmem.NewStateWithOptions(
-					inmem.WithBackingStore(store.WithNamespace(ns)),
-				)(ns)
-			},
-		)),
-		Namespaces: []resource.Namespace{"default", "controller", "system", "runtime"},
-	})
+	return namespaced.NewState(
+		func(ns resource.Namespace) state.CoreState {
+			return inmem.NewStateWithOptions(
+				inmem.WithBackingStore(store.WithNamespace(ns)),
+			)(ns)
+		},
+	)
 }
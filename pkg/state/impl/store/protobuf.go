@@ -13,11 +13,20 @@ import (
 // ProtobufMarshaler implements Marshaler using resources protobuf representation.
 //
 // Resources should implement protobuf marshaling.
-type ProtobufMarshaler struct{}
+type ProtobufMarshaler struct {
+	// Deterministic marshals the resources deterministically, so that the stored bytes of the equal resources are the same.
+	Deterministic bool
+}
 
 // MarshalResource implements Marshaler interface.
-func (ProtobufMarshaler) MarshalResource(r resource.Resource) ([]byte, error) {
-	protoR, err := protobuf.FromResource(r)
+func (marshaler ProtobufMarshaler) MarshalResource(r resource.Resource) ([]byte, error) {
+	var opts []protobuf.MarshalOption
+
+	if marshaler.Deterministic {
+		opts = append(opts, protobuf.WithDeterministic())
+	}
+
+	protoR, err := protobuf.FromResource(r, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -27,7 +36,7 @@ func (ProtobufMarshaler) MarshalResource(r resource.Resource) ([]byte, error) {
 		return nil, err
 	}
 
-	return protobuf.ProtoMarshal(protoD)
+	return protobuf.ProtoMarshal(protoD, opts...)
 }
 
 // UnmarshalResource implements Marshaler interface.
@@ -5,6 +5,8 @@
 package store_test
 
 import (
+	"fmt"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -31,3 +33,44 @@ func TestProtobufMarshaler(t *testing.T) {
 
 	assert.Equal(t, resource.String(path), resource.String(unmarshaled))
 }
+
+func TestProtobufMarshalerMetadata(t *testing.T) {
+	path := conformance.NewPathResource("default", "var/log")
+	path.Metadata().SetGeneration(3)
+	path.Metadata().SetObservedGeneration(2)
+	path.Metadata().SetStatusOwner("controller")
+	path.Metadata().SetCustomPhase("pending")
+
+	marshaler := store.ProtobufMarshaler{}
+
+	data, err := marshaler.MarshalResource(path)
+	require.NoError(t, err)
+
+	unmarshaled, err := marshaler.UnmarshalResource(data)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 3, unmarshaled.Metadata().Generation())
+	assert.EqualValues(t, 2, unmarshaled.Metadata().ObservedGeneration())
+	assert.Equal(t, "controller", unmarshaled.Metadata().StatusOwner())
+	assert.Equal(t, "pending", unmarshaled.Metadata().CustomPhase())
+}
+
+func TestProtobufMarshalerDeterministic(t *testing.T) {
+	path := conformance.NewPathResource("default", "var/lib")
+
+	for i := 0; i < 20; i++ {
+		path.Metadata().Labels().Set(fmt.Sprintf("label%d", i), strconv.Itoa(i))
+	}
+
+	marshaler := store.ProtobufMarshaler{Deterministic: true}
+
+	expected, err := marshaler.MarshalResource(path)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		data, err := marshaler.MarshalResource(path.DeepCopy())
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, data)
+	}
+}
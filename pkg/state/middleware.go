@@ -0,0 +1,21 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package state
+
+// Middleware wraps the state to run code before and after each operation (e.g. logging, quotas, auditing).
+//
+// Middleware should embed the wrapped state, and override the operations it intercepts.
+type Middleware func(CoreState) CoreState
+
+// Chain wraps the state with the middlewares.
+//
+// The first middleware is the outermost one: it sees the operations first, and their results last.
+func Chain(coreState CoreState, middlewares ...Middleware) CoreState { //nolint:ireturn
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		coreState = middlewares[i](coreState)
+	}
+
+	return coreState
+}
@@ -104,7 +104,8 @@ type TeardownOption func(*TeardownOptions)
 
 // DestroyOptions for the CoreState.Destroy function.
 type DestroyOptions struct {
-	Owner string
+	Owner  string
+	Reason string
 }
 
 // DestroyOption builds DestroyOptions.
@@ -117,6 +118,13 @@ func WithDestroyOwner(owner string) DestroyOption {
 	}
 }
 
+// WithDestroyReason records the reason of the destroy operation in the Destroyed event.
+func WithDestroyReason(reason string) DestroyOption {
+	return func(opts *DestroyOptions) {
+		opts.Reason = reason
+	}
+}
+
 // WatchOptions for the CoreState.Watch function.
 type WatchOptions struct {
 	TailEvents int
@@ -0,0 +1,402 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// CachedState serves reads from a local cache shared by all consumers of the state.
+//
+// Each kind is cached on the first read: a single watch is established with the underlying state per kind,
+// and it is multiplexed to all watchers of the kind. Writes go to the underlying state, and they become
+// visible in the cache once the watch event arrives, so a read right after a write might return stale data.
+//
+// Watches with tail events or label queries are not shared, and they are passed to the underlying state.
+type CachedState struct {
+	state.CoreState
+
+	ctx   context.Context //nolint:containedctx
+	kinds map[kindKey]*kindCache
+	mu    sync.Mutex
+}
+
+type kindKey struct {
+	ns  resource.Namespace
+	typ resource.Type
+}
+
+// NewCachedState creates new CachedState on top of the underlying state (usually the Adapter).
+//
+// Shared watches are stopped when the context is canceled.
+func NewCachedState(ctx context.Context, st state.CoreState) *CachedState {
+	return &CachedState{
+		CoreState: st,
+		ctx:       ctx,
+		kinds:     map[kindKey]*kindCache{},
+	}
+}
+
+// Get a resource by type and ID.
+//
+// If a resource is not found, error is returned.
+func (st *CachedState) Get(ctx context.Context, ptr resource.Pointer, _ ...state.GetOption) (resource.Resource, error) { //nolint:ireturn
+	kc, err := st.getKind(ctx, ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	r, ok := kc.resources[ptr.ID()]
+	if !ok {
+		return nil, eNotFound{fmt.Errorf("resource %s doesn't exist", ptr)}
+	}
+
+	return r.DeepCopy(), nil
+}
+
+// List resources by type.
+func (st *CachedState) List(ctx context.Context, kind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	var options state.ListOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	kc, err := st.getKind(ctx, kind)
+	if err != nil {
+		return resource.List{}, err
+	}
+
+	items := kc.snapshot(options.LabelQuery)
+
+	for i := range items {
+		items[i] = items[i].DeepCopy()
+	}
+
+	return resource.List{
+		Items: items,
+	}, nil
+}
+
+// Watch state of a resource by type.
+func (st *CachedState) Watch(ctx context.Context, ptr resource.Pointer, ch chan<- state.Event, opts ...state.WatchOption) error {
+	var options state.WatchOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.TailEvents > 0 {
+		return st.CoreState.Watch(ctx, ptr, ch, opts...)
+	}
+
+	kc, err := st.getKind(ctx, ptr)
+	if err != nil {
+		return err
+	}
+
+	sub := newSubscriber(ptr.ID(), ch)
+
+	kc.mu.Lock()
+
+	if r, ok := kc.resources[ptr.ID()]; ok {
+		sub.push(state.Event{Type: state.Created, Resource: r})
+	} else {
+		sub.push(state.Event{
+			Type:     state.Destroyed,
+			Resource: resource.NewTombstone(resource.NewMetadata(ptr.Namespace(), ptr.Type(), ptr.ID(), resource.VersionUndefined)),
+		})
+	}
+
+	kc.subscribers[sub] = struct{}{}
+
+	kc.mu.Unlock()
+
+	go sub.run(ctx, kc)
+
+	return nil
+}
+
+// WatchKind watches resources of specific kind (namespace and type).
+func (st *CachedState) WatchKind(ctx context.Context, kind resource.Kind, ch chan<- state.Event, opts ...state.WatchKindOption) error {
+	var options state.WatchKindOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.TailEvents > 0 || len(options.LabelQuery.Terms) > 0 {
+		return st.CoreState.WatchKind(ctx, kind, ch, opts...)
+	}
+
+	kc, err := st.getKind(ctx, kind)
+	if err != nil {
+		return err
+	}
+
+	sub := newSubscriber("", ch)
+
+	kc.mu.Lock()
+
+	if options.BootstrapContents {
+		for _, r := range kc.snapshotLocked(resource.LabelQuery{}) {
+			sub.push(state.Event{Type: state.Created, Resource: r})
+		}
+	}
+
+	kc.subscribers[sub] = struct{}{}
+
+	kc.mu.Unlock()
+
+	go sub.run(ctx, kc)
+
+	return nil
+}
+
+// UpdateStatus implements state.StatusUpdater interface, the update is passed to the underlying state.
+func (st *CachedState) UpdateStatus(ctx context.Context, curVersion resource.Version, newResource resource.StatusResource, opts ...state.UpdateOption) error {
+	return state.UpdateStatus(ctx, st.CoreState, curVersion, newResource, opts...)
+}
+
+// BatchCreate implements state.Batcher interface, the batch is passed to the underlying state.
+func (st *CachedState) BatchCreate(ctx context.Context, items []state.BatchCreateItem) error {
+	return state.BatchCreate(ctx, st.CoreState, items)
+}
+
+// BatchUpdate implements state.Batcher interface, the batch is passed to the underlying state.
+func (st *CachedState) BatchUpdate(ctx context.Context, items []state.BatchUpdateItem) error {
+	return state.BatchUpdate(ctx, st.CoreState, items)
+}
+
+// BatchDestroy implements state.Batcher interface, the batch is passed to the underlying state.
+func (st *CachedState) BatchDestroy(ctx context.Context, items []state.BatchDestroyItem) error {
+	return state.BatchDestroy(ctx, st.CoreState, items)
+}
+
+// getKind returns the synced cache of the kind, establishing the shared watch on the first call.
+func (st *CachedState) getKind(ctx context.Context, kind resource.Kind) (*kindCache, error) {
+	key := kindKey{ns: kind.Namespace(), typ: kind.Type()}
+
+	st.mu.Lock()
+
+	kc, ok := st.kinds[key]
+	if !ok {
+		kc = &kindCache{
+			resources:   map[resource.ID]resource.Resource{},
+			subscribers: map[*subscriber]struct{}{},
+			ready:       make(chan struct{}),
+		}
+
+		st.kinds[key] = kc
+	}
+
+	st.mu.Unlock()
+
+	if !ok {
+		kc.err = kc.start(st.ctx, st.CoreState, kind)
+
+		if kc.err != nil {
+			// drop the failed cache, so that the next call retries
+			st.mu.Lock()
+			delete(st.kinds, key)
+			st.mu.Unlock()
+		}
+
+		close(kc.ready)
+	}
+
+	select {
+	case <-kc.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if kc.err != nil {
+		return nil, kc.err
+	}
+
+	return kc, nil
+}
+
+// kindCache holds the resources of a single kind, and the watchers of the kind.
+type kindCache struct {
+	resources   map[resource.ID]resource.Resource
+	subscribers map[*subscriber]struct{}
+	ready       chan struct{}
+	err         error
+	mu          sync.Mutex
+}
+
+// start establishes the watch, and seeds the cache with the current resources.
+//
+// Events which arrive while the resources are listed are deduplicated by the resource version.
+func (kc *kindCache) start(ctx context.Context, st state.CoreState, kind resource.Kind) error {
+	ctx, cancel := context.WithCancel(ctx)
+
+	ch := make(chan state.Event)
+
+	if err := st.WatchKind(ctx, kind, ch); err != nil {
+		cancel()
+
+		return fmt.Errorf("error watching %s: %w", kind.Type(), err)
+	}
+
+	list, err := st.List(ctx, kind)
+	if err != nil {
+		cancel()
+
+		return fmt.Errorf("error listing %s: %w", kind.Type(), err)
+	}
+
+	for _, r := range list.Items {
+		kc.resources[r.Metadata().ID()] = r
+	}
+
+	go func() {
+		defer cancel()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-ch:
+				kc.apply(event)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// apply updates the cache with the event, and fans it out to the subscribers.
+func (kc *kindCache) apply(event state.Event) {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	id := event.Resource.Metadata().ID()
+	prev, exists := kc.resources[id]
+
+	switch event.Type {
+	case state.Created, state.Updated:
+		if exists {
+			if !isNewer(event.Resource, prev) {
+				return
+			}
+
+			event.Type = state.Updated
+
+			if event.Old == nil {
+				event.Old = prev
+			}
+		}
+
+		kc.resources[id] = event.Resource
+	case state.Destroyed:
+		if !exists {
+			return
+		}
+
+		delete(kc.resources, id)
+	}
+
+	for sub := range kc.subscribers {
+		if sub.id == "" || sub.id == id {
+			sub.push(event)
+		}
+	}
+}
+
+func (kc *kindCache) snapshot(query resource.LabelQuery) []resource.Resource {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	return kc.snapshotLocked(query)
+}
+
+// snapshotLocked should be called with kc.mu held.
+func (kc *kindCache) snapshotLocked(query resource.LabelQuery) []resource.Resource {
+	items := make([]resource.Resource, 0, len(kc.resources))
+
+	for _, r := range kc.resources {
+		if query.Matches(*r.Metadata().Labels()) {
+			items = append(items, r)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Metadata().ID() < items[j].Metadata().ID()
+	})
+
+	return items
+}
+
+func (kc *kindCache) unsubscribe(sub *subscriber) {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	delete(kc.subscribers, sub)
+}
+
+// subscriber queues the events for a single watcher, so that slow watchers don't block the others.
+type subscriber struct {
+	ch     chan<- state.Event
+	notify chan struct{}
+	id     resource.ID
+	queue  []state.Event
+	mu     sync.Mutex
+}
+
+func newSubscriber(id resource.ID, ch chan<- state.Event) *subscriber {
+	return &subscriber{
+		id:     id,
+		ch:     ch,
+		notify: make(chan struct{}, 1),
+	}
+}
+
+func (sub *subscriber) push(event state.Event) {
+	sub.mu.Lock()
+	sub.queue = append(sub.queue, event)
+	sub.mu.Unlock()
+
+	select {
+	case sub.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (sub *subscriber) run(ctx context.Context, kc *kindCache) {
+	defer kc.unsubscribe(sub)
+
+	for {
+		sub.mu.Lock()
+		queue := sub.queue
+		sub.queue = nil
+		sub.mu.Unlock()
+
+		for _, event := range queue {
+			select {
+			case sub.ch <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-sub.notify:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -22,13 +22,23 @@ import (
 
 // Adapter implement state.CoreState from the gRPC State client.
 type Adapter struct {
-	client v1alpha1.StateClient
+	client  v1alpha1.StateClient
+	options AdapterOptions
 }
 
 // NewAdapter returns new Adapter from the gRPC client.
-func NewAdapter(client v1alpha1.StateClient) *Adapter {
+//
+// COSI-level middlewares can be applied to the adapter with state.Chain.
+func NewAdapter(client v1alpha1.StateClient, opts ...AdapterOption) *Adapter {
+	options := DefaultAdapterOptions()
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	return &Adapter{
-		client: client,
+		client:  client,
+		options: options,
 	}
 }
 
@@ -42,31 +52,49 @@ func (adapter *Adapter) Get(ctx context.Context, resourcePointer resource.Pointe
 		o(&opts)
 	}
 
-	resp, err := adapter.client.Get(ctx, &v1alpha1.GetRequest{
-		Namespace: resourcePointer.Namespace(),
-		Type:      resourcePointer.Type(),
-		Id:        resourcePointer.ID(),
-		Options:   &v1alpha1.GetOptions{},
+	var resp *v1alpha1.GetResponse
+
+	err := adapter.retry(ctx, VerbGet, func() error {
+		var err error
+
+		resp, err = adapter.client.Get(ctx, &v1alpha1.GetRequest{
+			Namespace: resourcePointer.Namespace(),
+			Type:      resourcePointer.Type(),
+			Id:        resourcePointer.ID(),
+			Options:   &v1alpha1.GetOptions{},
+		})
+
+		return wrapTransportError(err)
 	})
 	if err != nil {
 		switch status.Code(err) { //nolint:exhaustive
 		case codes.NotFound:
 			return nil, eNotFound{err}
 		default:
-			return nil, err
+			return nil, wrapTransportError(err)
 		}
 	}
 
-	unmarshaled, err := protobuf.Unmarshal(resp.Resource)
-	if err != nil {
-		return nil, err
-	}
-
-	return protobuf.UnmarshalResource(unmarshaled)
+	return adapter.unmarshalResource(resp.Resource)
 }
 
 // List resources by type.
 func (adapter *Adapter) List(ctx context.Context, resourceKind resource.Kind, opt ...state.ListOption) (resource.List, error) {
+	list := resource.List{}
+
+	err := adapter.ListStream(ctx, resourceKind, func(r resource.Resource) bool {
+		list.Items = append(list.Items, r)
+
+		return true
+	}, opt...)
+
+	return list, err
+}
+
+// ListStream implements state.ListStreamer interface.
+//
+// Resources are yielded as they are received from the server.
+func (adapter *Adapter) ListStream(ctx context.Context, resourceKind resource.Kind, yield func(resource.Resource) bool, opt ...state.ListOption) error {
 	opts := state.ListOptions{}
 
 	for _, o := range opt {
@@ -80,49 +108,69 @@ func (adapter *Adapter) List(ctx context.Context, resourceKind resource.Kind, op
 
 		labelQuery, err = transformLabelQuery(opts.LabelQuery)
 		if err != nil {
-			return resource.List{}, err
+			return err
 		}
 	}
 
-	cli, err := adapter.client.List(ctx, &v1alpha1.ListRequest{
+	req := &v1alpha1.ListRequest{
 		Namespace: resourceKind.Namespace(),
 		Type:      resourceKind.Type(),
 		Options: &v1alpha1.ListOptions{
 			LabelQuery: labelQuery,
 		},
+	}
+
+	yielded := false
+
+	return adapter.retry(ctx, VerbList, func() error {
+		err := adapter.listStream(ctx, req, func(r resource.Resource) bool {
+			yielded = true
+
+			return yield(r)
+		})
+
+		// the resources which were already yielded can't be taken back
+		if err != nil && yielded {
+			return permanentError{err}
+		}
+
+		return err
 	})
+}
+
+func (adapter *Adapter) listStream(ctx context.Context, req *v1alpha1.ListRequest, yield func(resource.Resource) bool) error {
+	// stop receiving if the consumer stops early
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cli, err := adapter.client.List(ctx, req)
 	if err != nil {
 		switch status.Code(err) { //nolint:exhaustive
 		case codes.NotFound:
-			return resource.List{}, eNotFound{err}
+			return eNotFound{err}
 		default:
-			return resource.List{}, err
+			return wrapTransportError(err)
 		}
 	}
 
-	list := resource.List{}
-
 	for {
 		resp, err := cli.Recv()
 
 		switch {
 		case errors.Is(err, io.EOF):
-			return list, nil
+			return nil
 		case err != nil:
-			return list, err
+			return wrapTransportError(err)
 		}
 
-		unmarshaled, err := protobuf.Unmarshal(resp.Resource)
+		r, err := adapter.unmarshalResource(resp.Resource)
 		if err != nil {
-			return list, err
+			return err
 		}
 
-		r, err := protobuf.UnmarshalResource(unmarshaled)
-		if err != nil {
-			return list, err
+		if !yield(r) {
+			return nil
 		}
-
-		list.Items = append(list.Items, r)
 	}
 }
 
@@ -130,6 +178,17 @@ func (adapter *Adapter) List(ctx context.Context, resourceKind resource.Kind, op
 //
 // If a resource already exists, Create returns an error.
 func (adapter *Adapter) Create(ctx context.Context, r resource.Resource, opt ...state.CreateOption) error {
+	req, err := createRequest(r, opt)
+	if err != nil {
+		return err
+	}
+
+	_, err = adapter.client.Create(ctx, req)
+
+	return createError(err)
+}
+
+func createRequest(r resource.Resource, opt []state.CreateOption) (*v1alpha1.CreateRequest, error) {
 	opts := state.CreateOptions{}
 
 	for _, o := range opt {
@@ -138,36 +197,38 @@ func (adapter *Adapter) Create(ctx context.Context, r resource.Resource, opt ...
 
 	protoR, err := protobuf.FromResource(r)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	marshaled, err := protoR.Marshal()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	_, err = adapter.client.Create(ctx, &v1alpha1.CreateRequest{
+	return &v1alpha1.CreateRequest{
 		Resource: marshaled,
 
 		Options: &v1alpha1.CreateOptions{
 			Owner: opts.Owner,
 		},
-	})
+	}, nil
+}
 
-	if err != nil {
-		switch status.Code(err) { //nolint:exhaustive
-		case codes.NotFound:
-			return eNotFound{err}
-		case codes.PermissionDenied:
-			return eOwnerConflict{eConflict{err}}
-		case codes.AlreadyExists:
-			return eConflict{err}
-		default:
-			return err
-		}
+func createError(err error) error {
+	if err == nil {
+		return nil
 	}
 
-	return nil
+	switch status.Code(err) { //nolint:exhaustive
+	case codes.NotFound:
+		return eNotFound{err}
+	case codes.PermissionDenied:
+		return eOwnerConflict{eConflict{err}}
+	case codes.AlreadyExists:
+		return eConflict{err}
+	default:
+		return wrapTransportError(err)
+	}
 }
 
 // Update a resource.
@@ -176,6 +237,17 @@ func (adapter *Adapter) Create(ctx context.Context, r resource.Resource, opt ...
 // On update current version of resource `new` in the state should match
 // curVersion, otherwise conflict error is returned.
 func (adapter *Adapter) Update(ctx context.Context, curVersion resource.Version, newResource resource.Resource, opt ...state.UpdateOption) error {
+	req, err := updateRequest(curVersion, newResource, opt)
+	if err != nil {
+		return err
+	}
+
+	_, err = adapter.client.Update(ctx, req)
+
+	return updateError(err)
+}
+
+func updateRequest(curVersion resource.Version, newResource resource.Resource, opt []state.UpdateOption) (*v1alpha1.UpdateRequest, error) {
 	opts := state.DefaultUpdateOptions()
 
 	for _, o := range opt {
@@ -184,12 +256,12 @@ func (adapter *Adapter) Update(ctx context.Context, curVersion resource.Version,
 
 	protoR, err := protobuf.FromResource(newResource)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	marshaled, err := protoR.Marshal()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var expectedPhase *string
@@ -198,31 +270,49 @@ func (adapter *Adapter) Update(ctx context.Context, curVersion resource.Version,
 		expectedPhase = pointer.To(opts.ExpectedPhase.String())
 	}
 
-	_, err = adapter.client.Update(ctx, &v1alpha1.UpdateRequest{
+	return &v1alpha1.UpdateRequest{
 		CurrentVersion: curVersion.String(),
 		NewResource:    marshaled,
 		Options: &v1alpha1.UpdateOptions{
 			Owner:         opts.Owner,
 			ExpectedPhase: expectedPhase,
 		},
-	})
+	}, nil
+}
 
+func updateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch status.Code(err) { //nolint:exhaustive
+	case codes.NotFound:
+		return eNotFound{err}
+	case codes.PermissionDenied:
+		return eOwnerConflict{eConflict{err}}
+	case codes.InvalidArgument:
+		return ePhaseConflict{eConflict{err}}
+	case codes.FailedPrecondition:
+		return eConflict{err}
+	default:
+		return wrapTransportError(err)
+	}
+}
+
+// UpdateStatus implements state.StatusUpdater interface.
+func (adapter *Adapter) UpdateStatus(ctx context.Context, curVersion resource.Version, newResource resource.StatusResource, opt ...state.UpdateOption) error {
+	req, err := updateRequest(curVersion, newResource, opt)
 	if err != nil {
-		switch status.Code(err) { //nolint:exhaustive
-		case codes.NotFound:
-			return eNotFound{err}
-		case codes.PermissionDenied:
-			return eOwnerConflict{eConflict{err}}
-		case codes.InvalidArgument:
-			return ePhaseConflict{eConflict{err}}
-		case codes.FailedPrecondition:
-			return eConflict{err}
-		default:
-			return err
-		}
+		return err
 	}
 
-	return nil
+	_, err = adapter.client.UpdateStatus(ctx, &v1alpha1.UpdateStatusRequest{
+		CurrentVersion: req.CurrentVersion,
+		NewResource:    req.NewResource,
+		Options:        req.Options,
+	})
+
+	return updateError(err)
 }
 
 // Destroy a resource.
@@ -230,35 +320,106 @@ func (adapter *Adapter) Update(ctx context.Context, curVersion resource.Version,
 // If a resource doesn't exist, error is returned.
 // If a resource has pending finalizers, error is returned.
 func (adapter *Adapter) Destroy(ctx context.Context, resourcePointer resource.Pointer, opt ...state.DestroyOption) error {
+	_, err := adapter.client.Destroy(ctx, destroyRequest(resourcePointer, opt))
+
+	return destroyError(err)
+}
+
+func destroyRequest(resourcePointer resource.Pointer, opt []state.DestroyOption) *v1alpha1.DestroyRequest {
 	opts := state.DestroyOptions{}
 
 	for _, o := range opt {
 		o(&opts)
 	}
 
-	_, err := adapter.client.Destroy(ctx, &v1alpha1.DestroyRequest{
+	return &v1alpha1.DestroyRequest{
 		Namespace: resourcePointer.Namespace(),
 		Type:      resourcePointer.Type(),
 		Id:        resourcePointer.ID(),
 
 		Options: &v1alpha1.DestroyOptions{
-			Owner: opts.Owner,
+			Owner:  opts.Owner,
+			Reason: opts.Reason,
 		},
-	})
-	if err != nil {
-		switch status.Code(err) { //nolint:exhaustive
-		case codes.NotFound:
-			return eNotFound{err}
-		case codes.PermissionDenied:
-			return eOwnerConflict{eConflict{err}}
-		case codes.FailedPrecondition:
-			return eConflict{err}
-		default:
+	}
+}
+
+func destroyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch status.Code(err) { //nolint:exhaustive
+	case codes.NotFound:
+		return eNotFound{err}
+	case codes.PermissionDenied:
+		return eOwnerConflict{eConflict{err}}
+	case codes.FailedPrecondition:
+		return eConflict{err}
+	default:
+		return wrapTransportError(err)
+	}
+}
+
+// BatchCreate implements state.Batcher interface.
+//
+// The batch is sent to the server in a single call.
+func (adapter *Adapter) BatchCreate(ctx context.Context, items []state.BatchCreateItem) error {
+	req := &v1alpha1.BatchCreateRequest{
+		Requests: make([]*v1alpha1.CreateRequest, 0, len(items)),
+	}
+
+	for _, item := range items {
+		itemReq, err := createRequest(item.Resource, item.Options)
+		if err != nil {
 			return err
 		}
+
+		req.Requests = append(req.Requests, itemReq)
+	}
+
+	_, err := adapter.client.BatchCreate(ctx, req)
+
+	return createError(err)
+}
+
+// BatchUpdate implements state.Batcher interface.
+//
+// The batch is sent to the server in a single call.
+func (adapter *Adapter) BatchUpdate(ctx context.Context, items []state.BatchUpdateItem) error {
+	req := &v1alpha1.BatchUpdateRequest{
+		Requests: make([]*v1alpha1.UpdateRequest, 0, len(items)),
+	}
+
+	for _, item := range items {
+		itemReq, err := updateRequest(item.CurrentVersion, item.Resource, item.Options)
+		if err != nil {
+			return err
+		}
+
+		req.Requests = append(req.Requests, itemReq)
+	}
+
+	_, err := adapter.client.BatchUpdate(ctx, req)
+
+	return updateError(err)
+}
+
+// BatchDestroy implements state.Batcher interface.
+//
+// The batch is sent to the server in a single call.
+func (adapter *Adapter) BatchDestroy(ctx context.Context, items []state.BatchDestroyItem) error {
+	req := &v1alpha1.BatchDestroyRequest{
+		Requests: make([]*v1alpha1.DestroyRequest, 0, len(items)),
+	}
+
+	for _, item := range items {
+		req.Requests = append(req.Requests, destroyRequest(item.Pointer, item.Options))
 	}
 
-	return nil
+	_, err := adapter.client.BatchDestroy(ctx, req)
+
+	return destroyError(err)
 }
 
 // Watch state of a resource by type.
@@ -274,27 +435,16 @@ func (adapter *Adapter) Watch(ctx context.Context, resourcePointer resource.Poin
 		o(&opts)
 	}
 
-	cli, err := adapter.client.Watch(ctx, &v1alpha1.WatchRequest{
+	req := &v1alpha1.WatchRequest{
 		Namespace: resourcePointer.Namespace(),
 		Type:      resourcePointer.Type(),
 		Id:        pointer.To(resourcePointer.ID()),
 		Options: &v1alpha1.WatchOptions{
 			TailEvents: int32(opts.TailEvents),
 		},
-	})
-	if err != nil {
-		return err
-	}
-
-	// receive first (empty) watch event
-	_, err = cli.Recv()
-	if err != nil {
-		return err
 	}
 
-	go watchAdapter(ctx, cli, ch)
-
-	return nil
+	return adapter.watch(ctx, resourcePointer, req, ch)
 }
 
 // WatchKind watches resources of specific kind (namespace and type).
@@ -316,7 +466,7 @@ func (adapter *Adapter) WatchKind(ctx context.Context, resourceKind resource.Kin
 		}
 	}
 
-	cli, err := adapter.client.Watch(ctx, &v1alpha1.WatchRequest{
+	req := &v1alpha1.WatchRequest{
 		Namespace: resourceKind.Namespace(),
 		Type:      resourceKind.Type(),
 		Options: &v1alpha1.WatchOptions{
@@ -324,75 +474,9 @@ func (adapter *Adapter) WatchKind(ctx context.Context, resourceKind resource.Kin
 			TailEvents:        int32(opts.TailEvents),
 			LabelQuery:        labelQuery,
 		},
-	})
-	if err != nil {
-		return err
 	}
 
-	// receive first (empty) watch event
-	_, err = cli.Recv()
-	if err != nil {
-		return err
-	}
-
-	go watchAdapter(ctx, cli, ch)
-
-	return nil
-}
-
-func watchAdapter(ctx context.Context, cli v1alpha1.State_WatchClient, ch chan<- state.Event) {
-	for {
-		msg, err := cli.Recv()
-
-		switch {
-		case errors.Is(err, io.EOF):
-			return
-		case err != nil:
-			// no way to signal error here?
-			return
-		}
-
-		event := state.Event{}
-
-		switch msg.Event.EventType {
-		case v1alpha1.EventType_CREATED:
-			event.Type = state.Created
-		case v1alpha1.EventType_UPDATED:
-			event.Type = state.Updated
-		case v1alpha1.EventType_DESTROYED:
-			event.Type = state.Destroyed
-		}
-
-		unmarshaled, err := protobuf.Unmarshal(msg.Event.Resource)
-		if err != nil {
-			// no way to signal error here?
-			return
-		}
-
-		event.Resource, err = protobuf.UnmarshalResource(unmarshaled)
-		if err != nil {
-			// no way to signal error here?
-			return
-		}
-
-		if msg.Event.Old != nil {
-			unmarshaled, err = protobuf.Unmarshal(msg.Event.Old)
-			if err != nil {
-				// no way to signal error here?
-				return
-			}
-
-			event.Old, err = protobuf.UnmarshalResource(unmarshaled)
-			if err != nil {
-				// no way to signal error here?
-				return
-			}
-		}
-
-		select {
-		case ch <- event:
-		case <-ctx.Done():
-			return
-		}
-	}
+	return adapter.watch(ctx, resourceKind, req, ch, func(listOpts *state.ListOptions) {
+		listOpts.LabelQuery = opts.LabelQuery
+	})
 }
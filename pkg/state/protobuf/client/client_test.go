@@ -5,12 +5,34 @@
 package client_test
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
 
+	"github.com/cosi-project/runtime/api/v1alpha1"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
 	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/conformance"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
 	"github.com/cosi-project/runtime/pkg/state/protobuf/client"
+	"github.com/cosi-project/runtime/pkg/state/protobuf/server"
 )
 
 func TestInterfaces(t *testing.T) {
@@ -18,3 +40,629 @@ func TestInterfaces(t *testing.T) {
 
 	assert.Implements(t, (*state.CoreState)(nil), new(client.Adapter))
 }
+
+func serve(t *testing.T, sockPath string, st state.CoreState, opts ...grpc.ServerOption) *grpc.Server {
+	t.Helper()
+
+	l, err := net.Listen("unix", sockPath)
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer(opts...)
+	v1alpha1.RegisterStateServer(grpcServer, server.NewState(st))
+
+	go func() {
+		grpcServer.Serve(l) //nolint:errcheck
+	}()
+
+	return grpcServer
+}
+
+func TestWatchReconnect(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	sockPath := filepath.Join(t.TempDir(), "api.sock")
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	a := conformance.NewPathResource("default", "a")
+	c := conformance.NewPathResource("default", "c")
+
+	require.NoError(t, st.Create(ctx, a))
+	require.NoError(t, st.Create(ctx, c))
+
+	grpcServer := serve(t, sockPath, st)
+
+	grpcConn, err := grpc.Dial("unix://"+sockPath, grpc.WithInsecure()) //nolint:staticcheck
+	require.NoError(t, err)
+
+	defer grpcConn.Close() //nolint:errcheck
+
+	reconnected := make(chan resource.Kind, 1)
+
+	adapter := client.NewAdapter(v1alpha1.NewStateClient(grpcConn),
+		client.WithWatchReconnect(100*time.Millisecond),
+		client.WithWatchReconnectHandler(func(kind resource.Kind, _ error) {
+			reconnected <- kind
+		}),
+	)
+
+	ch := make(chan state.Event)
+
+	require.NoError(t, adapter.WatchKind(ctx, resource.NewMetadata("default", conformance.PathResourceType, "", resource.VersionUndefined), ch,
+		state.WithBootstrapContents(true)))
+
+	for _, id := range []resource.ID{"a", "c"} {
+		select {
+		case event := <-ch:
+			assert.Equal(t, state.Created, event.Type)
+			assert.Equal(t, id, event.Resource.Metadata().ID())
+		case <-ctx.Done():
+			t.Fatal("timeout")
+		}
+	}
+
+	grpcServer.Stop()
+
+	// changes while the watch is broken
+	_, err = st.UpdateWithConflicts(ctx, a.Metadata(), func(r resource.Resource) error {
+		r.Metadata().Labels().Set("updated", "")
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, st.Create(ctx, conformance.NewPathResource("default", "b")))
+	require.NoError(t, st.Destroy(ctx, c.Metadata()))
+
+	grpcServer = serve(t, sockPath, st)
+	defer grpcServer.Stop()
+
+	select {
+	case kind := <-reconnected:
+		assert.Equal(t, conformance.PathResourceType, kind.Type())
+	case <-ctx.Done():
+		t.Fatal("timeout")
+	}
+
+	for _, expected := range []struct {
+		id  resource.ID
+		typ state.EventType
+	}{
+		{"a", state.Updated},
+		{"b", state.Created},
+		{"c", state.Destroyed},
+	} {
+		select {
+		case event := <-ch:
+			assert.Equal(t, expected.typ, event.Type)
+			assert.Equal(t, expected.id, event.Resource.Metadata().ID())
+		case <-ctx.Done():
+			t.Fatal("timeout")
+		}
+	}
+
+	// events after the reconnect are delivered as usual
+	require.NoError(t, st.Create(ctx, conformance.NewPathResource("default", "d")))
+
+	select {
+	case event := <-ch:
+		assert.Equal(t, state.Created, event.Type)
+		assert.Equal(t, "d", event.Resource.Metadata().ID())
+	case <-ctx.Done():
+		t.Fatal("timeout")
+	}
+}
+
+type countingState struct {
+	state.CoreState
+
+	lists, watches int32
+}
+
+func (st *countingState) List(ctx context.Context, kind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	atomic.AddInt32(&st.lists, 1)
+
+	return st.CoreState.List(ctx, kind, opts...)
+}
+
+func (st *countingState) WatchKind(ctx context.Context, kind resource.Kind, ch chan<- state.Event, opts ...state.WatchKindOption) error {
+	atomic.AddInt32(&st.watches, 1)
+
+	return st.CoreState.WatchKind(ctx, kind, ch, opts...)
+}
+
+func TestCachedState(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	underlying := &countingState{CoreState: namespaced.NewState(inmem.Build)}
+	st := client.NewCachedState(ctx, underlying)
+
+	kind := resource.NewMetadata("default", conformance.PathResourceType, "", resource.VersionUndefined)
+
+	require.NoError(t, st.Create(ctx, conformance.NewPathResource("default", "a")))
+
+	list, err := st.List(ctx, kind)
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+
+	channels := make([]chan state.Event, 3)
+
+	for i := range channels {
+		channels[i] = make(chan state.Event)
+
+		require.NoError(t, st.WatchKind(ctx, kind, channels[i], state.WithBootstrapContents(true)))
+	}
+
+	b := conformance.NewPathResource("default", "b")
+	require.NoError(t, st.Create(ctx, b))
+
+	for _, ch := range channels {
+		for _, id := range []resource.ID{"a", "b"} {
+			select {
+			case event := <-ch:
+				assert.Equal(t, state.Created, event.Type)
+				assert.Equal(t, id, event.Resource.Metadata().ID())
+			case <-ctx.Done():
+				t.Fatal("timeout")
+			}
+		}
+	}
+
+	// the watch event has been delivered, so the cache has the resource
+	r, err := st.Get(ctx, b.Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, b.Metadata().Version(), r.Metadata().Version())
+
+	require.NoError(t, st.Destroy(ctx, b.Metadata()))
+
+	for _, ch := range channels {
+		select {
+		case event := <-ch:
+			assert.Equal(t, state.Destroyed, event.Type)
+		case <-ctx.Done():
+			t.Fatal("timeout")
+		}
+	}
+
+	_, err = st.Get(ctx, b.Metadata())
+	assert.True(t, state.IsNotFoundError(err))
+
+	list, err = st.List(ctx, kind)
+	require.NoError(t, err)
+	assert.Len(t, list.Items, 1)
+
+	assert.EqualValues(t, 1, atomic.LoadInt32(&underlying.lists))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&underlying.watches))
+}
+
+// countingCompressor wraps gzip compressor counting the compressed messages.
+type countingCompressor struct {
+	encoding.Compressor
+
+	count int32
+}
+
+func (c *countingCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	atomic.AddInt32(&c.count, 1)
+
+	return c.Compressor.Compress(w)
+}
+
+func (c *countingCompressor) Name() string {
+	return "counting"
+}
+
+var compressor = &countingCompressor{Compressor: encoding.GetCompressor("gzip")}
+
+func init() {
+	encoding.RegisterCompressor(compressor)
+}
+
+func TestTransportOptions(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	sockPath := filepath.Join(t.TempDir(), "api.sock")
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	grpcServer := serve(t, sockPath, st, server.GRPCServerOptions(
+		server.WithMaxMessageSize(1024, 1024),
+	)...)
+	defer grpcServer.Stop()
+
+	grpcConn, err := grpc.Dial("unix://"+sockPath, append(client.GRPCDialOptions(
+		client.WithCompression(compressor.Name()),
+		client.WithMaxMessageSize(1024, 0),
+	), grpc.WithInsecure())...) //nolint:staticcheck
+	require.NoError(t, err)
+
+	defer grpcConn.Close() //nolint:errcheck
+
+	adapter := client.NewAdapter(v1alpha1.NewStateClient(grpcConn))
+
+	small := conformance.NewPathResource("default", "small")
+	require.NoError(t, adapter.Create(ctx, small))
+
+	// both the request and the response are compressed
+	assert.EqualValues(t, 2, atomic.LoadInt32(&compressor.count))
+
+	large := conformance.NewPathResource("default", "large")
+	large.Metadata().Labels().Set("data", strings.Repeat("a", 4096))
+
+	// compressed message fits, but the server limit applies to the uncompressed size
+	err = adapter.Create(ctx, large)
+	require.Error(t, err)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// client receive limit
+	require.NoError(t, st.Create(ctx, large))
+
+	_, err = adapter.Get(ctx, large.Metadata())
+	require.Error(t, err)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+// flakyInterceptors fail the first calls of each method with Unavailable.
+type flakyInterceptors struct {
+	calls    map[string]*int32
+	failures int32
+}
+
+func newFlakyInterceptors(failures int32) *flakyInterceptors {
+	return &flakyInterceptors{
+		failures: failures,
+		calls: map[string]*int32{
+			"/cosi.resource.State/Get":   new(int32),
+			"/cosi.resource.State/List":  new(int32),
+			"/cosi.resource.State/Watch": new(int32),
+		},
+	}
+}
+
+func (f *flakyInterceptors) fail(method string) bool {
+	return atomic.AddInt32(f.calls[method], 1) <= f.failures
+}
+
+func (f *flakyInterceptors) count(method string) int32 {
+	return atomic.LoadInt32(f.calls["/cosi.resource.State/"+method])
+}
+
+func (f *flakyInterceptors) unary(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if f.fail(method) {
+		return status.Error(codes.Unavailable, "connection refused")
+	}
+
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+func (f *flakyInterceptors) stream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	if f.fail(method) {
+		return nil, status.Error(codes.Unavailable, "connection refused")
+	}
+
+	return streamer(ctx, desc, cc, method, opts...)
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	sockPath := filepath.Join(t.TempDir(), "api.sock")
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	a := conformance.NewPathResource("default", "a")
+	require.NoError(t, st.Create(ctx, a))
+
+	grpcServer := serve(t, sockPath, st)
+	defer grpcServer.Stop()
+
+	connect := func(flaky *flakyInterceptors, opts ...client.AdapterOption) *client.Adapter {
+		grpcConn, err := grpc.Dial("unix://"+sockPath, append(client.GRPCDialOptions(
+			client.WithUnaryInterceptors(flaky.unary),
+			client.WithStreamInterceptors(flaky.stream),
+		), grpc.WithInsecure())...) //nolint:staticcheck
+		require.NoError(t, err)
+
+		t.Cleanup(func() { grpcConn.Close() }) //nolint:errcheck
+
+		return client.NewAdapter(v1alpha1.NewStateClient(grpcConn), opts...)
+	}
+
+	policy := client.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 10 * time.Millisecond,
+	}
+
+	t.Run("no retries", func(t *testing.T) {
+		flaky := newFlakyInterceptors(1)
+		adapter := connect(flaky)
+
+		_, err := adapter.Get(ctx, a.Metadata())
+		require.Error(t, err)
+		assert.True(t, client.IsTransportError(err))
+		assert.False(t, state.IsNotFoundError(err))
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.EqualValues(t, 1, flaky.count("Get"))
+	})
+
+	t.Run("retries", func(t *testing.T) {
+		flaky := newFlakyInterceptors(2)
+		adapter := connect(flaky, client.WithRetry(policy))
+
+		r, err := adapter.Get(ctx, a.Metadata())
+		require.NoError(t, err)
+		assert.Equal(t, a.Metadata().ID(), r.Metadata().ID())
+		assert.EqualValues(t, 3, flaky.count("Get"))
+
+		list, err := adapter.List(ctx, a.Metadata())
+		require.NoError(t, err)
+		assert.Len(t, list.Items, 1)
+		assert.EqualValues(t, 3, flaky.count("List"))
+
+		ch := make(chan state.Event)
+
+		require.NoError(t, adapter.Watch(ctx, a.Metadata(), ch))
+		assert.EqualValues(t, 3, flaky.count("Watch"))
+
+		select {
+		case event := <-ch:
+			assert.Equal(t, state.Created, event.Type)
+		case <-ctx.Done():
+			t.Fatal("timeout")
+		}
+
+		// logical errors are not retried
+		_, err = adapter.Get(ctx, conformance.NewPathResource("default", "b").Metadata())
+		require.Error(t, err)
+		assert.True(t, state.IsNotFoundError(err))
+		assert.False(t, client.IsTransportError(err))
+		assert.EqualValues(t, 4, flaky.count("Get"))
+	})
+
+	t.Run("verb overrides", func(t *testing.T) {
+		flaky := newFlakyInterceptors(2)
+		adapter := connect(flaky, client.WithRetry(policy), client.WithVerbRetry(client.VerbList, client.RetryPolicy{}))
+
+		_, err := adapter.List(ctx, a.Metadata())
+		require.Error(t, err)
+		assert.True(t, client.IsTransportError(err))
+		assert.EqualValues(t, 1, flaky.count("List"))
+
+		_, err = adapter.Get(ctx, a.Metadata())
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, flaky.count("Get"))
+	})
+
+	t.Run("attempts exhausted", func(t *testing.T) {
+		flaky := newFlakyInterceptors(5)
+		adapter := connect(flaky, client.WithRetry(policy))
+
+		_, err := adapter.Get(ctx, a.Metadata())
+		require.Error(t, err)
+		assert.True(t, client.IsTransportError(err))
+		assert.EqualValues(t, 3, flaky.count("Get"))
+	})
+}
+
+type knownSpec = protobuf.ResourceSpec[v1alpha1.Metadata, *v1alpha1.Metadata]
+
+type knownRD struct{}
+
+func (knownRD) ResourceDefinition(resource.Metadata, knownSpec) meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type: "Known.test.cosi.dev",
+	}
+}
+
+type unknownSpec struct {
+	Name string `yaml:"name"`
+	Port int    `yaml:"port"`
+}
+
+func (spec unknownSpec) DeepCopy() unknownSpec { return spec }
+
+func (spec unknownSpec) MarshalProto() ([]byte, error) { return nil, nil }
+
+type unknownRD struct{}
+
+func (unknownRD) ResourceDefinition(resource.Metadata, unknownSpec) meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type: "Unknown.test.cosi.dev",
+	}
+}
+
+func TestTypedDecoding(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	require.NoError(t, protobuf.RegisterResource("Known.test.cosi.dev", &typed.Resource[knownSpec, knownRD]{}))
+
+	sockPath := filepath.Join(t.TempDir(), "api.sock")
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	known := typed.NewResource[knownSpec, knownRD](resource.NewMetadata("default", "Known.test.cosi.dev", "a", resource.VersionUndefined),
+		protobuf.NewResourceSpec(&v1alpha1.Metadata{Id: "known"}))
+	unknown := typed.NewResource[unknownSpec, unknownRD](resource.NewMetadata("default", "Unknown.test.cosi.dev", "b", resource.VersionUndefined),
+		unknownSpec{Name: "http", Port: 80})
+
+	require.NoError(t, st.Create(ctx, known))
+	require.NoError(t, st.Create(ctx, unknown))
+
+	grpcServer := serve(t, sockPath, st)
+	defer grpcServer.Stop()
+
+	grpcConn, err := grpc.Dial("unix://"+sockPath, grpc.WithInsecure()) //nolint:staticcheck
+	require.NoError(t, err)
+
+	defer grpcConn.Close() //nolint:errcheck
+
+	adapter := client.NewAdapter(v1alpha1.NewStateClient(grpcConn))
+
+	r, err := adapter.Get(ctx, known.Metadata())
+	require.NoError(t, err)
+	require.IsType(t, &typed.Resource[knownSpec, knownRD]{}, r)
+	assert.Equal(t, "known", r.(*typed.Resource[knownSpec, knownRD]).TypedSpec().Value.Id) //nolint:forcetypeassert
+
+	r, err = adapter.Get(ctx, unknown.Metadata())
+	require.NoError(t, err)
+	assert.IsType(t, &protobuf.Resource{}, r)
+
+	adapter = client.NewAdapter(v1alpha1.NewStateClient(grpcConn), client.WithUnknownAsAny())
+
+	r, err = adapter.Get(ctx, known.Metadata())
+	require.NoError(t, err)
+	assert.IsType(t, &typed.Resource[knownSpec, knownRD]{}, r)
+
+	list, err := adapter.List(ctx, unknown.Metadata())
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	require.IsType(t, &resource.Any{}, list.Items[0])
+
+	anyR := list.Items[0].(*resource.Any) //nolint:forcetypeassert
+	assert.Equal(t, unknown.Metadata().ID(), anyR.Metadata().ID())
+
+	name, err := anyR.GetString(".name")
+	require.NoError(t, err)
+	assert.Equal(t, "http", name)
+
+	port, err := anyR.GetInt(".port")
+	require.NoError(t, err)
+	assert.EqualValues(t, 80, port)
+
+	ch := make(chan state.Event)
+
+	require.NoError(t, adapter.WatchKind(ctx, unknown.Metadata(), ch, state.WithBootstrapContents(true)))
+
+	select {
+	case event := <-ch:
+		assert.IsType(t, &resource.Any{}, event.Resource)
+	case <-ctx.Done():
+		t.Fatal("timeout")
+	}
+}
+
+type deltaRD struct{}
+
+func (deltaRD) ResourceDefinition(resource.Metadata, knownSpec) meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type: "Delta.test.cosi.dev",
+	}
+}
+
+// deltaCountingStream counts the watch events sent with the spec delta.
+type deltaCountingStream struct {
+	grpc.ServerStream
+
+	deltas *int32
+}
+
+func (stream deltaCountingStream) SendMsg(m interface{}) error {
+	if resp, ok := m.(*v1alpha1.WatchResponse); ok && resp.GetEvent().GetDelta() != nil {
+		atomic.AddInt32(stream.deltas, 1)
+	}
+
+	return stream.ServerStream.SendMsg(m)
+}
+
+func TestWatchDeltas(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	require.NoError(t, protobuf.RegisterResource("Delta.test.cosi.dev", &typed.Resource[knownSpec, deltaRD]{}))
+
+	sockPath := filepath.Join(t.TempDir(), "api.sock")
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	var deltas int32
+
+	grpcServer := serve(t, sockPath, st, grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, deltaCountingStream{ServerStream: ss, deltas: &deltas})
+	}))
+	defer grpcServer.Stop()
+
+	grpcConn, err := grpc.Dial("unix://"+sockPath, grpc.WithInsecure()) //nolint:staticcheck
+	require.NoError(t, err)
+
+	defer grpcConn.Close() //nolint:errcheck
+
+	adapter := state.WrapCore(client.NewAdapter(v1alpha1.NewStateClient(grpcConn), client.WithWatchDeltas()))
+
+	labels := map[string]string{}
+
+	for i := 0; i < 100; i++ {
+		labels[fmt.Sprintf("label%d", i)] = strings.Repeat("x", 10)
+	}
+
+	r := typed.NewResource[knownSpec, deltaRD](resource.NewMetadata("default", "Delta.test.cosi.dev", "a", resource.VersionUndefined),
+		protobuf.NewResourceSpec(&v1alpha1.Metadata{Id: "delta", Owner: "owner", Labels: labels}))
+
+	require.NoError(t, st.Create(ctx, r))
+
+	ch := make(chan state.Event)
+
+	require.NoError(t, adapter.WatchKind(ctx, r.Metadata(), ch, state.WithBootstrapContents(true)))
+
+	receive := func() state.Event {
+		select {
+		case event := <-ch:
+			return event
+		case <-ctx.Done():
+			t.Fatal("timeout")
+		}
+
+		panic("unreachable")
+	}
+
+	event := receive()
+	assert.Equal(t, state.Created, event.Type)
+
+	for _, update := range []func(spec *v1alpha1.Metadata){
+		func(spec *v1alpha1.Metadata) { spec.Id = "changed" },
+		func(spec *v1alpha1.Metadata) { spec.Owner = "" },
+	} {
+		_, err = st.UpdateWithConflicts(ctx, r.Metadata(), func(res resource.Resource) error {
+			update(res.(*typed.Resource[knownSpec, deltaRD]).TypedSpec().Value) //nolint:forcetypeassert
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		var updated resource.Resource
+
+		updated, err = st.Get(ctx, r.Metadata())
+		require.NoError(t, err)
+
+		prev := event.Resource
+
+		event = receive()
+		assert.Equal(t, state.Updated, event.Type)
+		assert.True(t, resource.Equal(updated, event.Resource))
+		assert.True(t, resource.Equal(prev, event.Old))
+	}
+
+	assert.EqualValues(t, 2, atomic.LoadInt32(&deltas))
+
+	spec := event.Resource.(*typed.Resource[knownSpec, deltaRD]).TypedSpec().Value //nolint:forcetypeassert
+	assert.Equal(t, "changed", spec.Id)
+	assert.Empty(t, spec.Owner)
+	assert.Equal(t, labels, spec.Labels)
+}
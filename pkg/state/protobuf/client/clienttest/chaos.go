@@ -0,0 +1,383 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package clienttest provides helpers for testing the code built on top of the gRPC state client.
+package clienttest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cosi-project/runtime/api/v1alpha1"
+)
+
+// ChaosPolicy configures the network failures simulated by ChaosClient.
+//
+// Calls, messages and streams are counted per ChaosClient, so the failures are deterministic
+// for the same sequence of the calls.
+type ChaosPolicy struct {
+	// DropWatchAfter breaks each watch stream with codes.Unavailable after the number of events, if set.
+	DropWatchAfter int
+	// MaxDroppedWatches limits the number of the dropped watch streams, zero means no limit.
+	MaxDroppedWatches int
+
+	// FailReconnects fails the number of the watch calls after each dropped watch stream with codes.Unavailable.
+	FailReconnects int
+	// ReorderReconnects delivers the first events of the re-established watch streams in the reverse order.
+	//
+	// The events are held back until the number of events is received or the stream ends.
+	ReorderReconnects int
+
+	// PartialListAfter breaks each list stream with codes.Unavailable after the number of resources, if set.
+	PartialListAfter int
+	// MaxPartialLists limits the number of the broken list streams, zero means no limit.
+	MaxPartialLists int
+
+	// LatencySpikeEvery delays every n-th call or stream message by LatencySpike, if set.
+	LatencySpikeEvery int
+	LatencySpike      time.Duration
+}
+
+// ChaosStats counts the failures simulated by ChaosClient.
+type ChaosStats struct {
+	DroppedWatches   int
+	FailedReconnects int
+	ReorderedStreams int
+	PartialLists     int
+	LatencySpikes    int
+}
+
+// ChaosClient wraps v1alpha1.StateClient simulating the network failures.
+//
+// ChaosClient is intended for the tests which verify the retry and resume logic of the client:
+//
+//	adapter := client.NewAdapter(clienttest.NewChaosClient(v1alpha1.NewStateClient(conn), policy), client.WithWatchReconnect(time.Second))
+//
+// Watch streams opened after a watch stream was dropped are considered to be the reconnects.
+type ChaosClient struct {
+	v1alpha1.StateClient
+
+	policy ChaosPolicy
+
+	mu                sync.Mutex
+	stats             ChaosStats
+	messages          int
+	pendingFailures   int
+	pendingReconnects int
+}
+
+// NewChaosClient wraps the client with the failures configured by the policy.
+func NewChaosClient(client v1alpha1.StateClient, policy ChaosPolicy) *ChaosClient {
+	return &ChaosClient{
+		StateClient: client,
+		policy:      policy,
+	}
+}
+
+// Stats returns the failures simulated so far.
+func (chaos *ChaosClient) Stats() ChaosStats {
+	chaos.mu.Lock()
+	defer chaos.mu.Unlock()
+
+	return chaos.stats
+}
+
+// Get implements v1alpha1.StateClient.
+func (chaos *ChaosClient) Get(ctx context.Context, in *v1alpha1.GetRequest, opts ...grpc.CallOption) (*v1alpha1.GetResponse, error) {
+	if err := chaos.spike(ctx); err != nil {
+		return nil, err
+	}
+
+	return chaos.StateClient.Get(ctx, in, opts...)
+}
+
+// Create implements v1alpha1.StateClient.
+func (chaos *ChaosClient) Create(ctx context.Context, in *v1alpha1.CreateRequest, opts ...grpc.CallOption) (*v1alpha1.CreateResponse, error) {
+	if err := chaos.spike(ctx); err != nil {
+		return nil, err
+	}
+
+	return chaos.StateClient.Create(ctx, in, opts...)
+}
+
+// Update implements v1alpha1.StateClient.
+func (chaos *ChaosClient) Update(ctx context.Context, in *v1alpha1.UpdateRequest, opts ...grpc.CallOption) (*v1alpha1.UpdateResponse, error) {
+	if err := chaos.spike(ctx); err != nil {
+		return nil, err
+	}
+
+	return chaos.StateClient.Update(ctx, in, opts...)
+}
+
+// Destroy implements v1alpha1.StateClient.
+func (chaos *ChaosClient) Destroy(ctx context.Context, in *v1alpha1.DestroyRequest, opts ...grpc.CallOption) (*v1alpha1.DestroyResponse, error) {
+	if err := chaos.spike(ctx); err != nil {
+		return nil, err
+	}
+
+	return chaos.StateClient.Destroy(ctx, in, opts...)
+}
+
+// List implements v1alpha1.StateClient.
+func (chaos *ChaosClient) List(ctx context.Context, in *v1alpha1.ListRequest, opts ...grpc.CallOption) (v1alpha1.State_ListClient, error) {
+	if err := chaos.spike(ctx); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	cli, err := chaos.StateClient.List(ctx, in, opts...)
+	if err != nil {
+		cancel()
+
+		return nil, err
+	}
+
+	return &chaosListClient{
+		State_ListClient: cli,
+		chaos:            chaos,
+		ctx:              ctx,
+		cancel:           cancel,
+	}, nil
+}
+
+// Watch implements v1alpha1.StateClient.
+func (chaos *ChaosClient) Watch(ctx context.Context, in *v1alpha1.WatchRequest, opts ...grpc.CallOption) (v1alpha1.State_WatchClient, error) {
+	if err := chaos.spike(ctx); err != nil {
+		return nil, err
+	}
+
+	chaos.mu.Lock()
+
+	if chaos.pendingFailures > 0 {
+		chaos.pendingFailures--
+		chaos.stats.FailedReconnects++
+
+		chaos.mu.Unlock()
+
+		return nil, status.Error(codes.Unavailable, "chaos: reconnect failed")
+	}
+
+	reconnect := chaos.pendingReconnects > 0
+
+	if reconnect {
+		chaos.pendingReconnects--
+	}
+
+	chaos.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	cli, err := chaos.StateClient.Watch(ctx, in, opts...)
+	if err != nil {
+		cancel()
+
+		return nil, err
+	}
+
+	stream := &chaosWatchClient{
+		State_WatchClient: cli,
+		chaos:             chaos,
+		ctx:               ctx,
+		cancel:            cancel,
+	}
+
+	if reconnect && chaos.policy.ReorderReconnects > 1 {
+		stream.reorder = chaos.policy.ReorderReconnects
+	}
+
+	return stream, nil
+}
+
+// spike delays every n-th call or message.
+func (chaos *ChaosClient) spike(ctx context.Context) error {
+	if chaos.policy.LatencySpikeEvery <= 0 {
+		return nil
+	}
+
+	chaos.mu.Lock()
+
+	chaos.messages++
+	spike := chaos.messages%chaos.policy.LatencySpikeEvery == 0
+
+	if spike {
+		chaos.stats.LatencySpikes++
+	}
+
+	chaos.mu.Unlock()
+
+	if !spike {
+		return nil
+	}
+
+	timer := time.NewTimer(chaos.policy.LatencySpike)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// dropWatch records the dropped watch stream, and reports whether the stream should be dropped.
+func (chaos *ChaosClient) dropWatch() bool {
+	chaos.mu.Lock()
+	defer chaos.mu.Unlock()
+
+	if chaos.policy.MaxDroppedWatches > 0 && chaos.stats.DroppedWatches >= chaos.policy.MaxDroppedWatches {
+		return false
+	}
+
+	chaos.stats.DroppedWatches++
+	chaos.pendingFailures += chaos.policy.FailReconnects
+	chaos.pendingReconnects++
+
+	return true
+}
+
+// breakList records the broken list stream, and reports whether the stream should be broken.
+func (chaos *ChaosClient) breakList() bool {
+	chaos.mu.Lock()
+	defer chaos.mu.Unlock()
+
+	if chaos.policy.MaxPartialLists > 0 && chaos.stats.PartialLists >= chaos.policy.MaxPartialLists {
+		return false
+	}
+
+	chaos.stats.PartialLists++
+
+	return true
+}
+
+type chaosListClient struct {
+	v1alpha1.State_ListClient
+
+	chaos  *ChaosClient
+	ctx    context.Context //nolint:containedctx
+	cancel context.CancelFunc
+
+	received int
+	err      error
+}
+
+func (stream *chaosListClient) Recv() (*v1alpha1.ListResponse, error) {
+	if stream.err != nil {
+		return nil, stream.err
+	}
+
+	if err := stream.chaos.spike(stream.ctx); err != nil {
+		return nil, err
+	}
+
+	if after := stream.chaos.policy.PartialListAfter; after > 0 && stream.received >= after && stream.chaos.breakList() {
+		stream.cancel()
+		stream.err = status.Error(codes.Unavailable, "chaos: list stream broken")
+
+		return nil, stream.err
+	}
+
+	msg, err := stream.State_ListClient.Recv()
+	if err != nil {
+		stream.cancel()
+
+		return nil, err
+	}
+
+	stream.received++
+
+	return msg, nil
+}
+
+type chaosWatchClient struct {
+	v1alpha1.State_WatchClient
+
+	chaos  *ChaosClient
+	ctx    context.Context //nolint:containedctx
+	cancel context.CancelFunc
+
+	// held are the events held back to be delivered in the reverse order
+	held    []*v1alpha1.WatchResponse
+	reorder int
+
+	received int
+	err      error
+}
+
+func (stream *chaosWatchClient) Recv() (*v1alpha1.WatchResponse, error) {
+	if len(stream.held) > 0 && (stream.reorder == 0 || stream.err != nil) {
+		return stream.pop(), nil
+	}
+
+	if stream.err != nil {
+		return nil, stream.err
+	}
+
+	for {
+		msg, err := stream.recv()
+		if err != nil {
+			stream.err = err
+			stream.cancel()
+
+			if len(stream.held) > 0 {
+				return stream.pop(), nil
+			}
+
+			return nil, err
+		}
+
+		// the first empty message signals that the watch is ready, it's not an event
+		if stream.reorder == 0 || msg.Event == nil {
+			return msg, nil
+		}
+
+		stream.held = append(stream.held, msg)
+
+		if len(stream.held) == stream.reorder {
+			stream.reorder = 0
+
+			stream.chaos.mu.Lock()
+			stream.chaos.stats.ReorderedStreams++
+			stream.chaos.mu.Unlock()
+
+			return stream.pop(), nil
+		}
+	}
+}
+
+// recv receives the next message from the underlying stream, dropping the stream if configured.
+func (stream *chaosWatchClient) recv() (*v1alpha1.WatchResponse, error) {
+	if err := stream.chaos.spike(stream.ctx); err != nil {
+		return nil, err
+	}
+
+	if after := stream.chaos.policy.DropWatchAfter; after > 0 && stream.received >= after && stream.chaos.dropWatch() {
+		return nil, status.Error(codes.Unavailable, "chaos: watch stream dropped")
+	}
+
+	msg, err := stream.State_WatchClient.Recv()
+	if err != nil {
+		return nil, err
+	}
+
+	if msg.Event != nil {
+		stream.received++
+	}
+
+	return msg, nil
+}
+
+// pop returns the last held event.
+func (stream *chaosWatchClient) pop() *v1alpha1.WatchResponse {
+	msg := stream.held[len(stream.held)-1]
+	stream.held = stream.held[:len(stream.held)-1]
+
+	return msg
+}
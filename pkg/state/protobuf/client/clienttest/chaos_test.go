@@ -0,0 +1,236 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package clienttest_test
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/cosi-project/runtime/api/v1alpha1"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/conformance"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/cosi-project/runtime/pkg/state/protobuf/client"
+	"github.com/cosi-project/runtime/pkg/state/protobuf/client/clienttest"
+	"github.com/cosi-project/runtime/pkg/state/protobuf/server"
+)
+
+func init() {
+	if err := protobuf.RegisterResource(conformance.PathResourceType, &conformance.PathResource{}); err != nil {
+		panic(err)
+	}
+}
+
+func serve(t *testing.T, st state.CoreState) v1alpha1.StateClient {
+	t.Helper()
+
+	sockPath := filepath.Join(t.TempDir(), "api.sock")
+
+	l, err := net.Listen("unix", sockPath)
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer()
+	v1alpha1.RegisterStateServer(grpcServer, server.NewState(st))
+
+	go func() {
+		grpcServer.Serve(l) //nolint:errcheck
+	}()
+
+	t.Cleanup(grpcServer.Stop)
+
+	grpcConn, err := grpc.Dial("unix://"+sockPath, grpc.WithInsecure()) //nolint:staticcheck
+	require.NoError(t, err)
+
+	t.Cleanup(func() { grpcConn.Close() }) //nolint:errcheck
+
+	return v1alpha1.NewStateClient(grpcConn)
+}
+
+func TestChaosWatchResume(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	chaos := clienttest.NewChaosClient(serve(t, st), clienttest.ChaosPolicy{
+		DropWatchAfter:    2,
+		MaxDroppedWatches: 1,
+		FailReconnects:    2,
+		ReorderReconnects: 2,
+	})
+
+	reconnected := make(chan struct{}, 1)
+
+	adapter := client.NewAdapter(chaos,
+		client.WithWatchReconnect(50*time.Millisecond),
+		client.WithWatchReconnectHandler(func(resource.Kind, error) {
+			reconnected <- struct{}{}
+		}),
+	)
+
+	ch := make(chan state.Event)
+
+	require.NoError(t, adapter.WatchKind(ctx, resource.NewMetadata("default", conformance.PathResourceType, "", resource.VersionUndefined), ch))
+
+	a := conformance.NewPathResource("default", "a")
+
+	require.NoError(t, st.Create(ctx, a))
+	require.NoError(t, st.Create(ctx, conformance.NewPathResource("default", "b")))
+
+	for _, id := range []resource.ID{"a", "b"} {
+		select {
+		case event := <-ch:
+			assert.Equal(t, state.Created, event.Type)
+			assert.Equal(t, id, event.Resource.Metadata().ID())
+		case <-ctx.Done():
+			require.FailNow(t, "timeout")
+		}
+	}
+
+	// the watch stream is dropped after two events
+	select {
+	case <-reconnected:
+	case <-ctx.Done():
+		require.FailNow(t, "timeout")
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := st.UpdateWithConflicts(ctx, a.Metadata(), func(r resource.Resource) error {
+			r.Metadata().Labels().Set("step", strconv.Itoa(i))
+
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	final, err := st.Get(ctx, a.Metadata())
+	require.NoError(t, err)
+
+	// the events of the reconnected stream arrive in the reverse order, but the consumer never sees the stale version
+	var lastVersion uint64
+
+	for {
+		var event state.Event
+
+		select {
+		case event = <-ch:
+		case <-ctx.Done():
+			require.FailNow(t, "timeout")
+		}
+
+		assert.Equal(t, state.Updated, event.Type)
+		assert.Equal(t, "a", event.Resource.Metadata().ID())
+
+		version, err := strconv.ParseUint(event.Resource.Metadata().Version().String(), 10, 64)
+		require.NoError(t, err)
+		assert.Greater(t, version, lastVersion)
+
+		lastVersion = version
+
+		if event.Resource.Metadata().Version().Equal(final.Metadata().Version()) {
+			break
+		}
+	}
+
+	stats := chaos.Stats()
+	assert.Equal(t, 1, stats.DroppedWatches)
+	assert.Equal(t, 2, stats.FailedReconnects)
+
+	// the final version might be delivered by the resync before the reordered events
+	assert.Eventually(t, func() bool {
+		return chaos.Stats().ReorderedStreams == 1
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestChaosPartialList(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	for _, id := range []resource.ID{"a", "b"} {
+		require.NoError(t, st.Create(ctx, conformance.NewPathResource("default", id)))
+	}
+
+	chaos := clienttest.NewChaosClient(serve(t, st), clienttest.ChaosPolicy{
+		PartialListAfter: 1,
+		MaxPartialLists:  1,
+	})
+
+	adapter := client.NewAdapter(chaos, client.WithRetry(client.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 10 * time.Millisecond,
+	}))
+
+	kind := resource.NewMetadata("default", conformance.PathResourceType, "", resource.VersionUndefined)
+
+	// the resources which were already received can't be retried
+	_, err := adapter.List(ctx, kind)
+	require.Error(t, err)
+	assert.True(t, client.IsTransportError(err))
+
+	list, err := adapter.List(ctx, kind)
+	require.NoError(t, err)
+	assert.Len(t, list.Items, 2)
+
+	assert.Equal(t, 1, chaos.Stats().PartialLists)
+}
+
+func TestChaosLatencySpikes(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	a := conformance.NewPathResource("default", "a")
+	require.NoError(t, st.Create(ctx, a))
+
+	chaos := clienttest.NewChaosClient(serve(t, st), clienttest.ChaosPolicy{
+		LatencySpikeEvery: 2,
+		LatencySpike:      100 * time.Millisecond,
+	})
+
+	adapter := client.NewAdapter(chaos)
+
+	for i := 0; i < 4; i++ {
+		start := time.Now()
+
+		_, err := adapter.Get(ctx, a.Metadata())
+		require.NoError(t, err)
+
+		if i%2 == 1 {
+			assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+		}
+	}
+
+	assert.Equal(t, 2, chaos.Stats().LatencySpikes)
+
+	// spikes are canceled with the call context
+	spikeCtx, spikeCancel := context.WithCancel(ctx)
+	spikeCancel()
+
+	_, err := adapter.Get(ctx, a.Metadata())
+	require.NoError(t, err)
+
+	_, err = adapter.Get(spikeCtx, a.Metadata())
+	require.Error(t, err)
+}
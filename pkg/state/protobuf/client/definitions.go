@@ -0,0 +1,77 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package client
+
+import (
+	"context"
+
+	"github.com/cosi-project/runtime/api/v1alpha1"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/meta/spec"
+)
+
+// ResourceDefinitions lists the resource definitions registered in the state.
+//
+// ResourceDefinitions allows generic clients to discover the available resource types
+// without registering the meta resources for decoding.
+func (adapter *Adapter) ResourceDefinitions(ctx context.Context) ([]meta.ResourceDefinitionSpec, error) {
+	var resp *v1alpha1.ListResourceDefinitionsResponse
+
+	err := adapter.retry(ctx, VerbList, func() error {
+		var err error
+
+		resp, err = adapter.client.ListResourceDefinitions(ctx, &v1alpha1.ListResourceDefinitionsRequest{})
+
+		return wrapTransportError(err)
+	})
+	if err != nil {
+		return nil, wrapTransportError(err)
+	}
+
+	result := make([]meta.ResourceDefinitionSpec, 0, len(resp.GetDefinitions()))
+
+	for _, definition := range resp.GetDefinitions() {
+		result = append(result, transformResourceDefinition(definition))
+	}
+
+	return result, nil
+}
+
+func transformResourceDefinition(definition *v1alpha1.ResourceDefinition) meta.ResourceDefinitionSpec {
+	result := meta.ResourceDefinitionSpec{
+		Type:             definition.GetType(),
+		DisplayType:      definition.GetDisplayType(),
+		Singular:         definition.GetSingular(),
+		Plural:           definition.GetPlural(),
+		DefaultNamespace: definition.GetDefaultNamespace(),
+		Aliases:          definition.GetAliases(),
+		AllAliases:       definition.GetAllAliases(),
+		Categories:       definition.GetCategories(),
+		Sensitivity:      spec.Sensitivity(definition.GetSensitivity()),
+		Deprecated:       definition.GetDeprecated(),
+		ReplacedBy:       definition.GetReplacedBy(),
+		RemovalVersion:   definition.GetRemovalVersion(),
+		IndexedLabels:    definition.GetIndexedLabels(),
+	}
+
+	for _, column := range definition.GetPrintColumns() {
+		result.PrintColumns = append(result.PrintColumns, meta.PrintColumn{
+			Name:     column.GetName(),
+			JSONPath: column.GetJsonPath(),
+			Format:   meta.ColumnFormat(column.GetFormat()),
+			Priority: int(column.GetPriority()),
+			Width:    int(column.GetWidth()),
+		})
+	}
+
+	for _, phase := range definition.GetCustomPhases() {
+		result.CustomPhases = append(result.CustomPhases, meta.CustomPhase{
+			Name:        phase.GetName(),
+			Transitions: phase.GetTransitions(),
+		})
+	}
+
+	return result
+}
@@ -4,6 +4,13 @@
 
 package client
 
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
 type eNotFound struct {
 	error
 }
@@ -27,3 +34,39 @@ type ePhaseConflict struct {
 }
 
 func (ePhaseConflict) PhaseConflictError() {}
+
+// eTransport is a failure to reach the server, the call can be retried.
+type eTransport struct {
+	error
+}
+
+func (eTransport) TransportError() {}
+
+// GRPCStatus keeps the status of the wrapped error accessible with status.Code.
+func (e eTransport) GRPCStatus() *status.Status {
+	return status.Convert(e.error)
+}
+
+func (e eTransport) Unwrap() error {
+	return e.error
+}
+
+// IsTransportError checks if the call failed to reach the server (e.g. the server is unavailable), and can be retried.
+//
+// Other errors are logical errors returned by the server (e.g. not found or conflict), retrying them doesn't change the outcome.
+func IsTransportError(err error) bool {
+	var transportErr interface {
+		TransportError()
+	}
+
+	return errors.As(err, &transportErr)
+}
+
+// wrapTransportError wraps the errors caused by the unavailable server as transport errors.
+func wrapTransportError(err error) error {
+	if err != nil && status.Code(err) == codes.Unavailable {
+		return eTransport{err}
+	}
+
+	return err
+}
@@ -0,0 +1,260 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	_ "google.golang.org/grpc/encoding/gzip" // register gzip compressor
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+// AdapterOptions configure Adapter.
+type AdapterOptions struct {
+	// OnWatchReconnect is called when the broken watch is re-established.
+	OnWatchReconnect func(kind resource.Kind, cause error)
+
+	// VerbRetryPolicies override RetryPolicy for the specific verbs.
+	VerbRetryPolicies map[Verb]RetryPolicy
+	RetryPolicy       RetryPolicy
+
+	WatchReconnect           bool
+	WatchReconnectMaxBackoff time.Duration
+
+	WatchDeltas bool
+
+	UnknownAsAny bool
+}
+
+// AdapterOption applies settings to AdapterOptions.
+type AdapterOption func(options *AdapterOptions)
+
+// WithWatchReconnect re-establishes the watches broken by the connection errors.
+//
+// After the watch is re-established, the current state is fetched again, and the events
+// which were already delivered are skipped, so the consumer sees the changes it missed.
+// Reconnect attempts are retried with an exponential backoff up to the maxBackoff interval
+// until the watch context is canceled.
+func WithWatchReconnect(maxBackoff time.Duration) AdapterOption {
+	return func(options *AdapterOptions) {
+		options.WatchReconnect = true
+		options.WatchReconnectMaxBackoff = maxBackoff
+	}
+}
+
+// WithWatchReconnectHandler sets the function called each time a watch is re-established, before the missed changes are delivered.
+//
+// The handler receives the kind (or the pointer for single resource watches) and the error which broke the watch.
+func WithWatchReconnectHandler(handler func(kind resource.Kind, cause error)) AdapterOption {
+	return func(options *AdapterOptions) {
+		options.OnWatchReconnect = handler
+	}
+}
+
+// WithRetry retries the idempotent calls (Get, List and Watch setup) which failed with the transport errors.
+//
+// Logical errors (e.g. not found) are never retried, List is not retried once some resources were yielded,
+// and the broken watches are re-established with WithWatchReconnect.
+// Default value is no retries.
+func WithRetry(policy RetryPolicy) AdapterOption {
+	return func(options *AdapterOptions) {
+		options.RetryPolicy = policy
+	}
+}
+
+// WithVerbRetry overrides the retry policy for the verb.
+func WithVerbRetry(verb Verb, policy RetryPolicy) AdapterOption {
+	return func(options *AdapterOptions) {
+		if options.VerbRetryPolicies == nil {
+			options.VerbRetryPolicies = map[Verb]RetryPolicy{}
+		}
+
+		options.VerbRetryPolicies[verb] = policy
+	}
+}
+
+// WithUnknownAsAny decodes the resources of the types which are not registered with protobuf.RegisterResource as *resource.Any.
+//
+// Resources of the registered types are always decoded into the typed resources.
+// By default the resources of the unknown types are returned as *protobuf.Resource, which can be written back to the state,
+// while *resource.Any gives generic access to the decoded spec, but can't be written back.
+func WithUnknownAsAny() AdapterOption {
+	return func(options *AdapterOptions) {
+		options.UnknownAsAny = true
+	}
+}
+
+// WithWatchDeltas requests the watch Updated events to carry the spec delta against the previous version
+// of the resource instead of the full resource, which cuts the bandwidth for large specs changed incrementally.
+//
+// The server sends the delta only for specs backed by protobuf messages (see protobuf.ResourceSpec), and only if it is smaller
+// than the full spec. The watched resource types should be registered with protobuf.RegisterResource,
+// as the spec is rebuilt from the previous version of the typed resource.
+func WithWatchDeltas() AdapterOption {
+	return func(options *AdapterOptions) {
+		options.WatchDeltas = true
+	}
+}
+
+// DefaultAdapterOptions returns default value of AdapterOptions.
+func DefaultAdapterOptions() AdapterOptions {
+	return AdapterOptions{
+		WatchReconnectMaxBackoff: 30 * time.Second,
+	}
+}
+
+// GRPCOptions configure the gRPC client connection to the State service.
+type GRPCOptions struct {
+	KeepaliveParams *keepalive.ClientParameters
+
+	Token       string
+	Compression string
+
+	UnaryInterceptors  []grpc.UnaryClientInterceptor
+	StreamInterceptors []grpc.StreamClientInterceptor
+
+	MaxRecvMsgSize int
+	MaxSendMsgSize int
+}
+
+// GRPCOption applies settings to GRPCOptions.
+type GRPCOption func(options *GRPCOptions)
+
+// WithToken sends the bearer token in the `authorization` metadata of each call.
+//
+// Default value is empty (no token is sent).
+func WithToken(token string) GRPCOption {
+	return func(options *GRPCOptions) {
+		options.Token = token
+	}
+}
+
+// WithUnaryInterceptors adds the unary interceptors to the client connection.
+func WithUnaryInterceptors(interceptors ...grpc.UnaryClientInterceptor) GRPCOption {
+	return func(options *GRPCOptions) {
+		options.UnaryInterceptors = append(options.UnaryInterceptors, interceptors...)
+	}
+}
+
+// WithStreamInterceptors adds the stream interceptors to the client connection.
+func WithStreamInterceptors(interceptors ...grpc.StreamClientInterceptor) GRPCOption {
+	return func(options *GRPCOptions) {
+		options.StreamInterceptors = append(options.StreamInterceptors, interceptors...)
+	}
+}
+
+// WithMaxMessageSize sets the maximum size of the messages the client can receive and send.
+//
+// Default value is 0 (gRPC defaults are used: 4MB for received messages, unlimited for sent messages).
+func WithMaxMessageSize(recv, send int) GRPCOption {
+	return func(options *GRPCOptions) {
+		options.MaxRecvMsgSize = recv
+		options.MaxSendMsgSize = send
+	}
+}
+
+// WithKeepalive sets the keepalive parameters of the client connection.
+//
+// Keepalive pings should be allowed by the server keepalive policy, otherwise the server closes the connection.
+func WithKeepalive(params keepalive.ClientParameters) GRPCOption {
+	return func(options *GRPCOptions) {
+		options.KeepaliveParams = &params
+	}
+}
+
+// WithCompression compresses the calls with the compressor registered under the name.
+//
+// gzip compressor is always registered, other compressors (e.g. zstd) should be registered with encoding.RegisterCompressor
+// both on the client and on the server.
+// Default value is empty (no compression).
+func WithCompression(name string) GRPCOption {
+	return func(options *GRPCOptions) {
+		options.Compression = name
+	}
+}
+
+// GRPCDialOptions builds the gRPC dial options which set up the authentication, the interceptors and the transport limits.
+//
+// The options should be passed to grpc.Dial which connects to the State service.
+func GRPCDialOptions(opts ...GRPCOption) []grpc.DialOption {
+	var options GRPCOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var dialOpts []grpc.DialOption
+
+	if options.Token != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(tokenCredentials(options.Token)))
+	}
+
+	var callOpts []grpc.CallOption
+
+	if options.MaxRecvMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(options.MaxRecvMsgSize))
+	}
+
+	if options.MaxSendMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallSendMsgSize(options.MaxSendMsgSize))
+	}
+
+	if options.Compression != "" {
+		callOpts = append(callOpts, grpc.UseCompressor(options.Compression))
+	}
+
+	if len(callOpts) > 0 {
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(callOpts...))
+	}
+
+	if options.KeepaliveParams != nil {
+		dialOpts = append(dialOpts, grpc.WithKeepaliveParams(*options.KeepaliveParams))
+	}
+
+	if len(options.UnaryInterceptors) > 0 {
+		dialOpts = append(dialOpts, grpc.WithChainUnaryInterceptor(options.UnaryInterceptors...))
+	}
+
+	if len(options.StreamInterceptors) > 0 {
+		dialOpts = append(dialOpts, grpc.WithChainStreamInterceptor(options.StreamInterceptors...))
+	}
+
+	return dialOpts
+}
+
+// ContextWithImpersonation makes the calls with the context act as the identity with the groups.
+//
+// The server should allow the client to impersonate the identity, otherwise the calls are rejected.
+func ContextWithImpersonation(ctx context.Context, name string, groups ...string) context.Context {
+	kv := make([]string, 0, 2*(len(groups)+1))
+	kv = append(kv, "cosi-impersonate-user", name)
+
+	for _, group := range groups {
+		kv = append(kv, "cosi-impersonate-group", group)
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+type tokenCredentials string
+
+// GetRequestMetadata implements credentials.PerRPCCredentials.
+func (token tokenCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{
+		"authorization": "Bearer " + string(token),
+	}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials.
+//
+// Transport security is not required, so that the token can be used over local connections (e.g. unix sockets).
+func (token tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
@@ -0,0 +1,81 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// Verb is an idempotent call of the Adapter which can be retried.
+type Verb string
+
+// Verb constants.
+const (
+	VerbGet   Verb = "get"
+	VerbList  Verb = "list"
+	VerbWatch Verb = "watch"
+)
+
+// RetryPolicy configures the retries of the calls failed with the transport errors.
+//
+// Calls are retried with an exponential backoff until the attempts are exhausted or the call context is canceled.
+type RetryPolicy struct {
+	// MaxAttempts is the number of the attempts including the first one, values below 2 disable the retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, if zero, the default of 500ms is used.
+	InitialBackoff time.Duration
+	// MaxBackoff limits the delay between the retries, if zero, the default of 60s is used.
+	MaxBackoff time.Duration
+}
+
+// retry runs the call until it succeeds, fails with an error which is not a transport error, or the retry policy of the verb is exhausted.
+func (adapter *Adapter) retry(ctx context.Context, verb Verb, call func() error) error {
+	policy, ok := adapter.options.VerbRetryPolicies[verb]
+	if !ok {
+		policy = adapter.options.RetryPolicy
+	}
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 0
+
+	if policy.InitialBackoff > 0 {
+		b.InitialInterval = policy.InitialBackoff
+	}
+
+	if policy.MaxBackoff > 0 {
+		b.MaxInterval = policy.MaxBackoff
+	}
+
+	b.Reset()
+
+	for attempt := 1; ; attempt++ {
+		err := call()
+
+		var permanent permanentError
+
+		if errors.As(err, &permanent) {
+			return permanent.error
+		}
+
+		if err == nil || !IsTransportError(err) || attempt >= policy.MaxAttempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-backoffTimer(b):
+		}
+	}
+}
+
+// permanentError stops the retries of the call, e.g. if the stream has already delivered some results.
+type permanentError struct {
+	error
+}
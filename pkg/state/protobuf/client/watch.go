@@ -0,0 +1,382 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/cosi-project/runtime/api/v1alpha1"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf/merge"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// watcher adapts the gRPC watch stream to the state events.
+type watcher struct {
+	adapter *Adapter
+	kind    resource.Kind
+	req     *v1alpha1.WatchRequest
+	ch      chan<- state.Event
+
+	// listOpts are used to fetch the current state of the kind after reconnect.
+	listOpts []state.ListOption
+
+	// known tracks the last delivered state of the resources, if watch reconnect is enabled.
+	known map[resource.ID]resource.Resource
+	// resumed is set after the first reconnect, events are deduplicated against the known resources after that.
+	resumed bool
+
+	// last tracks the last received version of the resources, if watch deltas are enabled.
+	last map[resource.ID]resource.Resource
+}
+
+func (adapter *Adapter) watch(ctx context.Context, kind resource.Kind, req *v1alpha1.WatchRequest, ch chan<- state.Event, listOpts ...state.ListOption) error {
+	req.Options.Deltas = adapter.options.WatchDeltas
+
+	var cli v1alpha1.State_WatchClient
+
+	err := adapter.retry(ctx, VerbWatch, func() error {
+		var err error
+
+		cli, err = adapter.openWatch(ctx, req)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	w := &watcher{
+		adapter:  adapter,
+		kind:     kind,
+		req:      req,
+		ch:       ch,
+		listOpts: listOpts,
+	}
+
+	if adapter.options.WatchReconnect {
+		w.known = map[resource.ID]resource.Resource{}
+	}
+
+	if adapter.options.WatchDeltas {
+		w.last = map[resource.ID]resource.Resource{}
+	}
+
+	go w.run(ctx, cli)
+
+	return nil
+}
+
+func (adapter *Adapter) openWatch(ctx context.Context, req *v1alpha1.WatchRequest) (v1alpha1.State_WatchClient, error) {
+	cli, err := adapter.client.Watch(ctx, req)
+	if err != nil {
+		return nil, wrapTransportError(err)
+	}
+
+	// receive first (empty) watch event
+	if _, err = cli.Recv(); err != nil {
+		return nil, wrapTransportError(err)
+	}
+
+	return cli, nil
+}
+
+func (w *watcher) run(ctx context.Context, cli v1alpha1.State_WatchClient) {
+	for {
+		err := w.receive(ctx, cli)
+
+		// err is nil if the watch was stopped, and it shouldn't be re-established
+		if err == nil || ctx.Err() != nil || w.known == nil {
+			return
+		}
+
+		cli = w.reconnect(ctx, err)
+		if cli == nil {
+			return
+		}
+	}
+}
+
+// receive delivers the events until the stream breaks, and returns the stream error.
+func (w *watcher) receive(ctx context.Context, cli v1alpha1.State_WatchClient) error {
+	for {
+		msg, err := cli.Recv()
+		if err != nil {
+			return err
+		}
+
+		event := state.Event{}
+
+		switch msg.Event.EventType {
+		case v1alpha1.EventType_CREATED:
+			event.Type = state.Created
+		case v1alpha1.EventType_UPDATED:
+			event.Type = state.Updated
+		case v1alpha1.EventType_DESTROYED:
+			event.Type = state.Destroyed
+		}
+
+		if destroyInfo := msg.Event.GetDestroy(); destroyInfo != nil {
+			event.Destroy = &state.DestroyInfo{
+				Timestamp: destroyInfo.GetTimestamp().AsTime(),
+				Owner:     destroyInfo.GetOwner(),
+				Reason:    destroyInfo.GetReason(),
+			}
+		}
+
+		if delta := msg.Event.GetDelta(); delta != nil {
+			event.Resource, event.Old, err = w.applyDelta(msg.Event.Resource, delta)
+			if err != nil {
+				// the watch can't continue without the previous version, so re-establish it if reconnect is enabled
+				return err
+			}
+		} else {
+			event.Resource, err = w.adapter.unmarshalResource(msg.Event.Resource)
+			if err != nil {
+				// no way to signal error here?
+				return nil
+			}
+
+			if msg.Event.Old != nil {
+				event.Old, err = w.adapter.unmarshalResource(msg.Event.Old)
+				if err != nil {
+					// no way to signal error here?
+					return nil
+				}
+			}
+		}
+
+		if w.last != nil {
+			if event.Type == state.Destroyed {
+				delete(w.last, event.Resource.Metadata().ID())
+			} else {
+				w.last[event.Resource.Metadata().ID()] = event.Resource
+			}
+		}
+
+		if !w.deliver(ctx, event) {
+			return nil
+		}
+	}
+}
+
+// specMessage is implemented by the specs backed by protobuf messages (see protobuf.ResourceSpec).
+type specMessage interface {
+	GetValue() proto.Message
+}
+
+// applyDelta rebuilds the updated resource by applying the spec delta to the previous version of the resource.
+//
+// The updated and the previous versions of the resource are returned.
+func (w *watcher) applyDelta(protoR *v1alpha1.Resource, delta *v1alpha1.SpecDelta) (resource.Resource, resource.Resource, error) {
+	id := protoR.GetMetadata().GetId()
+
+	prev, ok := w.last[id]
+	if !ok {
+		return nil, nil, fmt.Errorf("received delta for %q without the previous version", id)
+	}
+
+	prevSpec, ok := prev.Spec().(specMessage)
+	if !ok {
+		return nil, nil, fmt.Errorf("spec of %s is not a protobuf message, delta can't be applied", prev)
+	}
+
+	spec := proto.Clone(prevSpec.GetValue())
+	patch := spec.ProtoReflect().New().Interface()
+
+	if err := protobuf.ProtoUnmarshal(delta.GetPatch(), patch); err != nil {
+		return nil, nil, err
+	}
+
+	if err := merge.Apply(spec, merge.Delta{Patch: patch, Cleared: delta.GetCleared()}); err != nil {
+		return nil, nil, fmt.Errorf("error applying delta to %s: %w", prev, err)
+	}
+
+	specBytes, err := protobuf.ProtoMarshal(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, err := w.adapter.unmarshalResource(&v1alpha1.Resource{
+		Metadata: protoR.GetMetadata(),
+		Spec: &v1alpha1.Spec{
+			ProtoSpec: specBytes,
+		},
+		Status: protoR.GetStatus(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return r, prev, nil
+}
+
+// reconnect re-establishes the watch, and delivers the changes missed while the watch was broken.
+//
+// If the context is canceled, nil is returned.
+func (w *watcher) reconnect(ctx context.Context, cause error) v1alpha1.State_WatchClient {
+	req := proto.Clone(w.req).(*v1alpha1.WatchRequest) //nolint:forcetypeassert,errcheck
+
+	// the current state is fetched with List, so neither tail events nor bootstrap contents are needed
+	req.Options.TailEvents = 0
+	req.Options.BootstrapContents = false
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 0
+	b.MaxInterval = w.adapter.options.WatchReconnectMaxBackoff
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-backoffTimer(b):
+		}
+
+		cli, err := w.adapter.openWatch(ctx, req)
+		if err != nil {
+			continue
+		}
+
+		w.resumed = true
+
+		if w.adapter.options.OnWatchReconnect != nil {
+			w.adapter.options.OnWatchReconnect(w.kind, cause)
+		}
+
+		// single resource watches send the current state of the resource as the first event
+		if req.Id == nil {
+			if err = w.resync(ctx); err != nil {
+				continue
+			}
+		}
+
+		return cli
+	}
+}
+
+// resync fetches the current state of the kind, and delivers the differences from the known state.
+func (w *watcher) resync(ctx context.Context) error {
+	list, err := w.adapter.List(ctx, w.kind, w.listOpts...)
+	if err != nil {
+		return err
+	}
+
+	present := make(map[resource.ID]struct{}, len(list.Items))
+
+	for _, r := range list.Items {
+		present[r.Metadata().ID()] = struct{}{}
+
+		if !w.deliver(ctx, state.Event{Type: state.Created, Resource: r}) {
+			return nil
+		}
+	}
+
+	var destroyed []resource.Resource
+
+	for id, r := range w.known {
+		if _, ok := present[id]; !ok {
+			destroyed = append(destroyed, r)
+		}
+	}
+
+	for _, r := range destroyed {
+		if !w.deliver(ctx, state.Event{Type: state.Destroyed, Resource: r}) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// deliver sends the event to the channel, skipping the events already delivered before the reconnect.
+//
+// If the context is canceled, false is returned.
+func (w *watcher) deliver(ctx context.Context, event state.Event) bool {
+	if w.known != nil {
+		id := event.Resource.Metadata().ID()
+		prev, exists := w.known[id]
+
+		switch event.Type {
+		case state.Created, state.Updated:
+			if w.resumed && exists {
+				if !isNewer(event.Resource, prev) {
+					return true
+				}
+
+				event.Type = state.Updated
+
+				if event.Old == nil {
+					event.Old = prev
+				}
+			}
+
+			w.known[id] = event.Resource
+		case state.Destroyed:
+			if w.resumed && !exists {
+				return true
+			}
+
+			delete(w.known, id)
+		}
+	}
+
+	select {
+	case w.ch <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// unmarshalResource decodes the resource into the typed resource registered with protobuf.RegisterResource.
+//
+// Resources of the unknown types are returned as *protobuf.Resource, or as *resource.Any if WithUnknownAsAny is set.
+func (adapter *Adapter) unmarshalResource(protoR *v1alpha1.Resource) (resource.Resource, error) { //nolint:ireturn
+	unmarshaled, err := protobuf.Unmarshal(protoR)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := protobuf.UnmarshalResource(unmarshaled)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, unknown := r.(*protobuf.Resource); unknown && adapter.options.UnknownAsAny {
+		return resource.NewAnyFromProto(protoR.GetMetadata(), yamlSpec(protoR.GetSpec().GetYamlSpec()))
+	}
+
+	return r, nil
+}
+
+// yamlSpec adapts the YAML spec of the resource to resource.SpecProto.
+type yamlSpec string
+
+func (spec yamlSpec) GetYaml() []byte {
+	return []byte(spec)
+}
+
+// isNewer compares the versions of the resources.
+func isNewer(r, prev resource.Resource) bool {
+	ver, err1 := strconv.ParseUint(r.Metadata().Version().String(), 10, 64)
+	prevVer, err2 := strconv.ParseUint(prev.Metadata().Version().String(), 10, 64)
+
+	if err1 != nil || err2 != nil {
+		return !r.Metadata().Version().Equal(prev.Metadata().Version())
+	}
+
+	return ver > prevVer
+}
+
+func backoffTimer(b backoff.BackOff) <-chan time.Time {
+	return time.After(b.NextBackOff())
+}
@@ -5,24 +5,51 @@
 package protobuf_test
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 
 	"github.com/cosi-project/runtime/api/v1alpha1"
 	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
 	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+	"github.com/cosi-project/runtime/pkg/safe"
 	"github.com/cosi-project/runtime/pkg/state"
 	"github.com/cosi-project/runtime/pkg/state/conformance"
 	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
 	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
 	"github.com/cosi-project/runtime/pkg/state/protobuf/client"
 	"github.com/cosi-project/runtime/pkg/state/protobuf/server"
+	"github.com/cosi-project/runtime/pkg/state/registry"
 )
 
 func TestProtobufConformance(t *testing.T) {
@@ -59,3 +86,1294 @@ func TestProtobufConformance(t *testing.T) {
 		Namespaces: []resource.Namespace{"default", "controller", "system", "runtime"},
 	})
 }
+
+type secretSpec = protobuf.ResourceSpec[v1alpha1.Metadata, *v1alpha1.Metadata]
+
+type secretRD struct{}
+
+func (secretRD) ResourceDefinition(resource.Metadata, secretSpec) meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:        "Secrets.test.cosi.dev",
+		Sensitivity: meta.Sensitive,
+	}
+}
+
+type readSensitiveKey struct{}
+
+func TestSensitiveRedaction(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	secret := typed.NewResource[secretSpec, secretRD](resource.NewMetadata("default", "Secrets.test.cosi.dev", "a", resource.VersionUndefined),
+		protobuf.NewResourceSpec(&v1alpha1.Metadata{Id: "secret"}))
+	require.NoError(t, st.Create(ctx, secret))
+
+	srv := server.NewState(st, server.WithCanReadSensitive(func(ctx context.Context) bool {
+		return ctx.Value(readSensitiveKey{}) != nil
+	}))
+
+	req := &v1alpha1.GetRequest{
+		Namespace: "default",
+		Type:      "Secrets.test.cosi.dev",
+		Id:        "a",
+	}
+
+	resp, err := srv.Get(ctx, req)
+	require.NoError(t, err)
+	assert.Empty(t, resp.Resource.Spec.ProtoSpec)
+	assert.Equal(t, meta.Redacted+"\n", resp.Resource.Spec.YamlSpec)
+	assert.Equal(t, "a", resp.Resource.Metadata.Id)
+
+	resp, err = srv.Get(context.WithValue(ctx, readSensitiveKey{}, true), req)
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Resource.Spec.ProtoSpec)
+	assert.Contains(t, resp.Resource.Spec.YamlSpec, "secret")
+}
+
+// rdLookupState counts the resource definition lookups.
+type rdLookupState struct {
+	state.CoreState
+
+	lookups atomic.Int64
+}
+
+func (st *rdLookupState) Get(ctx context.Context, ptr resource.Pointer, opts ...state.GetOption) (resource.Resource, error) { //nolint:ireturn
+	if ptr.Type() == meta.ResourceDefinitionType {
+		st.lookups.Add(1)
+	}
+
+	return st.CoreState.Get(ctx, ptr, opts...)
+}
+
+func TestSensitiveLookupOnce(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	backend := &rdLookupState{CoreState: namespaced.NewState(inmem.Build)}
+	st := state.WrapCore(backend)
+
+	rd, err := meta.NewResourceDefinition(meta.ResourceDefinitionSpec{
+		Type:        "PlainSecrets.test.cosi.dev",
+		Sensitivity: meta.Sensitive,
+	})
+	require.NoError(t, err)
+	require.NoError(t, st.Create(ctx, rd))
+
+	for _, id := range []resource.ID{"a", "b", "c"} {
+		// protobuf.Resource doesn't provide the resource definition, so it is looked up in the state
+		secret, err := protobuf.FromResource(typed.NewResource[secretSpec, secretRD](
+			resource.NewMetadata("default", "PlainSecrets.test.cosi.dev", id, resource.VersionUndefined),
+			protobuf.NewResourceSpec(&v1alpha1.Metadata{Id: "secret"})))
+		require.NoError(t, err)
+		require.NoError(t, st.Create(ctx, secret))
+	}
+
+	sock, err := ioutil.TempFile("", "api*.sock")
+	require.NoError(t, err)
+
+	require.NoError(t, os.Remove(sock.Name()))
+
+	defer os.Remove(sock.Name()) //nolint:errcheck
+
+	l, err := net.Listen("unix", sock.Name())
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer()
+	v1alpha1.RegisterStateServer(grpcServer, server.NewState(st, server.WithCanReadSensitive(func(context.Context) bool { return false })))
+
+	go func() {
+		grpcServer.Serve(l) //nolint:errcheck
+	}()
+
+	defer grpcServer.Stop()
+
+	grpcConn, err := grpc.Dial("unix://"+sock.Name(), grpc.WithInsecure()) //nolint:staticcheck
+	require.NoError(t, err)
+
+	defer grpcConn.Close() //nolint:errcheck
+
+	cli, err := v1alpha1.NewStateClient(grpcConn).List(ctx, &v1alpha1.ListRequest{
+		Namespace: "default",
+		Type:      "PlainSecrets.test.cosi.dev",
+	})
+	require.NoError(t, err)
+
+	var items int
+
+	for {
+		resp, err := cli.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		require.NoError(t, err)
+		assert.Empty(t, resp.Resource.Spec.ProtoSpec)
+
+		items++
+	}
+
+	assert.Equal(t, 3, items)
+	assert.EqualValues(t, 1, backend.lookups.Load())
+}
+
+func TestDeprecationWarning(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	rd, err := meta.NewResourceDefinition(meta.ResourceDefinitionSpec{
+		Type:           "OldResources.test.cosi.dev",
+		Deprecated:     true,
+		ReplacedBy:     "NewResources.test.cosi.dev",
+		RemovalVersion: "v2",
+	})
+	require.NoError(t, err)
+	require.NoError(t, st.Create(ctx, rd))
+
+	core, logs := observer.New(zap.WarnLevel)
+
+	srv := server.NewState(st, server.WithLogger(zap.New(core)), server.WithDeprecationWarnings())
+
+	_, err = srv.Get(ctx, &v1alpha1.GetRequest{
+		Namespace: "default",
+		Type:      "Secrets.test.cosi.dev",
+		Id:        "a",
+	})
+	require.Error(t, err)
+	assert.Zero(t, logs.Len())
+
+	_, err = srv.Get(ctx, &v1alpha1.GetRequest{
+		Namespace: "default",
+		Type:      "OldResources.test.cosi.dev",
+		Id:        "a",
+	})
+	require.Error(t, err)
+
+	entries := logs.TakeAll()
+	require.Len(t, entries, 1)
+	assert.Equal(t,
+		`resource type "OldResources.test.cosi.dev" is deprecated, use "NewResources.test.cosi.dev" instead, it will be removed in v2`,
+		entries[0].ContextMap()["warning"],
+	)
+
+	// warnings are opt-in
+	srv = server.NewState(st, server.WithLogger(zap.New(core)))
+
+	_, err = srv.Get(ctx, &v1alpha1.GetRequest{
+		Namespace: "default",
+		Type:      "OldResources.test.cosi.dev",
+		Id:        "a",
+	})
+	require.Error(t, err)
+	assert.Zero(t, logs.Len())
+}
+
+func TestTokenAuthentication(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sock, err := ioutil.TempFile("", "api*.sock")
+	require.NoError(t, err)
+
+	require.NoError(t, os.Remove(sock.Name()))
+
+	defer os.Remove(sock.Name()) //nolint:errcheck
+
+	l, err := net.Listen("unix", sock.Name())
+	require.NoError(t, err)
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	secret := typed.NewResource[secretSpec, secretRD](resource.NewMetadata("default", "Secrets.test.cosi.dev", "a", resource.VersionUndefined),
+		protobuf.NewResourceSpec(&v1alpha1.Metadata{Id: "secret"}))
+	require.NoError(t, st.Create(ctx, secret))
+
+	authenticator := server.TokenAuthenticator(func(_ context.Context, token string) (server.Identity, error) {
+		switch token {
+		case "admin-token":
+			return server.Identity{Name: "admin"}, nil
+		case "user-token":
+			return server.Identity{Name: "user"}, nil
+		default:
+			return server.Identity{}, status.Error(codes.Unauthenticated, "invalid token")
+		}
+	})
+
+	grpcServer := grpc.NewServer(server.GRPCServerOptions(server.WithAuthenticator(authenticator))...)
+	v1alpha1.RegisterStateServer(grpcServer, server.NewState(st, server.WithCanReadSensitive(func(ctx context.Context) bool {
+		identity, ok := server.IdentityFromContext(ctx)
+
+		return ok && identity.Name == "admin"
+	})))
+
+	go func() {
+		grpcServer.Serve(l) //nolint:errcheck
+	}()
+
+	defer grpcServer.Stop()
+
+	grpcConn, err := grpc.Dial("unix://"+sock.Name(), grpc.WithInsecure()) //nolint:staticcheck
+	require.NoError(t, err)
+
+	defer grpcConn.Close() //nolint:errcheck
+
+	stateClient := v1alpha1.NewStateClient(grpcConn)
+
+	req := &v1alpha1.GetRequest{
+		Namespace: "default",
+		Type:      "Secrets.test.cosi.dev",
+		Id:        "a",
+	}
+
+	_, err = stateClient.Get(ctx, req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = stateClient.Get(metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer wrong-token"), req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	resp, err := stateClient.Get(metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer user-token"), req)
+	require.NoError(t, err)
+	assert.Equal(t, meta.Redacted+"\n", resp.Resource.Spec.YamlSpec)
+
+	resp, err = stateClient.Get(metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer admin-token"), req)
+	require.NoError(t, err)
+	assert.Contains(t, resp.Resource.Spec.YamlSpec, "secret")
+}
+
+func TestCertificateAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	authenticator := server.CertificateAuthenticator()
+
+	_, err := authenticator.Authenticate(context.Background())
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	cert := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   "controller",
+			Organization: []string{"system:controllers"},
+		},
+	}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{cert}},
+			},
+		},
+	})
+
+	identity, err := authenticator.Authenticate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, server.Identity{Name: "controller", Groups: []string{"system:controllers"}}, identity)
+}
+
+func TestPolicyAuthorization(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	secret := typed.NewResource[secretSpec, secretRD](resource.NewMetadata("default", "Secrets.test.cosi.dev", "a", resource.VersionUndefined),
+		protobuf.NewResourceSpec(&v1alpha1.Metadata{Id: "secret"}))
+	require.NoError(t, st.Create(ctx, secret))
+
+	policy := meta.NewAccessPolicy("readers", meta.AccessPolicySpec{
+		Subjects: []string{meta.SubjectGroupPrefix + "readers", meta.SubjectUserPrefix + "carol"},
+		Rules: []meta.AccessRule{
+			{
+				Verbs:      []string{"get", "list"},
+				Namespaces: []resource.Namespace{"default"},
+				Types:      []resource.Type{"*"},
+			},
+		},
+	})
+	require.NoError(t, st.Create(ctx, policy))
+
+	srv := server.NewState(st, server.WithAuthorizer(server.NewPolicyAuthorizer(st)))
+
+	req := &v1alpha1.GetRequest{
+		Namespace: "default",
+		Type:      "Secrets.test.cosi.dev",
+		Id:        "a",
+	}
+
+	_, err := srv.Get(ctx, req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = srv.Get(server.ContextWithIdentity(ctx, server.Identity{Name: "bob"}), req)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// user named after the group doesn't get the group's access
+	_, err = srv.Get(server.ContextWithIdentity(ctx, server.Identity{Name: "group:readers"}), req)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = srv.Get(server.ContextWithIdentity(ctx, server.Identity{Name: "carol"}), req)
+	assert.NoError(t, err)
+
+	readerCtx := server.ContextWithIdentity(ctx, server.Identity{Name: "alice", Groups: []string{"readers"}})
+
+	resp, err := srv.Get(readerCtx, req)
+	require.NoError(t, err)
+	assert.Equal(t, meta.Redacted+"\n", resp.Resource.Spec.YamlSpec)
+
+	_, err = srv.Destroy(readerCtx, &v1alpha1.DestroyRequest{
+		Namespace: "default",
+		Type:      "Secrets.test.cosi.dev",
+		Id:        "a",
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// policy changes apply to the next request
+	_, err = safe.StateUpdateWithConflicts(ctx, st, policy.Metadata(), func(p *meta.AccessPolicy) error {
+		p.TypedSpec().Rules[0].Sensitive = true
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	resp, err = srv.Get(readerCtx, req)
+	require.NoError(t, err)
+	assert.Contains(t, resp.Resource.Spec.YamlSpec, "secret")
+}
+
+type countingState struct {
+	state.CoreState
+
+	creates *int32
+}
+
+func (st countingState) Create(ctx context.Context, r resource.Resource, opts ...state.CreateOption) error {
+	atomic.AddInt32(st.creates, 1)
+
+	return st.CoreState.Create(ctx, r, opts...)
+}
+
+func countingMiddleware(creates *int32) state.Middleware {
+	return func(st state.CoreState) state.CoreState {
+		return countingState{CoreState: st, creates: creates}
+	}
+}
+
+func TestMiddlewares(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sock, err := ioutil.TempFile("", "api*.sock")
+	require.NoError(t, err)
+
+	require.NoError(t, os.Remove(sock.Name()))
+
+	defer os.Remove(sock.Name()) //nolint:errcheck
+
+	l, err := net.Listen("unix", sock.Name())
+	require.NoError(t, err)
+
+	var (
+		serverCreates, clientCreates int32
+		serverMethods, clientMethods []string
+		mu                           sync.Mutex
+	)
+
+	authenticator := server.TokenAuthenticator(func(_ context.Context, token string) (server.Identity, error) {
+		return server.Identity{Name: token}, nil
+	})
+
+	grpcServer := grpc.NewServer(server.GRPCServerOptions(
+		server.WithAuthenticator(authenticator),
+		server.WithUnaryInterceptors(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			identity, _ := server.IdentityFromContext(ctx)
+
+			mu.Lock()
+			serverMethods = append(serverMethods, identity.Name+":"+info.FullMethod)
+			mu.Unlock()
+
+			return handler(ctx, req)
+		}),
+	)...)
+	v1alpha1.RegisterStateServer(grpcServer, server.NewState(state.WrapCore(namespaced.NewState(inmem.Build)),
+		server.WithMiddlewares(countingMiddleware(&serverCreates))))
+
+	go func() {
+		grpcServer.Serve(l) //nolint:errcheck
+	}()
+
+	defer grpcServer.Stop()
+
+	grpcConn, err := grpc.Dial("unix://"+sock.Name(), append(client.GRPCDialOptions(
+		client.WithToken("alice"),
+		client.WithUnaryInterceptors(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			mu.Lock()
+			clientMethods = append(clientMethods, method)
+			mu.Unlock()
+
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}),
+	), grpc.WithInsecure())...) //nolint:staticcheck
+	require.NoError(t, err)
+
+	defer grpcConn.Close() //nolint:errcheck
+
+	st := state.WrapCore(state.Chain(client.NewAdapter(v1alpha1.NewStateClient(grpcConn)), countingMiddleware(&clientCreates)))
+
+	require.NoError(t, st.Create(ctx, conformance.NewPathResource("default", "/var/run")))
+
+	assert.EqualValues(t, 1, atomic.LoadInt32(&clientCreates))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&serverCreates))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, []string{"/cosi.resource.State/Create"}, clientMethods)
+	assert.Equal(t, []string{"alice:/cosi.resource.State/Create"}, serverMethods)
+}
+
+func TestAudit(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		buf     bytes.Buffer
+		records []server.AuditRecord
+	)
+
+	srv := server.NewState(state.WrapCore(namespaced.NewState(inmem.Build)), server.WithAuditSinks(
+		server.AuditSinkFunc(func(_ context.Context, record server.AuditRecord) {
+			records = append(records, record)
+		}),
+		server.WriterAuditSink(&buf),
+	))
+
+	ctx = server.ContextWithIdentity(ctx, server.Identity{Name: "alice"})
+
+	protoR, err := protobuf.FromResource(conformance.NewPathResource("default", "/var/run"))
+	require.NoError(t, err)
+
+	marshaled, err := protoR.Marshal()
+	require.NoError(t, err)
+
+	_, err = srv.Create(ctx, &v1alpha1.CreateRequest{Resource: marshaled})
+	require.NoError(t, err)
+
+	_, err = srv.Create(ctx, &v1alpha1.CreateRequest{Resource: marshaled})
+	require.Error(t, err)
+
+	_, err = srv.Get(ctx, &v1alpha1.GetRequest{Namespace: "default", Type: conformance.PathResourceType, Id: "/var/run"})
+	require.NoError(t, err)
+
+	_, err = srv.Destroy(ctx, &v1alpha1.DestroyRequest{Namespace: "default", Type: conformance.PathResourceType, Id: "/var/run"})
+	require.NoError(t, err)
+
+	// reads are not audited
+	require.Len(t, records, 3)
+
+	for i, expected := range []struct {
+		verb server.Verb
+		code codes.Code
+	}{
+		{server.VerbCreate, codes.OK},
+		{server.VerbCreate, codes.AlreadyExists},
+		{server.VerbDestroy, codes.OK},
+	} {
+		assert.Equal(t, expected.verb, records[i].Verb)
+		assert.Equal(t, expected.code, records[i].Code)
+		assert.Equal(t, "alice", records[i].Identity.Name)
+		assert.Equal(t, conformance.PathResourceType, records[i].Type)
+		assert.Equal(t, "/var/run", records[i].ID)
+	}
+
+	assert.NotEmpty(t, records[1].Error)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3)
+
+	var line map[string]interface{}
+
+	require.NoError(t, json.Unmarshal(lines[1], &line))
+	assert.Equal(t, "alice", line["identity"])
+	assert.Equal(t, "create", line["verb"])
+	assert.Equal(t, "AlreadyExists", line["code"])
+}
+
+func TestRateLimiterFairness(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	interceptor := server.NewRateLimiter(server.WithTotalConcurrency(1)).UnaryServerInterceptor()
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+
+	unblock := make(chan struct{})
+
+	call := func(name string, wait bool) {
+		_, err := interceptor(server.ContextWithIdentity(ctx, server.Identity{Name: name}), nil, &grpc.UnaryServerInfo{},
+			func(context.Context, interface{}) (interface{}, error) {
+				mu.Lock()
+				order = append(order, name)
+				mu.Unlock()
+
+				if wait {
+					<-unblock
+				}
+
+				return nil, nil //nolint:nilnil
+			})
+		assert.NoError(t, err)
+	}
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		call("greedy", true)
+	}()
+
+	// wait for the first request to occupy the only slot
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(order) == 1
+	}, time.Second, time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			call("greedy", false)
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		call("polite", false)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+
+	close(unblock)
+	wg.Wait()
+
+	// the polite client is served before the queued requests of the greedy client are drained
+	assert.Equal(t, []string{"greedy", "greedy", "polite", "greedy", "greedy"}, order)
+}
+
+func TestRateLimiterQPS(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	interceptor := server.NewRateLimiter(server.WithClientQPS(20, 1)).UnaryServerInterceptor()
+
+	call := func(ctx context.Context, name string) error {
+		_, err := interceptor(server.ContextWithIdentity(ctx, server.Identity{Name: name}), nil, &grpc.UnaryServerInfo{},
+			func(context.Context, interface{}) (interface{}, error) {
+				return nil, nil //nolint:nilnil
+			})
+
+		return err
+	}
+
+	start := time.Now()
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, call(ctx, "alice"))
+	}
+
+	assert.GreaterOrEqual(t, time.Since(start), 140*time.Millisecond)
+
+	// other clients are not affected
+	start = time.Now()
+
+	require.NoError(t, call(ctx, "bob"))
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	// canceled while waiting
+	require.NoError(t, call(ctx, "carol"))
+
+	shortCtx, shortCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer shortCancel()
+
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(call(shortCtx, "carol")))
+}
+
+type unavailableState struct {
+	state.CoreState
+
+	unavailable *int32
+}
+
+func (st unavailableState) Get(ctx context.Context, ptr resource.Pointer, opts ...state.GetOption) (resource.Resource, error) { //nolint:ireturn
+	if ptr.Namespace() == "remote" && atomic.LoadInt32(st.unavailable) == 1 {
+		return nil, errors.New("backend is unavailable")
+	}
+
+	return st.CoreState.Get(ctx, ptr, opts...)
+}
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sock, err := ioutil.TempFile("", "api*.sock")
+	require.NoError(t, err)
+
+	require.NoError(t, os.Remove(sock.Name()))
+
+	defer os.Remove(sock.Name()) //nolint:errcheck
+
+	l, err := net.Listen("unix", sock.Name())
+	require.NoError(t, err)
+
+	var unavailable int32
+
+	st := unavailableState{CoreState: namespaced.NewState(inmem.Build), unavailable: &unavailable}
+	health := server.NewHealth(st, server.WithHealthNamespaces("default", "remote"))
+
+	grpcServer := grpc.NewServer()
+	v1alpha1.RegisterStateServer(grpcServer, server.NewState(st))
+	healthpb.RegisterHealthServer(grpcServer, health)
+
+	go func() {
+		grpcServer.Serve(l) //nolint:errcheck
+	}()
+
+	defer grpcServer.Stop()
+
+	grpcConn, err := grpc.Dial("unix://"+sock.Name(), grpc.WithInsecure()) //nolint:staticcheck
+	require.NoError(t, err)
+
+	defer grpcConn.Close() //nolint:errcheck
+
+	healthClient := healthpb.NewHealthClient(grpcConn)
+
+	assertStatus := func(service string, expected healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+
+		resp, err := healthClient.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		assert.Equal(t, expected, resp.Status, service)
+	}
+
+	// not ready and not probed yet
+	assertStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	assertStatus("cosi.resource.State/default", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	health.Probe(ctx)
+
+	assertStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	health.SetReady(true)
+
+	assertStatus("", healthpb.HealthCheckResponse_SERVING)
+	assertStatus("cosi.resource.State", healthpb.HealthCheckResponse_SERVING)
+	assertStatus("cosi.resource.State/default", healthpb.HealthCheckResponse_SERVING)
+	assertStatus("cosi.resource.State/remote", healthpb.HealthCheckResponse_SERVING)
+
+	atomic.StoreInt32(&unavailable, 1)
+	health.Probe(ctx)
+
+	assertStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	assertStatus("cosi.resource.State/default", healthpb.HealthCheckResponse_SERVING)
+	assertStatus("cosi.resource.State/remote", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	atomic.StoreInt32(&unavailable, 0)
+	health.Probe(ctx)
+
+	assertStatus("", healthpb.HealthCheckResponse_SERVING)
+	assertStatus("cosi.resource.State/remote", healthpb.HealthCheckResponse_SERVING)
+
+	health.SetReady(false)
+
+	assertStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	assertStatus("cosi.resource.State/default", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	// unknown services are reported as not found
+	_, err = healthClient.Check(ctx, &healthpb.HealthCheckRequest{Service: "cosi.resource.State/other"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestImpersonation(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sock, err := ioutil.TempFile("", "api*.sock")
+	require.NoError(t, err)
+
+	require.NoError(t, os.Remove(sock.Name()))
+
+	defer os.Remove(sock.Name()) //nolint:errcheck
+
+	l, err := net.Listen("unix", sock.Name())
+	require.NoError(t, err)
+
+	var (
+		identities []server.Identity
+		records    []server.AuditRecord
+		mu         sync.Mutex
+	)
+
+	authenticator := server.TokenAuthenticator(func(_ context.Context, token string) (server.Identity, error) {
+		return server.Identity{Name: token}, nil
+	})
+
+	grpcServer := grpc.NewServer(server.GRPCServerOptions(
+		server.WithAuthenticator(authenticator),
+		server.WithImpersonationAuthorizer(func(_ context.Context, actor, target server.Identity) error {
+			if actor.Name != "gateway" {
+				return fmt.Errorf("%q can't act as %q", actor.Name, target.Name)
+			}
+
+			return nil
+		}),
+		server.WithUnaryInterceptors(func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			identity, _ := server.IdentityFromContext(ctx)
+
+			mu.Lock()
+			identities = append(identities, identity)
+			mu.Unlock()
+
+			return handler(ctx, req)
+		}),
+	)...)
+	v1alpha1.RegisterStateServer(grpcServer, server.NewState(state.WrapCore(namespaced.NewState(inmem.Build)),
+		server.WithAuditSinks(server.AuditSinkFunc(func(_ context.Context, record server.AuditRecord) {
+			mu.Lock()
+			records = append(records, record)
+			mu.Unlock()
+		}))))
+
+	go func() {
+		grpcServer.Serve(l) //nolint:errcheck
+	}()
+
+	defer grpcServer.Stop()
+
+	connect := func(token string) state.State {
+		grpcConn, err := grpc.Dial("unix://"+sock.Name(), append(client.GRPCDialOptions(client.WithToken(token)), grpc.WithInsecure())...) //nolint:staticcheck
+		require.NoError(t, err)
+
+		t.Cleanup(func() { grpcConn.Close() }) //nolint:errcheck
+
+		return state.WrapCore(client.NewAdapter(v1alpha1.NewStateClient(grpcConn)))
+	}
+
+	gateway, alice := connect("gateway"), connect("alice")
+
+	require.NoError(t, gateway.Create(client.ContextWithImpersonation(ctx, "bob", "devs", "ops"), conformance.NewPathResource("default", "a")))
+	require.NoError(t, gateway.Create(ctx, conformance.NewPathResource("default", "b")))
+
+	_, err = alice.Get(client.ContextWithImpersonation(ctx, "bob"), conformance.NewPathResource("default", "a").Metadata())
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = gateway.Get(metadata.AppendToOutgoingContext(ctx, server.ImpersonateGroupHeader, "devs"), conformance.NewPathResource("default", "a").Metadata())
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Len(t, identities, 2)
+
+	assert.Equal(t, "bob", identities[0].Name)
+	assert.Equal(t, []string{"devs", "ops"}, identities[0].Groups)
+	require.NotNil(t, identities[0].ImpersonatedBy)
+	assert.Equal(t, "gateway", identities[0].ImpersonatedBy.Name)
+
+	assert.Equal(t, "gateway", identities[1].Name)
+	assert.Nil(t, identities[1].ImpersonatedBy)
+
+	require.Len(t, records, 2)
+
+	encoded, err := json.Marshal(records[0])
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, "bob", decoded["identity"])
+	assert.Equal(t, "gateway", decoded["impersonatedBy"])
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sock, err := ioutil.TempFile("", "api*.sock")
+	require.NoError(t, err)
+
+	require.NoError(t, os.Remove(sock.Name()))
+
+	defer os.Remove(sock.Name()) //nolint:errcheck
+
+	l, err := net.Listen("unix", sock.Name())
+	require.NoError(t, err)
+
+	metrics := server.NewMetrics()
+
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(metrics))
+
+	grpcServer := grpc.NewServer(server.GRPCServerOptions(server.WithMetrics(metrics))...)
+	v1alpha1.RegisterStateServer(grpcServer, server.NewState(state.WrapCore(namespaced.NewState(inmem.Build))))
+
+	go func() {
+		grpcServer.Serve(l) //nolint:errcheck
+	}()
+
+	defer grpcServer.Stop()
+
+	grpcConn, err := grpc.Dial("unix://"+sock.Name(), grpc.WithInsecure()) //nolint:staticcheck
+	require.NoError(t, err)
+
+	defer grpcConn.Close() //nolint:errcheck
+
+	st := state.WrapCore(client.NewAdapter(v1alpha1.NewStateClient(grpcConn)))
+
+	path := conformance.NewPathResource("default", "/var/lib")
+
+	watchCtx, watchCancel := context.WithCancel(ctx)
+	defer watchCancel()
+
+	ch := make(chan state.Event)
+
+	require.NoError(t, st.WatchKind(watchCtx, path.Metadata(), ch))
+
+	require.NoError(t, st.Create(ctx, path))
+
+	select {
+	case <-ch:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	_, err = st.Get(ctx, path.Metadata())
+	require.NoError(t, err)
+
+	_, err = st.Get(ctx, conformance.NewPathResource("default", "/var/run").Metadata())
+	require.Error(t, err)
+
+	_, err = st.List(ctx, path.Metadata())
+	require.NoError(t, err)
+
+	values := gatherMetrics(t, registry)
+
+	assert.Equal(t, 1.0, values["cosi_state_server_active_watches"])
+	assert.Equal(t, 1.0, values["cosi_state_server_requests_total{code=OK,verb=create}"])
+	assert.Equal(t, 1.0, values["cosi_state_server_requests_total{code=OK,verb=get}"])
+	assert.Equal(t, 1.0, values["cosi_state_server_requests_total{code=NotFound,verb=get}"])
+	assert.Equal(t, 1.0, values["cosi_state_server_requests_total{code=OK,verb=list}"])
+	assert.Equal(t, 2.0, values["cosi_state_server_request_duration_seconds{verb=get}"])
+	assert.NotContains(t, values, "cosi_state_server_request_duration_seconds{verb=watch}")
+
+	watchCancel()
+
+	require.Eventually(t, func() bool {
+		return gatherMetrics(t, registry)["cosi_state_server_active_watches"] == 0
+	}, 10*time.Second, 10*time.Millisecond)
+
+	values = gatherMetrics(t, registry)
+
+	assert.Equal(t, 1.0, values["cosi_state_server_watch_events_sent_total"])
+	assert.Equal(t, 1.0, values["cosi_state_server_requests_total{code=OK,verb=watch}"])
+}
+
+// gatherMetrics flattens the gathered metrics into name{labels} -> value, histograms are represented by the sample count.
+func gatherMetrics(t *testing.T, gatherer prometheus.Gatherer) map[string]float64 {
+	t.Helper()
+
+	families, err := gatherer.Gather()
+	require.NoError(t, err)
+
+	values := map[string]float64{}
+
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := make([]string, 0, len(metric.GetLabel()))
+
+			for _, label := range metric.GetLabel() {
+				labels = append(labels, label.GetName()+"="+label.GetValue())
+			}
+
+			name := family.GetName()
+
+			if len(labels) > 0 {
+				name += "{" + strings.Join(labels, ",") + "}"
+			}
+
+			switch {
+			case metric.GetCounter() != nil:
+				values[name] = metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				values[name] = metric.GetGauge().GetValue()
+			case metric.GetHistogram() != nil:
+				values[name] = float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+
+	return values
+}
+
+// serveState serves the state over gRPC, and returns the client connected to it.
+func serveState(t *testing.T, st state.State, opts ...server.StateOption) state.State {
+	t.Helper()
+
+	return state.WrapCore(serveAdapter(t, st, opts...))
+}
+
+func serveAdapter(t *testing.T, st state.State, opts ...server.StateOption) *client.Adapter {
+	t.Helper()
+
+	sock, err := ioutil.TempFile("", "api*.sock")
+	require.NoError(t, err)
+
+	require.NoError(t, os.Remove(sock.Name()))
+
+	t.Cleanup(func() { os.Remove(sock.Name()) }) //nolint:errcheck
+
+	l, err := net.Listen("unix", sock.Name())
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer()
+	v1alpha1.RegisterStateServer(grpcServer, server.NewState(st, opts...))
+
+	go func() {
+		grpcServer.Serve(l) //nolint:errcheck
+	}()
+
+	t.Cleanup(grpcServer.Stop)
+
+	grpcConn, err := grpc.Dial("unix://"+sock.Name(), grpc.WithInsecure()) //nolint:staticcheck
+	require.NoError(t, err)
+
+	t.Cleanup(func() { grpcConn.Close() }) //nolint:errcheck
+
+	return client.NewAdapter(v1alpha1.NewStateClient(grpcConn))
+}
+
+func TestGeneration(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	st := serveState(t, state.WrapCore(namespaced.NewState(inmem.Build)))
+
+	path := conformance.NewPathResource("default", "/var/gen")
+	path.Metadata().SetObservedGeneration(5)
+
+	require.NoError(t, st.Create(ctx, path))
+
+	r, err := st.Get(ctx, path.Metadata())
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 1, r.Metadata().Generation())
+	assert.EqualValues(t, 5, r.Metadata().ObservedGeneration())
+}
+
+func TestDestroyInfo(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	st := serveState(t, state.WrapCore(namespaced.NewState(inmem.Build)))
+
+	path := conformance.NewPathResource("default", "/var/destroy")
+
+	require.NoError(t, st.Create(ctx, path, state.WithCreateOwner("gc")))
+
+	ch := make(chan state.Event)
+
+	require.NoError(t, st.Watch(ctx, path.Metadata(), ch))
+
+	event := <-ch
+	assert.Equal(t, state.Created, event.Type)
+	assert.Nil(t, event.Destroy)
+
+	require.NoError(t, st.Destroy(ctx, path.Metadata(), state.WithDestroyOwner("gc"), state.WithDestroyReason("orphaned")))
+
+	event = <-ch
+	assert.Equal(t, state.Destroyed, event.Type)
+	require.NotNil(t, event.Destroy)
+	assert.Equal(t, "gc", event.Destroy.Owner)
+	assert.Equal(t, "orphaned", event.Destroy.Reason)
+	assert.False(t, event.Destroy.Timestamp.IsZero())
+}
+
+func TestCustomPhase(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	st := serveState(t, state.WrapCore(namespaced.NewState(inmem.Build)))
+
+	path := conformance.NewPathResource("default", "/var/phase")
+	path.Metadata().SetCustomPhase("pending")
+
+	require.NoError(t, st.Create(ctx, path))
+
+	r, err := st.Get(ctx, path.Metadata())
+	require.NoError(t, err)
+
+	assert.Equal(t, "pending", r.Metadata().CustomPhase())
+}
+
+func TestResourceDefinitions(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	rd, err := meta.NewResourceDefinition(meta.ResourceDefinitionSpec{
+		Type:       "Secrets.test.cosi.dev",
+		Aliases:    []resource.Type{"sec"},
+		Categories: []string{"test"},
+		PrintColumns: []meta.PrintColumn{
+			{Name: "Size", JSONPath: "{.size}", Format: meta.FormatBytes, Priority: 1, Width: 10},
+		},
+		CustomPhases: []meta.CustomPhase{
+			{Name: "pending", Transitions: []string{"ready"}},
+			{Name: "ready"},
+		},
+		Sensitivity: meta.Sensitive,
+	})
+	require.NoError(t, err)
+	require.NoError(t, st.Create(ctx, rd))
+
+	adapter := serveAdapter(t, st)
+
+	definitions, err := adapter.ResourceDefinitions(ctx)
+	require.NoError(t, err)
+	require.Len(t, definitions, 1)
+
+	assert.Equal(t, *rd.TypedSpec(), definitions[0])
+
+	adapter = serveAdapter(t, st, server.WithAuthorizer(server.NewPolicyAuthorizer(st)))
+
+	_, err = adapter.ResourceDefinitions(ctx)
+	require.Error(t, err)
+}
+
+func TestBatch(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	backend := state.WrapCore(namespaced.NewState(inmem.Build))
+	st := serveState(t, backend)
+
+	path1 := conformance.NewPathResource("default", "/var/batch1")
+	path2 := conformance.NewPathResource("default", "/var/batch2")
+
+	require.NoError(t, state.BatchCreate(ctx, st, []state.BatchCreateItem{
+		{Resource: path1},
+		{Resource: path2, Options: []state.CreateOption{state.WithCreateOwner("owner")}},
+	}))
+
+	r, err := backend.Get(ctx, path2.Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, "owner", r.Metadata().Owner())
+
+	err = state.BatchCreate(ctx, st, []state.BatchCreateItem{{Resource: path1}})
+	require.Error(t, err)
+	assert.True(t, state.IsConflictError(err))
+
+	updated := path1.DeepCopy()
+	updated.Metadata().BumpVersion()
+
+	require.NoError(t, state.BatchUpdate(ctx, st, []state.BatchUpdateItem{
+		{CurrentVersion: path1.Metadata().Version(), Resource: updated},
+	}))
+
+	err = state.BatchUpdate(ctx, st, []state.BatchUpdateItem{
+		{CurrentVersion: path1.Metadata().Version(), Resource: updated},
+	})
+	require.Error(t, err)
+	assert.True(t, state.IsConflictError(err))
+
+	// the backend doesn't apply batches atomically, so path1 is destroyed before the owner conflict on path2
+	err = state.BatchDestroy(ctx, st, []state.BatchDestroyItem{
+		{Pointer: path1.Metadata()},
+		{Pointer: path2.Metadata()},
+	})
+	require.Error(t, err)
+	assert.True(t, state.IsOwnerConflictError(err))
+
+	require.NoError(t, state.BatchDestroy(ctx, st, []state.BatchDestroyItem{
+		{Pointer: path2.Metadata(), Options: []state.DestroyOption{state.WithDestroyOwner("owner")}},
+	}))
+
+	list, err := backend.List(ctx, path1.Metadata())
+	require.NoError(t, err)
+	assert.Empty(t, list.Items)
+}
+
+func TestUpdateStatus(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	require.NoError(t, protobuf.RegisterResource(conformance.ReplicaResourceType, &conformance.ReplicaResource{}))
+
+	st := serveState(t, state.WrapCore(namespaced.NewState(inmem.Build)), server.WithMiddlewares(registry.AutoRegisterNamespaces()))
+
+	replica := conformance.NewReplicaResource("default", "one", 3)
+	require.NoError(t, st.Create(ctx, replica))
+
+	status := replica.DeepCopy().(*conformance.ReplicaResource) //nolint:forcetypeassert
+	status.TypedSpec().Replicas = 5
+	status.TypedSpec().Ready = 2
+	status.Metadata().BumpVersion()
+
+	require.NoError(t, state.UpdateStatus(ctx, st, replica.Metadata().Version(), status, state.WithUpdateOwner("controller")))
+
+	r, err := st.Get(ctx, replica.Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, conformance.ReplicaSpec{Replicas: 3, Ready: 2}, *r.(*conformance.ReplicaResource).TypedSpec()) //nolint:forcetypeassert
+	assert.Equal(t, "controller", r.Metadata().StatusOwner())
+
+	status = r.DeepCopy().(*conformance.ReplicaResource) //nolint:forcetypeassert
+	status.Metadata().BumpVersion()
+
+	err = state.UpdateStatus(ctx, st, r.Metadata().Version(), status, state.WithUpdateOwner("other"))
+	require.Error(t, err)
+	assert.True(t, state.IsOwnerConflictError(err))
+}
+
+type jobStatusSpec = protobuf.ResourceSpec[v1alpha1.Metadata, *v1alpha1.Metadata]
+
+type jobStatusRD struct{}
+
+func (jobStatusRD) ResourceDefinition(resource.Metadata, jobStatusSpec) meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{}
+}
+
+type jobStatusResource = typed.ResourceWithStatus[jobStatusSpec, jobStatusSpec, jobStatusRD]
+
+func TestUpdateStatusTyped(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, protobuf.RegisterResource("StatusJobs.test.cosi.dev", &jobStatusResource{}))
+
+	for _, test := range []struct {
+		name         string
+		resourceType resource.Type
+	}{
+		{
+			// server unmarshals the resource into the registered type
+			name:         "registered",
+			resourceType: "StatusJobs.test.cosi.dev",
+		},
+		{
+			// server keeps the status in protobuf.Resource
+			name:         "unregistered",
+			resourceType: "RawStatusJobs.test.cosi.dev",
+		},
+	} {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			st := serveState(t, state.WrapCore(namespaced.NewState(inmem.Build)), server.WithMiddlewares(registry.AutoRegisterNamespaces()))
+
+			get := func() *jobStatusResource {
+				r, err := st.Get(ctx, resource.NewMetadata("default", test.resourceType, "job", resource.VersionUndefined))
+				require.NoError(t, err)
+
+				if job, ok := r.(*jobStatusResource); ok {
+					return job
+				}
+
+				protoR, ok := r.(*protobuf.Resource)
+				require.True(t, ok)
+
+				var job jobStatusResource
+
+				require.NoError(t, protoR.Unmarshal(&job))
+
+				return &job
+			}
+
+			job := typed.NewResourceWithStatus[jobStatusSpec, jobStatusSpec, jobStatusRD](
+				resource.NewMetadata("default", test.resourceType, "job", resource.VersionUndefined),
+				protobuf.NewResourceSpec(&v1alpha1.Metadata{Id: "spec"}),
+				protobuf.NewResourceSpec(&v1alpha1.Metadata{}),
+			)
+			require.NoError(t, st.Create(ctx, job))
+
+			status := get()
+			generation := status.Metadata().Generation()
+			status.TypedStatus().Value = &v1alpha1.Metadata{Id: "running"}
+			status.Metadata().BumpVersion()
+
+			require.NoError(t, state.UpdateStatus(ctx, st, job.Metadata().Version(), status, state.WithUpdateOwner("controller")))
+
+			updated := get()
+			assert.Equal(t, "spec", updated.TypedSpec().Value.Id)
+			assert.Equal(t, "running", updated.TypedStatus().Value.Id)
+			assert.Equal(t, "controller", updated.Metadata().StatusOwner())
+			assert.Equal(t, generation, updated.Metadata().Generation())
+
+			// spec updates keep the status
+			spec := updated.DeepCopy().(*jobStatusResource) //nolint:forcetypeassert
+			spec.TypedSpec().Value = &v1alpha1.Metadata{Id: "changed"}
+			spec.TypedStatus().Value = &v1alpha1.Metadata{}
+			spec.Metadata().BumpVersion()
+
+			require.NoError(t, st.Update(ctx, updated.Metadata().Version(), spec))
+
+			updated = get()
+			assert.Equal(t, "changed", updated.TypedSpec().Value.Id)
+			assert.Equal(t, "running", updated.TypedStatus().Value.Id)
+		})
+	}
+}
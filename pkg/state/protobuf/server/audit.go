@@ -0,0 +1,167 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+// AuditRecord describes a mutating call served by the server.
+type AuditRecord struct {
+	Timestamp time.Time
+	// Identity is empty if the request is not authenticated.
+	//
+	// If the identity is impersonated, the client acting as the identity is recorded in Identity.ImpersonatedBy.
+	Identity Identity
+	Verb     Verb
+
+	Namespace resource.Namespace
+	Type      resource.Type
+	ID        resource.ID
+
+	// Code is the outcome of the call, codes.OK if the call succeeded.
+	Code  codes.Code
+	Error string
+
+	Latency time.Duration
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (record AuditRecord) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		Timestamp      string   `json:"timestamp"`
+		Identity       string   `json:"identity,omitempty"`
+		Groups         []string `json:"groups,omitempty"`
+		ImpersonatedBy string   `json:"impersonatedBy,omitempty"`
+		Verb           Verb     `json:"verb"`
+		Namespace      string   `json:"namespace"`
+		Type           string   `json:"type"`
+		ID             string   `json:"id"`
+		Code           string   `json:"code"`
+		Error          string   `json:"error,omitempty"`
+		LatencyMs      float64  `json:"latencyMs"`
+	}{
+		Timestamp:      record.Timestamp.Format(time.RFC3339Nano),
+		Identity:       record.Identity.Name,
+		Groups:         record.Identity.Groups,
+		ImpersonatedBy: record.Identity.impersonatorName(),
+		Verb:           record.Verb,
+		Namespace:      record.Namespace,
+		Type:           record.Type,
+		ID:             record.ID,
+		Code:           record.Code.String(),
+		Error:          record.Error,
+		LatencyMs:      float64(record.Latency) / float64(time.Millisecond),
+	})
+}
+
+func (identity Identity) impersonatorName() string {
+	if identity.ImpersonatedBy == nil {
+		return ""
+	}
+
+	return identity.ImpersonatedBy.Name
+}
+
+// AuditSink receives the audit records.
+//
+// Audit is called synchronously once the call is finished, so the sinks should not block for long.
+type AuditSink interface {
+	Audit(ctx context.Context, record AuditRecord)
+}
+
+// AuditSinkFunc is a function which implements AuditSink.
+type AuditSinkFunc func(ctx context.Context, record AuditRecord)
+
+// Audit implements AuditSink.
+func (f AuditSinkFunc) Audit(ctx context.Context, record AuditRecord) {
+	f(ctx, record)
+}
+
+// LoggerAuditSink logs the audit records.
+func LoggerAuditSink(logger *zap.Logger) AuditSink { //nolint:ireturn
+	return AuditSinkFunc(func(_ context.Context, record AuditRecord) {
+		logger.Info("audit",
+			zap.Time("timestamp", record.Timestamp),
+			zap.String("identity", record.Identity.Name),
+			zap.Strings("groups", record.Identity.Groups),
+			zap.String("impersonated_by", record.Identity.impersonatorName()),
+			zap.String("verb", string(record.Verb)),
+			zap.String("namespace", record.Namespace),
+			zap.String("type", record.Type),
+			zap.String("id", record.ID),
+			zap.Stringer("code", record.Code),
+			zap.String("error", record.Error),
+			zap.Duration("latency", record.Latency),
+		)
+	})
+}
+
+// WriterAuditSink writes the audit records as JSON lines, e.g. to a file.
+//
+// Write errors are ignored.
+func WriterAuditSink(w io.Writer) AuditSink { //nolint:ireturn
+	var mu sync.Mutex
+
+	encoder := json.NewEncoder(w)
+
+	return AuditSinkFunc(func(_ context.Context, record AuditRecord) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		encoder.Encode(record) //nolint:errcheck
+	})
+}
+
+// StreamAuditSink sends the audit records to the channel.
+//
+// The call blocks until the record is received or the call context is canceled,
+// so the channel should be consumed continuously.
+func StreamAuditSink(ch chan<- AuditRecord) AuditSink { //nolint:ireturn
+	return AuditSinkFunc(func(ctx context.Context, record AuditRecord) {
+		select {
+		case ch <- record:
+		case <-ctx.Done():
+		}
+	})
+}
+
+// audit sends the record of the finished call to the audit sinks.
+func (server *State) audit(ctx context.Context, verb Verb, ns resource.Namespace, typ resource.Type, id resource.ID, start time.Time, err error) {
+	if len(server.options.AuditSinks) == 0 {
+		return
+	}
+
+	identity, _ := IdentityFromContext(ctx)
+
+	record := AuditRecord{
+		Timestamp: start,
+		Identity:  identity,
+		Verb:      verb,
+		Namespace: ns,
+		Type:      typ,
+		ID:        id,
+		Code:      status.Code(err),
+		Latency:   time.Since(start),
+	}
+
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	for _, sink := range server.options.AuditSinks {
+		sink.Audit(ctx, record)
+	}
+}
@@ -0,0 +1,149 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package server
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Identity of the authenticated client.
+type Identity struct {
+	// ImpersonatedBy is the authenticated client acting as this identity, nil if the identity is not impersonated.
+	ImpersonatedBy *Identity
+
+	Name   string
+	Groups []string
+}
+
+// Authenticator maps the connection or the request credentials to the client identity.
+//
+// Authenticate should return an error with codes.Unauthenticated if the client can't be authenticated.
+type Authenticator interface {
+	Authenticate(ctx context.Context) (Identity, error)
+}
+
+// AuthenticatorFunc is an adapter to use functions as Authenticator.
+type AuthenticatorFunc func(ctx context.Context) (Identity, error)
+
+// Authenticate implements Authenticator.
+func (f AuthenticatorFunc) Authenticate(ctx context.Context) (Identity, error) {
+	return f(ctx)
+}
+
+type identityKey struct{}
+
+// ContextWithIdentity attaches the client identity to the context.
+func ContextWithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the client identity attached to the context.
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+
+	return identity, ok
+}
+
+// CertificateAuthenticator authenticates clients by the verified TLS client certificate.
+//
+// Identity name is the common name of the certificate, and the groups are the organizations.
+func CertificateAuthenticator() Authenticator { //nolint:ireturn
+	return AuthenticatorFunc(func(ctx context.Context) (Identity, error) {
+		p, ok := peer.FromContext(ctx)
+		if !ok {
+			return Identity{}, status.Error(codes.Unauthenticated, "no peer information")
+		}
+
+		tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+		if !ok {
+			return Identity{}, status.Error(codes.Unauthenticated, "connection is not using TLS")
+		}
+
+		if len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+			return Identity{}, status.Error(codes.Unauthenticated, "no verified client certificate")
+		}
+
+		cert := tlsInfo.State.VerifiedChains[0][0]
+
+		return Identity{
+			Name:   cert.Subject.CommonName,
+			Groups: append([]string(nil), cert.Subject.Organization...),
+		}, nil
+	})
+}
+
+// TokenAuthenticator authenticates clients by the bearer token in the `authorization` request metadata.
+func TokenAuthenticator(verify func(ctx context.Context, token string) (Identity, error)) Authenticator { //nolint:ireturn
+	return AuthenticatorFunc(func(ctx context.Context) (Identity, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return Identity{}, status.Error(codes.Unauthenticated, "missing authorization token")
+		}
+
+		token := strings.TrimPrefix(values[0], "Bearer ")
+		if token == values[0] {
+			return Identity{}, status.Error(codes.Unauthenticated, "authorization is not a bearer token")
+		}
+
+		return verify(ctx, token)
+	})
+}
+
+// UnaryAuthInterceptor authenticates the unary calls and attaches the identity to the request context.
+func UnaryAuthInterceptor(authenticator Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, authenticator)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor authenticates the streaming calls and attaches the identity to the stream context.
+func StreamAuthInterceptor(authenticator Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), authenticator)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context, authenticator Authenticator) (context.Context, error) {
+	identity, err := authenticator.Authenticate(ctx)
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	return ContextWithIdentity(ctx, identity), nil
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+
+	ctx context.Context //nolint:containedctx
+}
+
+func (stream *authenticatedStream) Context() context.Context {
+	return stream.ctx
+}
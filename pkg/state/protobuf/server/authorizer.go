@@ -0,0 +1,144 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// Verb is the operation on the resources.
+type Verb string
+
+// Verb constants.
+const (
+	VerbGet     Verb = "get"
+	VerbList    Verb = "list"
+	VerbWatch   Verb = "watch"
+	VerbCreate  Verb = "create"
+	VerbUpdate  Verb = "update"
+	VerbDestroy Verb = "destroy"
+)
+
+// Authorizer decides whether the client is allowed to perform the operation.
+type Authorizer interface {
+	// Authorize returns an error with codes.PermissionDenied if the verb is not allowed on the type in the namespace.
+	Authorize(ctx context.Context, verb Verb, ns resource.Namespace, typ resource.Type) error
+	// CanReadSensitive returns true if the client can read the specs of the sensitive resources of the type in the namespace.
+	CanReadSensitive(ctx context.Context, ns resource.Namespace, typ resource.Type) bool
+}
+
+// PolicyAuthorizer authorizes the clients with the meta.AccessPolicy resources stored in the state.
+//
+// Policies are read on each request, so the changes to the policies apply immediately.
+// The client identity should be attached to the context by the authentication interceptors.
+type PolicyAuthorizer struct {
+	state state.CoreState
+}
+
+// NewPolicyAuthorizer creates new PolicyAuthorizer.
+func NewPolicyAuthorizer(st state.CoreState) *PolicyAuthorizer {
+	return &PolicyAuthorizer{
+		state: st,
+	}
+}
+
+// Authorize implements Authorizer.
+func (authorizer *PolicyAuthorizer) Authorize(ctx context.Context, verb Verb, ns resource.Namespace, typ resource.Type) error {
+	identity, ok := IdentityFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "request is not authenticated")
+	}
+
+	allowed, err := authorizer.match(ctx, identity, func(rule meta.AccessRule) bool {
+		return matchesAny(rule.Verbs, string(verb)) && matchesAny(rule.Namespaces, ns) && matchesAny(rule.Types, typ)
+	})
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return status.Errorf(codes.PermissionDenied, "%q is not allowed to %s %q in namespace %q", identity.Name, verb, typ, ns)
+	}
+
+	return nil
+}
+
+// CanReadSensitive implements Authorizer.
+func (authorizer *PolicyAuthorizer) CanReadSensitive(ctx context.Context, ns resource.Namespace, typ resource.Type) bool {
+	identity, ok := IdentityFromContext(ctx)
+	if !ok {
+		return false
+	}
+
+	allowed, err := authorizer.match(ctx, identity, func(rule meta.AccessRule) bool {
+		return rule.Sensitive && matchesAny(rule.Namespaces, ns) && matchesAny(rule.Types, typ)
+	})
+
+	return err == nil && allowed
+}
+
+// match returns true if any rule of the policies which apply to the identity matches.
+func (authorizer *PolicyAuthorizer) match(ctx context.Context, identity Identity, matches func(meta.AccessRule) bool) (bool, error) {
+	policies, err := authorizer.state.List(ctx,
+		resource.NewMetadata(meta.NamespaceName, meta.AccessPolicyType, "", resource.VersionUndefined))
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "error listing access policies: %s", err)
+	}
+
+	subjects := make([]string, 0, len(identity.Groups)+1)
+	subjects = append(subjects, meta.SubjectUserPrefix+identity.Name)
+
+	for _, group := range identity.Groups {
+		subjects = append(subjects, meta.SubjectGroupPrefix+group)
+	}
+
+	for _, item := range policies.Items {
+		policy, ok := item.(*meta.AccessPolicy)
+		if !ok {
+			continue
+		}
+
+		spec := policy.TypedSpec()
+
+		if !matchesSubject(spec.Subjects, subjects) {
+			continue
+		}
+
+		for _, rule := range spec.Rules {
+			if matches(rule) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func matchesSubject(policySubjects, subjects []string) bool {
+	for _, subject := range subjects {
+		if matchesAny(policySubjects, subject) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == value {
+			return true
+		}
+	}
+
+	return false
+}
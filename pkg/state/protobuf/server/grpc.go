@@ -0,0 +1,213 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	_ "google.golang.org/grpc/encoding/gzip" // register gzip compressor
+	"google.golang.org/grpc/keepalive"
+)
+
+// GRPCOptions configure the gRPC server serving the State service.
+type GRPCOptions struct {
+	TLSConfig     *tls.Config
+	ClientCAs     *x509.CertPool
+	Authenticator Authenticator
+	RateLimiter   *RateLimiter
+	Metrics       *Metrics
+
+	ImpersonationAuthorizer ImpersonationAuthorizer
+
+	UnaryInterceptors  []grpc.UnaryServerInterceptor
+	StreamInterceptors []grpc.StreamServerInterceptor
+
+	KeepaliveParams *keepalive.ServerParameters
+	KeepalivePolicy *keepalive.EnforcementPolicy
+
+	MaxRecvMsgSize int
+	MaxSendMsgSize int
+}
+
+// GRPCOption applies settings to GRPCOptions.
+type GRPCOption func(options *GRPCOptions)
+
+// WithTLSConfig serves the State service over TLS.
+//
+// Default value is nil (no TLS, the transport security is left to the caller).
+func WithTLSConfig(config *tls.Config) GRPCOption {
+	return func(options *GRPCOptions) {
+		options.TLSConfig = config
+	}
+}
+
+// WithClientCertVerification requires the clients to present certificates signed by one of the CAs (mTLS).
+//
+// Client certificate verification requires TLS config to be set.
+func WithClientCertVerification(clientCAs *x509.CertPool) GRPCOption {
+	return func(options *GRPCOptions) {
+		options.ClientCAs = clientCAs
+	}
+}
+
+// WithAuthenticator authenticates each request, and attaches the client identity to the request context.
+//
+// Default value is nil (requests are not authenticated).
+func WithAuthenticator(authenticator Authenticator) GRPCOption {
+	return func(options *GRPCOptions) {
+		options.Authenticator = authenticator
+	}
+}
+
+// WithImpersonationAuthorizer allows the authenticated clients to act as other identities.
+//
+// Clients request the impersonation with the ImpersonateUserHeader and ImpersonateGroupHeader metadata,
+// the impersonated identity is used for the authorization, rate limiting and audit.
+// Default value is nil (requests with the impersonation metadata are rejected).
+func WithImpersonationAuthorizer(authorize ImpersonationAuthorizer) GRPCOption {
+	return func(options *GRPCOptions) {
+		options.ImpersonationAuthorizer = authorize
+	}
+}
+
+// WithMetrics instruments the requests with the metrics.
+//
+// Metrics run before the authentication, so that the rejected requests are counted as well.
+// Default value is nil (requests are not instrumented).
+func WithMetrics(metrics *Metrics) GRPCOption {
+	return func(options *GRPCOptions) {
+		options.Metrics = metrics
+	}
+}
+
+// WithRateLimiter enforces the per-client limits of the rate limiter on the requests.
+//
+// Rate limiter runs after the authentication, so that the clients are identified by their identity.
+// Default value is nil (requests are not limited).
+func WithRateLimiter(limiter *RateLimiter) GRPCOption {
+	return func(options *GRPCOptions) {
+		options.RateLimiter = limiter
+	}
+}
+
+// WithUnaryInterceptors adds the unary interceptors to the server.
+//
+// Interceptors run after the authentication, so they can access the client identity.
+func WithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) GRPCOption {
+	return func(options *GRPCOptions) {
+		options.UnaryInterceptors = append(options.UnaryInterceptors, interceptors...)
+	}
+}
+
+// WithStreamInterceptors adds the stream interceptors to the server.
+//
+// Interceptors run after the authentication, so they can access the client identity.
+func WithStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) GRPCOption {
+	return func(options *GRPCOptions) {
+		options.StreamInterceptors = append(options.StreamInterceptors, interceptors...)
+	}
+}
+
+// WithMaxMessageSize sets the maximum size of the messages the server can receive and send.
+//
+// Default value is 0 (gRPC defaults are used: 4MB for received messages, unlimited for sent messages).
+func WithMaxMessageSize(recv, send int) GRPCOption {
+	return func(options *GRPCOptions) {
+		options.MaxRecvMsgSize = recv
+		options.MaxSendMsgSize = send
+	}
+}
+
+// WithKeepalive sets the keepalive parameters of the server, and the keepalive policy enforced on the clients.
+//
+// Default value is nil (gRPC defaults are used).
+func WithKeepalive(params keepalive.ServerParameters, policy keepalive.EnforcementPolicy) GRPCOption {
+	return func(options *GRPCOptions) {
+		options.KeepaliveParams = &params
+		options.KeepalivePolicy = &policy
+	}
+}
+
+// GRPCServerOptions builds the gRPC server options which set up the transport security, the authentication, the interceptors
+// and the transport limits.
+//
+// The options should be passed to grpc.NewServer which serves the State service.
+// The server responds with the compression used by the client, gzip compressor is always registered,
+// other compressors (e.g. zstd) should be registered with encoding.RegisterCompressor by the application.
+func GRPCServerOptions(opts ...GRPCOption) []grpc.ServerOption {
+	var options GRPCOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var serverOpts []grpc.ServerOption
+
+	if options.TLSConfig != nil {
+		config := options.TLSConfig.Clone()
+
+		if config.MinVersion == 0 {
+			config.MinVersion = tls.VersionTLS12
+		}
+
+		if options.ClientCAs != nil {
+			config.ClientCAs = options.ClientCAs
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(config)))
+	}
+
+	if options.MaxRecvMsgSize > 0 {
+		serverOpts = append(serverOpts, grpc.MaxRecvMsgSize(options.MaxRecvMsgSize))
+	}
+
+	if options.MaxSendMsgSize > 0 {
+		serverOpts = append(serverOpts, grpc.MaxSendMsgSize(options.MaxSendMsgSize))
+	}
+
+	if options.KeepaliveParams != nil {
+		serverOpts = append(serverOpts, grpc.KeepaliveParams(*options.KeepaliveParams), grpc.KeepaliveEnforcementPolicy(*options.KeepalivePolicy))
+	}
+
+	var (
+		unaryInterceptors  []grpc.UnaryServerInterceptor
+		streamInterceptors []grpc.StreamServerInterceptor
+	)
+
+	if options.Metrics != nil {
+		unaryInterceptors = append(unaryInterceptors, options.Metrics.UnaryServerInterceptor())
+		streamInterceptors = append(streamInterceptors, options.Metrics.StreamServerInterceptor())
+	}
+
+	if options.Authenticator != nil {
+		unaryInterceptors = append(unaryInterceptors, UnaryAuthInterceptor(options.Authenticator))
+		streamInterceptors = append(streamInterceptors, StreamAuthInterceptor(options.Authenticator))
+
+		unaryInterceptors = append(unaryInterceptors, UnaryImpersonationInterceptor(options.ImpersonationAuthorizer))
+		streamInterceptors = append(streamInterceptors, StreamImpersonationInterceptor(options.ImpersonationAuthorizer))
+	}
+
+	if options.RateLimiter != nil {
+		unaryInterceptors = append(unaryInterceptors, options.RateLimiter.UnaryServerInterceptor())
+		streamInterceptors = append(streamInterceptors, options.RateLimiter.StreamServerInterceptor())
+	}
+
+	unaryInterceptors = append(unaryInterceptors, options.UnaryInterceptors...)
+	streamInterceptors = append(streamInterceptors, options.StreamInterceptors...)
+
+	if len(unaryInterceptors) > 0 {
+		serverOpts = append(serverOpts, grpc.ChainUnaryInterceptor(unaryInterceptors...))
+	}
+
+	if len(streamInterceptors) > 0 {
+		serverOpts = append(serverOpts, grpc.ChainStreamInterceptor(streamInterceptors...))
+	}
+
+	return serverOpts
+}
@@ -0,0 +1,199 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/cosi-project/runtime/api/v1alpha1"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// healthProbeType is the resource type which is fetched to check the backend, it is never stored.
+const healthProbeType = resource.Type("HealthProbes.cosi.dev")
+
+// HealthOptions configure Health.
+type HealthOptions struct {
+	// Check probes the backend serving the namespace, nil error means the namespace is healthy.
+	Check func(ctx context.Context, st state.CoreState, namespace resource.Namespace) error
+
+	Namespaces []resource.Namespace
+
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+// HealthOption applies settings to HealthOptions.
+type HealthOption func(options *HealthOptions)
+
+// WithHealthNamespaces reports the serving status of each namespace.
+//
+// The status of the namespace is reported under the service name "cosi.resource.State/<namespace>".
+// Default value is nil (only the overall status is reported).
+func WithHealthNamespaces(namespaces ...resource.Namespace) HealthOption {
+	return func(options *HealthOptions) {
+		options.Namespaces = append(options.Namespaces, namespaces...)
+	}
+}
+
+// WithHealthCheck sets the function which probes the backend serving the namespace.
+//
+// Default check fetches a resource which doesn't exist, and treats the not found error as healthy.
+func WithHealthCheck(check func(ctx context.Context, st state.CoreState, namespace resource.Namespace) error) HealthOption {
+	return func(options *HealthOptions) {
+		options.Check = check
+	}
+}
+
+// WithHealthInterval sets the interval between the probes, and the timeout of each probe.
+//
+// Default values are 10s and 5s.
+func WithHealthInterval(interval, timeout time.Duration) HealthOption {
+	return func(options *HealthOptions) {
+		options.Interval = interval
+		options.Timeout = timeout
+	}
+}
+
+// DefaultHealthOptions returns default value of HealthOptions.
+func DefaultHealthOptions() HealthOptions {
+	return HealthOptions{
+		Check:    probeNamespace,
+		Interval: 10 * time.Second,
+		Timeout:  5 * time.Second,
+	}
+}
+
+// Health implements the standard gRPC health service for the State service.
+//
+// The overall status is reported under both the empty service name and "cosi.resource.State",
+// it is SERVING only if the server is ready (see SetReady) and all namespaces are healthy.
+// Health should be registered with grpc_health_v1.RegisterHealthServer next to the State service,
+// and the probes should be run with Run.
+type Health struct {
+	*health.Server
+
+	st        state.CoreState
+	unhealthy map[resource.Namespace]struct{}
+	options   HealthOptions
+	mu        sync.Mutex
+	ready     bool
+	probed    bool
+}
+
+// NewHealth creates new Health probing the state.
+//
+// Health reports NOT_SERVING until the server is marked ready and the first probes succeed.
+func NewHealth(st state.CoreState, opts ...HealthOption) *Health {
+	options := DefaultHealthOptions()
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	h := &Health{
+		Server:    health.NewServer(),
+		st:        st,
+		unhealthy: map[resource.Namespace]struct{}{},
+		options:   options,
+	}
+
+	h.update()
+
+	return h
+}
+
+// SetReady opens or closes the readiness gate.
+//
+// The server should be marked ready once the backend is available (e.g. the database is open),
+// and marked not ready before the backend is shut down, so that the clients move away first.
+func (h *Health) SetReady(ready bool) {
+	h.mu.Lock()
+	h.ready = ready
+	h.mu.Unlock()
+
+	h.update()
+}
+
+// Run probes the namespaces until the context is canceled.
+func (h *Health) Run(ctx context.Context) error {
+	ticker := time.NewTicker(h.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		h.Probe(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Probe checks the namespaces once, and updates the serving status.
+func (h *Health) Probe(ctx context.Context) {
+	unhealthy := map[resource.Namespace]struct{}{}
+
+	for _, namespace := range h.options.Namespaces {
+		if err := h.probe(ctx, namespace); err != nil {
+			unhealthy[namespace] = struct{}{}
+		}
+	}
+
+	h.mu.Lock()
+	h.unhealthy = unhealthy
+	h.probed = true
+	h.mu.Unlock()
+
+	h.update()
+}
+
+func (h *Health) probe(ctx context.Context, namespace resource.Namespace) error {
+	ctx, cancel := context.WithTimeout(ctx, h.options.Timeout)
+	defer cancel()
+
+	return h.options.Check(ctx, h.st, namespace)
+}
+
+// update sets the serving status of the services.
+func (h *Health) update() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	overall := h.ready && h.probed && len(h.unhealthy) == 0
+
+	h.Server.SetServingStatus("", servingStatus(overall))
+	h.Server.SetServingStatus(v1alpha1.State_ServiceDesc.ServiceName, servingStatus(overall))
+
+	for _, namespace := range h.options.Namespaces {
+		_, unhealthy := h.unhealthy[namespace]
+
+		h.Server.SetServingStatus(v1alpha1.State_ServiceDesc.ServiceName+"/"+namespace, servingStatus(h.ready && h.probed && !unhealthy))
+	}
+}
+
+func servingStatus(serving bool) healthpb.HealthCheckResponse_ServingStatus {
+	if serving {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
+
+func probeNamespace(ctx context.Context, st state.CoreState, namespace resource.Namespace) error {
+	_, err := st.Get(ctx, resource.NewMetadata(namespace, healthProbeType, "", resource.VersionUndefined))
+	if state.IsNotFoundError(err) {
+		return nil
+	}
+
+	return err
+}
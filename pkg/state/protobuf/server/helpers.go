@@ -10,6 +10,7 @@ import (
 
 	"github.com/cosi-project/runtime/api/v1alpha1"
 	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
 )
 
 // ConvertLabelQuery converts protobuf representation of LabelQuery to state representation.
@@ -31,3 +32,41 @@ func ConvertLabelQuery(terms []*v1alpha1.LabelTerm) ([]resource.LabelQueryOption
 
 	return labelOpts, nil
 }
+
+// ConvertResourceDefinition converts resource definition spec to protobuf representation.
+func ConvertResourceDefinition(spec meta.ResourceDefinitionSpec) *v1alpha1.ResourceDefinition {
+	definition := &v1alpha1.ResourceDefinition{
+		Type:             spec.Type,
+		DisplayType:      spec.DisplayType,
+		Singular:         spec.Singular,
+		Plural:           spec.Plural,
+		DefaultNamespace: spec.DefaultNamespace,
+		Aliases:          spec.Aliases,
+		AllAliases:       spec.AllAliases,
+		Categories:       spec.Categories,
+		Sensitivity:      string(spec.Sensitivity),
+		Deprecated:       spec.Deprecated,
+		ReplacedBy:       spec.ReplacedBy,
+		RemovalVersion:   spec.RemovalVersion,
+		IndexedLabels:    spec.IndexedLabels,
+	}
+
+	for _, column := range spec.PrintColumns {
+		definition.PrintColumns = append(definition.PrintColumns, &v1alpha1.ResourceDefinition_PrintColumn{
+			Name:     column.Name,
+			JsonPath: column.JSONPath,
+			Format:   string(column.Format),
+			Priority: int32(column.Priority),
+			Width:    int32(column.Width),
+		})
+	}
+
+	for _, phase := range spec.CustomPhases {
+		definition.CustomPhases = append(definition.CustomPhases, &v1alpha1.ResourceDefinition_CustomPhase{
+			Name:        phase.Name,
+			Transitions: phase.Transitions,
+		})
+	}
+
+	return definition
+}
@@ -0,0 +1,46 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package server_test
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/cosi-project/runtime/api/v1alpha1"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/resourcetest"
+	"github.com/cosi-project/runtime/pkg/state/protobuf/server"
+)
+
+func FuzzConvertLabelQuery(f *testing.F) {
+	for _, seed := range resourcetest.LabelQueryCorpus() {
+		f.Add(seed, "app", "web")
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte, key, value string) {
+		var query v1alpha1.LabelQuery
+
+		if err := proto.Unmarshal(data, &query); err != nil {
+			return
+		}
+
+		opts, err := server.ConvertLabelQuery(query.GetTerms())
+		if err != nil {
+			return
+		}
+
+		var labelQuery resource.LabelQuery
+
+		for _, opt := range opts {
+			opt(&labelQuery)
+		}
+
+		labels := resource.Labels{}
+		labels.Set(key, value)
+
+		labelQuery.Matches(labels)
+	})
+}
@@ -0,0 +1,102 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Impersonation request metadata keys.
+//
+// The user key carries the name of the impersonated identity, the group key can be repeated for each group.
+const (
+	ImpersonateUserHeader  = "cosi-impersonate-user"
+	ImpersonateGroupHeader = "cosi-impersonate-group"
+)
+
+// ImpersonationAuthorizer decides whether the authenticated actor is allowed to act as the target identity.
+//
+// ImpersonationAuthorizer should return an error with codes.PermissionDenied if the impersonation is not allowed.
+type ImpersonationAuthorizer func(ctx context.Context, actor, target Identity) error
+
+// UnaryImpersonationInterceptor replaces the identity of the unary calls with the impersonated identity.
+//
+// The interceptor should run after the authentication interceptor.
+func UnaryImpersonationInterceptor(authorize ImpersonationAuthorizer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := impersonate(ctx, authorize)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamImpersonationInterceptor replaces the identity of the streaming calls with the impersonated identity.
+//
+// The interceptor should run after the authentication interceptor.
+func StreamImpersonationInterceptor(authorize ImpersonationAuthorizer) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := impersonate(ss.Context(), authorize)
+		if err != nil {
+			return err
+		}
+
+		if ctx == ss.Context() {
+			return handler(srv, ss)
+		}
+
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// impersonate attaches the impersonated identity to the context if the request carries the impersonation metadata.
+//
+// The identity of the actor is kept in the ImpersonatedBy field, so that it can be audited.
+func impersonate(ctx context.Context, authorize ImpersonationAuthorizer) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	users := md.Get(ImpersonateUserHeader)
+	groups := md.Get(ImpersonateGroupHeader)
+
+	switch {
+	case len(users) == 0 && len(groups) == 0:
+		return ctx, nil
+	case len(users) != 1 || users[0] == "":
+		return nil, status.Error(codes.InvalidArgument, "impersonation requires exactly one user")
+	}
+
+	actor, ok := IdentityFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "request is not authenticated")
+	}
+
+	if authorize == nil {
+		return nil, status.Errorf(codes.PermissionDenied, "%q is not allowed to impersonate", actor.Name)
+	}
+
+	target := Identity{
+		Name:   users[0],
+		Groups: groups,
+	}
+
+	if err := authorize(ctx, actor, target); err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	target.ImpersonatedBy = &actor
+
+	return ContextWithIdentity(ctx, target), nil
+}
@@ -0,0 +1,155 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package server
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/cosi-project/runtime/api/v1alpha1"
+)
+
+// Metrics instruments the State service, it implements prometheus.Collector.
+//
+// Metrics should be registered with the Prometheus registry, and the interceptors should be set up with WithMetrics.
+type Metrics struct {
+	requests     *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+	watches      prometheus.Gauge
+	eventsSent   prometheus.Counter
+	stateService string
+}
+
+// NewMetrics creates new Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "cosi",
+			Subsystem: "state_server",
+			Name:      "requests_total",
+			Help:      "Number of the requests handled by the state server by verb and status code.",
+		}, []string{"verb", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "cosi",
+			Subsystem: "state_server",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of the requests handled by the state server by verb, watches are not included.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"verb"}),
+		watches: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "cosi",
+			Subsystem: "state_server",
+			Name:      "active_watches",
+			Help:      "Number of the watches currently served by the state server.",
+		}),
+		eventsSent: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "cosi",
+			Subsystem: "state_server",
+			Name:      "watch_events_sent_total",
+			Help:      "Number of the watch events sent by the state server.",
+		}),
+		stateService: "/" + v1alpha1.State_ServiceDesc.ServiceName + "/",
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (metrics *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	metrics.requests.Describe(ch)
+	metrics.duration.Describe(ch)
+	metrics.watches.Describe(ch)
+	metrics.eventsSent.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (metrics *Metrics) Collect(ch chan<- prometheus.Metric) {
+	metrics.requests.Collect(ch)
+	metrics.duration.Collect(ch)
+	metrics.watches.Collect(ch)
+	metrics.eventsSent.Collect(ch)
+}
+
+// UnaryServerInterceptor returns the interceptor which instruments unary requests.
+func (metrics *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		verb, ok := metrics.verb(info.FullMethod)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		metrics.observe(verb, start, err)
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns the interceptor which instruments stream requests.
+func (metrics *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		verb, ok := metrics.verb(info.FullMethod)
+		if !ok {
+			return handler(srv, ss)
+		}
+
+		if verb != VerbWatch {
+			start := time.Now()
+
+			err := handler(srv, ss)
+
+			metrics.observe(verb, start, err)
+
+			return err
+		}
+
+		metrics.watches.Inc()
+		defer metrics.watches.Dec()
+
+		err := handler(srv, &instrumentedStream{ServerStream: ss, eventsSent: metrics.eventsSent})
+
+		metrics.requests.WithLabelValues(string(verb), status.Code(err).String()).Inc()
+
+		return err
+	}
+}
+
+func (metrics *Metrics) observe(verb Verb, start time.Time, err error) {
+	metrics.requests.WithLabelValues(string(verb), status.Code(err).String()).Inc()
+	metrics.duration.WithLabelValues(string(verb)).Observe(time.Since(start).Seconds())
+}
+
+// verb returns the verb of the State service method.
+func (metrics *Metrics) verb(fullMethod string) (Verb, bool) {
+	if !strings.HasPrefix(fullMethod, metrics.stateService) {
+		return "", false
+	}
+
+	return Verb(strings.ToLower(strings.TrimPrefix(fullMethod, metrics.stateService))), true
+}
+
+// instrumentedStream counts the watch events sent to the client.
+type instrumentedStream struct {
+	grpc.ServerStream
+
+	eventsSent prometheus.Counter
+}
+
+func (stream *instrumentedStream) SendMsg(m interface{}) error {
+	err := stream.ServerStream.SendMsg(m)
+
+	// the first empty message signals that the watch is ready, it's not an event
+	if resp, ok := m.(*v1alpha1.WatchResponse); ok && err == nil && resp.Event != nil {
+		stream.eventsSent.Inc()
+	}
+
+	return err
+}
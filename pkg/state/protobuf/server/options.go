@@ -0,0 +1,97 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package server
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// DeprecationWarningHeader is the gRPC header which carries the warning when a deprecated resource type is accessed.
+const DeprecationWarningHeader = "cosi-deprecation-warning"
+
+// StateOptions configure State.
+type StateOptions struct {
+	CanReadSensitive func(ctx context.Context) bool
+	Authorizer       Authorizer
+	Logger           *zap.Logger
+	Middlewares      []state.Middleware
+	AuditSinks       []AuditSink
+
+	DeprecationWarnings bool
+}
+
+// StateOption applies settings to StateOptions.
+type StateOption func(options *StateOptions)
+
+// WithCanReadSensitive sets the check whether the client has the capability to read sensitive resources.
+//
+// Specs of the sensitive resources are redacted for clients without the capability.
+// Default value is nil (all clients can read sensitive resources).
+func WithCanReadSensitive(check func(ctx context.Context) bool) StateOption {
+	return func(options *StateOptions) {
+		options.CanReadSensitive = check
+	}
+}
+
+// WithAuthorizer authorizes each request.
+//
+// Requests which are not allowed are rejected, and the specs of the sensitive resources are redacted
+// unless the authorizer allows reading them.
+// Default value is nil (all requests are allowed).
+func WithAuthorizer(authorizer Authorizer) StateOption {
+	return func(options *StateOptions) {
+		options.Authorizer = authorizer
+	}
+}
+
+// WithMiddlewares wraps the state served by the server with the middlewares.
+//
+// Middlewares see the operations after the request is authorized.
+func WithMiddlewares(middlewares ...state.Middleware) StateOption {
+	return func(options *StateOptions) {
+		options.Middlewares = append(options.Middlewares, middlewares...)
+	}
+}
+
+// WithAuditSinks emits an audit record for every mutating call (create, update, destroy) to the sinks.
+//
+// Calls rejected by the authentication interceptors never reach the server, so they are not audited.
+// Default value is nil (calls are not audited).
+func WithAuditSinks(sinks ...AuditSink) StateOption {
+	return func(options *StateOptions) {
+		options.AuditSinks = append(options.AuditSinks, sinks...)
+	}
+}
+
+// WithDeprecationWarnings enables the warnings when deprecated resource types are accessed.
+//
+// Warnings are logged and sent to the client in the DeprecationWarningHeader.
+// Each request looks up the resource definition of the type in the state.
+// Default value is false (no warnings).
+func WithDeprecationWarnings() StateOption {
+	return func(options *StateOptions) {
+		options.DeprecationWarnings = true
+	}
+}
+
+// WithLogger sets the logger used to log the warnings.
+//
+// Default value is zap.NewNop().
+func WithLogger(logger *zap.Logger) StateOption {
+	return func(options *StateOptions) {
+		options.Logger = logger
+	}
+}
+
+// DefaultStateOptions returns default value of StateOptions.
+func DefaultStateOptions() StateOptions {
+	return StateOptions{
+		Logger: zap.NewNop(),
+	}
+}
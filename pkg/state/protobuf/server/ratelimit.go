@@ -0,0 +1,335 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// RateLimitOptions configure RateLimiter.
+type RateLimitOptions struct {
+	ClientQPS         float64
+	ClientBurst       int
+	ClientConcurrency int
+	TotalConcurrency  int
+}
+
+// RateLimitOption applies settings to RateLimitOptions.
+type RateLimitOption func(options *RateLimitOptions)
+
+// WithClientQPS limits the rate of the requests of each client, allowing bursts of up to burst requests.
+//
+// Requests over the limit wait until they are allowed.
+// Default value is 0 (no rate limit).
+func WithClientQPS(qps float64, burst int) RateLimitOption {
+	return func(options *RateLimitOptions) {
+		options.ClientQPS = qps
+		options.ClientBurst = burst
+	}
+}
+
+// WithClientConcurrency limits the number of the concurrent unary requests of each client.
+//
+// Default value is 0 (no limit).
+func WithClientConcurrency(n int) RateLimitOption {
+	return func(options *RateLimitOptions) {
+		options.ClientConcurrency = n
+	}
+}
+
+// WithTotalConcurrency limits the number of the concurrent unary requests of all clients.
+//
+// Requests over the limit are queued per client, and the queues are served round-robin,
+// so that a client with many requests doesn't starve the other clients.
+// Default value is 0 (no limit).
+func WithTotalConcurrency(n int) RateLimitOption {
+	return func(options *RateLimitOptions) {
+		options.TotalConcurrency = n
+	}
+}
+
+// DefaultRateLimitOptions returns default value of RateLimitOptions.
+func DefaultRateLimitOptions() RateLimitOptions {
+	return RateLimitOptions{}
+}
+
+// RateLimiter enforces the per-client rate and concurrency limits on the requests.
+//
+// Clients are identified by the identity attached to the request context by the authentication interceptors,
+// unauthenticated requests share the limits of a single client.
+// Stream requests (list and watch) are subject to the rate limit only, as watches are long-lived.
+type RateLimiter struct {
+	clients  map[string]*clientLimits
+	queue    []string
+	options  RateLimitOptions
+	inFlight int
+	mu       sync.Mutex
+}
+
+type clientLimits struct {
+	last     time.Time
+	waiters  []chan struct{}
+	tokens   float64
+	inFlight int
+}
+
+// NewRateLimiter creates new RateLimiter.
+func NewRateLimiter(opts ...RateLimitOption) *RateLimiter {
+	options := DefaultRateLimitOptions()
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.ClientQPS > 0 && options.ClientBurst < 1 {
+		options.ClientBurst = 1
+	}
+
+	return &RateLimiter{
+		clients: map[string]*clientLimits{},
+		options: options,
+	}
+}
+
+// UnaryServerInterceptor returns the interceptor which limits unary requests.
+func (limiter *RateLimiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		identity, _ := IdentityFromContext(ctx)
+
+		release, err := limiter.acquire(ctx, identity.Name, true)
+		if err != nil {
+			return nil, err
+		}
+
+		defer release()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns the interceptor which limits stream requests.
+func (limiter *RateLimiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		identity, _ := IdentityFromContext(ss.Context())
+
+		release, err := limiter.acquire(ss.Context(), identity.Name, false)
+		if err != nil {
+			return err
+		}
+
+		release()
+
+		return handler(srv, ss)
+	}
+}
+
+// acquire waits until the request of the client is allowed to run.
+//
+// The returned function should be called once the request is finished.
+func (limiter *RateLimiter) acquire(ctx context.Context, name string, concurrent bool) (func(), error) {
+	if err := limiter.wait(ctx, name); err != nil {
+		return nil, err
+	}
+
+	if !concurrent || (limiter.options.ClientConcurrency == 0 && limiter.options.TotalConcurrency == 0) {
+		limiter.mu.Lock()
+		limiter.cleanup(name)
+		limiter.mu.Unlock()
+
+		return func() {}, nil
+	}
+
+	limiter.mu.Lock()
+
+	client := limiter.client(name)
+	ch := make(chan struct{})
+
+	if len(client.waiters) == 0 {
+		limiter.queue = append(limiter.queue, name)
+	}
+
+	client.waiters = append(client.waiters, ch)
+
+	limiter.dispatch()
+	limiter.mu.Unlock()
+
+	release := func() {
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+
+		limiter.inFlight--
+		limiter.clients[name].inFlight--
+
+		limiter.dispatch()
+		limiter.cleanup(name)
+	}
+
+	select {
+	case <-ch:
+		return release, nil
+	case <-ctx.Done():
+	}
+
+	limiter.mu.Lock()
+	removed := limiter.removeWaiter(name, ch)
+	limiter.mu.Unlock()
+
+	if !removed {
+		// the request was admitted concurrently with the cancellation
+		release()
+	}
+
+	return nil, status.FromContextError(ctx.Err()).Err()
+}
+
+// removeWaiter returns false if the waiter was already admitted.
+//
+// removeWaiter should be called with limiter.mu held.
+func (limiter *RateLimiter) removeWaiter(name string, ch chan struct{}) bool {
+	client := limiter.clients[name]
+
+	for i := range client.waiters {
+		if client.waiters[i] == ch {
+			client.waiters = append(client.waiters[:i], client.waiters[i+1:]...)
+
+			if len(client.waiters) == 0 {
+				limiter.dequeue(name)
+			}
+
+			limiter.cleanup(name)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// wait blocks until the rate limit of the client allows the request.
+func (limiter *RateLimiter) wait(ctx context.Context, name string) error {
+	if limiter.options.ClientQPS <= 0 {
+		return nil
+	}
+
+	limiter.mu.Lock()
+
+	client := limiter.client(name)
+	client.refill(time.Now(), limiter.options)
+
+	// reserve the token, the deficit is covered by waiting
+	client.tokens--
+	delay := time.Duration(-client.tokens / limiter.options.ClientQPS * float64(time.Second))
+
+	limiter.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+	}
+
+	// return the reserved token
+	limiter.mu.Lock()
+	limiter.client(name).tokens++
+	limiter.cleanup(name)
+	limiter.mu.Unlock()
+
+	return status.FromContextError(ctx.Err()).Err()
+}
+
+// client should be called with limiter.mu held.
+func (limiter *RateLimiter) client(name string) *clientLimits {
+	client, ok := limiter.clients[name]
+	if !ok {
+		client = &clientLimits{
+			tokens: float64(limiter.options.ClientBurst),
+			last:   time.Now(),
+		}
+
+		limiter.clients[name] = client
+	}
+
+	return client
+}
+
+// dispatch admits the queued requests round-robin across the clients while the limits allow.
+//
+// dispatch should be called with limiter.mu held.
+func (limiter *RateLimiter) dispatch() {
+	for skipped := 0; skipped < len(limiter.queue); {
+		if limiter.options.TotalConcurrency > 0 && limiter.inFlight >= limiter.options.TotalConcurrency {
+			return
+		}
+
+		name := limiter.queue[0]
+		limiter.queue = limiter.queue[1:]
+
+		client := limiter.clients[name]
+
+		if limiter.options.ClientConcurrency > 0 && client.inFlight >= limiter.options.ClientConcurrency {
+			skipped++
+		} else {
+			close(client.waiters[0])
+			client.waiters = client.waiters[1:]
+
+			client.inFlight++
+			limiter.inFlight++
+
+			skipped = 0
+		}
+
+		if len(client.waiters) > 0 {
+			limiter.queue = append(limiter.queue, name)
+		}
+	}
+}
+
+// dequeue should be called with limiter.mu held.
+func (limiter *RateLimiter) dequeue(name string) {
+	for i := range limiter.queue {
+		if limiter.queue[i] == name {
+			limiter.queue = append(limiter.queue[:i], limiter.queue[i+1:]...)
+
+			return
+		}
+	}
+}
+
+// cleanup forgets the idle client which has the full rate limit budget.
+//
+// cleanup should be called with limiter.mu held.
+func (limiter *RateLimiter) cleanup(name string) {
+	client, ok := limiter.clients[name]
+	if !ok || client.inFlight > 0 || len(client.waiters) > 0 {
+		return
+	}
+
+	client.refill(time.Now(), limiter.options)
+
+	if limiter.options.ClientQPS <= 0 || client.tokens >= float64(limiter.options.ClientBurst) {
+		delete(limiter.clients, name)
+	}
+}
+
+func (client *clientLimits) refill(now time.Time, options RateLimitOptions) {
+	client.tokens += now.Sub(client.last).Seconds() * options.ClientQPS
+	client.last = now
+
+	if client.tokens > float64(options.ClientBurst) {
+		client.tokens = float64(options.ClientBurst)
+	}
+}
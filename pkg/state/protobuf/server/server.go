@@ -7,27 +7,243 @@ package server
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/cosi-project/runtime/api/v1alpha1"
 	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
 	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf/merge"
 	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/registry"
 )
 
 // State implements gRPC State service.
 type State struct {
 	v1alpha1.UnimplementedStateServer
 
-	state state.CoreState
+	state   state.CoreState
+	backend state.CoreState
+	options StateOptions
 }
 
 // NewState initializes new gRPC State service implementation.
-func NewState(state state.CoreState) *State {
+func NewState(st state.CoreState, opts ...StateOption) *State {
+	options := DefaultStateOptions()
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	return &State{
-		state: state,
+		state:   state.Chain(st, options.Middlewares...),
+		backend: st,
+		options: options,
+	}
+}
+
+// sensitivityCache holds the sensitivity of the resource types looked up during a single request.
+//
+// Resource definition of each type is looked up once per request, even if the request returns many resources.
+// Watches keep the cache for the lifetime of the watch.
+type sensitivityCache map[resource.Type]bool
+
+// marshal the resource redacting the spec if the client can't read sensitive resources.
+func (server *State) marshal(ctx context.Context, cache sensitivityCache, r resource.Resource) (*v1alpha1.Resource, error) {
+	redacted, err := server.redacted(ctx, cache, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if redacted {
+		r = meta.NewRedacted(*r.Metadata())
+	}
+
+	protoR, err := protobuf.FromResource(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return protoR.Marshal()
+}
+
+// redacted checks whether the spec of the resource should be hidden from the client.
+func (server *State) redacted(ctx context.Context, cache sensitivityCache, r resource.Resource) (bool, error) {
+	if resource.IsTombstone(r) || server.canReadSensitive(ctx, r) {
+		return false, nil
+	}
+
+	return server.isSensitive(ctx, cache, r)
+}
+
+// specMessage is implemented by the specs backed by protobuf messages (see protobuf.ResourceSpec).
+type specMessage interface {
+	GetValue() proto.Message
+}
+
+// specDelta computes the delta of the updated resource spec against the previous version.
+//
+// Nil is returned if the delta can't be computed, or if it's not smaller than the full spec.
+func (server *State) specDelta(ctx context.Context, cache sensitivityCache, event state.Event, full *v1alpha1.Resource) (*v1alpha1.SpecDelta, error) {
+	if event.Type != state.Updated || event.Old == nil {
+		return nil, nil
+	}
+
+	oldSpec, ok := event.Old.Spec().(specMessage)
+	if !ok {
+		return nil, nil
+	}
+
+	newSpec, ok := event.Resource.Spec().(specMessage)
+	if !ok {
+		return nil, nil
+	}
+
+	if oldSpec.GetValue().ProtoReflect().Descriptor().FullName() != newSpec.GetValue().ProtoReflect().Descriptor().FullName() {
+		return nil, nil
+	}
+
+	if redacted, err := server.redacted(ctx, cache, event.Resource); err != nil || redacted {
+		return nil, err
+	}
+
+	delta, err := merge.Diff(oldSpec.GetValue(), newSpec.GetValue())
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := protobuf.ProtoMarshal(delta.Patch)
+	if err != nil {
+		return nil, err
+	}
+
+	if proto.Size(full.GetSpec()) <= len(patch) {
+		return nil, nil
+	}
+
+	return &v1alpha1.SpecDelta{
+		Patch:   patch,
+		Cleared: delta.Cleared,
+	}, nil
+}
+
+// canReadSensitive checks whether the client can read the resource if it is sensitive.
+func (server *State) canReadSensitive(ctx context.Context, r resource.Resource) bool {
+	if server.options.CanReadSensitive != nil && !server.options.CanReadSensitive(ctx) {
+		return false
+	}
+
+	if server.options.Authorizer != nil && !server.options.Authorizer.CanReadSensitive(ctx, r.Metadata().Namespace(), r.Metadata().Type()) {
+		return false
+	}
+
+	return true
+}
+
+// authorize checks whether the client is allowed to perform the operation.
+func (server *State) authorize(ctx context.Context, verb Verb, ns resource.Namespace, typ resource.Type) error {
+	if server.options.Authorizer == nil {
+		return nil
+	}
+
+	return server.options.Authorizer.Authorize(ctx, verb, ns, typ)
+}
+
+// isSensitive checks the resource definition of the resource.
+//
+// If the resource doesn't provide the resource definition, it is looked up in the state once per request.
+// Resource definitions which can't be decoded are treated as sensitive.
+func (server *State) isSensitive(ctx context.Context, cache sensitivityCache, r resource.Resource) (bool, error) {
+	if provider, ok := r.(meta.ResourceDefinitionProvider); ok {
+		return provider.ResourceDefinition().Sensitivity == meta.Sensitive, nil
+	}
+
+	typ := r.Metadata().Type()
+
+	if sensitive, cached := cache[typ]; cached {
+		return sensitive, nil
+	}
+
+	rd, err := server.lookupDefinition(ctx, typ)
+	if err != nil {
+		return false, err
+	}
+
+	var sensitive bool
+
+	if rd != nil {
+		typedRD, ok := rd.(*meta.ResourceDefinition)
+
+		sensitive = !ok || typedRD.TypedSpec().Sensitivity == meta.Sensitive
+	}
+
+	cache[typ] = sensitive
+
+	return sensitive, nil
+}
+
+// lookupDefinition looks up the resource definition of the type in the state.
+//
+// If the resource definition doesn't exist, nil is returned.
+func (server *State) lookupDefinition(ctx context.Context, typ resource.Type) (resource.Resource, error) {
+	rd, err := server.backend.Get(ctx,
+		resource.NewMetadata(meta.NamespaceName, meta.ResourceDefinitionType, strings.ToLower(typ), resource.VersionUndefined))
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error looking up resource definition for %q: %w", typ, err)
+	}
+
+	return rd, nil
+}
+
+// warnDeprecated sends the deprecation warning to the client if the resource type is deprecated.
+//
+// Warnings are enabled with WithDeprecationWarnings.
+// Warnings are best effort: failures to look up the resource definition or to send the header don't fail the request.
+func (server *State) warnDeprecated(ctx context.Context, typ resource.Type, setHeader func(metadata.MD) error) {
+	if !server.options.DeprecationWarnings {
+		return
+	}
+
+	rd, err := server.lookupDefinition(ctx, typ)
+	if err != nil {
+		server.options.Logger.Warn("failed to check deprecation", zap.String("type", typ), zap.Error(err))
+
+		return
+	}
+
+	typedRD, ok := rd.(*meta.ResourceDefinition)
+	if !ok {
+		return
+	}
+
+	warning := typedRD.TypedSpec().DeprecationWarning()
+	if warning == "" {
+		return
+	}
+
+	server.options.Logger.Warn("deprecated resource type accessed", zap.String("type", typ), zap.String("warning", warning))
+
+	setHeader(metadata.Pairs(DeprecationWarningHeader, warning)) //nolint:errcheck
+}
+
+// unaryHeader sets the header of the unary call.
+func unaryHeader(ctx context.Context) func(metadata.MD) error {
+	return func(md metadata.MD) error {
+		return grpc.SetHeader(ctx, md)
 	}
 }
 
@@ -35,6 +251,12 @@ func NewState(state state.CoreState) *State {
 //
 // If a resource is not found, error is returned.
 func (server *State) Get(ctx context.Context, req *v1alpha1.GetRequest) (*v1alpha1.GetResponse, error) {
+	if err := server.authorize(ctx, VerbGet, req.Namespace, req.Type); err != nil {
+		return nil, err
+	}
+
+	server.warnDeprecated(ctx, req.Type, unaryHeader(ctx))
+
 	r, err := server.state.Get(ctx, resource.NewMetadata(req.Namespace, req.Type, req.Id, resource.VersionUndefined))
 
 	switch {
@@ -44,12 +266,7 @@ func (server *State) Get(ctx context.Context, req *v1alpha1.GetRequest) (*v1alph
 		return nil, err
 	}
 
-	protoR, err := protobuf.FromResource(r)
-	if err != nil {
-		return nil, err
-	}
-
-	marshaled, err := protoR.Marshal()
+	marshaled, err := server.marshal(ctx, sensitivityCache{}, r)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +277,13 @@ func (server *State) Get(ctx context.Context, req *v1alpha1.GetRequest) (*v1alph
 }
 
 // List resources by type.
+//
+// Resources are streamed to the client as they are listed, if the state supports streaming.
 func (server *State) List(req *v1alpha1.ListRequest, srv v1alpha1.State_ListServer) error {
+	if err := server.authorize(srv.Context(), VerbList, req.Namespace, req.Type); err != nil {
+		return err
+	}
+
 	var opts []state.ListOption
 
 	if req.GetOptions() != nil {
@@ -76,7 +299,26 @@ func (server *State) List(req *v1alpha1.ListRequest, srv v1alpha1.State_ListServ
 		}
 	}
 
-	items, err := server.state.List(srv.Context(), resource.NewMetadata(req.Namespace, req.Type, "", resource.VersionUndefined), opts...)
+	server.warnDeprecated(srv.Context(), req.Type, srv.SetHeader)
+
+	var sendErr error
+
+	cache := sensitivityCache{}
+
+	err := state.ListStream(srv.Context(), server.state, resource.NewMetadata(req.Namespace, req.Type, "", resource.VersionUndefined), func(r resource.Resource) bool {
+		var marshaled *v1alpha1.Resource
+
+		marshaled, sendErr = server.marshal(srv.Context(), cache, r)
+		if sendErr != nil {
+			return false
+		}
+
+		sendErr = srv.Send(&v1alpha1.ListResponse{
+			Resource: marshaled,
+		})
+
+		return sendErr == nil
+	}, opts...)
 
 	switch {
 	case state.IsNotFoundError(err):
@@ -85,55 +327,65 @@ func (server *State) List(req *v1alpha1.ListRequest, srv v1alpha1.State_ListServ
 		return err
 	}
 
-	for _, r := range items.Items {
-		protoR, err := protobuf.FromResource(r)
-		if err != nil {
-			return err
-		}
+	return sendErr
+}
 
-		marshaled, err := protoR.Marshal()
-		if err != nil {
-			return err
-		}
+// Create a resource.
+//
+// If a resource already exists, Create returns an error.
+func (server *State) Create(ctx context.Context, req *v1alpha1.CreateRequest) (_ *v1alpha1.CreateResponse, err error) {
+	defer func(start time.Time, md *v1alpha1.Metadata) {
+		server.audit(ctx, VerbCreate, md.GetNamespace(), md.GetType(), md.GetId(), start, err)
+	}(time.Now(), req.GetResource().GetMetadata())
 
-		if err = srv.Send(&v1alpha1.ListResponse{
-			Resource: marshaled,
-		}); err != nil {
-			return err
-		}
+	item, err := server.createItem(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = server.state.Create(ctx, item.Resource, item.Options...); err != nil {
+		return nil, createErrorStatus(err)
 	}
 
-	return nil
+	return &v1alpha1.CreateResponse{}, nil
 }
 
-// Create a resource.
-//
-// If a resource already exists, Create returns an error.
-func (server *State) Create(ctx context.Context, req *v1alpha1.CreateRequest) (*v1alpha1.CreateResponse, error) {
+// createItem decodes and authorizes the create request.
+func (server *State) createItem(ctx context.Context, req *v1alpha1.CreateRequest) (state.BatchCreateItem, error) {
 	protoR, err := protobuf.Unmarshal(req.Resource)
 	if err != nil {
-		return nil, err
+		return state.BatchCreateItem{}, err
 	}
 
 	r, err := protobuf.UnmarshalResource(protoR)
 	if err != nil {
-		return nil, err
+		return state.BatchCreateItem{}, err
 	}
 
-	err = server.state.Create(ctx, r, state.WithCreateOwner(req.GetOptions().GetOwner()))
+	if err = server.authorize(ctx, VerbCreate, r.Metadata().Namespace(), r.Metadata().Type()); err != nil {
+		return state.BatchCreateItem{}, err
+	}
+
+	server.warnDeprecated(ctx, r.Metadata().Type(), unaryHeader(ctx))
+
+	return state.BatchCreateItem{
+		Resource: r,
+		Options:  []state.CreateOption{state.WithCreateOwner(req.GetOptions().GetOwner())},
+	}, nil
+}
 
+// createErrorStatus converts the create error to the gRPC status.
+func createErrorStatus(err error) error {
 	switch {
 	case state.IsNotFoundError(err):
-		return nil, status.Error(codes.NotFound, err.Error())
+		return status.Error(codes.NotFound, err.Error())
 	case state.IsOwnerConflictError(err):
-		return nil, status.Error(codes.PermissionDenied, err.Error())
+		return status.Error(codes.PermissionDenied, err.Error())
 	case state.IsConflictError(err):
-		return nil, status.Error(codes.AlreadyExists, err.Error())
-	case err != nil:
-		return nil, err
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return err
 	}
-
-	return &v1alpha1.CreateResponse{}, nil
 }
 
 // Update a resource.
@@ -141,78 +393,254 @@ func (server *State) Create(ctx context.Context, req *v1alpha1.CreateRequest) (*
 // If a resource doesn't exist, error is returned.
 // On update current version of resource `new` in the state should match
 // curVersion, otherwise conflict error is returned.
-func (server *State) Update(ctx context.Context, req *v1alpha1.UpdateRequest) (*v1alpha1.UpdateResponse, error) {
-	protoR, err := protobuf.Unmarshal(req.NewResource)
+func (server *State) Update(ctx context.Context, req *v1alpha1.UpdateRequest) (_ *v1alpha1.UpdateResponse, err error) {
+	defer func(start time.Time, md *v1alpha1.Metadata) {
+		server.audit(ctx, VerbUpdate, md.GetNamespace(), md.GetType(), md.GetId(), start, err)
+	}(time.Now(), req.GetNewResource().GetMetadata())
+
+	item, err := server.updateItem(ctx, req.CurrentVersion, req.NewResource, req.GetOptions())
 	if err != nil {
 		return nil, err
 	}
 
+	if err = server.state.Update(ctx, item.CurrentVersion, item.Resource, item.Options...); err != nil {
+		return nil, updateErrorStatus(err)
+	}
+
+	return &v1alpha1.UpdateResponse{}, nil
+}
+
+// updateItem decodes and authorizes the update request.
+func (server *State) updateItem(ctx context.Context, curVersion string, newResource *v1alpha1.Resource, options *v1alpha1.UpdateOptions) (state.BatchUpdateItem, error) {
+	protoR, err := protobuf.Unmarshal(newResource)
+	if err != nil {
+		return state.BatchUpdateItem{}, err
+	}
+
 	r, err := protobuf.UnmarshalResource(protoR)
 	if err != nil {
-		return nil, err
+		return state.BatchUpdateItem{}, err
+	}
+
+	if err = server.authorize(ctx, VerbUpdate, r.Metadata().Namespace(), r.Metadata().Type()); err != nil {
+		return state.BatchUpdateItem{}, err
 	}
 
-	currentVersion, err := resource.ParseVersion(req.CurrentVersion)
+	currentVersion, err := resource.ParseVersion(curVersion)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return state.BatchUpdateItem{}, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	opts := []state.UpdateOption{state.WithUpdateOwner(req.GetOptions().GetOwner())}
+	opts := []state.UpdateOption{state.WithUpdateOwner(options.GetOwner())}
 
-	if req.GetOptions().ExpectedPhase == nil {
+	if options == nil || options.ExpectedPhase == nil {
 		opts = append(opts, state.WithExpectedPhaseAny())
 	} else {
 		var expectedPhase resource.Phase
 
-		expectedPhase, err = resource.ParsePhase(req.GetOptions().GetExpectedPhase())
+		expectedPhase, err = resource.ParsePhase(options.GetExpectedPhase())
 		if err != nil {
-			return nil, err
+			return state.BatchUpdateItem{}, err
 		}
 
 		opts = append(opts, state.WithExpectedPhase(expectedPhase))
 	}
 
-	err = server.state.Update(ctx, currentVersion, r, opts...)
+	server.warnDeprecated(ctx, r.Metadata().Type(), unaryHeader(ctx))
+
+	return state.BatchUpdateItem{
+		CurrentVersion: currentVersion,
+		Resource:       r,
+		Options:        opts,
+	}, nil
+}
 
+// updateErrorStatus converts the update error to the gRPC status.
+func updateErrorStatus(err error) error {
 	switch {
 	case state.IsNotFoundError(err):
-		return nil, status.Error(codes.NotFound, err.Error())
+		return status.Error(codes.NotFound, err.Error())
 	case state.IsOwnerConflictError(err):
-		return nil, status.Error(codes.PermissionDenied, err.Error())
+		return status.Error(codes.PermissionDenied, err.Error())
 	case state.IsPhaseConflictError(err):
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return status.Error(codes.InvalidArgument, err.Error())
 	case state.IsConflictError(err):
-		return nil, status.Error(codes.FailedPrecondition, err.Error())
-	case err != nil:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return err
+	}
+}
+
+// UpdateStatus updates the status of a resource.
+//
+// Only the status of the resource is replaced, the spec and the generation are not changed.
+// Status has its own owner, which is claimed by the first status update.
+func (server *State) UpdateStatus(ctx context.Context, req *v1alpha1.UpdateStatusRequest) (_ *v1alpha1.UpdateStatusResponse, err error) {
+	defer func(start time.Time, md *v1alpha1.Metadata) {
+		server.audit(ctx, VerbUpdate, md.GetNamespace(), md.GetType(), md.GetId(), start, err)
+	}(time.Now(), req.GetNewResource().GetMetadata())
+
+	item, err := server.updateItem(ctx, req.CurrentVersion, req.NewResource, req.GetOptions())
+	if err != nil {
 		return nil, err
 	}
 
-	return &v1alpha1.UpdateResponse{}, nil
+	statusResource, ok := item.Resource.(resource.StatusResource)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "resource %s doesn't have a status", item.Resource.Metadata())
+	}
+
+	if err = state.UpdateStatus(ctx, server.state, item.CurrentVersion, statusResource, item.Options...); err != nil {
+		return nil, updateErrorStatus(err)
+	}
+
+	return &v1alpha1.UpdateStatusResponse{}, nil
 }
 
 // Destroy a resource.
 //
 // If a resource doesn't exist, error is returned.
 // If a resource has pending finalizers, error is returned.
-func (server *State) Destroy(ctx context.Context, req *v1alpha1.DestroyRequest) (*v1alpha1.DestroyResponse, error) {
-	err := server.state.Destroy(
-		ctx,
-		resource.NewMetadata(req.Namespace, req.Type, req.Id, resource.VersionUndefined),
-		state.WithDestroyOwner(req.GetOptions().GetOwner()),
-	)
+func (server *State) Destroy(ctx context.Context, req *v1alpha1.DestroyRequest) (_ *v1alpha1.DestroyResponse, err error) {
+	defer func(start time.Time) {
+		server.audit(ctx, VerbDestroy, req.Namespace, req.Type, req.Id, start, err)
+	}(time.Now())
+
+	item, err := server.destroyItem(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = server.state.Destroy(ctx, item.Pointer, item.Options...); err != nil {
+		return nil, destroyErrorStatus(err)
+	}
+
+	return &v1alpha1.DestroyResponse{}, nil
+}
+
+// destroyItem authorizes the destroy request.
+func (server *State) destroyItem(ctx context.Context, req *v1alpha1.DestroyRequest) (state.BatchDestroyItem, error) {
+	if err := server.authorize(ctx, VerbDestroy, req.Namespace, req.Type); err != nil {
+		return state.BatchDestroyItem{}, err
+	}
+
+	server.warnDeprecated(ctx, req.Type, unaryHeader(ctx))
+
+	return state.BatchDestroyItem{
+		Pointer: resource.NewMetadata(req.Namespace, req.Type, req.Id, resource.VersionUndefined),
+		Options: []state.DestroyOption{
+			state.WithDestroyOwner(req.GetOptions().GetOwner()),
+			state.WithDestroyReason(req.GetOptions().GetReason()),
+		},
+	}, nil
+}
 
+// destroyErrorStatus converts the destroy error to the gRPC status.
+func destroyErrorStatus(err error) error {
 	switch {
 	case state.IsNotFoundError(err):
-		return nil, status.Error(codes.NotFound, err.Error())
+		return status.Error(codes.NotFound, err.Error())
 	case state.IsOwnerConflictError(err):
-		return nil, status.Error(codes.PermissionDenied, err.Error())
+		return status.Error(codes.PermissionDenied, err.Error())
 	case state.IsConflictError(err):
-		return nil, status.Error(codes.FailedPrecondition, err.Error())
-	case err != nil:
-		return nil, err
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return err
 	}
+}
 
-	return &v1alpha1.DestroyResponse{}, nil
+// BatchCreate creates multiple resources in a single call.
+//
+// The batch is applied atomically if the state supports it, otherwise the requests
+// are applied in order stopping on the first error.
+func (server *State) BatchCreate(ctx context.Context, req *v1alpha1.BatchCreateRequest) (_ *v1alpha1.BatchCreateResponse, err error) {
+	defer func(start time.Time) {
+		for _, itemReq := range req.GetRequests() {
+			md := itemReq.GetResource().GetMetadata()
+
+			server.audit(ctx, VerbCreate, md.GetNamespace(), md.GetType(), md.GetId(), start, err)
+		}
+	}(time.Now())
+
+	items := make([]state.BatchCreateItem, 0, len(req.GetRequests()))
+
+	for _, itemReq := range req.GetRequests() {
+		var item state.BatchCreateItem
+
+		if item, err = server.createItem(ctx, itemReq); err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	if err = state.BatchCreate(ctx, server.state, items); err != nil {
+		return nil, createErrorStatus(err)
+	}
+
+	return &v1alpha1.BatchCreateResponse{}, nil
+}
+
+// BatchUpdate updates multiple resources in a single call.
+//
+// The batch is applied atomically if the state supports it, otherwise the requests
+// are applied in order stopping on the first error.
+func (server *State) BatchUpdate(ctx context.Context, req *v1alpha1.BatchUpdateRequest) (_ *v1alpha1.BatchUpdateResponse, err error) {
+	defer func(start time.Time) {
+		for _, itemReq := range req.GetRequests() {
+			md := itemReq.GetNewResource().GetMetadata()
+
+			server.audit(ctx, VerbUpdate, md.GetNamespace(), md.GetType(), md.GetId(), start, err)
+		}
+	}(time.Now())
+
+	items := make([]state.BatchUpdateItem, 0, len(req.GetRequests()))
+
+	for _, itemReq := range req.GetRequests() {
+		var item state.BatchUpdateItem
+
+		if item, err = server.updateItem(ctx, itemReq.CurrentVersion, itemReq.NewResource, itemReq.GetOptions()); err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	if err = state.BatchUpdate(ctx, server.state, items); err != nil {
+		return nil, updateErrorStatus(err)
+	}
+
+	return &v1alpha1.BatchUpdateResponse{}, nil
+}
+
+// BatchDestroy destroys multiple resources in a single call.
+//
+// The batch is applied atomically if the state supports it, otherwise the requests
+// are applied in order stopping on the first error.
+func (server *State) BatchDestroy(ctx context.Context, req *v1alpha1.BatchDestroyRequest) (_ *v1alpha1.BatchDestroyResponse, err error) {
+	defer func(start time.Time) {
+		for _, itemReq := range req.GetRequests() {
+			server.audit(ctx, VerbDestroy, itemReq.Namespace, itemReq.Type, itemReq.Id, start, err)
+		}
+	}(time.Now())
+
+	items := make([]state.BatchDestroyItem, 0, len(req.GetRequests()))
+
+	for _, itemReq := range req.GetRequests() {
+		var item state.BatchDestroyItem
+
+		if item, err = server.destroyItem(ctx, itemReq); err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	if err = state.BatchDestroy(ctx, server.state, items); err != nil {
+		return nil, destroyErrorStatus(err)
+	}
+
+	return &v1alpha1.BatchDestroyResponse{}, nil
 }
 
 // Watch state of a resource by (namespace, type) or a specific resource by (namespace, type, id).
@@ -226,8 +654,14 @@ func (server *State) Destroy(ctx context.Context, req *v1alpha1.DestroyRequest)
 func (server *State) Watch(req *v1alpha1.WatchRequest, srv v1alpha1.State_WatchServer) error {
 	ch := make(chan state.Event)
 
+	if err := server.authorize(srv.Context(), VerbWatch, req.Namespace, req.Type); err != nil {
+		return err
+	}
+
 	var err error
 
+	server.warnDeprecated(srv.Context(), req.Type, srv.SetHeader)
+
 	if req.Id == nil {
 		var opts []state.WatchKindOption
 
@@ -278,28 +712,67 @@ func (server *State) Watch(req *v1alpha1.WatchRequest, srv v1alpha1.State_WatchS
 		return err
 	}
 
-	for event := range ch {
-		protoR, err := protobuf.FromResource(event.Resource)
-		if err != nil {
-			return err
+	// sent tracks the versions of the resources sent to the client, if deltas are requested
+	var sent map[resource.ID]resource.Version
+
+	if req.Options.GetDeltas() {
+		sent = map[resource.ID]resource.Version{}
+	}
+
+	cache := sensitivityCache{}
+
+	for {
+		var (
+			event state.Event
+			ok    bool
+		)
+
+		// the state doesn't close the channel when the watch is canceled, so the client going away should be watched for
+		select {
+		case <-srv.Context().Done():
+			return nil
+		case event, ok = <-ch:
+		}
+
+		if !ok {
+			return nil
 		}
 
-		marshaled, err := protoR.Marshal()
+		marshaled, err := server.marshal(srv.Context(), cache, event.Resource)
 		if err != nil {
 			return err
 		}
 
-		var oldMarshaled *v1alpha1.Resource
+		var delta *v1alpha1.SpecDelta
 
-		if event.Old != nil {
-			var oldProtoR *protobuf.Resource
+		if sent != nil {
+			id := event.Resource.Metadata().ID()
 
-			oldProtoR, err = protobuf.FromResource(event.Old)
-			if err != nil {
-				return err
+			// the delta can be applied by the client only to the previous version it has received
+			if prev, ok := sent[id]; ok && event.Old != nil && prev.Equal(event.Old.Metadata().Version()) {
+				delta, err = server.specDelta(srv.Context(), cache, event, marshaled)
+				if err != nil {
+					return err
+				}
 			}
 
-			oldMarshaled, err = oldProtoR.Marshal()
+			if event.Type == state.Destroyed {
+				delete(sent, id)
+			} else {
+				sent[id] = event.Resource.Metadata().Version()
+			}
+		}
+
+		var oldMarshaled *v1alpha1.Resource
+
+		if delta != nil {
+			// the client rebuilds the spec from the previous version it already has
+			marshaled = &v1alpha1.Resource{
+				Metadata: marshaled.Metadata,
+				Status:   marshaled.Status,
+			}
+		} else if event.Old != nil {
+			oldMarshaled, err = server.marshal(srv.Context(), cache, event.Old)
 			if err != nil {
 				return err
 			}
@@ -316,16 +789,48 @@ func (server *State) Watch(req *v1alpha1.WatchRequest, srv v1alpha1.State_WatchS
 			eventType = v1alpha1.EventType_DESTROYED
 		}
 
+		var destroyInfo *v1alpha1.DestroyInfo
+
+		if event.Destroy != nil {
+			destroyInfo = &v1alpha1.DestroyInfo{
+				Timestamp: timestamppb.New(event.Destroy.Timestamp),
+				Owner:     event.Destroy.Owner,
+				Reason:    event.Destroy.Reason,
+			}
+		}
+
 		if err = srv.Send(&v1alpha1.WatchResponse{
 			Event: &v1alpha1.Event{
 				EventType: eventType,
 				Resource:  marshaled,
 				Old:       oldMarshaled,
+				Destroy:   destroyInfo,
+				Delta:     delta,
 			},
 		}); err != nil {
 			return err
 		}
 	}
+}
+
+// ListResourceDefinitions lists the resource definitions registered in the state.
+func (server *State) ListResourceDefinitions(ctx context.Context, _ *v1alpha1.ListResourceDefinitionsRequest) (*v1alpha1.ListResourceDefinitionsResponse, error) {
+	if err := server.authorize(ctx, VerbList, meta.NamespaceName, meta.ResourceDefinitionType); err != nil {
+		return nil, err
+	}
+
+	definitions, err := registry.NewResourceRegistry(state.WrapCore(server.state)).Definitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &v1alpha1.ListResourceDefinitionsResponse{
+		Definitions: make([]*v1alpha1.ResourceDefinition, 0, len(definitions)),
+	}
+
+	for _, definition := range definitions {
+		resp.Definitions = append(resp.Definitions, ConvertResourceDefinition(definition))
+	}
 
-	return nil
+	return resp, nil
 }
@@ -0,0 +1,87 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package vsock provides AF_VSOCK transport for the gRPC State service.
+//
+// vsock allows the runtime on the host to expose the state to the guest VMs (and vice versa) without configuring networking:
+//
+//	l, err := vsock.Listen(port)
+//	grpcServer.Serve(l)
+//
+//	grpc.Dial("passthrough:///2:1024", grpc.WithContextDialer(vsock.DialContext), ...)
+//
+// vsock is supported only on Linux.
+package vsock
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Well-known context IDs.
+const (
+	// ContextIDHypervisor is the hypervisor process.
+	ContextIDHypervisor uint32 = 0
+	// ContextIDLocal is the local communication (loopback).
+	ContextIDLocal uint32 = 1
+	// ContextIDHost is the host, the guests connect to it.
+	ContextIDHost uint32 = 2
+)
+
+// PortAny makes Listen pick any available port.
+const PortAny uint32 = 0xffffffff
+
+// Addr is the address of the vsock socket.
+type Addr struct {
+	ContextID uint32
+	Port      uint32
+}
+
+// Network implements net.Addr.
+func (addr Addr) Network() string {
+	return "vsock"
+}
+
+// String implements net.Addr, the address is formatted as "cid:port".
+func (addr Addr) String() string {
+	return fmt.Sprintf("%d:%d", addr.ContextID, addr.Port)
+}
+
+// ParseAddr parses the address in the "cid:port" format.
+func ParseAddr(address string) (Addr, error) {
+	cid, port, ok := strings.Cut(address, ":")
+	if !ok {
+		return Addr{}, fmt.Errorf("vsock address %q should be in the cid:port format", address)
+	}
+
+	parsedCID, err := strconv.ParseUint(cid, 10, 32)
+	if err != nil {
+		return Addr{}, fmt.Errorf("error parsing context ID of vsock address %q: %w", address, err)
+	}
+
+	parsedPort, err := strconv.ParseUint(port, 10, 32)
+	if err != nil {
+		return Addr{}, fmt.Errorf("error parsing port of vsock address %q: %w", address, err)
+	}
+
+	return Addr{
+		ContextID: uint32(parsedCID),
+		Port:      uint32(parsedPort),
+	}, nil
+}
+
+// DialContext connects to the address in the "cid:port" format.
+//
+// DialContext can be used with grpc.WithContextDialer, the target should use the passthrough resolver.
+func DialContext(ctx context.Context, address string) (net.Conn, error) {
+	addr, err := ParseAddr(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return Dial(ctx, addr)
+}
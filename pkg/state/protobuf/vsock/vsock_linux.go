@@ -0,0 +1,235 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build linux
+
+package vsock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Listen announces on the vsock port of the local context ID.
+//
+// The listener accepts connections from any context ID.
+func Listen(port uint32) (net.Listener, error) {
+	fd, err := socket()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = unix.Bind(fd, &unix.SockaddrVM{CID: unix.VMADDR_CID_ANY, Port: port}); err != nil {
+		unix.Close(fd) //nolint:errcheck
+
+		return nil, fmt.Errorf("error binding vsock port %d: %w", port, err)
+	}
+
+	if err = unix.Listen(fd, unix.SOMAXCONN); err != nil {
+		unix.Close(fd) //nolint:errcheck
+
+		return nil, fmt.Errorf("error listening on vsock port %d: %w", port, err)
+	}
+
+	f := os.NewFile(uintptr(fd), "vsock-listener")
+
+	addr, err := localAddr(fd)
+	if err != nil {
+		f.Close() //nolint:errcheck
+
+		return nil, err
+	}
+
+	return &listener{f: f, addr: addr}, nil
+}
+
+// Dial connects to the vsock address.
+func Dial(ctx context.Context, addr Addr) (net.Conn, error) {
+	fd, err := socket()
+	if err != nil {
+		return nil, err
+	}
+
+	err = unix.Connect(fd, &unix.SockaddrVM{CID: addr.ContextID, Port: addr.Port})
+	if err != nil && !errors.Is(err, unix.EINPROGRESS) {
+		unix.Close(fd) //nolint:errcheck
+
+		return nil, fmt.Errorf("error connecting to vsock %s: %w", addr, err)
+	}
+
+	f := os.NewFile(uintptr(fd), "vsock-conn")
+
+	if err != nil {
+		if err = waitConnected(ctx, f); err != nil {
+			f.Close() //nolint:errcheck
+
+			return nil, fmt.Errorf("error connecting to vsock %s: %w", addr, err)
+		}
+	}
+
+	local, err := localAddr(fd)
+	if err != nil {
+		f.Close() //nolint:errcheck
+
+		return nil, err
+	}
+
+	return &conn{File: f, local: local, remote: addr}, nil
+}
+
+// socket creates non-blocking vsock socket, so that it's handled by the runtime poller.
+func socket() (int, error) {
+	fd, err := unix.Socket(unix.AF_VSOCK, unix.SOCK_STREAM|unix.SOCK_NONBLOCK|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return -1, fmt.Errorf("error creating vsock socket: %w", err)
+	}
+
+	return fd, nil
+}
+
+// waitConnected waits for the non-blocking connect to complete.
+func waitConnected(ctx context.Context, f *os.File) error {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			// unblock the wait below
+			f.SetWriteDeadline(time.Unix(1, 0)) //nolint:errcheck
+		case <-done:
+		}
+	}()
+
+	var connectErr error
+
+	err = rc.Write(func(fd uintptr) bool {
+		var errno int
+
+		errno, connectErr = unix.GetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_ERROR)
+		if connectErr != nil {
+			return true
+		}
+
+		switch syscall.Errno(errno) { //nolint:exhaustive
+		case unix.EINPROGRESS, unix.EALREADY, unix.EINTR:
+			return false
+		case 0:
+			if _, connectErr = unix.Getpeername(int(fd)); errors.Is(connectErr, unix.ENOTCONN) {
+				connectErr = nil
+
+				return false
+			}
+
+			return true
+		default:
+			connectErr = syscall.Errno(errno)
+
+			return true
+		}
+	})
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return connectErr
+}
+
+func localAddr(fd int) (Addr, error) {
+	sa, err := unix.Getsockname(fd)
+	if err != nil {
+		return Addr{}, fmt.Errorf("error getting vsock socket address: %w", err)
+	}
+
+	vm, ok := sa.(*unix.SockaddrVM)
+	if !ok {
+		return Addr{}, fmt.Errorf("unexpected vsock socket address %T", sa)
+	}
+
+	return Addr{ContextID: vm.CID, Port: vm.Port}, nil
+}
+
+type listener struct {
+	f    *os.File
+	addr Addr
+}
+
+// Accept implements net.Listener.
+func (l *listener) Accept() (net.Conn, error) {
+	rc, err := l.f.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		fd        int
+		sa        unix.Sockaddr
+		acceptErr error
+	)
+
+	err = rc.Read(func(s uintptr) bool {
+		fd, sa, acceptErr = unix.Accept4(int(s), unix.SOCK_NONBLOCK|unix.SOCK_CLOEXEC)
+
+		return !errors.Is(acceptErr, unix.EAGAIN)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if acceptErr != nil {
+		return nil, fmt.Errorf("error accepting vsock connection: %w", acceptErr)
+	}
+
+	remote := Addr{}
+
+	if vm, ok := sa.(*unix.SockaddrVM); ok {
+		remote = Addr{ContextID: vm.CID, Port: vm.Port}
+	}
+
+	return &conn{File: os.NewFile(uintptr(fd), "vsock-conn"), local: l.addr, remote: remote}, nil
+}
+
+// Close implements net.Listener.
+func (l *listener) Close() error {
+	return l.f.Close()
+}
+
+// Addr implements net.Listener.
+func (l *listener) Addr() net.Addr {
+	return l.addr
+}
+
+type conn struct {
+	*os.File
+
+	local  Addr
+	remote Addr
+}
+
+// LocalAddr implements net.Conn.
+func (c *conn) LocalAddr() net.Addr {
+	return c.local
+}
+
+// RemoteAddr implements net.Conn.
+func (c *conn) RemoteAddr() net.Addr {
+	return c.remote
+}
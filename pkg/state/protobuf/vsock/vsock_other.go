@@ -0,0 +1,25 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build !linux
+
+package vsock
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+var errNotSupported = errors.New("vsock is supported only on Linux")
+
+// Listen announces on the vsock port of the local context ID.
+func Listen(port uint32) (net.Listener, error) {
+	return nil, errNotSupported
+}
+
+// Dial connects to the vsock address.
+func Dial(ctx context.Context, addr Addr) (net.Conn, error) {
+	return nil, errNotSupported
+}
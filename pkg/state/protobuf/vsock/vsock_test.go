@@ -0,0 +1,96 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vsock_test
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/cosi-project/runtime/api/v1alpha1"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/conformance"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/cosi-project/runtime/pkg/state/protobuf/client"
+	"github.com/cosi-project/runtime/pkg/state/protobuf/server"
+	"github.com/cosi-project/runtime/pkg/state/protobuf/vsock"
+)
+
+func TestParseAddr(t *testing.T) {
+	t.Parallel()
+
+	addr, err := vsock.ParseAddr("2:1024")
+	require.NoError(t, err)
+
+	assert.Equal(t, vsock.Addr{ContextID: vsock.ContextIDHost, Port: 1024}, addr)
+	assert.Equal(t, "2:1024", addr.String())
+	assert.Equal(t, "vsock", addr.Network())
+
+	for _, address := range []string{"", "2", "host:1024", "2:port", "2:4294967296"} {
+		_, err = vsock.ParseAddr(address)
+		assert.Error(t, err, address)
+	}
+}
+
+func TestLoopback(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS != "linux" {
+		t.Skip("vsock is supported only on Linux")
+	}
+
+	l, err := vsock.Listen(vsock.PortAny)
+	if err != nil {
+		t.Skipf("vsock is not available: %s", err)
+	}
+
+	port := l.Addr().(vsock.Addr).Port //nolint:forcetypeassert,errcheck
+
+	grpcServer := grpc.NewServer()
+	v1alpha1.RegisterStateServer(grpcServer, server.NewState(state.WrapCore(namespaced.NewState(inmem.Build))))
+
+	go grpcServer.Serve(l) //nolint:errcheck
+
+	defer grpcServer.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// loopback transport might be not loaded, or not allowed in the sandbox
+	dialCtx, dialCancel := context.WithTimeout(ctx, time.Second)
+	defer dialCancel()
+
+	probe, err := vsock.Dial(dialCtx, vsock.Addr{ContextID: vsock.ContextIDLocal, Port: port})
+	if err != nil {
+		t.Skipf("vsock loopback is not available: %s", err)
+	}
+
+	require.NoError(t, probe.Close())
+
+	conn, err := grpc.DialContext(ctx, "passthrough:///"+vsock.Addr{ContextID: vsock.ContextIDLocal, Port: port}.String(),
+		grpc.WithInsecure(), //nolint:staticcheck
+		grpc.WithContextDialer(vsock.DialContext),
+	)
+	require.NoError(t, err)
+
+	defer conn.Close() //nolint:errcheck
+
+	st := state.WrapCore(client.NewAdapter(v1alpha1.NewStateClient(conn)))
+
+	path := conformance.NewPathResource("default", "var/run")
+	require.NoError(t, st.Create(ctx, path))
+
+	got, err := st.Get(ctx, path.Metadata())
+	require.NoError(t, err)
+
+	assert.Equal(t, path.Metadata().ID(), got.Metadata().ID())
+	assert.Equal(t, path.Metadata().Version(), got.Metadata().Version())
+}
@@ -6,9 +6,12 @@ package registry
 
 import (
 	"context"
+	"fmt"
+	"sync"
 
 	"github.com/cosi-project/runtime/pkg/resource"
 	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/safe"
 	"github.com/cosi-project/runtime/pkg/state"
 )
 
@@ -24,14 +27,139 @@ func NewNamespaceRegistry(state state.State) *NamespaceRegistry {
 	}
 }
 
+// NamespaceOptions configure the registered namespace.
+type NamespaceOptions struct {
+	Labels map[string]string
+	Owner  string
+}
+
+// NamespaceOption applies settings to NamespaceOptions.
+type NamespaceOption func(options *NamespaceOptions)
+
+// WithNamespaceOwner sets the component or the team responsible for the namespace.
+func WithNamespaceOwner(owner string) NamespaceOption {
+	return func(options *NamespaceOptions) {
+		options.Owner = owner
+	}
+}
+
+// WithNamespaceLabels sets the labels of the namespace.
+func WithNamespaceLabels(labels map[string]string) NamespaceOption {
+	return func(options *NamespaceOptions) {
+		if options.Labels == nil {
+			options.Labels = make(map[string]string, len(labels))
+		}
+
+		for k, v := range labels {
+			options.Labels[k] = v
+		}
+	}
+}
+
+// DefaultNamespaceOptions returns default value of NamespaceOptions.
+func DefaultNamespaceOptions() NamespaceOptions {
+	return NamespaceOptions{}
+}
+
 // RegisterDefault registers default namespaces.
 func (registry *NamespaceRegistry) RegisterDefault(ctx context.Context) error {
 	return registry.Register(ctx, meta.NamespaceName, "Metadata namespace which contains resource and namespace definitions.")
 }
 
 // Register a namespace.
-func (registry *NamespaceRegistry) Register(ctx context.Context, ns resource.Namespace, description string) error {
-	return registry.state.Create(ctx, meta.NewNamespace(ns, meta.NamespaceSpec{
+func (registry *NamespaceRegistry) Register(ctx context.Context, ns resource.Namespace, description string, opts ...NamespaceOption) error {
+	return registry.state.Create(ctx, newNamespace(ns, description, opts...), state.WithCreateOwner(meta.Owner))
+}
+
+// Namespaces returns the registered namespaces ordered by ID.
+func (registry *NamespaceRegistry) Namespaces(ctx context.Context) ([]*meta.Namespace, error) {
+	namespaces, err := safe.StateList[*meta.Namespace](ctx, registry.state,
+		resource.NewMetadata(meta.NamespaceName, meta.NamespaceType, "", resource.VersionUndefined))
+	if err != nil {
+		return nil, fmt.Errorf("error listing namespaces: %w", err)
+	}
+
+	result := make([]*meta.Namespace, 0, namespaces.Len())
+
+	for iter := safe.IteratorFromList(namespaces); iter.Next(); {
+		result = append(result, iter.Value())
+	}
+
+	return result, nil
+}
+
+func newNamespace(ns resource.Namespace, description string, opts ...NamespaceOption) *meta.Namespace {
+	options := DefaultNamespaceOptions()
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	namespace := meta.NewNamespace(ns, meta.NamespaceSpec{
 		Description: description,
-	}), state.WithCreateOwner(meta.Owner))
+		Owner:       options.Owner,
+	})
+
+	for k, v := range options.Labels {
+		namespace.Metadata().Labels().Set(k, v)
+	}
+
+	return namespace
+}
+
+// AutoRegisterNamespaces returns the middleware which registers the namespace on the first resource created in it.
+//
+// Backends should install the middleware, so that the namespaces show up as the meta resources
+// without explicit registration, and the controllers can watch for the namespace creation.
+// The metadata namespace is not registered automatically, see RegisterDefault.
+// Namespaces which are already registered are left intact.
+func AutoRegisterNamespaces(opts ...NamespaceOption) state.Middleware {
+	return func(coreState state.CoreState) state.CoreState {
+		return &autoRegisterState{
+			CoreState: coreState,
+			opts:      opts,
+		}
+	}
+}
+
+type autoRegisterState struct {
+	state.CoreState
+
+	registered sync.Map
+	opts       []NamespaceOption
+}
+
+func (st *autoRegisterState) Create(ctx context.Context, res resource.Resource, opts ...state.CreateOption) error {
+	if err := st.register(ctx, res.Metadata().Namespace()); err != nil {
+		return err
+	}
+
+	return st.CoreState.Create(ctx, res, opts...)
+}
+
+func (st *autoRegisterState) UpdateStatus(ctx context.Context, curVersion resource.Version, newResource resource.StatusResource, opts ...state.UpdateOption) error {
+	return state.UpdateStatus(ctx, st.CoreState, curVersion, newResource, opts...)
+}
+
+func (st *autoRegisterState) ListStream(ctx context.Context, kind resource.Kind, yield func(resource.Resource) bool, opts ...state.ListOption) error {
+	return state.ListStream(ctx, st.CoreState, kind, yield, opts...)
+}
+
+func (st *autoRegisterState) register(ctx context.Context, ns resource.Namespace) error {
+	if ns == meta.NamespaceName {
+		return nil
+	}
+
+	if _, ok := st.registered.Load(ns); ok {
+		return nil
+	}
+
+	err := st.CoreState.Create(ctx, newNamespace(ns, "", st.opts...), state.WithCreateOwner(meta.Owner))
+	if err != nil && !state.IsConflictError(err) {
+		return fmt.Errorf("error registering namespace %q: %w", ns, err)
+	}
+
+	st.registered.Store(ns, struct{}{})
+
+	return nil
 }
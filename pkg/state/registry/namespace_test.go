@@ -6,11 +6,16 @@ package registry_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
 	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/conformance"
 	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
 	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
 	"github.com/cosi-project/runtime/pkg/state/registry"
@@ -23,3 +28,56 @@ func TestNamespaceRegistry(t *testing.T) {
 
 	assert.NoError(t, r.RegisterDefault(context.Background()))
 }
+
+func TestNamespaceRegistryOptions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	r := registry.NewNamespaceRegistry(state.WrapCore(namespaced.NewState(inmem.Build)))
+
+	require.NoError(t, r.RegisterDefault(ctx))
+	require.NoError(t, r.Register(ctx, "system", "System resources.",
+		registry.WithNamespaceOwner("platform"),
+		registry.WithNamespaceLabels(map[string]string{"tier": "core"}),
+	))
+
+	namespaces, err := r.Namespaces(ctx)
+	require.NoError(t, err)
+	require.Len(t, namespaces, 2)
+
+	assert.Equal(t, meta.NamespaceName, namespaces[0].Metadata().ID())
+	assert.Equal(t, "system", namespaces[1].Metadata().ID())
+	assert.Equal(t, "platform", namespaces[1].TypedSpec().Owner)
+	assert.Equal(t, "System resources.", namespaces[1].TypedSpec().Description)
+
+	tier, ok := namespaces[1].Metadata().Labels().Get("tier")
+	assert.True(t, ok)
+	assert.Equal(t, "core", tier)
+}
+
+func TestAutoRegisterNamespaces(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	st := state.WrapCore(state.Chain(namespaced.NewState(inmem.Build), registry.AutoRegisterNamespaces(registry.WithNamespaceOwner("edge"))))
+	r := registry.NewNamespaceRegistry(st)
+
+	// explicitly registered namespaces are kept as is
+	require.NoError(t, r.Register(ctx, "runtime", "Runtime resources."))
+
+	for i, ns := range []resource.Namespace{"default", "default", "runtime"} {
+		require.NoError(t, st.Create(ctx, conformance.NewPathResource(ns, fmt.Sprintf("var/%d", i))))
+	}
+
+	namespaces, err := r.Namespaces(ctx)
+	require.NoError(t, err)
+	require.Len(t, namespaces, 2)
+
+	assert.Equal(t, "default", namespaces[0].Metadata().ID())
+	assert.Equal(t, "edge", namespaces[0].TypedSpec().Owner)
+	assert.Equal(t, "runtime", namespaces[1].Metadata().ID())
+	assert.Equal(t, "Runtime resources.", namespaces[1].TypedSpec().Description)
+	assert.Empty(t, namespaces[1].TypedSpec().Owner)
+}
@@ -0,0 +1,105 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// EnforceCustomPhases wraps the state to validate custom phases of the resources on Create and Update.
+//
+// Custom phases and the transitions between them should be declared by the resource definition
+// registered in the state. Writes which violate the declarations are rejected with state.ValidationError.
+func EnforceCustomPhases(coreState state.CoreState) state.CoreState { //nolint:ireturn
+	return &customPhaseState{
+		CoreState: coreState,
+	}
+}
+
+type customPhaseState struct {
+	state.CoreState
+}
+
+// Create a resource.
+//
+// If a resource has undeclared custom phase, Create returns ValidationError.
+func (st *customPhaseState) Create(ctx context.Context, res resource.Resource, opts ...state.CreateOption) error {
+	if err := st.validate(ctx, res, ""); err != nil {
+		return err
+	}
+
+	return st.CoreState.Create(ctx, res, opts...)
+}
+
+// Update a resource.
+//
+// If a resource transitions to the custom phase which is not allowed, Update returns ValidationError.
+func (st *customPhaseState) Update(ctx context.Context, curVersion resource.Version, newResource resource.Resource, opts ...state.UpdateOption) error {
+	if newResource.Metadata().CustomPhase() != "" {
+		current, err := st.CoreState.Get(ctx, newResource.Metadata())
+		if err != nil {
+			return err
+		}
+
+		if err = st.validate(ctx, newResource, current.Metadata().CustomPhase()); err != nil {
+			return err
+		}
+	}
+
+	return st.CoreState.Update(ctx, curVersion, newResource, opts...)
+}
+
+// UpdateStatus implements state.StatusUpdater interface.
+//
+// Status updates don't change the custom phase, so they are not validated.
+func (st *customPhaseState) UpdateStatus(ctx context.Context, curVersion resource.Version, newResource resource.StatusResource, opts ...state.UpdateOption) error {
+	return state.UpdateStatus(ctx, st.CoreState, curVersion, newResource, opts...)
+}
+
+// ListStream implements state.ListStreamer interface.
+func (st *customPhaseState) ListStream(ctx context.Context, kind resource.Kind, yield func(resource.Resource) bool, opts ...state.ListOption) error {
+	return state.ListStream(ctx, st.CoreState, kind, yield, opts...)
+}
+
+func (st *customPhaseState) validate(ctx context.Context, res resource.Resource, from string) error {
+	to := res.Metadata().CustomPhase()
+	if to == "" || to == from {
+		return nil
+	}
+
+	rd, err := st.CoreState.Get(ctx,
+		resource.NewMetadata(meta.NamespaceName, meta.ResourceDefinitionType, strings.ToLower(res.Metadata().Type()), resource.VersionUndefined))
+	if err != nil && !state.IsNotFoundError(err) {
+		return fmt.Errorf("error looking up resource definition for %q: %w", res.Metadata().Type(), err)
+	}
+
+	definition := meta.ResourceDefinitionSpec{
+		Type: res.Metadata().Type(),
+	}
+
+	if typedRD, ok := rd.(*meta.ResourceDefinition); ok {
+		definition = *typedRD.TypedSpec()
+	}
+
+	if err = definition.ValidateCustomPhaseTransition(from, to); err != nil {
+		return &state.ValidationError{
+			Resource: res.Metadata(),
+			Fields: []state.FieldError{
+				{
+					Field:   "metadata.customPhase",
+					Message: err.Error(),
+				},
+			},
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package registry_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/api/v1alpha1"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/conformance"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/cosi-project/runtime/pkg/state/registry"
+)
+
+type jobSpec = protobuf.ResourceSpec[v1alpha1.Metadata, *v1alpha1.Metadata]
+
+type jobRD struct{}
+
+func (jobRD) ResourceDefinition(resource.Metadata, jobSpec) meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type: "Jobs.test.cosi.dev",
+		CustomPhases: []meta.CustomPhase{
+			{Name: "pending", Transitions: []string{"succeeded", "failed"}},
+			{Name: "succeeded"},
+			{Name: "failed", Transitions: []string{"pending"}},
+		},
+	}
+}
+
+func newJob(id resource.ID) *typed.Resource[jobSpec, jobRD] {
+	return typed.NewResource[jobSpec, jobRD](resource.NewMetadata("default", "Jobs.test.cosi.dev", id, resource.VersionUndefined),
+		protobuf.NewResourceSpec(&v1alpha1.Metadata{}))
+}
+
+func TestEnforceCustomPhases(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	st := state.WrapCore(registry.EnforceCustomPhases(namespaced.NewState(inmem.Build)))
+
+	require.NoError(t, registry.NewResourceRegistry(st).Register(ctx, newJob("")))
+
+	job := newJob("a")
+	job.Metadata().SetCustomPhase("running")
+
+	err := st.Create(ctx, job)
+	require.Error(t, err)
+	assert.True(t, state.IsValidationError(err))
+
+	job.Metadata().SetCustomPhase("pending")
+	require.NoError(t, st.Create(ctx, job))
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		_, watchErr := st.WatchFor(ctx, job.Metadata(), state.WithCustomPhases("failed"))
+		errCh <- watchErr
+	}()
+
+	_, err = st.UpdateWithConflicts(ctx, job.Metadata(), func(r resource.Resource) error {
+		r.Metadata().SetCustomPhase("failed")
+
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, <-errCh)
+
+	_, err = st.UpdateWithConflicts(ctx, job.Metadata(), func(r resource.Resource) error {
+		r.Metadata().SetCustomPhase("succeeded")
+
+		return nil
+	})
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `transition from custom phase "failed" to "succeeded" is not allowed`)
+
+	_, err = st.UpdateWithConflicts(ctx, job.Metadata(), func(r resource.Resource) error {
+		r.Metadata().SetCustomPhase("pending")
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestWrappersPassThrough(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	for name, st := range map[string]state.CoreState{
+		"EnforceCustomPhases":    registry.EnforceCustomPhases(namespaced.NewState(inmem.Build)),
+		"AutoRegisterNamespaces": state.Chain(namespaced.NewState(inmem.Build), registry.AutoRegisterNamespaces()),
+	} {
+		assert.Implements(t, (*state.ListStreamer)(nil), st, name)
+
+		replica := conformance.NewReplicaResource("default", "one", 3)
+		require.NoError(t, st.Create(ctx, replica), name)
+
+		status := replica.DeepCopy().(*conformance.ReplicaResource) //nolint:forcetypeassert
+		status.TypedSpec().Ready = 2
+		status.Metadata().BumpVersion()
+
+		require.NoError(t, state.UpdateStatus(ctx, st, replica.Metadata().Version(), status), name)
+	}
+}
@@ -7,9 +7,11 @@ package registry
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/cosi-project/runtime/pkg/resource"
 	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/safe"
 	"github.com/cosi-project/runtime/pkg/state"
 )
 
@@ -52,3 +54,64 @@ func (registry *ResourceRegistry) Register(ctx context.Context, r resource.Resou
 
 	return registry.state.Create(ctx, r, state.WithCreateOwner(meta.Owner))
 }
+
+// Definitions returns all registered resource definitions ordered by ID.
+//
+// Definitions allows generic clients (e.g. over the gRPC client adapter) to discover the available resource types,
+// their namespaces, aliases, sensitivity and print columns.
+func (registry *ResourceRegistry) Definitions(ctx context.Context) ([]meta.ResourceDefinitionSpec, error) {
+	definitions, err := safe.StateList[*meta.ResourceDefinition](ctx, registry.state,
+		resource.NewMetadata(meta.NamespaceName, meta.ResourceDefinitionType, "", resource.VersionUndefined))
+	if err != nil {
+		return nil, fmt.Errorf("error listing resource definitions: %w", err)
+	}
+
+	result := make([]meta.ResourceDefinitionSpec, 0, definitions.Len())
+
+	for iter := safe.IteratorFromList(definitions); iter.Next(); {
+		result = append(result, *iter.Value().TypedSpec())
+	}
+
+	return result, nil
+}
+
+// Resolve expands the name into the resource definitions it refers to.
+//
+// The name is matched against the type aliases first (which yields a single definition),
+// and then against the categories (which yields all definitions in the category ordered by ID).
+func (registry *ResourceRegistry) Resolve(ctx context.Context, name string) ([]meta.ResourceDefinitionSpec, error) {
+	definitions, err := registry.Definitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.ToLower(name)
+
+	var result []meta.ResourceDefinitionSpec
+
+	for _, spec := range definitions {
+		if spec.ID() == name {
+			return []meta.ResourceDefinitionSpec{spec}, nil
+		}
+
+		for _, alias := range spec.AllAliases {
+			if alias == name {
+				return []meta.ResourceDefinitionSpec{spec}, nil
+			}
+		}
+
+		for _, category := range spec.Categories {
+			if category == name {
+				result = append(result, spec)
+
+				break
+			}
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no resource types found for %q", name)
+	}
+
+	return result, nil
+}
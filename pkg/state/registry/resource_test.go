@@ -9,7 +9,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
 	"github.com/cosi-project/runtime/pkg/state"
 	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
 	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
@@ -23,3 +26,84 @@ func TestResourceRegistry(t *testing.T) {
 
 	assert.NoError(t, r.RegisterDefault(context.Background()))
 }
+
+type categorizedRD struct {
+	typ        resource.Type
+	categories []string
+}
+
+func (rd categorizedRD) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:       rd.typ,
+		Categories: rd.categories,
+	}
+}
+
+func (rd categorizedRD) Metadata() *resource.Metadata { return nil }
+func (rd categorizedRD) Spec() interface{}            { return nil }
+func (rd categorizedRD) DeepCopy() resource.Resource  { return rd } //nolint:ireturn
+
+func TestResourceRegistryResolve(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	r := registry.NewResourceRegistry(state.WrapCore(namespaced.NewState(inmem.Build)))
+
+	require.NoError(t, r.RegisterDefault(ctx))
+
+	for _, rd := range []categorizedRD{
+		{typ: "Routes.net.cosi.dev", categories: []string{"all", "networking"}},
+		{typ: "Links.net.cosi.dev", categories: []string{"all", "networking"}},
+		{typ: "Mounts.fs.cosi.dev", categories: []string{"all"}},
+	} {
+		require.NoError(t, r.Register(ctx, rd))
+	}
+
+	types := func(name string) []resource.Type {
+		definitions, err := r.Resolve(ctx, name)
+		require.NoError(t, err)
+
+		result := make([]resource.Type, 0, len(definitions))
+
+		for _, definition := range definitions {
+			result = append(result, definition.Type)
+		}
+
+		return result
+	}
+
+	assert.Equal(t, []resource.Type{"Links.net.cosi.dev", "Routes.net.cosi.dev"}, types("networking"))
+	assert.Equal(t, []resource.Type{"Links.net.cosi.dev", "Mounts.fs.cosi.dev", "Routes.net.cosi.dev"}, types("all"))
+	assert.Equal(t, []resource.Type{"Routes.net.cosi.dev"}, types("route"))
+	assert.Equal(t, []resource.Type{"Mounts.fs.cosi.dev"}, types("Mounts.fs.cosi.dev"))
+
+	_, err := r.Resolve(ctx, "storage")
+	assert.EqualError(t, err, "no resource types found for \"storage\"")
+
+	assert.ErrorContains(t, r.Register(ctx, categorizedRD{typ: "Disks.fs.cosi.dev", categories: []string{"Block Devices"}}),
+		"category \"Block Devices\" doesn't match \"^[a-z][a-z0-9-]*$\"")
+}
+
+func TestResourceRegistryDefinitions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	r := registry.NewResourceRegistry(state.WrapCore(namespaced.NewState(inmem.Build)))
+
+	require.NoError(t, r.RegisterDefault(ctx))
+	require.NoError(t, r.Register(ctx, categorizedRD{typ: "Routes.net.cosi.dev", categories: []string{"networking"}}))
+
+	definitions, err := r.Definitions(ctx)
+	require.NoError(t, err)
+
+	types := make([]resource.Type, 0, len(definitions))
+
+	for _, definition := range definitions {
+		types = append(types, definition.Type)
+	}
+
+	assert.Equal(t, []resource.Type{meta.NamespaceType, meta.ResourceDefinitionType, "Routes.net.cosi.dev"}, types)
+	assert.Equal(t, []string{"networking"}, definitions[2].Categories)
+}
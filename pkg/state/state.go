@@ -7,6 +7,7 @@ package state
 
 import (
 	"context"
+	"time"
 
 	"github.com/cosi-project/runtime/pkg/resource"
 )
@@ -32,7 +33,19 @@ func (eventType EventType) String() string {
 type Event struct {
 	Resource resource.Resource
 	Old      resource.Resource
-	Type     EventType
+	// Destroy is set for Destroyed events caused by the resource destroy operation.
+	Destroy *DestroyInfo
+	Type    EventType
+}
+
+// DestroyInfo describes the destroy operation of the resource.
+type DestroyInfo struct {
+	// Timestamp of the destroy operation.
+	Timestamp time.Time
+	// Owner which destroyed the resource, empty for resources not owned by controllers.
+	Owner string
+	// Reason is an optional free-form reason of the destroy operation.
+	Reason string
 }
 
 // CoreState is the central broker in the system handling state and changes.
@@ -78,6 +91,43 @@ type CoreState interface {
 	WatchKind(context.Context, resource.Kind, chan<- Event, ...WatchKindOption) error
 }
 
+// ListStreamer is implemented by the states which can stream resources on List without building the full list.
+//
+// Streaming stops when yield returns false.
+type ListStreamer interface {
+	ListStream(ctx context.Context, kind resource.Kind, yield func(resource.Resource) bool, opts ...ListOption) error
+}
+
+// ListStream streams the resources of the kind from the state.
+//
+// If the state doesn't implement ListStreamer, the full list is fetched and streamed.
+func ListStream(ctx context.Context, st CoreState, kind resource.Kind, yield func(resource.Resource) bool, opts ...ListOption) error {
+	if streamer, ok := st.(ListStreamer); ok {
+		return streamer.ListStream(ctx, kind, yield, opts...)
+	}
+
+	list, err := st.List(ctx, kind, opts...)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range list.Items {
+		if !yield(r) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// StatusUpdater is implemented by the states which support status subresource updates.
+//
+// UpdateStatus replaces only the status of the resource, the rest of newResource except for the version is ignored.
+// Status owner is checked against the owner in the options, the first status update claims the status ownership.
+type StatusUpdater interface {
+	UpdateStatus(ctx context.Context, curVersion resource.Version, newResource resource.StatusResource, opts ...UpdateOption) error
+}
+
 // UpdaterFunc is called on resource to update it to the desired state.
 //
 // UpdaterFunc should also bump resource version.
@@ -0,0 +1,251 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package statetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/clock"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/store"
+)
+
+// EventRecord is a single state.Event in the recording.
+//
+// Resources are stored in the protobuf format, resource types should be registered with protobuf.RegisterResource.
+type EventRecord struct {
+	Time     time.Time          `json:"time"`
+	Type     string             `json:"type"`
+	Resource []byte             `json:"resource"`
+	Old      []byte             `json:"old,omitempty"`
+	Destroy  *state.DestroyInfo `json:"destroy,omitempty"`
+}
+
+// Event returns the state.Event stored in the record.
+func (record *EventRecord) Event() (state.Event, error) {
+	event := state.Event{
+		Destroy: record.Destroy,
+	}
+
+	switch record.Type {
+	case state.Created.String():
+		event.Type = state.Created
+	case state.Updated.String():
+		event.Type = state.Updated
+	case state.Destroyed.String():
+		event.Type = state.Destroyed
+	default:
+		return state.Event{}, fmt.Errorf("unknown event type %q", record.Type)
+	}
+
+	var (
+		marshaler store.ProtobufMarshaler
+		err       error
+	)
+
+	if event.Resource, err = marshaler.UnmarshalResource(record.Resource); err != nil {
+		return state.Event{}, fmt.Errorf("error unmarshaling resource: %w", err)
+	}
+
+	if record.Old != nil {
+		if event.Old, err = marshaler.UnmarshalResource(record.Old); err != nil {
+			return state.Event{}, fmt.Errorf("error unmarshaling old resource: %w", err)
+		}
+	}
+
+	return event, nil
+}
+
+// EventRecorder writes the state events as a stream of JSON records.
+//
+// EventRecorder is safe for concurrent use, so that the events of several watches can be captured into a single recording.
+type EventRecorder struct {
+	enc *json.Encoder
+	mu  sync.Mutex
+}
+
+// NewEventRecorder creates an EventRecorder which writes to w.
+func NewEventRecorder(w io.Writer) *EventRecorder {
+	return &EventRecorder{
+		enc: json.NewEncoder(w),
+	}
+}
+
+// Record writes the event to the recording.
+func (recorder *EventRecorder) Record(event state.Event) error {
+	record := EventRecord{
+		Time:    time.Now(),
+		Type:    event.Type.String(),
+		Destroy: event.Destroy,
+	}
+
+	var (
+		marshaler store.ProtobufMarshaler
+		err       error
+	)
+
+	if record.Resource, err = marshaler.MarshalResource(event.Resource); err != nil {
+		return fmt.Errorf("error marshaling resource: %w", err)
+	}
+
+	if event.Old != nil {
+		if record.Old, err = marshaler.MarshalResource(event.Old); err != nil {
+			return fmt.Errorf("error marshaling old resource: %w", err)
+		}
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	if err = recorder.enc.Encode(record); err != nil {
+		return fmt.Errorf("error writing event record: %w", err)
+	}
+
+	return nil
+}
+
+// Tee records the events received from in and passes them on to out, until the context is canceled or in is closed.
+//
+// Tee is placed between the watch and the consumer to capture the events the consumer sees:
+//
+//	watchCh := make(chan state.Event)
+//	st.WatchKind(ctx, kind, watchCh)
+//	go recorder.Tee(ctx, watchCh, consumerCh)
+func (recorder *EventRecorder) Tee(ctx context.Context, in <-chan state.Event, out chan<- state.Event) error {
+	for {
+		var (
+			event state.Event
+			ok    bool
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok = <-in:
+			if !ok {
+				return nil
+			}
+		}
+
+		if err := recorder.Record(event); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case out <- event:
+		}
+	}
+}
+
+// ReadEvents reads all records written by the EventRecorder.
+func ReadEvents(r io.Reader) ([]EventRecord, error) {
+	var records []EventRecord
+
+	dec := json.NewDecoder(r)
+
+	for {
+		var record EventRecord
+
+		if err := dec.Decode(&record); err != nil {
+			if errors.Is(err, io.EOF) {
+				return records, nil
+			}
+
+			return nil, fmt.Errorf("error reading event record: %w", err)
+		}
+
+		records = append(records, record)
+	}
+}
+
+// ReplayOptions configure ReplayEvents.
+type ReplayOptions struct {
+	Clock clock.Clock
+	Speed float64
+}
+
+// ReplayOption applies settings to ReplayOptions.
+type ReplayOption func(options *ReplayOptions)
+
+// WithSpeed scales the intervals between the events.
+//
+// Speed 1 keeps the original timing, speed 10 replays the events ten times faster,
+// speed 0 delivers the events without any delays.
+func WithSpeed(speed float64) ReplayOption {
+	return func(options *ReplayOptions) {
+		options.Speed = speed
+	}
+}
+
+// WithReplayClock sets the time source used to wait between the events.
+func WithReplayClock(c clock.Clock) ReplayOption {
+	return func(options *ReplayOptions) {
+		options.Clock = c
+	}
+}
+
+// DefaultReplayOptions returns default value of ReplayOptions.
+func DefaultReplayOptions() ReplayOptions {
+	return ReplayOptions{
+		Clock: clock.Real(),
+		Speed: 1,
+	}
+}
+
+// ReplayEvents sends the recorded events to the channel in the order they were recorded.
+//
+// The intervals between the events follow the recording scaled by the speed (see WithSpeed),
+// the time spent blocked on sending to the channel counts towards the interval.
+func ReplayEvents(ctx context.Context, records []EventRecord, ch chan<- state.Event, opts ...ReplayOption) error {
+	options := DefaultReplayOptions()
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var (
+		start     time.Time
+		firstTime time.Time
+	)
+
+	for i := range records {
+		record := &records[i]
+
+		event, err := record.Event()
+		if err != nil {
+			return fmt.Errorf("error replaying event %d: %w", i, err)
+		}
+
+		if i == 0 {
+			start, firstTime = options.Clock.Now(), record.Time
+		} else if options.Speed > 0 {
+			offset := time.Duration(float64(record.Time.Sub(firstTime)) / options.Speed)
+
+			if wait := options.Clock.Until(start.Add(offset)); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-options.Clock.After(wait):
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ch <- event:
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package statetest_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/clock"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/conformance"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/cosi-project/runtime/pkg/state/statetest"
+)
+
+func init() {
+	if err := protobuf.RegisterResource(conformance.PathResourceType, &conformance.PathResource{}); err != nil {
+		panic(err)
+	}
+}
+
+func TestRecordReplayEvents(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	watchCh := make(chan state.Event)
+	consumerCh := make(chan state.Event)
+
+	require.NoError(t, st.WatchKind(ctx, resource.NewMetadata("default", conformance.PathResourceType, "", resource.VersionUndefined), watchCh))
+
+	var buf bytes.Buffer
+
+	recorder := statetest.NewEventRecorder(&buf)
+
+	teeErr := make(chan error, 1)
+
+	go func() {
+		teeErr <- recorder.Tee(ctx, watchCh, consumerCh)
+	}()
+
+	path := conformance.NewPathResource("default", "var/run")
+
+	require.NoError(t, st.Create(ctx, path))
+
+	_, err := st.UpdateWithConflicts(ctx, path.Metadata(), func(r resource.Resource) error {
+		r.Metadata().Labels().Set("app", "os")
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, st.Destroy(ctx, path.Metadata()))
+
+	var consumed []state.Event
+
+	for i := 0; i < 3; i++ {
+		select {
+		case event := <-consumerCh:
+			consumed = append(consumed, event)
+		case <-ctx.Done():
+			require.FailNow(t, "timed out waiting for event")
+		}
+	}
+
+	cancel()
+	require.NoError(t, <-teeErr)
+
+	records, err := statetest.ReadEvents(&buf)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	replayCh := make(chan state.Event, len(records))
+
+	require.NoError(t, statetest.ReplayEvents(context.Background(), records, replayCh, statetest.WithSpeed(0)))
+
+	for _, expected := range consumed {
+		replayed := <-replayCh
+
+		assert.Equal(t, expected.Type, replayed.Type)
+		assert.True(t, resource.Equal(expected.Resource, replayed.Resource))
+
+		if expected.Old != nil {
+			require.NotNil(t, replayed.Old)
+			assert.True(t, resource.Equal(expected.Old, replayed.Old))
+		}
+	}
+
+	value, ok := consumed[1].Resource.Metadata().Labels().Get("app")
+	assert.True(t, ok)
+	assert.Equal(t, "os", value)
+}
+
+func TestReplayEventsTiming(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var buf bytes.Buffer
+
+	recorder := statetest.NewEventRecorder(&buf)
+
+	for _, path := range []string{"a", "b", "c"} {
+		require.NoError(t, recorder.Record(state.Event{
+			Type:     state.Created,
+			Resource: conformance.NewPathResource("default", path),
+		}))
+	}
+
+	records, err := statetest.ReadEvents(&buf)
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	// events are 1s and 2s apart, replayed twice as fast
+	records[0].Time = start
+	records[1].Time = start.Add(time.Second)
+	records[2].Time = start.Add(3 * time.Second)
+
+	fake := clock.NewFake(start)
+	ch := make(chan state.Event)
+	replayErr := make(chan error, 1)
+
+	go func() {
+		replayErr <- statetest.ReplayEvents(ctx, records, ch, statetest.WithSpeed(2), statetest.WithReplayClock(fake))
+	}()
+
+	assert.Equal(t, "a", (<-ch).Resource.Metadata().ID())
+
+	for _, step := range []struct {
+		id      resource.ID
+		advance time.Duration
+	}{
+		{"b", 500 * time.Millisecond},
+		{"c", time.Second},
+	} {
+		require.NoError(t, fake.BlockUntil(ctx, 1))
+
+		fake.Advance(step.advance - time.Millisecond)
+
+		select {
+		case <-ch:
+			require.FailNow(t, "event replayed too early")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		fake.Advance(time.Millisecond)
+
+		assert.Equal(t, step.id, (<-ch).Resource.Metadata().ID())
+	}
+
+	require.NoError(t, <-replayErr)
+}
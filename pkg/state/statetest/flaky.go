@@ -0,0 +1,214 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package statetest provides helpers for testing the code built on top of the state.
+package statetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// ErrInjectedFault is returned from the state calls failed by Flaky.
+var ErrInjectedFault = errors.New("injected fault")
+
+// Policy configures the faults injected by Flaky.
+//
+// Calls are counted from 1 across all verbs, writes (Create, Update, UpdateStatus and Destroy) are counted separately,
+// so the faults are deterministic for the same sequence of the calls.
+type Policy struct {
+	// Error is returned from the failed calls, default is ErrInjectedFault.
+	Error error
+
+	// ErrorOnCalls lists the calls which fail with Error.
+	ErrorOnCalls []int
+	// ConflictOnWrites lists the writes which fail with a spurious conflict error.
+	ConflictOnWrites []int
+
+	// ErrorEvery fails every n-th call with Error, if set.
+	ErrorEvery int
+
+	// Latency delays each call.
+	Latency time.Duration
+}
+
+// FlakyState injects faults into the calls of the underlying state.
+type FlakyState struct {
+	state.CoreState
+
+	policy Policy
+
+	mu       sync.Mutex
+	calls    int
+	writes   int
+	injected int
+}
+
+// Flaky wraps the state to inject errors, latencies and spurious conflicts according to the policy.
+//
+// Flaky is intended for the unit tests which verify resilience of the controllers and other state clients.
+func Flaky(st state.CoreState, policy Policy) *FlakyState {
+	if policy.Error == nil {
+		policy.Error = ErrInjectedFault
+	}
+
+	return &FlakyState{
+		CoreState: st,
+		policy:    policy,
+	}
+}
+
+// Injected returns the number of the faults injected so far.
+func (st *FlakyState) Injected() int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return st.injected
+}
+
+type conflictError struct {
+	error
+}
+
+func (conflictError) ConflictError() {}
+
+func (err conflictError) Unwrap() error {
+	return err.error
+}
+
+// inject delays the call and returns an error if the call should fail.
+func (st *FlakyState) inject(ctx context.Context, write bool) error {
+	if st.policy.Latency > 0 {
+		timer := time.NewTimer(st.policy.Latency)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.calls++
+
+	if contains(st.policy.ErrorOnCalls, st.calls) || (st.policy.ErrorEvery > 0 && st.calls%st.policy.ErrorEvery == 0) {
+		st.injected++
+
+		return fmt.Errorf("state call #%d: %w", st.calls, st.policy.Error)
+	}
+
+	if !write {
+		return nil
+	}
+
+	st.writes++
+
+	if contains(st.policy.ConflictOnWrites, st.writes) {
+		st.injected++
+
+		return conflictError{fmt.Errorf("conflict on state write #%d: %w", st.writes, ErrInjectedFault)}
+	}
+
+	return nil
+}
+
+func contains(calls []int, call int) bool {
+	for _, c := range calls {
+		if c == call {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get a resource by type and ID.
+func (st *FlakyState) Get(ctx context.Context, ptr resource.Pointer, opts ...state.GetOption) (resource.Resource, error) { //nolint:ireturn
+	if err := st.inject(ctx, false); err != nil {
+		return nil, err
+	}
+
+	return st.CoreState.Get(ctx, ptr, opts...)
+}
+
+// List resources by kind.
+func (st *FlakyState) List(ctx context.Context, kind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	if err := st.inject(ctx, false); err != nil {
+		return resource.List{}, err
+	}
+
+	return st.CoreState.List(ctx, kind, opts...)
+}
+
+// ListStream streams resources by kind.
+func (st *FlakyState) ListStream(ctx context.Context, kind resource.Kind, yield func(resource.Resource) bool, opts ...state.ListOption) error {
+	if err := st.inject(ctx, false); err != nil {
+		return err
+	}
+
+	return state.ListStream(ctx, st.CoreState, kind, yield, opts...)
+}
+
+// Create a resource.
+func (st *FlakyState) Create(ctx context.Context, res resource.Resource, opts ...state.CreateOption) error {
+	if err := st.inject(ctx, true); err != nil {
+		return err
+	}
+
+	return st.CoreState.Create(ctx, res, opts...)
+}
+
+// Update a resource.
+func (st *FlakyState) Update(ctx context.Context, curVersion resource.Version, newResource resource.Resource, opts ...state.UpdateOption) error {
+	if err := st.inject(ctx, true); err != nil {
+		return err
+	}
+
+	return st.CoreState.Update(ctx, curVersion, newResource, opts...)
+}
+
+// UpdateStatus updates the status of a resource.
+func (st *FlakyState) UpdateStatus(ctx context.Context, curVersion resource.Version, newResource resource.StatusResource, opts ...state.UpdateOption) error {
+	if err := st.inject(ctx, true); err != nil {
+		return err
+	}
+
+	return state.UpdateStatus(ctx, st.CoreState, curVersion, newResource, opts...)
+}
+
+// Destroy a resource.
+func (st *FlakyState) Destroy(ctx context.Context, ptr resource.Pointer, opts ...state.DestroyOption) error {
+	if err := st.inject(ctx, true); err != nil {
+		return err
+	}
+
+	return st.CoreState.Destroy(ctx, ptr, opts...)
+}
+
+// Watch state of a resource by type.
+func (st *FlakyState) Watch(ctx context.Context, ptr resource.Pointer, ch chan<- state.Event, opts ...state.WatchOption) error {
+	if err := st.inject(ctx, false); err != nil {
+		return err
+	}
+
+	return st.CoreState.Watch(ctx, ptr, ch, opts...)
+}
+
+// WatchKind watches resources of specific kind (namespace and type).
+func (st *FlakyState) WatchKind(ctx context.Context, kind resource.Kind, ch chan<- state.Event, opts ...state.WatchKindOption) error {
+	if err := st.inject(ctx, false); err != nil {
+		return err
+	}
+
+	return st.CoreState.WatchKind(ctx, kind, ch, opts...)
+}
@@ -0,0 +1,112 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package statetest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/conformance"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/cosi-project/runtime/pkg/state/statetest"
+)
+
+func TestFlaky(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	flaky := statetest.Flaky(namespaced.NewState(inmem.Build), statetest.Policy{
+		ErrorOnCalls:     []int{2},
+		ConflictOnWrites: []int{2},
+	})
+	st := state.WrapCore(flaky)
+
+	path := conformance.NewPathResource("default", "var/run")
+
+	// call #1, write #1
+	require.NoError(t, st.Create(ctx, path))
+
+	// call #2
+	_, err := st.Get(ctx, path.Metadata())
+	assert.ErrorIs(t, err, statetest.ErrInjectedFault)
+	assert.False(t, state.IsConflictError(err))
+
+	// call #3, write #2
+	err = st.Destroy(ctx, path.Metadata())
+	assert.ErrorIs(t, err, statetest.ErrInjectedFault)
+	assert.True(t, state.IsConflictError(err))
+
+	// call #4, write #3
+	require.NoError(t, st.Destroy(ctx, path.Metadata()))
+
+	assert.Equal(t, 2, flaky.Injected())
+}
+
+func TestFlakyUpdateStatus(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	flaky := statetest.Flaky(namespaced.NewState(inmem.Build), statetest.Policy{
+		ConflictOnWrites: []int{2},
+	})
+
+	replica := conformance.NewReplicaResource("default", "one", 3)
+
+	// write #1
+	require.NoError(t, flaky.Create(ctx, replica))
+
+	status := replica.DeepCopy().(*conformance.ReplicaResource) //nolint:forcetypeassert
+	status.TypedSpec().Ready = 2
+	status.Metadata().BumpVersion()
+
+	// write #2
+	err := state.UpdateStatus(ctx, flaky, replica.Metadata().Version(), status)
+	assert.True(t, state.IsConflictError(err))
+
+	// write #3
+	require.NoError(t, state.UpdateStatus(ctx, flaky, replica.Metadata().Version(), status))
+
+	assert.Equal(t, 1, flaky.Injected())
+}
+
+func TestFlakyErrorEvery(t *testing.T) {
+	t.Parallel()
+
+	errUnavailable := errors.New("unavailable")
+
+	flaky := statetest.Flaky(namespaced.NewState(inmem.Build), statetest.Policy{
+		Error:      errUnavailable,
+		ErrorEvery: 3,
+		Latency:    time.Millisecond,
+	})
+
+	path := conformance.NewPathResource("default", "var/run")
+
+	var failed int
+
+	for i := 0; i < 9; i++ {
+		if _, err := flaky.Get(context.Background(), path.Metadata()); errors.Is(err, errUnavailable) {
+			failed++
+		}
+	}
+
+	assert.Equal(t, 3, failed)
+	assert.Equal(t, 3, flaky.Injected())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := statetest.Flaky(flaky, statetest.Policy{Latency: time.Hour}).Get(ctx, path.Metadata())
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,175 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package statetest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "update the golden files compared by statetest.AssertGolden")
+
+// goldenMetadata is the part of the metadata which is stable between the test runs.
+type goldenMetadata struct { //nolint:govet
+	Namespace  string            `yaml:"namespace"`
+	Type       string            `yaml:"type"`
+	ID         string            `yaml:"id"`
+	Owner      string            `yaml:"owner,omitempty"`
+	Phase      string            `yaml:"phase"`
+	Labels     map[string]string `yaml:"labels,omitempty"`
+	Finalizers []string          `yaml:"finalizers,omitempty"`
+}
+
+type goldenResource struct { //nolint:govet
+	Metadata goldenMetadata `yaml:"metadata"`
+	Spec     *yaml.Node     `yaml:"spec"`
+}
+
+// MarshalGolden serializes the resources to the canonical YAML.
+//
+// Resources are sorted by namespace, type and ID, and each one is written as a separate YAML document.
+// Versions and timestamps are omitted, so that the output is stable between the test runs.
+func MarshalGolden(resources ...resource.Resource) ([]byte, error) {
+	sorted := append([]resource.Resource(nil), resources...)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i].Metadata(), sorted[j].Metadata()
+
+		if a.Namespace() != b.Namespace() {
+			return a.Namespace() < b.Namespace()
+		}
+
+		if a.Type() != b.Type() {
+			return a.Type() < b.Type()
+		}
+
+		return a.ID() < b.ID()
+	})
+
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	for _, r := range sorted {
+		md := r.Metadata()
+
+		doc := goldenResource{
+			Metadata: goldenMetadata{
+				Namespace:  md.Namespace(),
+				Type:       md.Type(),
+				ID:         md.ID(),
+				Owner:      md.Owner(),
+				Phase:      md.Phase().String(),
+				Finalizers: *md.Finalizers(),
+			},
+		}
+
+		if !md.Labels().Empty() {
+			doc.Metadata.Labels = md.Labels().Raw()
+		}
+
+		specYAML, err := yaml.Marshal(r.Spec())
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling spec of %s: %w", md, err)
+		}
+
+		var spec yaml.Node
+
+		if err = yaml.Unmarshal(specYAML, &spec); err != nil {
+			return nil, fmt.Errorf("error parsing spec of %s: %w", md, err)
+		}
+
+		if len(spec.Content) > 0 {
+			doc.Spec = spec.Content[0]
+		}
+
+		if err = encoder.Encode(doc); err != nil {
+			return nil, fmt.Errorf("error marshaling %s: %w", md, err)
+		}
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// AssertGolden compares the resources serialized with MarshalGolden against the golden file.
+//
+// The golden files are (re)written instead of compared if the tests are run with the -update-golden flag.
+func AssertGolden(t testing.TB, path string, resources ...resource.Resource) bool {
+	t.Helper()
+
+	actual, err := MarshalGolden(resources...)
+	if err != nil {
+		t.Errorf("error marshaling resources: %s", err)
+
+		return false
+	}
+
+	if *updateGolden {
+		if err = os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+			err = os.WriteFile(path, actual, 0o644)
+		}
+
+		if err != nil {
+			t.Errorf("error updating golden file: %s", err)
+
+			return false
+		}
+
+		return true
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			t.Errorf("golden file %q doesn't exist, run the test with -update-golden to create it", path)
+		} else {
+			t.Errorf("error reading golden file: %s", err)
+		}
+
+		return false
+	}
+
+	return assert.Equal(t, string(expected), string(actual), "resources don't match golden file %q, run the test with -update-golden to update it", path)
+}
+
+// AssertGoldenState lists the resources of the kinds from the state, and compares them against the golden file.
+//
+// AssertGoldenState is useful to check all outputs of the controller at once.
+func AssertGoldenState(t testing.TB, st state.CoreState, path string, kinds ...resource.Kind) bool {
+	t.Helper()
+
+	var resources []resource.Resource
+
+	for _, kind := range kinds {
+		list, err := st.List(context.Background(), kind)
+		if err != nil {
+			t.Errorf("error listing %s/%s: %s", kind.Namespace(), kind.Type(), err)
+
+			return false
+		}
+
+		resources = append(resources, list.Items...)
+	}
+
+	return AssertGolden(t, path, resources...)
+}
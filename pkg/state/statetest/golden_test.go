@@ -0,0 +1,72 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package statetest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/conformance"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/cosi-project/runtime/pkg/state/statetest"
+)
+
+func TestMarshalGolden(t *testing.T) {
+	t.Parallel()
+
+	etc := conformance.NewPathResource("system", "etc")
+	etc.Metadata().Labels().Set("app", "os")
+	etc.Metadata().Finalizers().Add("cleanup")
+
+	out, err := statetest.MarshalGolden(etc, conformance.NewPathResource("default", "var/run"))
+	require.NoError(t, err)
+
+	assert.Equal(t, `metadata:
+  namespace: default
+  type: os/path
+  id: var/run
+  phase: running
+spec: {}
+---
+metadata:
+  namespace: system
+  type: os/path
+  id: etc
+  phase: running
+  labels:
+    app: os
+  finalizers:
+    - cleanup
+spec: {}
+`, string(out))
+}
+
+func TestAssertGoldenState(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	for _, path := range []string{"var/run", "etc", "var/lib"} {
+		require.NoError(t, st.Create(ctx, conformance.NewPathResource("default", path), state.WithCreateOwner("PathController")))
+	}
+
+	// versions differ from the golden file, but they are not compared
+	_, err := st.UpdateWithConflicts(ctx, conformance.NewPathResource("default", "etc").Metadata(), func(r resource.Resource) error {
+		r.Metadata().Labels().Set("updated", "true")
+
+		return nil
+	}, state.WithUpdateOwner("PathController"))
+	require.NoError(t, err)
+
+	statetest.AssertGoldenState(t, st, "testdata/paths.yaml", conformance.NewPathResource("default", "").Metadata())
+}
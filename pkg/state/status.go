@@ -0,0 +1,24 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package state
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+// UpdateStatus updates the status of the resource.
+//
+// The state should implement StatusUpdater, otherwise an error is returned.
+func UpdateStatus(ctx context.Context, st CoreState, curVersion resource.Version, newResource resource.StatusResource, opts ...UpdateOption) error {
+	updater, ok := st.(StatusUpdater)
+	if !ok {
+		return fmt.Errorf("state %T doesn't support status updates", st)
+	}
+
+	return updater.UpdateStatus(ctx, curVersion, newResource, opts...)
+}
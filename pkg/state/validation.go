@@ -0,0 +1,156 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package state
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+// FieldError describes a validation failure of a single spec field.
+type FieldError struct {
+	// Field is the path to the field, e.g. `spec.addresses[0]`.
+	Field   string
+	Message string
+}
+
+// String implements fmt.Stringer.
+func (err FieldError) String() string {
+	if err.Field == "" {
+		return err.Message
+	}
+
+	return err.Field + ": " + err.Message
+}
+
+// ValidationError is returned when the resource fails validation on write.
+type ValidationError struct {
+	Resource resource.Reference
+	Fields   []FieldError
+}
+
+// ValidationError implements ErrValidation.
+func (*ValidationError) ValidationError() {}
+
+// Error implements error interface.
+func (err *ValidationError) Error() string {
+	fields := make([]string, 0, len(err.Fields))
+
+	for _, field := range err.Fields {
+		fields = append(fields, field.String())
+	}
+
+	return fmt.Sprintf("resource %s failed validation: %s", err.Resource, strings.Join(fields, "; "))
+}
+
+// Validator checks the resource, and returns the list of field errors, if any.
+type Validator func(resource.Resource) []FieldError
+
+// ValidatorRegistry holds validators attached to resource types.
+//
+// ValidatorRegistry is safe for concurrent use.
+type ValidatorRegistry struct {
+	validators map[resource.Type][]Validator
+	mu         sync.RWMutex
+}
+
+// NewValidatorRegistry creates new ValidatorRegistry.
+func NewValidatorRegistry() *ValidatorRegistry {
+	return &ValidatorRegistry{
+		validators: map[resource.Type][]Validator{},
+	}
+}
+
+// Register attaches the validator to the resource type.
+//
+// Several validators can be attached to the same type, their errors are combined.
+func (registry *ValidatorRegistry) Register(resourceType resource.Type, validator Validator) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.validators[resourceType] = append(registry.validators[resourceType], validator)
+}
+
+// Validate runs the validators of the resource type.
+//
+// If any validator fails, ValidationError is returned.
+func (registry *ValidatorRegistry) Validate(r resource.Resource) error {
+	registry.mu.RLock()
+	validators := registry.validators[r.Metadata().Type()]
+	registry.mu.RUnlock()
+
+	var fields []FieldError
+
+	for _, validator := range validators {
+		fields = append(fields, validator(r)...)
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return &ValidationError{
+		Resource: r.Metadata(),
+		Fields:   fields,
+	}
+}
+
+// Validate wraps the state to validate resources on Create and Update.
+//
+// Writes of resources which fail validation are rejected with ValidationError.
+func Validate(coreState CoreState, registry *ValidatorRegistry) CoreState { //nolint:ireturn
+	return &validatingState{
+		CoreState: coreState,
+		registry:  registry,
+	}
+}
+
+type validatingState struct {
+	CoreState
+
+	registry *ValidatorRegistry
+}
+
+// Create a resource.
+//
+// If a resource fails validation, Create returns ValidationError.
+func (st *validatingState) Create(ctx context.Context, res resource.Resource, opts ...CreateOption) error {
+	if err := st.registry.Validate(res); err != nil {
+		return err
+	}
+
+	return st.CoreState.Create(ctx, res, opts...)
+}
+
+// Update a resource.
+//
+// If a resource fails validation, Update returns ValidationError.
+func (st *validatingState) Update(ctx context.Context, curVersion resource.Version, newResource resource.Resource, opts ...UpdateOption) error {
+	if err := st.registry.Validate(newResource); err != nil {
+		return err
+	}
+
+	return st.CoreState.Update(ctx, curVersion, newResource, opts...)
+}
+
+// UpdateStatus implements StatusUpdater interface.
+//
+// If a resource fails validation, UpdateStatus returns ValidationError.
+func (st *validatingState) UpdateStatus(ctx context.Context, curVersion resource.Version, newResource resource.StatusResource, opts ...UpdateOption) error {
+	if err := st.registry.Validate(newResource); err != nil {
+		return err
+	}
+
+	return UpdateStatus(ctx, st.CoreState, curVersion, newResource, opts...)
+}
+
+// ListStream implements ListStreamer interface.
+func (st *validatingState) ListStream(ctx context.Context, kind resource.Kind, yield func(resource.Resource) bool, opts ...ListOption) error {
+	return ListStream(ctx, st.CoreState, kind, yield, opts...)
+}
@@ -0,0 +1,106 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package state_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	ctrlconformance "github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/conformance"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestValidatePasshtroughConformance(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &conformance.StateSuite{
+		State:      state.WrapCore(state.Validate(namespaced.NewState(inmem.Build), state.NewValidatorRegistry())),
+		Namespaces: []resource.Namespace{"default", "controller", "system", "runtime"},
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	registry := state.NewValidatorRegistry()
+	registry.Register(ctrlconformance.IntResourceType, func(r resource.Resource) []state.FieldError {
+		if r.(*ctrlconformance.IntResource).Value() < 0 { //nolint:forcetypeassert
+			return []state.FieldError{{Field: "value", Message: "should be non-negative"}}
+		}
+
+		return nil
+	})
+
+	st := state.WrapCore(state.Validate(namespaced.NewState(inmem.Build), registry))
+
+	err := st.Create(ctx, ctrlconformance.NewIntResource("default", "negative", -1))
+	require.Error(t, err)
+	assert.True(t, state.IsValidationError(err))
+	assert.EqualError(t, err, "resource test/int(default/negative@1) failed validation: value: should be non-negative")
+
+	r := ctrlconformance.NewIntResource("default", "one", 1)
+	require.NoError(t, st.Create(ctx, r))
+
+	_, err = st.UpdateWithConflicts(ctx, r.Metadata(), func(r resource.Resource) error {
+		r.(*ctrlconformance.IntResource).SetValue(-2) //nolint:forcetypeassert
+
+		return nil
+	})
+	assert.True(t, state.IsValidationError(err))
+
+	_, err = st.UpdateWithConflicts(ctx, r.Metadata(), func(r resource.Resource) error {
+		r.(*ctrlconformance.IntResource).SetValue(2) //nolint:forcetypeassert
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	// other types are not validated
+	require.NoError(t, st.Create(ctx, ctrlconformance.NewStrResource("default", "str", "")))
+}
+
+func TestValidateStatus(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	registry := state.NewValidatorRegistry()
+	registry.Register(conformance.ReplicaResourceType, func(r resource.Resource) []state.FieldError {
+		spec := r.(*conformance.ReplicaResource).TypedSpec() //nolint:forcetypeassert
+
+		if spec.Ready > spec.Replicas {
+			return []state.FieldError{{Field: "ready", Message: "should not exceed replicas"}}
+		}
+
+		return nil
+	})
+
+	st := state.WrapCore(state.Validate(namespaced.NewState(inmem.Build), registry))
+
+	r := conformance.NewReplicaResource("default", "one", 3)
+	require.NoError(t, st.Create(ctx, r))
+
+	status := r.DeepCopy().(*conformance.ReplicaResource) //nolint:forcetypeassert
+	status.TypedSpec().Ready = 4
+	status.Metadata().BumpVersion()
+
+	err := state.UpdateStatus(ctx, st, r.Metadata().Version(), status)
+	require.Error(t, err)
+	assert.True(t, state.IsValidationError(err))
+
+	status.TypedSpec().Ready = 2
+
+	require.NoError(t, state.UpdateStatus(ctx, st, r.Metadata().Version(), status))
+}
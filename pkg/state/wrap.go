@@ -21,6 +21,18 @@ type coreWrapper struct {
 	CoreState
 }
 
+// ListStream implements ListStreamer interface.
+//
+// If the underlying CoreState doesn't support streaming, the full list is fetched and streamed.
+func (state coreWrapper) ListStream(ctx context.Context, kind resource.Kind, yield func(resource.Resource) bool, opts ...ListOption) error {
+	return ListStream(ctx, state.CoreState, kind, yield, opts...)
+}
+
+// UpdateStatus implements StatusUpdater interface.
+func (state coreWrapper) UpdateStatus(ctx context.Context, curVersion resource.Version, newResource resource.StatusResource, opts ...UpdateOption) error {
+	return UpdateStatus(ctx, state.CoreState, curVersion, newResource, opts...)
+}
+
 // UpdateWithConflicts automatically handles conflicts on update.
 func (state coreWrapper) UpdateWithConflicts(ctx context.Context, resourcePointer resource.Pointer, f UpdaterFunc, opts ...UpdateOption) (resource.Resource, error) { //nolint:ireturn
 	options := DefaultUpdateOptions()
@@ -48,7 +60,7 @@ func (state coreWrapper) UpdateWithConflicts(ctx context.Context, resourcePointe
 			return nil, err
 		}
 
-		if resource.Equal(current, newResource) {
+		if resource.ContentEqual(current, newResource) {
 			return current, nil
 		}
 
@@ -169,3 +181,18 @@ func (state coreWrapper) RemoveFinalizer(ctx context.Context, resourcePointer re
 
 	return err
 }
+
+// BatchCreate implements Batcher interface.
+func (state coreWrapper) BatchCreate(ctx context.Context, items []BatchCreateItem) error {
+	return BatchCreate(ctx, state.CoreState, items)
+}
+
+// BatchUpdate implements Batcher interface.
+func (state coreWrapper) BatchUpdate(ctx context.Context, items []BatchUpdateItem) error {
+	return BatchUpdate(ctx, state.CoreState, items)
+}
+
+// BatchDestroy implements Batcher interface.
+func (state coreWrapper) BatchDestroy(ctx context.Context, items []BatchDestroyItem) error {
+	return BatchDestroy(ctx, state.CoreState, items)
+}
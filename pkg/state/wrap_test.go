@@ -5,8 +5,11 @@
 package state_test
 
 import (
+	"context"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/cosi-project/runtime/pkg/resource"
@@ -24,3 +27,71 @@ func TestWrapConformance(t *testing.T) {
 		Namespaces: []resource.Namespace{"default", "controller", "system", "runtime"},
 	})
 }
+
+func TestWrappersPassThrough(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		wrap func(state.CoreState) state.CoreState
+		name string
+	}{
+		{
+			name: "Validate",
+			wrap: func(st state.CoreState) state.CoreState {
+				return state.Validate(st, state.NewValidatorRegistry())
+			},
+		},
+		{
+			name: "Default",
+			wrap: func(st state.CoreState) state.CoreState {
+				return state.Default(st, state.NewDefaulterRegistry())
+			},
+		},
+		{
+			name: "Filter",
+			wrap: func(st state.CoreState) state.CoreState {
+				return state.Filter(st, func(context.Context, state.Access) error { return nil })
+			},
+		},
+		{
+			name: "Convert",
+			wrap: func(st state.CoreState) state.CoreState {
+				return state.Convert(st, state.NewConversionRegistry())
+			},
+		},
+	} {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			st := test.wrap(namespaced.NewState(inmem.Build))
+
+			assert.Implements(t, (*state.ListStreamer)(nil), st)
+			assert.Implements(t, (*state.StatusUpdater)(nil), st)
+
+			r := conformance.NewReplicaResource("default", "one", 3)
+			require.NoError(t, st.Create(ctx, r))
+
+			status := r.DeepCopy().(*conformance.ReplicaResource) //nolint:forcetypeassert
+			status.TypedSpec().Ready = 2
+			status.Metadata().BumpVersion()
+
+			require.NoError(t, state.UpdateStatus(ctx, st, r.Metadata().Version(), status, state.WithUpdateOwner("controller")))
+
+			var listed []resource.Resource
+
+			require.NoError(t, state.ListStream(ctx, st, r.Metadata(), func(r resource.Resource) bool {
+				listed = append(listed, r)
+
+				return true
+			}))
+
+			require.Len(t, listed, 1)
+			assert.Equal(t, conformance.ReplicaSpec{Replicas: 3, Ready: 2}, *listed[0].(*conformance.ReplicaResource).TypedSpec()) //nolint:forcetypeassert
+			assert.Equal(t, "controller", listed[0].Metadata().StatusOwner())
+		})
+	}
+}